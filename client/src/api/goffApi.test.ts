@@ -0,0 +1,57 @@
+import { afterEach, describe, expect, it, vi } from "vitest";
+import { getFeatureFlags } from "./goffApi";
+import type { FeatureFlagDTO } from "../models/featureFlagDTO";
+
+function flags(count: number, start: number): FeatureFlagDTO[] {
+  return Array.from({ length: count }, (_, i) => {
+    const name = `flag${start + i}`;
+    return { id: name, name } as FeatureFlagDTO;
+  });
+}
+
+function listResponse(page: FeatureFlagDTO[], total: number, offset: number) {
+  return {
+    json: () => Promise.resolve({ flags: page, total, limit: 100, offset }),
+  } as Response;
+}
+
+describe("getFeatureFlags", () => {
+  afterEach(() => {
+    vi.unstubAllGlobals();
+  });
+
+  it("should fetch all the pages of flags", async () => {
+    const fetchMock = vi
+      .fn()
+      .mockResolvedValueOnce(listResponse(flags(100, 0), 150, 0))
+      .mockResolvedValueOnce(listResponse(flags(50, 100), 150, 100));
+    vi.stubGlobal("fetch", fetchMock);
+
+    const result = await getFeatureFlags(
+      new Request("https://example.com/?filter=foo"),
+    );
+
+    expect(result).toHaveLength(150);
+    expect(result[149].name).toEqual("flag149");
+    expect(fetchMock).toHaveBeenCalledTimes(2);
+    expect(fetchMock.mock.calls[0][0]).toContain(
+      "/v1/flags?limit=100&offset=0&filter=foo",
+    );
+    expect(fetchMock.mock.calls[1][0]).toContain(
+      "/v1/flags?limit=100&offset=100&filter=foo",
+    );
+  });
+
+  it("should stop when a page is empty", async () => {
+    const fetchMock = vi
+      .fn()
+      .mockResolvedValueOnce(listResponse(flags(2, 0), 5, 0))
+      .mockResolvedValueOnce(listResponse([], 5, 2));
+    vi.stubGlobal("fetch", fetchMock);
+
+    const result = await getFeatureFlags(new Request("https://example.com/"));
+
+    expect(result).toHaveLength(2);
+    expect(fetchMock).toHaveBeenCalledTimes(2);
+  });
+});
@@ -1,23 +1,44 @@
 import { config } from "../config.ts";
 import type {
   FeatureFlagDTO,
+  FeatureFlagListDTO,
   FeatureFlagStatusUpdateDTO,
   NewFeatureFlagDTO,
 } from "../models/featureFlagDTO.ts";
 
 const BASE_URL = config.apiURL;
 
-export function getFeatureFlags(req: Request): Promise<FeatureFlagDTO[]> {
+// FLAGS_PAGE_SIZE is the maximum number of flags per page accepted by the API.
+const FLAGS_PAGE_SIZE = 100;
+
+export async function getFeatureFlags(
+  req: Request,
+): Promise<FeatureFlagDTO[]> {
   const url = new URL(req.url);
   const filter = url.searchParams.get("filter");
-  return fetch(`${BASE_URL}/v1/flags${filter ? `?filter=${filter}` : ""}`, {
-    cache: "no-cache",
-  })
-    .then((response) => response.json())
-    .catch((error) => {
-      console.error("Error in getFeatureFlags:", error);
-      throw error;
-    });
+  const flags: FeatureFlagDTO[] = [];
+  try {
+    // the API is paginated, we request the pages until we have all the flags
+    for (;;) {
+      const params = new URLSearchParams({
+        limit: `${FLAGS_PAGE_SIZE}`,
+        offset: `${flags.length}`,
+      });
+      if (filter) {
+        params.set("filter", filter);
+      }
+      const pageURL = `${BASE_URL}/v1/flags?${params.toString()}`;
+      const response = await fetch(pageURL, { cache: "no-cache" });
+      const list: FeatureFlagListDTO = await response.json();
+      flags.push(...list.flags);
+      if (list.flags.length === 0 || flags.length >= list.total) {
+        return flags;
+      }
+    }
+  } catch (error) {
+    console.error("Error in getFeatureFlags:", error);
+    throw error;
+  }
 }
 
 export function createFeatureFlag(
@@ -43,10 +43,18 @@ export interface ProgressiveRolloutBand {
 
 export interface FeatureFlagDTO extends commonApiDto {
   id: string;
+  revision?: number;
 }
 
 export type NewFeatureFlagDTO = commonApiDto;
 
+export interface FeatureFlagListDTO {
+  flags: FeatureFlagDTO[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
 export interface FeatureFlagStatusUpdateDTO {
   disable: boolean;
 }
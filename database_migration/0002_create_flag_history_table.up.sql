@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS flag_history
+(
+    id              UUID      NOT NULL PRIMARY KEY,
+    feature_flag_id UUID      NOT NULL,
+    version_number  INTEGER   NOT NULL,
+    action          TEXT      NOT NULL CHECK (action IN ('created', 'updated', 'deleted')),
+    snapshot        JSONB     NOT NULL,
+    created_date    TIMESTAMP NOT NULL,
+    UNIQUE (feature_flag_id, version_number)
+);
+
+CREATE INDEX idx_flag_history_feature_flag_id ON flag_history (feature_flag_id);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS flag_history_immutable ON flag_history;
+DROP FUNCTION IF EXISTS prevent_flag_history_modification();
@@ -0,0 +1,13 @@
+-- flag_history is an audit trail, once a version is written it should never change.
+CREATE OR REPLACE FUNCTION prevent_flag_history_modification() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'flag_history is append-only, % is not allowed', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER flag_history_immutable
+    BEFORE UPDATE OR DELETE
+    ON flag_history
+    FOR EACH ROW
+EXECUTE FUNCTION prevent_flag_history_modification();
@@ -0,0 +1 @@
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id             UUID      NOT NULL PRIMARY KEY,
+    name           TEXT      NOT NULL,
+    prefix         TEXT      NOT NULL,
+    hashed_key     TEXT      NOT NULL UNIQUE,
+    scopes         TEXT      NOT NULL,
+    created_date   TIMESTAMP NOT NULL,
+    created_by     TEXT      NOT NULL,
+    expires_at     TIMESTAMP,
+    last_used_date TIMESTAMP
+);
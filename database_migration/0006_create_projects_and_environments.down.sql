@@ -0,0 +1,7 @@
+ALTER TABLE feature_flags DROP CONSTRAINT IF EXISTS feature_flags_project_environment_name_key;
+ALTER TABLE feature_flags DROP CONSTRAINT IF EXISTS feature_flags_environment_fkey;
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS environment;
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS project;
+ALTER TABLE feature_flags ADD CONSTRAINT feature_flags_name_key UNIQUE (name);
+DROP TABLE IF EXISTS environments;
+DROP TABLE IF EXISTS projects;
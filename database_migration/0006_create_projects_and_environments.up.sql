@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS projects
+(
+    name         TEXT      NOT NULL PRIMARY KEY CHECK (name <> ''),
+    description  TEXT,
+    created_date TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS environments
+(
+    project      TEXT      NOT NULL REFERENCES projects (name),
+    name         TEXT      NOT NULL CHECK (name <> ''),
+    description  TEXT,
+    created_date TIMESTAMP NOT NULL,
+    PRIMARY KEY (project, name)
+);
+
+-- the existing flags are moved to the default project and environment
+INSERT INTO projects (name, created_date) VALUES ('default', NOW()) ON CONFLICT DO NOTHING;
+INSERT INTO environments (project, name, created_date) VALUES ('default', 'default', NOW()) ON CONFLICT DO NOTHING;
+
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS project TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS environment TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE feature_flags
+    ADD CONSTRAINT feature_flags_environment_fkey FOREIGN KEY (project, environment) REFERENCES environments (project, name);
+
+-- a flag name is now unique inside an environment
+ALTER TABLE feature_flags DROP CONSTRAINT IF EXISTS feature_flags_name_key;
+ALTER TABLE feature_flags
+    ADD CONSTRAINT feature_flags_project_environment_name_key UNIQUE (project, environment, name);
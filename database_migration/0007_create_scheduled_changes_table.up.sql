@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS scheduled_changes
+(
+    id                UUID      NOT NULL PRIMARY KEY,
+    feature_flag_id   UUID      NOT NULL REFERENCES feature_flags (id) ON DELETE CASCADE,
+    scheduled_date    TIMESTAMP NOT NULL,
+    changes           JSONB     NOT NULL,
+    status            TEXT      NOT NULL CHECK (status IN ('pending', 'applied', 'failed', 'cancelled')),
+    error             TEXT,
+    created_date      TIMESTAMP NOT NULL,
+    created_by        TEXT      NOT NULL,
+    last_updated_date TIMESTAMP NOT NULL,
+    applied_date      TIMESTAMP
+);
+
+CREATE INDEX idx_scheduled_changes_feature_flag_id ON scheduled_changes (feature_flag_id);
+CREATE INDEX idx_scheduled_changes_status_scheduled_date ON scheduled_changes (status, scheduled_date);
@@ -0,0 +1,2 @@
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS experimentation_end;
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS experimentation_start;
@@ -0,0 +1,2 @@
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS experimentation_start TIMESTAMP;
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS experimentation_end TIMESTAMP;
@@ -0,0 +1,32 @@
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS progressive_rollout_initial_variation TEXT;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS progressive_rollout_end_variation TEXT;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS progressive_rollout_initial_percentage FLOAT;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS progressive_rollout_end_percentage FLOAT;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS progressive_rollout_start_date TIMESTAMP;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS progressive_rollout_end_date TIMESTAMP;
+
+-- only the first and the last steps of a rollout can be kept, the intermediate steps are lost
+UPDATE rules r
+SET progressive_rollout_initial_variation  = first_step.variation,
+    progressive_rollout_initial_percentage = first_step.percentage,
+    progressive_rollout_start_date         = first_step.date,
+    progressive_rollout_end_variation      = last_step.variation,
+    progressive_rollout_end_percentage     = last_step.percentage,
+    progressive_rollout_end_date           = last_step.date
+FROM progressive_rollout_steps first_step,
+     progressive_rollout_steps last_step
+WHERE first_step.rule_id = r.id
+  AND first_step.step_index = (SELECT MIN(step_index) FROM progressive_rollout_steps WHERE rule_id = r.id)
+  AND last_step.rule_id = r.id
+  AND last_step.step_index = (SELECT MAX(step_index) FROM progressive_rollout_steps WHERE rule_id = r.id);
+
+ALTER TABLE rules ADD CONSTRAINT rule_return_something CHECK (percentages IS NOT NULL
+    OR variation_result IS NOT NULL
+    OR (progressive_rollout_initial_variation IS NOT NULL
+        AND progressive_rollout_end_variation IS NOT NULL
+        AND progressive_rollout_start_date IS NOT NULL
+        AND progressive_rollout_end_date IS NOT NULL
+        )
+    );
+
+DROP TABLE IF EXISTS progressive_rollout_steps;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS progressive_rollout_steps
+(
+    rule_id    UUID      NOT NULL REFERENCES rules (id) ON DELETE CASCADE,
+    step_index INTEGER   NOT NULL,
+    variation  TEXT      NOT NULL,
+    percentage FLOAT,
+    date       TIMESTAMP NOT NULL,
+    PRIMARY KEY (rule_id, step_index)
+);
+
+-- the existing rollouts are moved in the steps table, the initial step is the step 0 and the end step the step 1
+INSERT INTO progressive_rollout_steps (rule_id, step_index, variation, percentage, date)
+SELECT id, 0, progressive_rollout_initial_variation, progressive_rollout_initial_percentage, progressive_rollout_start_date
+FROM rules
+WHERE progressive_rollout_initial_variation IS NOT NULL
+  AND progressive_rollout_end_variation IS NOT NULL
+  AND progressive_rollout_start_date IS NOT NULL
+  AND progressive_rollout_end_date IS NOT NULL;
+
+INSERT INTO progressive_rollout_steps (rule_id, step_index, variation, percentage, date)
+SELECT id, 1, progressive_rollout_end_variation, progressive_rollout_end_percentage, progressive_rollout_end_date
+FROM rules
+WHERE progressive_rollout_initial_variation IS NOT NULL
+  AND progressive_rollout_end_variation IS NOT NULL
+  AND progressive_rollout_start_date IS NOT NULL
+  AND progressive_rollout_end_date IS NOT NULL;
+
+-- a rule with a progressive rollout has its variations in the steps table, the check cannot be done anymore
+ALTER TABLE rules DROP CONSTRAINT IF EXISTS rule_return_something;
+ALTER TABLE rules DROP COLUMN IF EXISTS progressive_rollout_initial_variation;
+ALTER TABLE rules DROP COLUMN IF EXISTS progressive_rollout_end_variation;
+ALTER TABLE rules DROP COLUMN IF EXISTS progressive_rollout_initial_percentage;
+ALTER TABLE rules DROP COLUMN IF EXISTS progressive_rollout_end_percentage;
+ALTER TABLE rules DROP COLUMN IF EXISTS progressive_rollout_start_date;
+ALTER TABLE rules DROP COLUMN IF EXISTS progressive_rollout_end_date;
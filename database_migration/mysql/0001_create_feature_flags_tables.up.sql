@@ -0,0 +1,126 @@
+-- MySQL/MariaDB version of the tables of database_migration, the ids are stored as text and the names use a
+-- binary collation to be case-sensitive like in postgres.
+CREATE TABLE IF NOT EXISTS projects
+(
+    name         VARCHAR(255) COLLATE utf8mb4_bin NOT NULL PRIMARY KEY CHECK (name <> ''),
+    description  TEXT,
+    created_date DATETIME(6)                      NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS environments
+(
+    project      VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    name         VARCHAR(255) COLLATE utf8mb4_bin NOT NULL CHECK (name <> ''),
+    description  TEXT,
+    created_date DATETIME(6)                      NOT NULL,
+    PRIMARY KEY (project, name),
+    CONSTRAINT environments_project_fkey FOREIGN KEY (project) REFERENCES projects (name)
+);
+
+INSERT IGNORE INTO projects (name, created_date) VALUES ('default', NOW(6));
+INSERT IGNORE INTO environments (project, name, created_date) VALUES ('default', 'default', NOW(6));
+
+CREATE TABLE IF NOT EXISTS feature_flags
+(
+    id                    CHAR(36)                                                NOT NULL PRIMARY KEY,
+    name                  VARCHAR(255) COLLATE utf8mb4_bin                        NOT NULL CHECK (name <> ''),
+    project               VARCHAR(255) COLLATE utf8mb4_bin                        NOT NULL DEFAULT 'default',
+    environment           VARCHAR(255) COLLATE utf8mb4_bin                        NOT NULL DEFAULT 'default',
+    description           TEXT,
+    variations            JSON                                                    NOT NULL,
+    type                  ENUM ('string', 'boolean', 'integer', 'double', 'json') NOT NULL,
+    bucketing_key         TEXT,
+    metadata              JSON,
+    track_events          BOOLEAN DEFAULT TRUE,
+    disable               BOOLEAN DEFAULT FALSE,
+    version               TEXT,
+    experimentation_start DATETIME(6),
+    experimentation_end   DATETIME(6),
+    created_date          DATETIME(6)                                             NOT NULL,
+    last_updated_date     DATETIME(6)                                             NOT NULL,
+    last_modified_by      TEXT                                                    NOT NULL,
+    revision              BIGINT                                                  NOT NULL DEFAULT 1,
+    CONSTRAINT feature_flags_environment_fkey FOREIGN KEY (project, environment) REFERENCES environments (project, name),
+    CONSTRAINT feature_flags_project_environment_name_key UNIQUE (project, environment, name),
+    INDEX idx_feature_flags_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS rules
+(
+    id               CHAR(36) NOT NULL PRIMARY KEY,
+    feature_flag_id  CHAR(36) NOT NULL,
+    name             TEXT,
+    query            TEXT,
+    variation_result TEXT,
+    percentages      JSON,
+    disable          BOOLEAN  NOT NULL DEFAULT FALSE,
+    is_default       BOOLEAN  NOT NULL DEFAULT FALSE,
+    order_index      INTEGER  NOT NULL,
+    CONSTRAINT rules_feature_flag_id_fkey FOREIGN KEY (feature_flag_id) REFERENCES feature_flags (id),
+    INDEX idx_rules_feature_flag_id (feature_flag_id)
+);
+
+CREATE TABLE IF NOT EXISTS progressive_rollout_steps
+(
+    rule_id    CHAR(36)    NOT NULL,
+    step_index INTEGER     NOT NULL,
+    variation  TEXT        NOT NULL,
+    percentage DOUBLE,
+    date       DATETIME(6) NOT NULL,
+    PRIMARY KEY (rule_id, step_index),
+    CONSTRAINT progressive_rollout_steps_rule_id_fkey FOREIGN KEY (rule_id) REFERENCES rules (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS flag_history
+(
+    id              CHAR(36)    NOT NULL PRIMARY KEY,
+    feature_flag_id CHAR(36)    NOT NULL,
+    version_number  INTEGER     NOT NULL,
+    action          VARCHAR(16) NOT NULL CHECK (action IN ('created', 'updated', 'deleted')),
+    snapshot        JSON        NOT NULL,
+    created_date    DATETIME(6) NOT NULL,
+    UNIQUE (feature_flag_id, version_number),
+    INDEX idx_flag_history_feature_flag_id (feature_flag_id)
+);
+
+-- flag_history is an audit trail, once a version is written it should never change.
+CREATE TRIGGER flag_history_immutable_update
+    BEFORE UPDATE
+    ON flag_history
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'flag_history is append-only, UPDATE is not allowed';
+
+CREATE TRIGGER flag_history_immutable_delete
+    BEFORE DELETE
+    ON flag_history
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'flag_history is append-only, DELETE is not allowed';
+
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id             CHAR(36)     NOT NULL PRIMARY KEY,
+    name           TEXT         NOT NULL,
+    prefix         TEXT         NOT NULL,
+    hashed_key     VARCHAR(255) NOT NULL UNIQUE,
+    scopes         TEXT         NOT NULL,
+    created_date   DATETIME(6)  NOT NULL,
+    created_by     TEXT         NOT NULL,
+    expires_at     DATETIME(6),
+    last_used_date DATETIME(6)
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_changes
+(
+    id                CHAR(36)    NOT NULL PRIMARY KEY,
+    feature_flag_id   CHAR(36)    NOT NULL,
+    scheduled_date    DATETIME(6) NOT NULL,
+    changes           JSON        NOT NULL,
+    status            VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'applied', 'failed', 'cancelled')),
+    error             TEXT,
+    created_date      DATETIME(6) NOT NULL,
+    created_by        TEXT        NOT NULL,
+    last_updated_date DATETIME(6) NOT NULL,
+    applied_date      DATETIME(6),
+    CONSTRAINT scheduled_changes_feature_flag_id_fkey
+        FOREIGN KEY (feature_flag_id) REFERENCES feature_flags (id) ON DELETE CASCADE,
+    INDEX idx_scheduled_changes_feature_flag_id (feature_flag_id),
+    INDEX idx_scheduled_changes_status_scheduled_date (status, scheduled_date)
+);
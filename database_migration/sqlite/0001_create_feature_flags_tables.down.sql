@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS scheduled_changes;
+DROP TABLE IF EXISTS api_keys;
+DROP TRIGGER IF EXISTS flag_history_immutable_delete;
+DROP TRIGGER IF EXISTS flag_history_immutable_update;
+DROP TABLE IF EXISTS flag_history;
+DROP TABLE IF EXISTS progressive_rollout_steps;
+DROP TABLE IF EXISTS rules;
+DROP TABLE IF EXISTS feature_flags;
+DROP TABLE IF EXISTS environments;
+DROP TABLE IF EXISTS projects;
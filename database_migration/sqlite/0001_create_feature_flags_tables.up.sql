@@ -0,0 +1,128 @@
+-- SQLite version of the tables of database_migration, the JSON values are stored as text and the
+-- enums are replaced by CHECK constraints. Apply it with migrate like the other storages.
+CREATE TABLE IF NOT EXISTS projects
+(
+    name         TEXT      NOT NULL PRIMARY KEY CHECK (name <> ''),
+    description  TEXT,
+    created_date TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS environments
+(
+    project      TEXT      NOT NULL REFERENCES projects (name),
+    name         TEXT      NOT NULL CHECK (name <> ''),
+    description  TEXT,
+    created_date TIMESTAMP NOT NULL,
+    PRIMARY KEY (project, name)
+);
+
+INSERT OR IGNORE INTO projects (name, created_date) VALUES ('default', CURRENT_TIMESTAMP);
+INSERT OR IGNORE INTO environments (project, name, created_date) VALUES ('default', 'default', CURRENT_TIMESTAMP);
+
+CREATE TABLE IF NOT EXISTS feature_flags
+(
+    id                    TEXT      NOT NULL PRIMARY KEY,
+    name                  TEXT      NOT NULL CHECK (name <> ''),
+    project               TEXT      NOT NULL DEFAULT 'default',
+    environment           TEXT      NOT NULL DEFAULT 'default',
+    description           TEXT,
+    variations            TEXT      NOT NULL,
+    type                  TEXT      NOT NULL CHECK (type IN ('string', 'boolean', 'integer', 'double', 'json')),
+    bucketing_key         TEXT,
+    metadata              TEXT,
+    track_events          BOOLEAN DEFAULT TRUE,
+    disable               BOOLEAN DEFAULT FALSE,
+    version               TEXT,
+    experimentation_start TIMESTAMP,
+    experimentation_end   TIMESTAMP,
+    created_date          TIMESTAMP NOT NULL,
+    last_updated_date     TIMESTAMP NOT NULL,
+    last_modified_by      TEXT      NOT NULL,
+    revision              INTEGER   NOT NULL DEFAULT 1,
+    FOREIGN KEY (project, environment) REFERENCES environments (project, name),
+    UNIQUE (project, environment, name)
+);
+
+CREATE TABLE IF NOT EXISTS rules
+(
+    id               TEXT    NOT NULL PRIMARY KEY,
+    feature_flag_id  TEXT    NOT NULL REFERENCES feature_flags (id),
+    name             TEXT,
+    query            TEXT,
+    variation_result TEXT,
+    percentages      TEXT,
+    disable          BOOLEAN NOT NULL DEFAULT FALSE,
+    is_default       BOOLEAN NOT NULL DEFAULT FALSE,
+    order_index      INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_feature_flags_name ON feature_flags (name);
+CREATE INDEX IF NOT EXISTS idx_rules_feature_flag_id ON rules (feature_flag_id);
+
+CREATE TABLE IF NOT EXISTS progressive_rollout_steps
+(
+    rule_id    TEXT      NOT NULL REFERENCES rules (id) ON DELETE CASCADE,
+    step_index INTEGER   NOT NULL,
+    variation  TEXT      NOT NULL,
+    percentage FLOAT,
+    date       TIMESTAMP NOT NULL,
+    PRIMARY KEY (rule_id, step_index)
+);
+
+CREATE TABLE IF NOT EXISTS flag_history
+(
+    id              TEXT      NOT NULL PRIMARY KEY,
+    feature_flag_id TEXT      NOT NULL,
+    version_number  INTEGER   NOT NULL,
+    action          TEXT      NOT NULL CHECK (action IN ('created', 'updated', 'deleted')),
+    snapshot        TEXT      NOT NULL,
+    created_date    TIMESTAMP NOT NULL,
+    UNIQUE (feature_flag_id, version_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_flag_history_feature_flag_id ON flag_history (feature_flag_id);
+
+-- flag_history is an audit trail, once a version is written it should never change.
+CREATE TRIGGER IF NOT EXISTS flag_history_immutable_update
+    BEFORE UPDATE
+    ON flag_history
+BEGIN
+    SELECT RAISE(ABORT, 'flag_history is append-only, UPDATE is not allowed');
+END;
+
+CREATE TRIGGER IF NOT EXISTS flag_history_immutable_delete
+    BEFORE DELETE
+    ON flag_history
+BEGIN
+    SELECT RAISE(ABORT, 'flag_history is append-only, DELETE is not allowed');
+END;
+
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id             TEXT      NOT NULL PRIMARY KEY,
+    name           TEXT      NOT NULL,
+    prefix         TEXT      NOT NULL,
+    hashed_key     TEXT      NOT NULL UNIQUE,
+    scopes         TEXT      NOT NULL,
+    created_date   TIMESTAMP NOT NULL,
+    created_by     TEXT      NOT NULL,
+    expires_at     TIMESTAMP,
+    last_used_date TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_changes
+(
+    id                TEXT      NOT NULL PRIMARY KEY,
+    feature_flag_id   TEXT      NOT NULL REFERENCES feature_flags (id) ON DELETE CASCADE,
+    scheduled_date    TIMESTAMP NOT NULL,
+    changes           TEXT      NOT NULL,
+    status            TEXT      NOT NULL CHECK (status IN ('pending', 'applied', 'failed', 'cancelled')),
+    error             TEXT,
+    created_date      TIMESTAMP NOT NULL,
+    created_by        TEXT      NOT NULL,
+    last_updated_date TIMESTAMP NOT NULL,
+    applied_date      TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_changes_feature_flag_id ON scheduled_changes (feature_flag_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_changes_status_scheduled_date ON scheduled_changes (status, scheduled_date);
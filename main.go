@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/go-feature-flag/flag-management/server/cmd"
 	"log"
+	"os"
 )
 
 // version, releaseDate are override by the makefile during the build.
@@ -20,9 +21,7 @@ var version = "localdev"
 // @in header
 // @name Authorization
 func main() {
-	command, err := cmd.NewGOFeatureFlagManagementAPICommand(cmd.APICommandOptions{})
-	if err != nil {
+	if err := cmd.Execute(os.Args[1:], cmd.APICommandOptions{}); err != nil {
 		log.Fatal(err)
 	}
-	command.Run()
 }
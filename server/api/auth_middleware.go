@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/model"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	errAuthNotConfigured   = errors.New("no authentication method configured")
+	errAPIKeyNotConfigured = errors.New("API keys are not supported by the storage")
+)
+
+// authMiddleware verifies the bearer token (a JWT or an API key) of the requests to the /v1 APIs and stores
+// the principal of the caller on the context.
+// In development mode the authentication is disabled.
+func (s *Server) authMiddleware() echo.MiddlewareFunc {
+	return echojwt.WithConfig(echojwt.Config{
+		Skipper: func(c echo.Context) bool {
+			return s.configuration.Mode == config.Development
+		},
+		ParseTokenFunc: func(c echo.Context, token string) (interface{}, error) {
+			if auth.IsAPIKey(token) {
+				if s.apiKeyValidator == nil {
+					return nil, errAPIKeyNotConfigured
+				}
+				return s.apiKeyValidator.Validate(c.Request().Context(), token)
+			}
+			if s.jwtValidator == nil {
+				return nil, errAuthNotConfigured
+			}
+			return s.jwtValidator.Validate(c.Request().Context(), token)
+		},
+		SuccessHandler: func(c echo.Context) {
+			switch user := c.Get("user").(type) {
+			case *jwt.Token:
+				auth.SetPrincipal(c, s.jwtValidator.Principal(user))
+			case model.APIKey:
+				auth.SetPrincipal(c, auth.APIKeyPrincipal(user))
+			}
+		},
+		ErrorHandler: func(c echo.Context, err error) error {
+			var extractionErr *echojwt.TokenExtractionError
+			if errors.As(err, &extractionErr) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed token").SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+		},
+	})
+}
+
+// authorize checks that the role of the caller has the permission needed by the route.
+// In development mode the authorization is disabled.
+func (s *Server) authorize(permission auth.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if s.configuration.Mode == config.Development {
+				return next(c)
+			}
+			principal := auth.PrincipalFromContext(c)
+			if !principal.Can(permission) {
+				return echo.NewHTTPError(http.StatusForbidden,
+					fmt.Sprintf("you don't have the permission %s", permission))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-feature-flag/flag-management/server/api"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	handler2 "github.com/go-feature-flag/flag-management/server/handler"
+	"github.com/go-feature-flag/flag-management/server/model"
+	testutils "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProductionServer(t *testing.T, auth config.AuthConfiguration) *api.Server {
+	dbImpl, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	dbImpl.SetFlags(testutils.DefaultInMemoryFlags())
+	h, err := handler2.InitHandlers(dbImpl)
+	require.NoError(t, err)
+	apiServer, err := api.New(&config.Configuration{Mode: config.Production, Auth: auth}, h)
+	require.NoError(t, err)
+	return apiServer
+}
+
+func hmacToken(t *testing.T, secret string, expiration time.Time) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "john.doe@gofeatureflag.org",
+		"aud": "goff-management",
+		"exp": expiration.Unix(),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	configured := newProductionServer(t, config.AuthConfiguration{
+		HMACSecret: "my-secret",
+		Audience:   []string{"goff-management"},
+	})
+	notConfigured := newProductionServer(t, config.AuthConfiguration{})
+
+	tests := []struct {
+		name          string
+		server        *api.Server
+		path          string
+		authorization string
+		wantCode      int
+	}{
+		{
+			name:          "valid token",
+			server:        configured,
+			path:          "/v1/flags",
+			authorization: "Bearer " + hmacToken(t, "my-secret", time.Now().Add(time.Hour)),
+			wantCode:      http.StatusOK,
+		},
+		{
+			name:     "missing token",
+			server:   configured,
+			path:     "/v1/flags",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:          "token signed with another secret",
+			server:        configured,
+			path:          "/v1/flags",
+			authorization: "Bearer " + hmacToken(t, "another-secret", time.Now().Add(time.Hour)),
+			wantCode:      http.StatusUnauthorized,
+		},
+		{
+			name:          "expired token",
+			server:        configured,
+			path:          "/v1/flags",
+			authorization: "Bearer " + hmacToken(t, "my-secret", time.Now().Add(-time.Hour)),
+			wantCode:      http.StatusUnauthorized,
+		},
+		{
+			name:          "no authentication configured",
+			server:        notConfigured,
+			path:          "/v1/flags",
+			authorization: "Bearer " + hmacToken(t, "my-secret", time.Now().Add(time.Hour)),
+			wantCode:      http.StatusUnauthorized,
+		},
+		{
+			name:     "health does not require a token",
+			server:   notConfigured,
+			path:     "/health",
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			tt.server.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestNewServerWithInvalidAuthConfiguration(t *testing.T) {
+	dbImpl, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	h, err := handler2.InitHandlers(dbImpl)
+	require.NoError(t, err)
+	_, err = api.New(&config.Configuration{
+		Mode: config.Production,
+		Auth: config.AuthConfiguration{PublicKeyFile: "/not/existing/file.pem"},
+	}, h)
+	assert.Error(t, err)
+}
+
+func TestAuthMiddlewareStampsPrincipal(t *testing.T) {
+	tests := []struct {
+		name               string
+		nameClaim          string
+		claims             jwt.MapClaims
+		wantLastModifiedBy string
+	}{
+		{
+			name:               "default name claim",
+			claims:             jwt.MapClaims{"sub": "1234", "email": "john.doe@gofeatureflag.org", "name": "John Doe"},
+			wantLastModifiedBy: "John Doe",
+		},
+		{
+			name:               "configured name claim",
+			nameClaim:          "preferred_username",
+			claims:             jwt.MapClaims{"sub": "1234", "name": "John Doe", "preferred_username": "jdoe"},
+			wantLastModifiedBy: "jdoe",
+		},
+		{
+			name:               "fallback on email",
+			claims:             jwt.MapClaims{"sub": "1234", "email": "john.doe@gofeatureflag.org"},
+			wantLastModifiedBy: "john.doe@gofeatureflag.org",
+		},
+		{
+			name:               "fallback on subject",
+			claims:             jwt.MapClaims{"sub": "1234"},
+			wantLastModifiedBy: "1234",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiServer := newProductionServer(t, config.AuthConfiguration{HMACSecret: "my-secret", NameClaim: tt.nameClaim})
+			tt.claims["exp"] = time.Now().Add(time.Hour).Unix()
+			tt.claims["roles"] = "editor"
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte("my-secret"))
+			require.NoError(t, err)
+
+			body := `{"name":"new-flag","LastModifiedBy":"someone-else","type":"string","variations":{"variation1":"A"},"defaultRule":{"variation":"variation1"}}`
+			req := httptest.NewRequest(http.MethodPost, "/v1/flags", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			apiServer.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusCreated, rec.Code)
+
+			var flag model.FeatureFlag
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &flag))
+			assert.Equal(t, tt.wantLastModifiedBy, flag.LastModifiedBy)
+		})
+	}
+}
+
+func TestAuthMiddlewareAPIKeys(t *testing.T) {
+	dbImpl, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	dbImpl.SetFlags(testutils.DefaultInMemoryFlags())
+	expired := time.Now().Add(-time.Hour)
+	apiKeys := []struct {
+		key    string
+		apiKey model.APIKey
+	}{
+		{key: "goff_read", apiKey: model.APIKey{ID: "1", Name: "read", Scopes: []model.APIKeyScope{model.APIKeyScopeRead}}},
+		{key: "goff_write", apiKey: model.APIKey{ID: "2", Name: "write", Scopes: []model.APIKeyScope{model.APIKeyScopeWrite}}},
+		{key: "goff_delete", apiKey: model.APIKey{ID: "4", Name: "delete", Scopes: []model.APIKeyScope{model.APIKeyScopeWrite, model.APIKeyScopeDelete}}},
+		{key: "goff_expired", apiKey: model.APIKey{ID: "3", Name: "expired", Scopes: []model.APIKeyScope{model.APIKeyScopeWrite}, ExpiresAt: &expired}},
+	}
+	for _, k := range apiKeys {
+		k.apiKey.HashedKey = auth.HashAPIKey(k.key)
+		_, daoErr := dbImpl.CreateAPIKey(context.Background(), k.apiKey)
+		require.Nil(t, daoErr)
+	}
+	h, err := handler2.InitHandlers(dbImpl)
+	require.NoError(t, err)
+
+	// no token authentication configured, the API keys are still accepted
+	apiServer, err := api.New(&config.Configuration{Mode: config.Production}, h)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		apiKey   string
+		wantCode int
+	}{
+		{
+			name:     "read scope can list the flags",
+			method:   http.MethodGet,
+			path:     "/v1/flags",
+			apiKey:   "goff_read",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "read scope cannot update a flag",
+			method:   http.MethodPatch,
+			path:     "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/status",
+			body:     `{"disable":true}`,
+			apiKey:   "goff_read",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "write scope can update a flag",
+			method:   http.MethodPatch,
+			path:     "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/status",
+			body:     `{"disable":true}`,
+			apiKey:   "goff_write",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "write scope can list the flags",
+			method:   http.MethodGet,
+			path:     "/v1/flags",
+			apiKey:   "goff_write",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "write scope cannot delete a flag",
+			method:   http.MethodDelete,
+			path:     "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a111",
+			apiKey:   "goff_write",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "delete scope can delete a flag",
+			method:   http.MethodDelete,
+			path:     "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a111",
+			apiKey:   "goff_delete",
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "delete scope cannot manage the API keys",
+			method:   http.MethodGet,
+			path:     "/v1/apikeys",
+			apiKey:   "goff_delete",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "API keys cannot manage the API keys",
+			method:   http.MethodGet,
+			path:     "/v1/apikeys",
+			apiKey:   "goff_write",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "expired API key",
+			method:   http.MethodGet,
+			path:     "/v1/flags",
+			apiKey:   "goff_expired",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unknown API key",
+			method:   http.MethodGet,
+			path:     "/v1/flags",
+			apiKey:   "goff_unknown",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "JWT without token authentication configured",
+			method:   http.MethodGet,
+			path:     "/v1/flags",
+			apiKey:   hmacToken(t, "my-secret", time.Now().Add(time.Hour)),
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+tt.apiKey)
+			rec := httptest.NewRecorder()
+			apiServer.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+
+	t.Run("the API key is stamped as last modifier", func(t *testing.T) {
+		flag, daoErr := dbImpl.GetFlagByID(context.Background(), "926214f3-80c1-46e6-a913-b2d40b92a932")
+		require.Nil(t, daoErr)
+		assert.Equal(t, "apikey:write", flag.LastModifiedBy)
+	})
+}
+
+func TestAuthorization(t *testing.T) {
+	token := func(role string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":    "john.doe@gofeatureflag.org",
+			"groups": []string{"goff", role},
+			"exp":    time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("my-secret"))
+		require.NoError(t, err)
+		return token
+	}
+	flagID := "926214f3-80c1-46e6-a913-b2d40b92a932"
+	routes := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		// wantCodes is the expected status code for the viewer, editor and admin roles
+		wantCodes [3]int
+	}{
+		{
+			name:      "list flags",
+			method:    http.MethodGet,
+			path:      "/v1/flags",
+			wantCodes: [3]int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "get flag",
+			method:    http.MethodGet,
+			path:      "/v1/flags/" + flagID,
+			wantCodes: [3]int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "get flag versions",
+			method:    http.MethodGet,
+			path:      "/v1/flags/" + flagID + "/versions",
+			wantCodes: [3]int{http.StatusNotFound, http.StatusNotFound, http.StatusNotFound},
+		},
+		{
+			name:      "create flag",
+			method:    http.MethodPost,
+			path:      "/v1/flags",
+			body:      `{"name":"new-flag","type":"string","variations":{"A":"A"},"defaultRule":{"variation":"A"}}`,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusCreated, http.StatusCreated},
+		},
+		{
+			name:      "update flag",
+			method:    http.MethodPut,
+			path:      "/v1/flags/" + flagID,
+			body:      `{"name":"flag1","type":"string","variations":{"A":"A"},"defaultRule":{"variation":"A"}}`,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "toggle flag",
+			method:    http.MethodPatch,
+			path:      "/v1/flags/" + flagID + "/status",
+			body:      `{"disable":true}`,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "restore flag",
+			method:    http.MethodPost,
+			path:      "/v1/flags/" + flagID + "/versions/unknown/restore",
+			wantCodes: [3]int{http.StatusForbidden, http.StatusNotFound, http.StatusNotFound},
+		},
+		{
+			name:      "evaluate flag",
+			method:    http.MethodPost,
+			path:      "/v1/flags/" + flagID + "/evaluate",
+			body:      `{"targetingKey":"user-1"}`,
+			wantCodes: [3]int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "simulate flags",
+			method:    http.MethodPost,
+			path:      "/v1/simulate",
+			body:      `[{"targetingKey":"user-1"}]`,
+			wantCodes: [3]int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "list API keys",
+			method:    http.MethodGet,
+			path:      "/v1/apikeys",
+			wantCodes: [3]int{http.StatusForbidden, http.StatusForbidden, http.StatusOK},
+		},
+		{
+			name:      "list scheduled changes",
+			method:    http.MethodGet,
+			path:      "/v1/schedules",
+			wantCodes: [3]int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "schedule a flag change",
+			method:    http.MethodPost,
+			path:      "/v1/flags/" + flagID + "/schedules",
+			body:      `{"scheduledDate":"2099-01-01T00:00:00Z","changes":{"disable":true}}`,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusCreated, http.StatusCreated},
+		},
+		{
+			name:      "list projects",
+			method:    http.MethodGet,
+			path:      "/v1/projects",
+			wantCodes: [3]int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "create project",
+			method:    http.MethodPost,
+			path:      "/v1/projects",
+			body:      `{"name":"checkout"}`,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusForbidden, http.StatusCreated},
+		},
+		{
+			name:      "list flags of an environment",
+			method:    http.MethodGet,
+			path:      "/v1/projects/default/environments/default/flags",
+			wantCodes: [3]int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:      "create flag in an environment",
+			method:    http.MethodPost,
+			path:      "/v1/projects/default/environments/default/flags",
+			body:      `{"name":"scoped-flag","type":"string","variations":{"A":"A"},"defaultRule":{"variation":"A"}}`,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusCreated, http.StatusCreated},
+		},
+		{
+			name:      "create flag in an unknown environment",
+			method:    http.MethodPost,
+			path:      "/v1/projects/unknown/environments/unknown/flags",
+			body:      `{"name":"scoped-flag","type":"string","variations":{"A":"A"},"defaultRule":{"variation":"A"}}`,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusNotFound, http.StatusNotFound},
+		},
+		{
+			name:      "delete flag in an unknown environment",
+			method:    http.MethodDelete,
+			path:      "/v1/projects/unknown/environments/unknown/flags/" + flagID,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusForbidden, http.StatusNotFound},
+		},
+		{
+			name:      "delete flag",
+			method:    http.MethodDelete,
+			path:      "/v1/flags/" + flagID,
+			wantCodes: [3]int{http.StatusForbidden, http.StatusForbidden, http.StatusNoContent},
+		},
+	}
+
+	for i, role := range []string{"goff-viewers", "goff-editors", "goff-admins"} {
+		apiServer := newProductionServer(t, config.AuthConfiguration{
+			HMACSecret:  "my-secret",
+			RoleClaim:   "groups",
+			RoleMapping: map[string]string{"goff-editors": "editor", "goff-admins": "admin"},
+		})
+		for _, route := range routes {
+			t.Run(role+" "+route.name, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+token(role))
+				rec := httptest.NewRecorder()
+				apiServer.ServeHTTP(rec, req)
+				assert.Equal(t, route.wantCodes[i], rec.Code, rec.Body.String())
+				if route.wantCodes[i] == http.StatusForbidden {
+					assert.Contains(t, rec.Body.String(), `"code":403`)
+				}
+			})
+		}
+	}
+}
@@ -5,21 +5,29 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/go-feature-flag/flag-management/server/model"
 	"github.com/labstack/echo/v4"
 )
 
 type CustomErr struct {
 	Code         int    `json:"code"`
 	ErrorDetails string `json:"errorDetails"`
+	// Errors contains all the invalid fields when a flag is rejected by the validation
+	Errors []model.FieldError `json:"errors,omitempty"`
 }
 
 func customHTTPErrorHandler(err error, c echo.Context) {
 	var he *echo.HTTPError
 	if errors.As(err, &he) {
-		_ = c.JSON(he.Code, CustomErr{
+		customErr := CustomErr{
 			Code:         he.Code,
 			ErrorDetails: fmt.Sprintf("%v", he.Message),
-		})
+		}
+		var validationErrors model.ValidationErrors
+		if messageErr, ok := he.Message.(error); ok && errors.As(messageErr, &validationErrors) {
+			customErr.Errors = validationErrors
+		}
+		_ = c.JSON(he.Code, customErr)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-feature-flag/flag-management/server/model"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -37,4 +38,26 @@ func Test_customHTTPErrorHandler(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 		assert.JSONEq(t, `{"errorDetails":"not found","code":404}`, rec.Body.String())
 	})
+	t.Run("should return the invalid fields if the error is a validation error", func(t *testing.T) {
+		e := echo.New()
+		e.HTTPErrorHandler = customHTTPErrorHandler
+		e.GET("/", func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusBadRequest, model.ValidationErrors{
+				{Field: "name", Message: "flag name is required"},
+				{Field: "targeting[0].variation", Message: "variation C does not exist"},
+			})
+		})
+		req := httptest.NewRequest("GET", "/", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{
+			"errorDetails":"name: flag name is required\ntargeting[0].variation: variation C does not exist",
+			"code":400,
+			"errors":[
+				{"field":"name","message":"flag name is required"},
+				{"field":"targeting[0].variation","message":"variation C does not exist"}
+			]}`, rec.Body.String())
+	})
 }
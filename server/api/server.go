@@ -1,15 +1,18 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
 	"github.com/go-feature-flag/flag-management/server/config"
 	"github.com/go-feature-flag/flag-management/server/handler"
 	"net/http"
 
 	_ "github.com/go-feature-flag/flag-management/server/docs"
-	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"go.uber.org/zap"
 )
 
 // New creates a new instance of the API server
@@ -20,20 +23,43 @@ func New(configuration *config.Configuration, handlers handler.Handlers) (*Serve
 	if handlers.FlagAPIHandler == nil {
 		return nil, handler.ErrMissingFlagAPIHandler
 	}
-	return &Server{
-		flagHandlers:   handlers.FlagAPIHandler,
-		healthHandlers: handlers.HealthHandler,
-		apiEcho:        echo.New(),
-		configuration:  configuration,
-	}, nil
+	s := &Server{
+		flagHandlers:            handlers.FlagAPIHandler,
+		healthHandlers:          handlers.HealthHandler,
+		projectHandlers:         handlers.ProjectHandler,
+		apiKeyHandlers:          handlers.APIKeyHandler,
+		apiKeyValidator:         handlers.APIKeyValidator,
+		scheduledChangeHandlers: handlers.ScheduledChangeHandler,
+		apiEcho:                 echo.New(),
+		configuration:           configuration,
+	}
+
+	if configuration.Mode != config.Development {
+		if !configuration.Auth.IsConfigured() {
+			zap.L().Warn("no token authentication configured, only the API keys are accepted to call the /v1 APIs")
+			return s, nil
+		}
+		validator, err := auth.NewJWTValidator(context.Background(), configuration.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("impossible to initialize authentication: %w", err)
+		}
+		s.jwtValidator = validator
+	}
+	return s, nil
 }
 
 // Server is the struct that represents the API server
 type Server struct {
-	flagHandlers   *handler.FlagAPIHandler
-	healthHandlers *handler.HealthHandler
-	apiEcho        *echo.Echo
-	configuration  *config.Configuration
+	flagHandlers    *handler.FlagAPIHandler
+	healthHandlers  *handler.HealthHandler
+	projectHandlers *handler.ProjectHandler
+	apiKeyHandlers  *handler.APIKeyHandler
+	apiEcho         *echo.Echo
+	configuration   *config.Configuration
+	jwtValidator    *auth.JWTValidator
+	apiKeyValidator *auth.APIKeyValidator
+
+	scheduledChangeHandlers *handler.ScheduledChangeHandler
 }
 
 func (s *Server) configure() {
@@ -43,7 +69,9 @@ func (s *Server) configure() {
 	s.apiEcho.HTTPErrorHandler = customHTTPErrorHandler
 
 	// Middlewares
-	s.apiEcho.Use(middleware.CORSWithConfig(middleware.DefaultCORSConfig))
+	corsConfig := middleware.DefaultCORSConfig
+	corsConfig.ExposeHeaders = []string{"ETag"}
+	s.apiEcho.Use(middleware.CORSWithConfig(corsConfig))
 
 	// init health routes
 	s.apiEcho.GET("/health", s.healthHandlers.Health)
@@ -53,18 +81,73 @@ func (s *Server) configure() {
 
 	// init API routes
 	groupV1 := s.apiEcho.Group("/v1")
-	groupV1.Use(echojwt.WithConfig(echojwt.Config{
-		Skipper: func(c echo.Context) bool {
-			return s.configuration.Mode == config.Development
-		},
-		SigningKey: []byte("JKapFhI4Srnos8Exdxm7IOQAt7fjgJDU"),
-	}))
-	groupV1.GET("/flags", s.flagHandlers.GetAllFeatureFlags)
-	groupV1.GET("/flags/:id", s.flagHandlers.GetFeatureFlagByID)
-	groupV1.POST("/flags", s.flagHandlers.CreateNewFlag)
-	groupV1.PUT("/flags/:id", s.flagHandlers.UpdateFlagByID)
-	groupV1.DELETE("/flags/:id", s.flagHandlers.DeleteFlagByID)
-	groupV1.PATCH("/flags/:id/status", s.flagHandlers.UpdateFeatureFlagStatus)
+	groupV1.Use(s.authMiddleware())
+	read := s.authorize(auth.PermissionReadFlags)
+	write := s.authorize(auth.PermissionWriteFlags)
+	groupV1.GET("/flags", s.flagHandlers.GetAllFeatureFlags, read)
+	groupV1.GET("/flags/:id", s.flagHandlers.GetFeatureFlagByID, read)
+	groupV1.POST("/flags", s.flagHandlers.CreateNewFlag, write)
+	groupV1.PUT("/flags/:id", s.flagHandlers.UpdateFlagByID, write)
+	groupV1.DELETE("/flags/:id", s.flagHandlers.DeleteFlagByID, s.authorize(auth.PermissionDeleteFlags))
+	groupV1.PATCH("/flags/:id/status", s.flagHandlers.UpdateFeatureFlagStatus, s.authorize(auth.PermissionToggleFlags))
+	groupV1.GET("/flags/:id/versions", s.flagHandlers.GetFlagVersions, read)
+	groupV1.POST("/flags/:id/versions/:versionId/restore", s.flagHandlers.RestoreFlagVersion, write)
+	groupV1.POST("/flags/:id/evaluate", s.flagHandlers.EvaluateFlag, read)
+	groupV1.GET("/export", s.flagHandlers.ExportFlags, read)
+	groupV1.POST("/import", s.flagHandlers.ImportFlags, write)
+	groupV1.POST("/simulate", s.flagHandlers.SimulateFlags, read)
+
+	// flags scoped by project and environment, the permission is checked before the environment so the callers
+	// without permission cannot discover which environments exist
+	scoped := groupV1.Group("/projects/:project/environments/:environment/flags")
+	env := s.flagHandlers.RequireEnvironment
+	scoped.GET("", s.flagHandlers.GetEnvironmentFeatureFlags, read, env)
+	scoped.GET("/:id", s.flagHandlers.GetEnvironmentFeatureFlagByID, read, env)
+	scoped.POST("", s.flagHandlers.CreateEnvironmentFlag, write, env)
+	scoped.PUT("/:id", s.flagHandlers.UpdateEnvironmentFlagByID, write, env)
+	scoped.DELETE("/:id", s.flagHandlers.DeleteEnvironmentFlagByID, s.authorize(auth.PermissionDeleteFlags), env)
+	scoped.PATCH("/:id/status", s.flagHandlers.UpdateEnvironmentFeatureFlagStatus,
+		s.authorize(auth.PermissionToggleFlags), env)
+	scoped.GET("/:id/versions", s.flagHandlers.GetEnvironmentFlagVersions, read, env)
+	scoped.POST("/:id/versions/:versionId/restore", s.flagHandlers.RestoreEnvironmentFlagVersion, write, env)
+	scoped.POST("/:id/evaluate", s.flagHandlers.EvaluateEnvironmentFlag, read, env)
+
+	s.configureOptionalRoutes(groupV1, scoped)
+}
+
+// configureOptionalRoutes registers the routes only available when the storage supports them
+func (s *Server) configureOptionalRoutes(groupV1 *echo.Group, scoped *echo.Group) {
+	read := s.authorize(auth.PermissionReadFlags)
+	write := s.authorize(auth.PermissionWriteFlags)
+	if s.projectHandlers != nil {
+		manageProjects := s.authorize(auth.PermissionManageProjects)
+		groupV1.GET("/projects", s.projectHandlers.GetProjects, read)
+		groupV1.POST("/projects", s.projectHandlers.CreateProject, manageProjects)
+		groupV1.GET("/projects/:project", s.projectHandlers.GetProjectByName, read)
+		groupV1.DELETE("/projects/:project", s.projectHandlers.DeleteProjectByName, manageProjects)
+		groupV1.GET("/projects/:project/environments", s.projectHandlers.GetEnvironments, read)
+		groupV1.POST("/projects/:project/environments", s.projectHandlers.CreateEnvironment, manageProjects)
+		groupV1.GET("/projects/:project/environments/:environment", s.projectHandlers.GetEnvironmentByName, read)
+		groupV1.DELETE("/projects/:project/environments/:environment", s.projectHandlers.DeleteEnvironmentByName,
+			manageProjects)
+	}
+	if s.apiKeyHandlers != nil {
+		manageAPIKeys := s.authorize(auth.PermissionManageAPIKeys)
+		groupV1.GET("/apikeys", s.apiKeyHandlers.GetAPIKeys, manageAPIKeys)
+		groupV1.POST("/apikeys", s.apiKeyHandlers.CreateAPIKey, manageAPIKeys)
+		groupV1.DELETE("/apikeys/:id", s.apiKeyHandlers.DeleteAPIKeyByID, manageAPIKeys)
+	}
+	if s.scheduledChangeHandlers != nil {
+		env := s.flagHandlers.RequireEnvironment
+		groupV1.GET("/flags/:id/schedules", s.scheduledChangeHandlers.GetFlagScheduledChanges, read)
+		groupV1.POST("/flags/:id/schedules", s.scheduledChangeHandlers.CreateFlagScheduledChange, write)
+		groupV1.GET("/schedules", s.scheduledChangeHandlers.GetScheduledChanges, read)
+		groupV1.GET("/schedules/:scheduleId", s.scheduledChangeHandlers.GetScheduledChangeByID, read)
+		groupV1.PUT("/schedules/:scheduleId", s.scheduledChangeHandlers.UpdateScheduledChangeByID, write)
+		groupV1.DELETE("/schedules/:scheduleId", s.scheduledChangeHandlers.CancelScheduledChangeByID, write)
+		scoped.GET("/:id/schedules", s.scheduledChangeHandlers.GetEnvironmentFlagScheduledChanges, read, env)
+		scoped.POST("/:id/schedules", s.scheduledChangeHandlers.CreateEnvironmentFlagScheduledChange, write, env)
+	}
 }
 
 // Start starts the API server
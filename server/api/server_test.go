@@ -88,7 +88,7 @@ func TestRouteExist(t *testing.T) {
 			path:     "/v1/flags",
 			body:     nil,
 			wantCode: http.StatusOK,
-			wantBody: `[{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a111","name":"flagr6w8","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a222","name":"flagr576987209","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}]`,
+			wantBody: `{"flags":[{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a222","name":"flagr576987209","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a111","name":"flagr6w8","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}],"total":3,"limit":20,"offset":0}`,
 		},
 		{
 			name:     "PATCH /v1/flags/:id/status",
@@ -96,7 +96,7 @@ func TestRouteExist(t *testing.T) {
 			path:     "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/status",
 			body:     testutils.String(`{"disable":true}`),
 			wantCode: http.StatusOK,
-			wantBody: `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
+			wantBody: `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":1,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
 		},
 		{
 			name:     "POST /v1/flags",
@@ -104,7 +104,7 @@ func TestRouteExist(t *testing.T) {
 			path:     "/v1/flags",
 			body:     testutils.String(`{"id":"926214f3-80c1-46e6-a913-b2d40b92a933","name":"flag2","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`),
 			wantCode: http.StatusCreated,
-			wantBody: `{"id":"926214f3-80c1-46e6-a913-b2d40b92a933","name":"flag2","createdDate":"2020-01-01T00:00:00Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"toto","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
+			wantBody: `{"id":"926214f3-80c1-46e6-a913-b2d40b92a933","name":"flag2","project":"default","environment":"default","createdDate":"2020-01-01T00:00:00Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":1,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
 		},
 		{
 			name:     "PATCH /v1/flags/:id",
@@ -112,7 +112,7 @@ func TestRouteExist(t *testing.T) {
 			path:     "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932",
 			body:     testutils.String(`{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`),
 			wantCode: http.StatusOK,
-			wantBody: `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
+			wantBody: `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":2,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
 		},
 		{
 			name:     "DELETE /v1/flags/:id",
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/go-feature-flag/flag-management/server/util"
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyPrefix is the prefix of all the API keys, it is used to distinguish them from the JWTs
+	APIKeyPrefix = "goff_"
+	// apiKeyDisplayLength is the number of characters of the key we keep to identify it
+	apiKeyDisplayLength = len(APIKeyPrefix) + 6
+	// apiKeyRandomBytes is the entropy of the keys
+	apiKeyRandomBytes = 32
+	// lastUsedDateResolution avoids writing in the database on every request done with the same key
+	lastUsedDateResolution = time.Minute
+)
+
+var (
+	errInvalidAPIKey = errors.New("invalid API key")
+	errExpiredAPIKey = errors.New("API key expired")
+)
+
+// GenerateAPIKey creates a new random API key, it returns the key, its display prefix and its hash.
+func GenerateAPIKey() (key string, prefix string, hashedKey string, err error) {
+	b := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("impossible to generate API key: %w", err)
+	}
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash of the API key as stored in the database.
+// The keys have enough entropy to use a fast hash function.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsAPIKey returns true if the bearer token looks like an API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// APIKeyValidator checks the API keys used to call the API.
+type APIKeyValidator struct {
+	storage dao.APIKeyStorage
+	clock   util.Clock
+}
+
+// NewAPIKeyValidator creates a new APIKeyValidator, if no clock is provided we use the system clock.
+func NewAPIKeyValidator(storage dao.APIKeyStorage, clock util.Clock) *APIKeyValidator {
+	if clock == nil {
+		clock = util.DefaultClock{}
+	}
+	return &APIKeyValidator{storage: storage, clock: clock}
+}
+
+// Validate returns the API key matching the key if it exists and is not expired.
+// It also records the date of usage of the key.
+func (v *APIKeyValidator) Validate(ctx context.Context, key string) (model.APIKey, error) {
+	apiKey, err := v.storage.GetAPIKeyByHashedKey(ctx, HashAPIKey(key))
+	if err != nil {
+		if err.Code() == daoErr.NotFound {
+			return model.APIKey{}, errInvalidAPIKey
+		}
+		return model.APIKey{}, err
+	}
+
+	now := v.clock.Now()
+	if apiKey.IsExpired(now) {
+		return model.APIKey{}, errExpiredAPIKey
+	}
+	if apiKey.LastUsedDate == nil || now.Sub(*apiKey.LastUsedDate) >= lastUsedDateResolution {
+		// not being able to record the usage should not block the caller
+		_ = v.storage.UpdateAPIKeyLastUsedDate(ctx, apiKey.ID, now)
+		apiKey.LastUsedDate = &now
+	}
+	return apiKey, nil
+}
+
+// APIKeyPrincipal returns the principal used for the requests authenticated with an API key.
+// The write scope gives the editor role and the read scope the viewer role, the API keys are never admin.
+// The delete scope adds the permission to delete the flags, see Principal.Can.
+func APIKeyPrincipal(apiKey model.APIKey) Principal {
+	var role Role
+	switch {
+	case apiKey.HasScope(model.APIKeyScopeWrite):
+		role = RoleEditor
+	case apiKey.HasScope(model.APIKeyScopeRead):
+		role = RoleViewer
+	}
+	return Principal{
+		Subject:  "apikey:" + apiKey.ID,
+		Name:     "apikey:" + apiKey.Name,
+		APIKeyID: apiKey.ID,
+		Scopes:   apiKey.Scopes,
+		Role:     role,
+	}
+}
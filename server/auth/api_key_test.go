@@ -0,0 +1,154 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hashedKey, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	assert.True(t, auth.IsAPIKey(key))
+	assert.Greater(t, len(key), 40)
+	assert.True(t, len(prefix) < len(key) && key[:len(prefix)] == prefix)
+	assert.Equal(t, auth.HashAPIKey(key), hashedKey)
+	assert.NotContains(t, hashedKey, key)
+
+	otherKey, _, _, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+}
+
+func TestIsAPIKey(t *testing.T) {
+	assert.True(t, auth.IsAPIKey("goff_abcdef"))
+	assert.False(t, auth.IsAPIKey("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.signature"))
+	assert.False(t, auth.IsAPIKey(""))
+}
+
+func TestAPIKeyValidator_Validate(t *testing.T) {
+	now := testutils.ClockMock{}.Now()
+	expired := now.Add(-time.Hour)
+	recentlyUsed := now.Add(-10 * time.Second)
+
+	tests := []struct {
+		name             string
+		apiKey           model.APIKey
+		key              string
+		ctx              context.Context
+		wantErr          assert.ErrorAssertionFunc
+		wantLastUsedDate time.Time
+	}{
+		{
+			name:             "valid key",
+			apiKey:           model.APIKey{ID: "1", HashedKey: auth.HashAPIKey("goff_valid")},
+			key:              "goff_valid",
+			ctx:              context.Background(),
+			wantErr:          assert.NoError,
+			wantLastUsedDate: now,
+		},
+		{
+			name:             "valid key recently used does not update the last used date",
+			apiKey:           model.APIKey{ID: "1", HashedKey: auth.HashAPIKey("goff_valid"), LastUsedDate: &recentlyUsed},
+			key:              "goff_valid",
+			ctx:              context.Background(),
+			wantErr:          assert.NoError,
+			wantLastUsedDate: recentlyUsed,
+		},
+		{
+			name:    "unknown key",
+			apiKey:  model.APIKey{ID: "1", HashedKey: auth.HashAPIKey("goff_valid")},
+			key:     "goff_unknown",
+			ctx:     context.Background(),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "expired key",
+			apiKey:  model.APIKey{ID: "1", HashedKey: auth.HashAPIKey("goff_valid"), ExpiresAt: &expired},
+			key:     "goff_valid",
+			ctx:     context.Background(),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "storage error",
+			apiKey:  model.APIKey{ID: "1", HashedKey: auth.HashAPIKey("goff_valid")},
+			key:     "goff_valid",
+			ctx:     context.WithValue(context.Background(), "error", daoErr.UnknownError),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			_, daoErr := storage.CreateAPIKey(context.Background(), tt.apiKey)
+			require.Nil(t, daoErr)
+
+			validator := auth.NewAPIKeyValidator(storage, testutils.ClockMock{})
+			got, err := validator.Validate(tt.ctx, tt.key)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.apiKey.ID, got.ID)
+
+			stored, _ := storage.GetAPIKeys(context.Background())
+			require.Len(t, stored, 1)
+			require.NotNil(t, stored[0].LastUsedDate)
+			assert.Equal(t, tt.wantLastUsedDate, *stored[0].LastUsedDate)
+		})
+	}
+}
+
+func TestAPIKeyPrincipal(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []model.APIKeyScope
+		wantRole auth.Role
+	}{
+		{
+			name:     "read scope is viewer",
+			scopes:   []model.APIKeyScope{model.APIKeyScopeRead},
+			wantRole: auth.RoleViewer,
+		},
+		{
+			name:     "write scope is editor",
+			scopes:   []model.APIKeyScope{model.APIKeyScopeWrite},
+			wantRole: auth.RoleEditor,
+		},
+		{
+			name:     "read and write scopes is editor",
+			scopes:   []model.APIKeyScope{model.APIKeyScopeRead, model.APIKeyScopeWrite},
+			wantRole: auth.RoleEditor,
+		},
+		{
+			name:     "no scope has no role",
+			wantRole: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := auth.APIKeyPrincipal(model.APIKey{ID: "123", Name: "ci", Scopes: tt.scopes})
+			assert.True(t, principal.IsAPIKey())
+			assert.Equal(t, "apikey:ci", principal.String())
+			assert.Equal(t, tt.wantRole, principal.Role)
+		})
+	}
+
+	deleteKey := auth.APIKeyPrincipal(model.APIKey{ID: "123", Name: "ci",
+		Scopes: []model.APIKeyScope{model.APIKeyScopeWrite, model.APIKeyScopeDelete}})
+	assert.Equal(t, auth.RoleEditor, deleteKey.Role)
+	assert.True(t, deleteKey.Can(auth.PermissionDeleteFlags), "the delete scope should allow to delete the flags")
+	assert.False(t, deleteKey.Can(auth.PermissionManageAPIKeys))
+	assert.False(t, auth.APIKeyPrincipal(model.APIKey{ID: "123", Name: "ci",
+		Scopes: []model.APIKeyScope{model.APIKeyScopeWrite}}).Can(auth.PermissionDeleteFlags))
+	assert.False(t, auth.Principal{Subject: "john"}.IsAPIKey())
+}
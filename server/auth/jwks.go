@@ -0,0 +1,283 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultJWKSRefreshInterval = time.Hour
+	// minJWKSRefreshInterval avoids fetching the JWKS URL on every request when a token uses an unknown key ID
+	minJWKSRefreshInterval = time.Minute
+	httpTimeout            = 10 * time.Second
+)
+
+var errKeyNotFound = errors.New("no key found for this token")
+
+// jsonWebKey is a public key as defined in RFC 7517, we only read the fields needed to verify signatures.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jwksKeySet contains the public keys of a JSON Web Key Set.
+// When the keys are coming from a URL, they are fetched again when they are too old or when a token is using
+// a key ID we don't know (the provider has probably rotated its keys).
+type jwksKeySet struct {
+	url             string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+
+	mutex       sync.RWMutex
+	keys        map[string]interface{}
+	lastRefresh time.Time
+}
+
+// newJWKSFromFile loads a JSON Web Key Set from a local file.
+func newJWKSFromFile(path string) (*jwksKeySet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to read JWKS file: %w", err)
+	}
+	keys, err := parseJWKS(content)
+	if err != nil {
+		return nil, err
+	}
+	return &jwksKeySet{keys: keys}, nil
+}
+
+// newJWKSFromURL fetches a JSON Web Key Set from a URL.
+func newJWKSFromURL(ctx context.Context, url string, refreshInterval time.Duration) (*jwksKeySet, error) {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefreshInterval
+	}
+	s := &jwksKeySet{
+		url:             url,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: httpTimeout},
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// key returns the public key with the given key ID.
+// If the token has no key ID, we accept it only if the set contains a single key.
+func (s *jwksKeySet) key(ctx context.Context, kid string) (interface{}, error) {
+	if s.url != "" && s.isExpired(s.refreshInterval) {
+		// if the refresh fails we keep using the keys we already have
+		_ = s.refresh(ctx)
+	}
+
+	if key, err := s.lookup(kid); err == nil {
+		return key, nil
+	}
+
+	if s.url == "" || !s.isExpired(minJWKSRefreshInterval) {
+		return nil, errKeyNotFound
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.lookup(kid)
+}
+
+func (s *jwksKeySet) lookup(kid string) (interface{}, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if kid == "" {
+		if len(s.keys) == 1 {
+			for _, key := range s.keys {
+				return key, nil
+			}
+		}
+		return nil, errKeyNotFound
+	}
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, errKeyNotFound
+}
+
+func (s *jwksKeySet) isExpired(maxAge time.Duration) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return time.Since(s.lastRefresh) > maxAge
+}
+
+func (s *jwksKeySet) refresh(ctx context.Context) error {
+	content, err := httpGet(ctx, s.httpClient, s.url)
+	if err != nil {
+		return fmt.Errorf("impossible to retrieve JWKS: %w", err)
+	}
+	keys, err := parseJWKS(content)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keys = keys
+	s.lastRefresh = time.Now()
+	return nil
+}
+
+// discoverJWKSURL reads the OpenID configuration of the issuer to find the URL of its JSON Web Key Set.
+func discoverJWKSURL(ctx context.Context, issuer string) (string, error) {
+	content, err := httpGet(ctx, &http.Client{Timeout: httpTimeout},
+		strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration")
+	if err != nil {
+		return "", fmt.Errorf("impossible to retrieve the OpenID configuration: %w", err)
+	}
+	var oidcConfig struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(content, &oidcConfig); err != nil {
+		return "", fmt.Errorf("invalid OpenID configuration: %w", err)
+	}
+	if oidcConfig.JWKSURI == "" {
+		return "", errors.New("no jwks_uri in the OpenID configuration")
+	}
+	return oidcConfig.JWKSURI, nil
+}
+
+func httpGet(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseJWKS parses a JSON Web Key Set and returns the signature keys indexed by key ID.
+// Keys used for encryption or with a type we don't support are ignored.
+func parseJWKS(content []byte) (map[string]interface{}, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s in JWKS: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signature key found in JWKS")
+	}
+	return keys, nil
+}
+
+// publicKey converts the JWK into a crypto public key, it returns nil if the type of key is not supported.
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		return k.ecdsaPublicKey()
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (k jsonWebKey) ecdsaPublicKey() (interface{}, error) {
+	var curve elliptic.Curve
+	var ecdhCurve ecdh.Curve
+	switch k.Crv {
+	case "P-256":
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, nil
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	// we check that the point is on the curve by loading it as an uncompressed point
+	size := (curve.Params().BitSize + 7) / 8
+	if len(x) != size || len(y) != size {
+		return nil, errors.New("invalid EC coordinates")
+	}
+	point := append([]byte{4}, append(x, y...)...)
+	if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("invalid EC public key: %w", err)
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,199 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	ecdhKey, _ := key.PublicKey.ECDH()
+	point := ecdhKey.Bytes()
+	size := (len(point) - 1) / 2
+	return map[string]string{
+		"kty": "EC", "kid": kid, "use": "sig", "crv": "P-256",
+		"x": b64(point[1 : 1+size]), "y": b64(point[1+size:]),
+	}
+}
+
+func jwks(t *testing.T, keys ...map[string]string) []byte {
+	content, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	return content
+}
+
+func writeJWKSFile(t *testing.T, content []byte) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	return path
+}
+
+func TestJWKSFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edJWK := map[string]string{"kty": "OKP", "kid": "ed-key", "crv": "Ed25519", "x": b64(edPublicKey)}
+
+	validator, err := auth.NewJWTValidator(context.Background(), config.AuthConfiguration{
+		JWKSFile: writeJWKSFile(t, jwks(t, rsaJWK("rsa-key", &rsaKey.PublicKey), ecJWK("ec-key", ecKey), edJWK)),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "RSA key",
+			token:   signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-key", validClaims()),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "ECDSA key",
+			token:   signToken(t, jwt.SigningMethodES256, ecKey, "ec-key", validClaims()),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "Ed25519 key",
+			token:   signToken(t, jwt.SigningMethodEdDSA, edKey, "ed-key", validClaims()),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "key ID of another key",
+			token:   signToken(t, jwt.SigningMethodRS256, rsaKey, "ec-key", validClaims()),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "unknown key ID",
+			token:   signToken(t, jwt.SigningMethodRS256, rsaKey, "unknown", validClaims()),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "no key ID with several keys in the set",
+			token:   signToken(t, jwt.SigningMethodRS256, rsaKey, "", validClaims()),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "HMAC token without HMAC secret",
+			token:   signToken(t, jwt.SigningMethodHS256, []byte("secret"), "rsa-key", validClaims()),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.Validate(context.Background(), tt.token)
+			tt.wantErr(t, err)
+		})
+	}
+}
+
+func TestInvalidJWKSFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	encryptionKey := rsaJWK("enc-key", &rsaKey.PublicKey)
+	encryptionKey["use"] = "enc"
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "not a JSON file", content: []byte("not a json")},
+		{name: "no keys", content: jwks(t)},
+		{name: "only encryption keys", content: jwks(t, encryptionKey)},
+		{name: "invalid RSA key", content: jwks(t, map[string]string{"kty": "RSA", "kid": "k", "n": "!!", "e": "AQAB"})},
+		{name: "EC point not on the curve", content: jwks(t, map[string]string{"kty": "EC", "kid": "k", "crv": "P-256", "x": "AQ", "y": "AQ"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.NewJWTValidator(context.Background(), config.AuthConfiguration{
+				JWKSFile: writeJWKSFile(t, tt.content),
+			})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJWKSURL(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	content := jwks(t, rsaJWK("rsa-key", &rsaKey.PublicKey))
+
+	var issuer string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"issuer":%q,"jwks_uri":%q}`, issuer, issuer+"/keys")
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(content)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	issuer = server.URL
+
+	claims := validClaims()
+	claims["iss"] = issuer
+	token := signToken(t, jwt.SigningMethodRS256, rsaKey, "rsa-key", claims)
+
+	tests := []struct {
+		name    string
+		config  config.AuthConfiguration
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "JWKS URL",
+			config:  config.AuthConfiguration{JWKSURL: server.URL + "/keys"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "JWKS URL discovered from the issuer",
+			config:  config.AuthConfiguration{Issuer: issuer, Audience: []string{"goff-management"}},
+			wantErr: assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := auth.NewJWTValidator(context.Background(), tt.config)
+			require.NoError(t, err)
+			_, err = validator.Validate(context.Background(), token)
+			tt.wantErr(t, err)
+		})
+	}
+
+	t.Run("issuer without OpenID configuration", func(t *testing.T) {
+		_, err := auth.NewJWTValidator(context.Background(), config.AuthConfiguration{Issuer: server.URL + "/unknown"})
+		assert.Error(t, err)
+	})
+}
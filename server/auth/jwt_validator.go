@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// leeway is the clock skew we accept between the token provider and the API
+const leeway = 30 * time.Second
+
+var (
+	hmacMethods       = []string{"HS256", "HS384", "HS512"}
+	rsaMethods        = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	ecdsaMethods      = []string{"ES256", "ES384", "ES512"}
+	asymmetricMethods = slices.Concat(rsaMethods, ecdsaMethods, []string{"EdDSA"})
+)
+
+// JWTValidator verifies the bearer tokens used to call the API.
+type JWTValidator struct {
+	parser     *jwt.Parser
+	audience   []string
+	nameClaim  string
+	roles      roleMapper
+	hmacSecret []byte
+	publicKey  interface{}
+	jwks       *jwksKeySet
+}
+
+// NewJWTValidator creates a JWTValidator from the configuration.
+// If the JWKS has to be retrieved from a URL, it is fetched during the creation.
+func NewJWTValidator(ctx context.Context, c config.AuthConfiguration) (*JWTValidator, error) {
+	if !c.IsConfigured() {
+		return nil, errors.New("no authentication method configured")
+	}
+
+	v := &JWTValidator{audience: c.Audience, nameClaim: c.NameClaim}
+	if v.nameClaim == "" {
+		v.nameClaim = defaultNameClaim
+	}
+	roles, err := newRoleMapper(c)
+	if err != nil {
+		return nil, err
+	}
+	v.roles = roles
+
+	validMethods := []string{}
+	if c.HMACSecret != "" {
+		v.hmacSecret = []byte(c.HMACSecret)
+		validMethods = append(validMethods, hmacMethods...)
+	}
+
+	if c.PublicKeyFile != "" {
+		key, err := loadPublicKeyFile(c.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.publicKey = key
+	}
+
+	switch {
+	case c.JWKSFile != "":
+		v.jwks, err = newJWKSFromFile(c.JWKSFile)
+	case c.JWKSURL != "":
+		v.jwks, err = newJWKSFromURL(ctx, c.JWKSURL, c.JWKSRefreshInterval)
+	case c.Issuer != "" && c.HMACSecret == "" && c.PublicKeyFile == "":
+		var jwksURL string
+		if jwksURL, err = discoverJWKSURL(ctx, c.Issuer); err == nil {
+			v.jwks, err = newJWKSFromURL(ctx, jwksURL, c.JWKSRefreshInterval)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v.publicKey != nil || v.jwks != nil {
+		validMethods = append(validMethods, asymmetricMethods...)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if c.Issuer != "" {
+		options = append(options, jwt.WithIssuer(c.Issuer))
+	}
+	v.parser = jwt.NewParser(options...)
+	return v, nil
+}
+
+// Validate checks the signature and the claims of the token and returns the parsed token.
+func (v *JWTValidator) Validate(ctx context.Context, tokenString string) (*jwt.Token, error) {
+	token, err := v.parser.ParseWithClaims(tokenString, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return v.keyFunc(ctx, token)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(v.audience) > 0 {
+		audience, err := token.Claims.GetAudience()
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(audience, func(aud string) bool { return slices.Contains(v.audience, aud) }) {
+			return nil, jwt.ErrTokenInvalidAudience
+		}
+	}
+	return token, nil
+}
+
+// Principal extracts the identity of the caller from a token returned by Validate.
+func (v *JWTValidator) Principal(token *jwt.Token) Principal {
+	claims, _ := token.Claims.(jwt.MapClaims)
+	subject, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	name, _ := claims[v.nameClaim].(string)
+	return Principal{Subject: subject, Email: email, Name: name, Role: v.roles.role(claims)}
+}
+
+// keyFunc selects the key used to verify the signature of the token, depending on its algorithm.
+func (v *JWTValidator) keyFunc(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if v.hmacSecret == nil {
+			return nil, errKeyNotFound
+		}
+		return v.hmacSecret, nil
+	}
+
+	if v.jwks != nil {
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.jwks.key(ctx, kid)
+		if err == nil || v.publicKey == nil {
+			return key, err
+		}
+	}
+	if v.publicKey != nil {
+		return v.publicKey, nil
+	}
+	return nil, errKeyNotFound
+}
+
+// loadPublicKeyFile reads a PEM encoded RSA, ECDSA or Ed25519 public key.
+func loadPublicKeyFile(path string) (interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to read public key file: %w", err)
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(content); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(content); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(content); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("public key file %s is not a valid PEM encoded RSA, ECDSA or Ed25519 public key", path)
+}
@@ -0,0 +1,229 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "john.doe@gofeatureflag.org",
+		"iss": "https://issuer.gofeatureflag.org",
+		"aud": "goff-management",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func writePublicKeyFile(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	return path
+}
+
+func TestNewJWTValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.AuthConfiguration
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "no configuration",
+			config:  config.AuthConfiguration{},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "HMAC secret",
+			config:  config.AuthConfiguration{HMACSecret: "secret"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "public key file does not exist",
+			config:  config.AuthConfiguration{PublicKeyFile: "/not/existing/file.pem"},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "JWKS file does not exist",
+			config:  config.AuthConfiguration{JWKSFile: "/not/existing/jwks.json"},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "JWKS URL not reachable",
+			config:  config.AuthConfiguration{JWKSURL: "http://127.0.0.1:1/jwks.json"},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.NewJWTValidator(context.Background(), tt.config)
+			tt.wantErr(t, err)
+		})
+	}
+}
+
+func TestJWTValidator_ValidateHMAC(t *testing.T) {
+	validator, err := auth.NewJWTValidator(context.Background(), config.AuthConfiguration{
+		Issuer:     "https://issuer.gofeatureflag.org",
+		Audience:   []string{"goff-management", "goff-other"},
+		HMACSecret: "my-secret",
+	})
+	require.NoError(t, err)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	noExpiration := validClaims()
+	delete(noExpiration, "exp")
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "https://evil.gofeatureflag.org"
+	wrongAudience := validClaims()
+	wrongAudience["aud"] = []string{"another-api"}
+	multipleAudiences := validClaims()
+	multipleAudiences["aud"] = []string{"another-api", "goff-other"}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "valid token",
+			token:   signToken(t, jwt.SigningMethodHS256, []byte("my-secret"), "", validClaims()),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "valid token with one of the audiences",
+			token:   signToken(t, jwt.SigningMethodHS512, []byte("my-secret"), "", multipleAudiences),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "wrong secret",
+			token:   signToken(t, jwt.SigningMethodHS256, []byte("another-secret"), "", validClaims()),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "expired token",
+			token:   signToken(t, jwt.SigningMethodHS256, []byte("my-secret"), "", expired),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "token without expiration",
+			token:   signToken(t, jwt.SigningMethodHS256, []byte("my-secret"), "", noExpiration),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "wrong issuer",
+			token:   signToken(t, jwt.SigningMethodHS256, []byte("my-secret"), "", wrongIssuer),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "wrong audience",
+			token:   signToken(t, jwt.SigningMethodHS256, []byte("my-secret"), "", wrongAudience),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "unsigned token",
+			token:   signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", validClaims()),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "not a token",
+			token:   "not-a-token",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := validator.Validate(context.Background(), tt.token)
+			tt.wantErr(t, err)
+			if err == nil {
+				sub, _ := token.Claims.GetSubject()
+				assert.Equal(t, "john.doe@gofeatureflag.org", sub)
+			}
+		})
+	}
+}
+
+func TestJWTValidator_ValidatePublicKeyFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rsaValidator, err := auth.NewJWTValidator(context.Background(), config.AuthConfiguration{
+		PublicKeyFile: writePublicKeyFile(t, &rsaKey.PublicKey),
+	})
+	require.NoError(t, err)
+	ecValidator, err := auth.NewJWTValidator(context.Background(), config.AuthConfiguration{
+		PublicKeyFile: writePublicKeyFile(t, &ecKey.PublicKey),
+	})
+	require.NoError(t, err)
+
+	rsaPublicKeyPEM, err := os.ReadFile(writePublicKeyFile(t, &rsaKey.PublicKey))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		validator *auth.JWTValidator
+		token     string
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "RSA signed token",
+			validator: rsaValidator,
+			token:     signToken(t, jwt.SigningMethodRS256, rsaKey, "", validClaims()),
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "RSA token signed by another key",
+			validator: rsaValidator,
+			token:     signToken(t, jwt.SigningMethodRS256, otherRSAKey, "", validClaims()),
+			wantErr:   assert.Error,
+		},
+		{
+			name:      "HMAC token signed with the public key should be rejected",
+			validator: rsaValidator,
+			token:     signToken(t, jwt.SigningMethodHS256, rsaPublicKeyPEM, "", validClaims()),
+			wantErr:   assert.Error,
+		},
+		{
+			name:      "ECDSA signed token",
+			validator: ecValidator,
+			token:     signToken(t, jwt.SigningMethodES256, ecKey, "", validClaims()),
+			wantErr:   assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.validator.Validate(context.Background(), tt.token)
+			tt.wantErr(t, err)
+		})
+	}
+}
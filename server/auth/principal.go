@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/model"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+// principalContextKey is the key used to store the Principal on the echo.Context
+const principalContextKey = "principal"
+
+// principalKey is the key used to store the Principal on the context.Context of the request
+type principalKey struct{}
+
+// defaultNameClaim is the claim used for the name of the principal if nothing is configured
+const defaultNameClaim = "name"
+
+// AnonymousPrincipal is used when the request is not authenticated (development mode)
+var AnonymousPrincipal = Principal{Subject: "anonymous"}
+
+// Principal is the identity of the caller of the API, extracted from its token.
+type Principal struct {
+	// Subject is the "sub" claim of the token
+	Subject string
+	// Email is the "email" claim of the token
+	Email string
+	// Name is the value of the name claim configured (default: "name")
+	Name string
+	// APIKeyID is the ID of the API key used to authenticate, empty for the users authenticated with a token
+	APIKeyID string
+	// Scopes are the permissions of the principal when it is authenticated with an API key
+	Scopes []model.APIKeyScope
+	// Role is the role of the principal, mapped from the claims of the token or the scopes of the API key
+	Role Role
+}
+
+// IsAPIKey returns true if the principal is authenticated with an API key
+func (p Principal) IsAPIKey() bool {
+	return p.APIKeyID != ""
+}
+
+// Can returns true if the role of the principal has the permission, or if one of the scopes of its API key
+// grants it.
+func (p Principal) Can(permission Permission) bool {
+	if p.Role.Can(permission) {
+		return true
+	}
+	for _, scope := range p.Scopes {
+		if slices.Contains(scopePermissions[scope], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the best human-readable identifier of the principal,
+// it is the value we store in the audit trail of the flags.
+func (p Principal) String() string {
+	switch {
+	case p.Name != "":
+		return p.Name
+	case p.Email != "":
+		return p.Email
+	default:
+		return p.Subject
+	}
+}
+
+// SetPrincipal stores the principal on the context of the request, it is also stored on the context.Context
+// of the request so the storages know who made a change.
+func SetPrincipal(c echo.Context, principal Principal) {
+	c.Set(principalContextKey, principal)
+	c.SetRequest(c.Request().WithContext(WithPrincipal(c.Request().Context(), principal)))
+}
+
+// WithPrincipal returns a copy of the context with the principal making the changes.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal stored on a context.Context by WithPrincipal, false if there is none.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// PrincipalFromContext returns the principal of the request, or AnonymousPrincipal if the request
+// is not authenticated.
+func PrincipalFromContext(c echo.Context) Principal {
+	if principal, ok := c.Get(principalContextKey).(Principal); ok {
+		return principal
+	}
+	return AnonymousPrincipal
+}
@@ -0,0 +1,64 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal_String(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		want      string
+	}{
+		{
+			name:      "name is preferred",
+			principal: auth.Principal{Subject: "1234", Email: "john.doe@gofeatureflag.org", Name: "John Doe"},
+			want:      "John Doe",
+		},
+		{
+			name:      "email if no name",
+			principal: auth.Principal{Subject: "1234", Email: "john.doe@gofeatureflag.org"},
+			want:      "john.doe@gofeatureflag.org",
+		},
+		{
+			name:      "subject if no name and no email",
+			principal: auth.Principal{Subject: "1234"},
+			want:      "1234",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.principal.String())
+		})
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Equal(t, auth.AnonymousPrincipal, auth.PrincipalFromContext(c))
+
+	principal := auth.Principal{Subject: "1234", Name: "John Doe"}
+	auth.SetPrincipal(c, principal)
+	assert.Equal(t, principal, auth.PrincipalFromContext(c))
+	fromRequest, ok := auth.PrincipalFrom(c.Request().Context())
+	assert.True(t, ok, "the principal should also be on the context of the request")
+	assert.Equal(t, principal, fromRequest)
+}
+
+func TestPrincipalFrom(t *testing.T) {
+	_, ok := auth.PrincipalFrom(context.Background())
+	assert.False(t, ok)
+
+	principal := auth.Principal{Subject: "1234", Email: "john@example.com"}
+	fromContext, ok := auth.PrincipalFrom(auth.WithPrincipal(context.Background(), principal))
+	assert.True(t, ok)
+	assert.Equal(t, principal, fromContext)
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/model"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Role string
+
+const (
+	// RoleViewer can read the flags and their history
+	RoleViewer Role = "viewer"
+	// RoleEditor can also create, update, restore and toggle the flags
+	RoleEditor Role = "editor"
+	// RoleAdmin can also delete the flags and manage the API keys, the projects and the environments
+	RoleAdmin Role = "admin"
+)
+
+// RoleFromValue converts a string to a Role
+func RoleFromValue(value string) (Role, error) {
+	switch Role(value) {
+	case RoleViewer, RoleEditor, RoleAdmin:
+		return Role(value), nil
+	default:
+		return "", fmt.Errorf("role %s not supported", value)
+	}
+}
+
+type Permission string
+
+const (
+	PermissionReadFlags      Permission = "flags:read"
+	PermissionWriteFlags     Permission = "flags:write"
+	PermissionToggleFlags    Permission = "flags:toggle"
+	PermissionDeleteFlags    Permission = "flags:delete"
+	PermissionManageAPIKeys  Permission = "apikeys:manage"
+	PermissionManageProjects Permission = "projects:manage"
+)
+
+// rolePermissions lists the permissions of each role, ordered from the least to the most privileged role
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {PermissionReadFlags},
+	RoleEditor: {PermissionReadFlags, PermissionWriteFlags, PermissionToggleFlags},
+	RoleAdmin: {
+		PermissionReadFlags, PermissionWriteFlags, PermissionToggleFlags, PermissionDeleteFlags,
+		PermissionManageAPIKeys, PermissionManageProjects,
+	},
+}
+
+// scopePermissions lists the permissions given by the scopes of the API keys in addition to their role
+var scopePermissions = map[model.APIKeyScope][]Permission{
+	model.APIKeyScopeDelete: {PermissionDeleteFlags},
+}
+
+// Can returns true if the role has the permission
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// morePrivilegedThan returns true if the role has more permissions than the other role
+func (r Role) morePrivilegedThan(other Role) bool {
+	return len(rolePermissions[r]) > len(rolePermissions[other])
+}
+
+// defaultRoleClaim is the claim containing the roles of the user if nothing is configured
+const defaultRoleClaim = "roles"
+
+// roleMapper finds the role of a user from the claims of its token.
+type roleMapper struct {
+	claimPath   []string
+	mapping     map[string]Role
+	defaultRole Role
+}
+
+func newRoleMapper(c config.AuthConfiguration) (roleMapper, error) {
+	m := roleMapper{
+		claimPath:   strings.Split(c.RoleClaim, "."),
+		mapping:     make(map[string]Role, len(c.RoleMapping)),
+		defaultRole: RoleViewer,
+	}
+	if c.RoleClaim == "" {
+		m.claimPath = []string{defaultRoleClaim}
+	}
+	if c.DefaultRole != "" {
+		role, err := RoleFromValue(c.DefaultRole)
+		if err != nil {
+			return roleMapper{}, fmt.Errorf("invalid default role: %w", err)
+		}
+		m.defaultRole = role
+	}
+	for value, roleName := range c.RoleMapping {
+		role, err := RoleFromValue(roleName)
+		if err != nil {
+			return roleMapper{}, fmt.Errorf("invalid role mapping for %s: %w", value, err)
+		}
+		m.mapping[value] = role
+	}
+	return m, nil
+}
+
+// role returns the most privileged role found in the claims, or the default role if there is none.
+func (m roleMapper) role(claims jwt.MapClaims) Role {
+	var claim interface{} = map[string]interface{}(claims)
+	for _, key := range m.claimPath {
+		nested, ok := claim.(map[string]interface{})
+		if !ok {
+			return m.defaultRole
+		}
+		claim = nested[key]
+	}
+
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var res Role
+	for _, value := range values {
+		role, ok := m.mapping[value]
+		if !ok {
+			if role, ok = roleFromClaimValue(value); !ok {
+				continue
+			}
+		}
+		if role.morePrivilegedThan(res) {
+			res = role
+		}
+	}
+	if res == "" {
+		return m.defaultRole
+	}
+	return res
+}
+
+func roleFromClaimValue(value string) (Role, bool) {
+	role, err := RoleFromValue(value)
+	return role, err == nil
+}
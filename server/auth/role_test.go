@@ -0,0 +1,145 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleFromValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      auth.Role
+		expectErr bool
+	}{
+		{"valid viewer role", "viewer", auth.RoleViewer, false},
+		{"valid editor role", "editor", auth.RoleEditor, false},
+		{"valid admin role", "admin", auth.RoleAdmin, false},
+		{"empty role", "", "", true},
+		{"unsupported role", "owner", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := auth.RoleFromValue(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRole_Can(t *testing.T) {
+	tests := []struct {
+		permission auth.Permission
+		viewer     bool
+		editor     bool
+		admin      bool
+	}{
+		{auth.PermissionReadFlags, true, true, true},
+		{auth.PermissionWriteFlags, false, true, true},
+		{auth.PermissionToggleFlags, false, true, true},
+		{auth.PermissionDeleteFlags, false, false, true},
+		{auth.PermissionManageAPIKeys, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.permission), func(t *testing.T) {
+			assert.Equal(t, tt.viewer, auth.RoleViewer.Can(tt.permission))
+			assert.Equal(t, tt.editor, auth.RoleEditor.Can(tt.permission))
+			assert.Equal(t, tt.admin, auth.RoleAdmin.Can(tt.permission))
+			assert.False(t, auth.Role("").Can(tt.permission))
+		})
+	}
+}
+
+func TestJWTValidator_PrincipalRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.AuthConfiguration
+		claims jwt.MapClaims
+		want   auth.Role
+	}{
+		{
+			name:   "no role claim gives the default role",
+			config: config.AuthConfiguration{HMACSecret: "secret"},
+			claims: jwt.MapClaims{},
+			want:   auth.RoleViewer,
+		},
+		{
+			name:   "configured default role",
+			config: config.AuthConfiguration{HMACSecret: "secret", DefaultRole: "editor"},
+			claims: jwt.MapClaims{},
+			want:   auth.RoleEditor,
+		},
+		{
+			name:   "role as a string",
+			config: config.AuthConfiguration{HMACSecret: "secret"},
+			claims: jwt.MapClaims{"roles": "admin"},
+			want:   auth.RoleAdmin,
+		},
+		{
+			name:   "most privileged role of a list",
+			config: config.AuthConfiguration{HMACSecret: "secret"},
+			claims: jwt.MapClaims{"roles": []interface{}{"viewer", "editor", "unknown"}},
+			want:   auth.RoleEditor,
+		},
+		{
+			name: "mapped role",
+			config: config.AuthConfiguration{
+				HMACSecret:  "secret",
+				RoleClaim:   "groups",
+				RoleMapping: map[string]string{"platform-team": "admin"},
+			},
+			claims: jwt.MapClaims{"groups": []interface{}{"developers", "platform-team"}},
+			want:   auth.RoleAdmin,
+		},
+		{
+			name:   "nested claim",
+			config: config.AuthConfiguration{HMACSecret: "secret", RoleClaim: "realm_access.roles"},
+			claims: jwt.MapClaims{"realm_access": map[string]interface{}{"roles": []interface{}{"editor"}}},
+			want:   auth.RoleEditor,
+		},
+		{
+			name:   "nested claim not found",
+			config: config.AuthConfiguration{HMACSecret: "secret", RoleClaim: "realm_access.roles"},
+			claims: jwt.MapClaims{"realm_access": "editor"},
+			want:   auth.RoleViewer,
+		},
+		{
+			name:   "unknown values give the default role",
+			config: config.AuthConfiguration{HMACSecret: "secret"},
+			claims: jwt.MapClaims{"roles": []interface{}{"developers"}},
+			want:   auth.RoleViewer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := auth.NewJWTValidator(context.Background(), tt.config)
+			require.NoError(t, err)
+			principal := validator.Principal(&jwt.Token{Claims: tt.claims})
+			assert.Equal(t, tt.want, principal.Role)
+		})
+	}
+}
+
+func TestNewJWTValidator_InvalidRoles(t *testing.T) {
+	_, err := auth.NewJWTValidator(context.Background(), config.AuthConfiguration{
+		HMACSecret:  "secret",
+		DefaultRole: "owner",
+	})
+	assert.Error(t, err)
+
+	_, err = auth.NewJWTValidator(context.Background(), config.AuthConfiguration{
+		HMACSecret:  "secret",
+		RoleMapping: map[string]string{"platform-team": "owner"},
+	})
+	assert.Error(t, err)
+}
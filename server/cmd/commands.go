@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Names of the subcommands of the management binary
+const (
+	serveCommand    = "serve"
+	exportCommand   = "export"
+	importCommand   = "import"
+	validateCommand = "validate"
+)
+
+// Execute runs the subcommand named by the first argument (serve, export, import or validate) with the
+// remaining arguments. Without subcommand the API server is started, so the binary keeps accepting the
+// arguments of the previous versions.
+// options - is used to override default services for testing purpose.
+func Execute(args []string, options APICommandOptions) error {
+	name := serveCommand
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	options.Args = append([]string{}, args...)
+
+	err := runCommand(name, options)
+	if errors.Is(err, pflag.ErrHelp) {
+		// the usage of the command has already been printed
+		return nil
+	}
+	return err
+}
+
+func runCommand(name string, options APICommandOptions) error {
+	switch name {
+	case serveCommand:
+		command, err := NewGOFeatureFlagManagementAPICommand(options)
+		if err != nil {
+			return err
+		}
+		command.Run()
+		return nil
+	case exportCommand:
+		return runExport(options)
+	case importCommand:
+		return runImport(options)
+	case validateCommand:
+		return runValidate(options)
+	default:
+		return fmt.Errorf("unknown command %s, use serve, export, import or validate", name)
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/flagconfig"
+	"os"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/spf13/pflag"
+)
+
+// runExport writes all the flags of an environment in a GO Feature Flag configuration file.
+func runExport(options APICommandOptions) error {
+	f := pflag.NewFlagSet(exportCommand, pflag.ContinueOnError)
+	addStorageFlags(f)
+	output := f.String("output", "", "File where the flags are written (default: standard output)")
+	formatValue := f.String("format", "", "Format of the file: json, yaml or toml (default: extension of the output file)")
+	project := f.String("project", model.DefaultProject, "Project of the flags to export")
+	environment := f.String("environment", model.DefaultEnvironment, "Environment of the flags to export")
+	if err := parseCommandFlags(f, options); err != nil {
+		return err
+	}
+
+	format, err := fileFormat(*formatValue, *output)
+	if err != nil {
+		return err
+	}
+	storage, err := openStorage(f, options)
+	if err != nil {
+		return err
+	}
+	defer closeStorage(options, storage)
+	ctx := context.Background()
+	if err := checkEnvironmentExists(ctx, storage, *project, *environment); err != nil {
+		return err
+	}
+	flags, exportErr := flagconfig.Export(ctx, storage, *project, *environment)
+	if exportErr != nil {
+		return fmt.Errorf("impossible to export the flags: %w", exportErr)
+	}
+	content, err := flagconfig.Marshal(flags, format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = options.output().Write(content)
+		return err
+	}
+	return os.WriteFile(*output, content, 0o600)
+}
+
+// runImport creates or updates the flags of an environment from a GO Feature Flag configuration file,
+// and writes a summary of the changes.
+func runImport(options APICommandOptions) error {
+	f := pflag.NewFlagSet(importCommand, pflag.ContinueOnError)
+	addStorageFlags(f)
+	input := f.String("input", "", "Configuration file to import")
+	formatValue := f.String("format", "", "Format of the file: json, yaml or toml (default: extension of the input file)")
+	project := f.String("project", model.DefaultProject, "Project of the flags to import")
+	environment := f.String("environment", model.DefaultEnvironment, "Environment of the flags to import")
+	dryRun := f.Bool("dryRun", false, "Print the changes without storing them")
+	author := f.String("author", "cli", "Name stored as the last modifier of the imported flags")
+	if err := parseCommandFlags(f, options); err != nil {
+		return err
+	}
+
+	file, err := readConfigurationFile(*input, *formatValue)
+	if err != nil {
+		return err
+	}
+	storage, err := openStorage(f, options)
+	if err != nil {
+		return err
+	}
+	defer closeStorage(options, storage)
+	ctx := context.Background()
+	if err := checkEnvironmentExists(ctx, storage, *project, *environment); err != nil {
+		return err
+	}
+	plan, err := flagconfig.PlanImport(ctx, storage, file, flagconfig.ImportOptions{
+		Project:     *project,
+		Environment: *environment,
+		Author:      *author,
+		Now:         time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if !*dryRun {
+		if err := plan.Apply(ctx, storage); err != nil {
+			return fmt.Errorf("impossible to import the flags: %w", err)
+		}
+	}
+	plan.Result.DryRun = *dryRun
+
+	encoder := json.NewEncoder(options.output())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(plan.Result)
+}
+
+// runValidate checks a GO Feature Flag configuration file without any access to the database.
+func runValidate(options APICommandOptions) error {
+	f := pflag.NewFlagSet(validateCommand, pflag.ContinueOnError)
+	input := f.String("input", "", "Configuration file to validate")
+	formatValue := f.String("format", "", "Format of the file: json, yaml or toml (default: extension of the input file)")
+	if err := parseCommandFlags(f, options); err != nil {
+		return err
+	}
+
+	file, err := readConfigurationFile(*input, *formatValue)
+	if err != nil {
+		return err
+	}
+	if err := flagconfig.ValidateFile(file); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(options.output(), "%s is valid (%d flags)\n", *input, len(file))
+	return err
+}
+
+// parseCommandFlags parses the arguments of a subcommand, unlike the serve command unknown flags are rejected.
+func parseCommandFlags(f *pflag.FlagSet, options APICommandOptions) error {
+	if err := f.Parse(options.args()); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// openStorage loads the configuration from the flags and connects to the database.
+func openStorage(f *pflag.FlagSet, options APICommandOptions) (dao.FlagStorage, error) {
+	c, err := config.LoadConfiguration(f)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to load configuration: %w", err)
+	}
+	storage, err := initDatabaseAccess(options, c)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to initialize database connection: %w", err)
+	}
+	return storage, nil
+}
+
+func checkEnvironmentExists(ctx context.Context, storage dao.FlagStorage, project string, environment string) error {
+	if _, err := storage.GetEnvironment(ctx, project, environment); err != nil {
+		if err.Code() == daoErr.NotFound {
+			return fmt.Errorf("environment %s not found in project %s", environment, project)
+		}
+		return err
+	}
+	return nil
+}
+
+// fileFormat returns the format given with the --format flag, or the one of the extension of the file.
+// Without file (standard output) we use JSON.
+func fileFormat(value string, fileName string) (flagconfig.Format, error) {
+	if value != "" {
+		return flagconfig.FormatFromValue(value)
+	}
+	if fileName == "" {
+		return flagconfig.FormatJSON, nil
+	}
+	if format, ok := flagconfig.FormatFromFileName(fileName); ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("impossible to guess the format of %s, use the --format flag", fileName)
+}
+
+func readConfigurationFile(input string, formatValue string) (map[string]flagconfig.Flag, error) {
+	if input == "" {
+		return nil, errors.New("input is required")
+	}
+	format, err := fileFormat(formatValue, input)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(input)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to read %s: %w", input, err)
+	}
+	file, err := flagconfig.Unmarshal(content, format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration file: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,257 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/go-feature-flag/flag-management/server/cmd"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlConfigurationFile = `flag1:
+  variations:
+    A: a
+    B: b
+  targeting:
+    - name: beta
+      query: beta eq true
+      variation: B
+  defaultRule:
+    variation: A
+flag2:
+  variations:
+    "on": true
+    "off": false
+  defaultRule:
+    variation: "off"
+`
+
+func writeConfigurationFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestExecuteImport(t *testing.T) {
+	input := writeConfigurationFile(t, "flags.yaml", yamlConfigurationFile)
+	tests := []struct {
+		name          string
+		args          []string
+		wantErr       assert.ErrorAssertionFunc
+		expectedErr   string
+		expectedOut   string
+		expectedFlags int
+	}{
+		{
+			name:    "should import all the flags of the file",
+			args:    []string{"import", "--input", input},
+			wantErr: assert.NoError,
+			expectedOut: `{
+  "dryRun": false,
+  "created": [
+    "flag1",
+    "flag2"
+  ],
+  "updated": [],
+  "unchanged": []
+}
+`,
+			expectedFlags: 2,
+		},
+		{
+			name:    "should not store anything in dry run mode",
+			args:    []string{"import", "--input", input, "--dryRun"},
+			wantErr: assert.NoError,
+			expectedOut: `{
+  "dryRun": true,
+  "created": [
+    "flag1",
+    "flag2"
+  ],
+  "updated": [],
+  "unchanged": []
+}
+`,
+			expectedFlags: 0,
+		},
+		{
+			name:        "should return an error if the input is missing",
+			args:        []string{"import"},
+			wantErr:     assert.Error,
+			expectedErr: "input is required",
+		},
+		{
+			name:        "should return an error if the environment does not exist",
+			args:        []string{"import", "--input", input, "--environment", "qa"},
+			wantErr:     assert.Error,
+			expectedErr: "environment qa not found in project default",
+		},
+		{
+			name:        "should return an error if a flag is unknown",
+			args:        []string{"import", "--input", input, "--unknown"},
+			wantErr:     assert.Error,
+			expectedErr: "invalid arguments: unknown flag: --unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			output := &bytes.Buffer{}
+			err = cmd.Execute(tt.args, cmd.APICommandOptions{OverrideDefaultDao: mockDao, Output: output})
+			tt.wantErr(t, err)
+			if tt.expectedErr != "" {
+				assert.Equal(t, tt.expectedErr, err.Error())
+				return
+			}
+			assert.Equal(t, tt.expectedOut, output.String())
+
+			flags, total, daoErr := mockDao.GetFlags(context.Background(), dao.FlagQuery{})
+			require.Nil(t, daoErr)
+			assert.Equal(t, tt.expectedFlags, total)
+			for _, flag := range flags {
+				assert.Equal(t, "cli", flag.LastModifiedBy)
+			}
+		})
+	}
+}
+
+func TestExecuteImportWithMemoryStorage(t *testing.T) {
+	input := writeConfigurationFile(t, "flags.yaml", yamlConfigurationFile)
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.json")
+	options := cmd.APICommandOptions{Output: &bytes.Buffer{}}
+
+	args := []string{"import", "--input", input, "--storage.type", "memory", "--storage.snapshotFile", snapshotFile}
+	require.NoError(t, cmd.Execute(args, options))
+
+	// the flags imported are reloaded from the snapshot
+	output := &bytes.Buffer{}
+	options.Output = output
+	args = []string{"export", "--storage.type", "memory", "--storage.snapshotFile", snapshotFile}
+	require.NoError(t, cmd.Execute(args, options))
+	assert.Contains(t, output.String(), `"flag1"`)
+	assert.Contains(t, output.String(), `"flag2"`)
+}
+
+func TestExecuteExport(t *testing.T) {
+	mockDao, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	mockDao.SetFlags([]model.FeatureFlag{
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+			Name:          "flag1",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils.String("A")},
+		},
+	})
+	options := cmd.APICommandOptions{OverrideDefaultDao: mockDao}
+
+	t.Run("should write the flags on the output", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		options.Output = output
+		require.NoError(t, cmd.Execute([]string{"export"}, options))
+		assert.JSONEq(t, `{"flag1":{"variations":{"A":"a","B":"b"},"defaultRule":{"variation":"A"}}}`, output.String())
+	})
+
+	t.Run("should write the flags in a file with the format of its extension", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "flags.yaml")
+		require.NoError(t, cmd.Execute([]string{"export", "--output", path}, options))
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "flag1:\n  variations:\n    A: a\n    B: b\n  defaultRule:\n    variation: A\n", string(content))
+	})
+
+	t.Run("should return an error if the format of the file is unknown", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "flags.txt")
+		err := cmd.Execute([]string{"export", "--output", path}, options)
+		assert.EqualError(t, err, "impossible to guess the format of "+path+", use the --format flag")
+	})
+
+	t.Run("should close the storage opened by the command", func(t *testing.T) {
+		storage := &closableStorage{FlagStorage: mockDao}
+		closableOptions := cmd.APICommandOptions{
+			Output: &bytes.Buffer{},
+			Storages: map[string]dao.StorageFactory{
+				"closable": func(string) (dao.FlagStorage, error) { return storage, nil },
+			},
+		}
+		args := []string{"export", "--storage.connectionString", "closable://"}
+		require.NoError(t, cmd.Execute(args, closableOptions))
+		assert.True(t, storage.closed)
+	})
+}
+
+// closableStorage records if the command closed the storage
+type closableStorage struct {
+	dao.FlagStorage
+	closed bool
+}
+
+func (s *closableStorage) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestExecuteValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		content     string
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			name:        "should validate a valid file",
+			fileName:    "flags.yaml",
+			content:     yamlConfigurationFile,
+			expectedOut: " is valid (2 flags)\n",
+		},
+		{
+			name:        "should return all the invalid flags",
+			fileName:    "flags.json",
+			content:     `{"flag1":{"variations":{"A":"a","B":true}},"flag2":{"variations":{"A":"a"}}}`,
+			expectedErr: "flag flag1: variation B is a boolean but other variations are string\nflag flag2: defaultRule: flag default rule is required",
+		},
+		{
+			name:        "should return an error if the file cannot be parsed",
+			fileName:    "flags.json",
+			content:     `{"flag1":`,
+			expectedErr: "invalid configuration file: unexpected end of JSON input",
+		},
+		{
+			name:        "should use the format flag before the extension of the file",
+			fileName:    "flags.txt",
+			content:     `{"flag1":{"variations":{"A":"a"},"defaultRule":{"variation":"A"}}}`,
+			args:        []string{"--format", "json"},
+			expectedOut: " is valid (1 flags)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := writeConfigurationFile(t, tt.fileName, tt.content)
+			output := &bytes.Buffer{}
+			args := append([]string{"validate", "--input", input}, tt.args...)
+			err := cmd.Execute(args, cmd.APICommandOptions{Output: output})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, input+tt.expectedOut, output.String())
+		})
+	}
+}
+
+func TestExecuteUnknownCommand(t *testing.T) {
+	err := cmd.Execute([]string{"migrate"}, cmd.APICommandOptions{})
+	assert.EqualError(t, err, "unknown command migrate, use serve, export, import or validate")
+}
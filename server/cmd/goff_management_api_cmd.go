@@ -1,22 +1,60 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-feature-flag/flag-management/server/api"
 	"github.com/go-feature-flag/flag-management/server/config"
 	"github.com/go-feature-flag/flag-management/server/dao"
-	"github.com/go-feature-flag/flag-management/server/dao/pgimpl"
+	// the storages of this repository are added to the registry of dao when their package is imported
+	_ "github.com/go-feature-flag/flag-management/server/dao/gitimpl"
+	_ "github.com/go-feature-flag/flag-management/server/dao/memoryimpl"
+	_ "github.com/go-feature-flag/flag-management/server/dao/mysqlimpl"
+	_ "github.com/go-feature-flag/flag-management/server/dao/pgimpl"
+	_ "github.com/go-feature-flag/flag-management/server/dao/sqliteimpl"
 	"github.com/go-feature-flag/flag-management/server/handler"
 	"github.com/go-feature-flag/flag-management/server/log"
+	"github.com/go-feature-flag/flag-management/server/scheduler"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
+	"go.uber.org/zap"
 )
 
 type APICommandOptions struct {
 	// OverrideDefaultDao is used to override the default dao used by the API server
 	// This is useful for testing purpose to provide a mock dao
 	OverrideDefaultDao dao.FlagStorage
+
+	// Args are the arguments of the command, without the name of the binary and of the subcommand.
+	// Default is os.Args[1:]
+	Args []string
+
+	// Output is where the subcommands write their result.
+	// Default is os.Stdout
+	Output io.Writer
+
+	// Storages are added to the storages of this repository, they are selected by the scheme of the
+	// storage.connectionString flag. It allows to use another FlagStorage without forking the command,
+	// a storage registered with the scheme of a built-in storage replaces it.
+	Storages map[string]dao.StorageFactory
+}
+
+func (o APICommandOptions) args() []string {
+	if o.Args == nil {
+		return os.Args[1:]
+	}
+	return o.Args
+}
+
+func (o APICommandOptions) output() io.Writer {
+	if o.Output == nil {
+		return os.Stdout
+	}
+	return o.Output
 }
 
 // NewGOFeatureFlagManagementAPICommand creates a new instance of the GOFeatureFlagManagementAPICommand
@@ -37,9 +75,22 @@ type GOFeatureFlagManagementAPICommand struct {
 	apiServer     *api.Server
 	options       APICommandOptions
 	configuration *config.Configuration
+
+	// storage is the storage of the flags, it is closed when the API server stops
+	storage dao.FlagStorage
+
+	// scheduler applies the scheduled changes of the flags, it is nil if the storage cannot store them
+	// or if the worker is disabled in the configuration
+	scheduler *scheduler.Worker
 }
 
 func (g *GOFeatureFlagManagementAPICommand) Run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if g.scheduler != nil {
+		go g.scheduler.Start(ctx)
+	}
+	defer closeStorage(g.options, g.storage)
 	g.apiServer.Start()
 	defer func() { _ = g.apiServer.Stop() }()
 }
@@ -51,10 +102,23 @@ func (g *GOFeatureFlagManagementAPICommand) initDependencies() error {
 
 	//init config
 	f := pflag.NewFlagSet("config", pflag.ContinueOnError)
-	f.String("postgresConnectionString", "", "Connection string to connect to the postgres database")
+	addStorageFlags(f)
 	f.String("serverAddress", ":3001", "Address where the API server will listen")
 	f.String("mode", "production", "Application mode (development or production)")
-	_ = f.Parse(os.Args[1:])
+	f.String("auth.issuer", "", "Expected issuer of the tokens, used to discover the JWKS URL if no other key is configured")
+	f.StringSlice("auth.audience", nil, "Accepted audiences of the tokens")
+	f.String("auth.jwksURL", "", "URL of the JSON Web Key Set used to verify the tokens")
+	f.String("auth.jwksFile", "", "Path to a local JSON Web Key Set file used to verify the tokens")
+	f.Duration("auth.jwksRefreshInterval", time.Hour, "Maximum time we keep the keys of the JWKS URL before fetching them again")
+	f.String("auth.hmacSecret", "", "Shared secret used to verify tokens signed with HMAC")
+	f.String("auth.publicKeyFile", "", "Path to a PEM encoded public key used to verify the tokens")
+	f.String("auth.nameClaim", "name", "Claim of the token used as the name of the user in the audit trail")
+	f.String("auth.roleClaim", "roles", "Claim of the token containing the roles of the user")
+	f.StringToString("auth.roleMapping", nil, "Mapping from the values of the role claim to the roles (viewer, editor or admin)")
+	f.String("auth.defaultRole", "viewer", "Role of the users without any role in their token")
+	f.Duration("scheduler.interval", scheduler.DefaultInterval,
+		"Time between two checks of the scheduled changes of the flags, 0 disables the scheduler")
+	_ = f.Parse(g.options.args())
 
 	c, err := config.LoadConfiguration(f)
 	if err != nil {
@@ -63,10 +127,17 @@ func (g *GOFeatureFlagManagementAPICommand) initDependencies() error {
 	g.configuration = c
 
 	// init database connection
-	databaseDao, err := g.initDatabaseAccess()
+	databaseDao, err := initDatabaseAccess(g.options, g.configuration)
 	if err != nil {
 		return fmt.Errorf("impossible to initialize database connection: %w", err)
 	}
+	g.storage = databaseDao
+
+	// init the worker applying the scheduled changes
+	if changeDao, ok := databaseDao.(dao.ScheduledChangeStorage); ok && g.configuration.Scheduler.Interval > 0 {
+		g.scheduler = scheduler.NewWorker(databaseDao, changeDao,
+			&scheduler.WorkerOptions{Interval: g.configuration.Scheduler.Interval})
+	}
 
 	// init API handlers
 	apiHandlers, err := handler.InitHandlers(databaseDao)
@@ -81,13 +152,62 @@ func (g *GOFeatureFlagManagementAPICommand) initDependencies() error {
 	return nil
 }
 
-func (g *GOFeatureFlagManagementAPICommand) initDatabaseAccess() (dao.FlagStorage, error) {
-	if g.options.OverrideDefaultDao != nil {
-		return g.options.OverrideDefaultDao, nil
+// addStorageFlags adds the flags used to connect to the database
+func addStorageFlags(f *pflag.FlagSet) {
+	f.String("storage.connectionString", "",
+		"Connection string of the storage, its scheme selects the storage "+
+			"(postgres://, mysql://, sqlite://, git://, file:// or memory://)")
+	f.String("storage.type", string(config.StoragePostgres),
+		"Deprecated, use storage.connectionString: "+
+			"type of storage used to persist the flags (postgres, mysql, sqlite or memory)")
+	f.String("storage.snapshotFile", "", "Deprecated, use storage.connectionString=file://<file>: "+
+		"JSON file where the memory storage persists the flags")
+	f.String("storage.sqliteFile", "", "Deprecated, use storage.connectionString=sqlite://<file>: "+
+		"database file of the sqlite storage")
+	f.String("storage.mysqlConnectionString", "", "Deprecated, use storage.connectionString=mysql://<dsn>: "+
+		"connection string to connect to the mysql database")
+	f.String("postgresConnectionString", "", "Deprecated, use storage.connectionString=postgres://<dsn>: "+
+		"connection string to connect to the postgres database")
+}
+
+func initDatabaseAccess(options APICommandOptions, c *config.Configuration) (dao.FlagStorage, error) {
+	if options.OverrideDefaultDao != nil {
+		return options.OverrideDefaultDao, nil
 	}
-	databaseDao, err := pgimpl.NewPostgresDao(g.configuration.PostgresConnectionString)
+	registry, err := newStorageRegistry(options)
 	if err != nil {
 		return nil, err
 	}
-	return databaseDao, nil
+	return registry.Open(c.Storage.ConnectionString)
+}
+
+// closeStorage closes the connections of the storage opened by initDatabaseAccess, the storage given
+// in the options is owned by the caller and is not closed.
+func closeStorage(options APICommandOptions, storage dao.FlagStorage) {
+	if options.OverrideDefaultDao != nil {
+		return
+	}
+	if closer, ok := storage.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			zap.L().Error("impossible to close the storage", zap.Error(err))
+		}
+	}
+}
+
+// newStorageRegistry returns the registry of the storages of dao and of the storages of the options.
+func newStorageRegistry(options APICommandOptions) (*dao.StorageRegistry, error) {
+	factories := map[string]dao.StorageFactory{}
+	for _, scheme := range dao.Schemes() {
+		factories[scheme], _ = dao.Factory(scheme)
+	}
+	for scheme, factory := range options.Storages {
+		factories[strings.ToLower(scheme)] = factory
+	}
+	registry := dao.NewStorageRegistry()
+	for scheme, factory := range factories {
+		if err := registry.Register(scheme, factory); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
 }
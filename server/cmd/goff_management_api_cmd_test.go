@@ -1,9 +1,12 @@
 package cmd_test
 
 import (
+	"fmt"
 	"github.com/go-feature-flag/flag-management/server/cmd"
 	"github.com/go-feature-flag/flag-management/server/dao"
 	"net/http"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -33,6 +36,109 @@ func TestNewGOFeatureFlagManagementAPICommand(t *testing.T) {
 			wantErr:        assert.Error,
 			expectedErrMsg: "error while initializing dependencies: impossible to initialize database connection: connection string is empty",
 		},
+		{
+			name:    "should use the memory storage if configured",
+			options: cmd.APICommandOptions{Args: []string{"--storage.type", "memory"}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:           "should have an error if the sqlite schema is not migrated",
+			options:        cmd.APICommandOptions{Args: []string{"--storage.type", "sqlite", "--storage.sqliteFile", ":memory:"}},
+			wantErr:        assert.Error,
+			expectedErrMsg: "error while initializing dependencies: impossible to initialize database connection: schema not migrated, run the migrations of database_migration/sqlite on :memory:",
+		},
+		{
+			name:           "should have an error if the sqlite file is missing",
+			options:        cmd.APICommandOptions{Args: []string{"--storage.type", "sqlite"}},
+			wantErr:        assert.Error,
+			expectedErrMsg: "error while initializing dependencies: impossible to initialize database connection: connection string is empty",
+		},
+		{
+			name: "should have an error if the mysql connection string is invalid",
+			options: cmd.APICommandOptions{
+				Args: []string{"--storage.type", "mysql", "--storage.mysqlConnectionString", "localhost:3306"}},
+			wantErr:        assert.Error,
+			expectedErrMsg: "error while initializing dependencies: impossible to initialize database connection: invalid connection string: invalid DSN: missing the slash separating the database name",
+		},
+		{
+			name:           "should have an error if the storage type is unknown",
+			options:        cmd.APICommandOptions{Args: []string{"--storage.type", "mongodb"}},
+			wantErr:        assert.Error,
+			expectedErrMsg: "error while initializing dependencies: impossible to initialize database connection: storage scheme mongodb not supported, use one of file, git, memory, mysql, postgres, postgresql, sqlite",
+		},
+		{
+			name:    "should select the storage with the scheme of the connection string",
+			options: cmd.APICommandOptions{Args: []string{"--storage.connectionString", "memory://"}},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should prefer the connection string to the storage type",
+			options: cmd.APICommandOptions{
+				Args: []string{"--storage.type", "mongodb", "--storage.connectionString", "memory://"}},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should translate the deprecated options of the memory storage into a connection string",
+			options: cmd.APICommandOptions{
+				Args: []string{"--storage.type", "memory", "--storage.snapshotFile", "flags-snapshot.json"},
+				Storages: map[string]dao.StorageFactory{
+					"file": func(connectionString string) (dao.FlagStorage, error) {
+						if connectionString != "file://flags-snapshot.json" {
+							return nil, fmt.Errorf("unexpected connection string %s", connectionString)
+						}
+						return mockDao, nil
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should translate the deprecated postgres connection string into a connection string",
+			options: cmd.APICommandOptions{
+				Args: []string{"--postgresConnectionString", "host=localhost dbname=flags"},
+				Storages: map[string]dao.StorageFactory{
+					"postgres": func(connectionString string) (dao.FlagStorage, error) {
+						if connectionString != "postgres://host=localhost dbname=flags" {
+							return nil, fmt.Errorf("unexpected connection string %s", connectionString)
+						}
+						return mockDao, nil
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:           "should have an error if the file storage has no snapshot file",
+			options:        cmd.APICommandOptions{Args: []string{"--storage.connectionString", "file://"}},
+			wantErr:        assert.Error,
+			expectedErrMsg: "error while initializing dependencies: impossible to initialize database connection: snapshot file is empty",
+		},
+		{
+			name: "should use a storage added in the options",
+			options: cmd.APICommandOptions{
+				Args: []string{"--storage.connectionString", "custom://flags"},
+				Storages: map[string]dao.StorageFactory{
+					"custom": func(_ string) (dao.FlagStorage, error) { return mockDao, nil },
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should select the storage type in the registry",
+			options: cmd.APICommandOptions{
+				Args: []string{"--storage.type", "custom"},
+				Storages: map[string]dao.StorageFactory{
+					"custom": func(_ string) (dao.FlagStorage, error) { return mockDao, nil },
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:           "should have an error if the scheme of the connection string is unknown",
+			options:        cmd.APICommandOptions{Args: []string{"--storage.connectionString", "redis://localhost:6379"}},
+			wantErr:        assert.Error,
+			expectedErrMsg: "error while initializing dependencies: impossible to initialize database connection: storage scheme redis not supported, use one of file, git, memory, mysql, postgres, postgresql, sqlite",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -46,6 +152,17 @@ func TestNewGOFeatureFlagManagementAPICommand(t *testing.T) {
 	}
 }
 
+func TestNewGOFeatureFlagManagementAPICommandWithGitStorage(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	workingCopy := t.TempDir()
+	_, err := cmd.NewGOFeatureFlagManagementAPICommand(cmd.APICommandOptions{
+		Args: []string{"--storage.connectionString", "git://" + workingCopy + "?branch=main"}})
+	require.NoError(t, err)
+	assert.DirExists(t, filepath.Join(workingCopy, ".git"), "the working copy should be initialized")
+}
+
 func TestNewGOFeatureFlagManagementAPICommandShouldStartAPI(t *testing.T) {
 	mockDao, _ := dao.NewInMemoryMockDao()
 	defaultOptions := cmd.APICommandOptions{
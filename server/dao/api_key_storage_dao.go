@@ -0,0 +1,29 @@
+package dao
+
+import (
+	"context"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+// APIKeyStorage is implemented by the storages able to persist the API keys.
+// It is optional, the API keys endpoints and the API key authentication are only available
+// when the storage used implements it.
+type APIKeyStorage interface {
+	// GetAPIKeys return all the API keys (the most recent first)
+	GetAPIKeys(ctx context.Context) ([]model.APIKey, daoErr.DaoError)
+
+	// GetAPIKeyByHashedKey return the API key matching the hash of the key
+	GetAPIKeyByHashedKey(ctx context.Context, hashedKey string) (model.APIKey, daoErr.DaoError)
+
+	// CreateAPIKey create a new API key, return the id of the API key
+	CreateAPIKey(ctx context.Context, apiKey model.APIKey) (string, daoErr.DaoError)
+
+	// DeleteAPIKeyByID revoke an API key, it returns a NotFound error if the key does not exist
+	DeleteAPIKeyByID(ctx context.Context, id string) daoErr.DaoError
+
+	// UpdateAPIKeyLastUsedDate record the last time the API key has been used
+	UpdateAPIKeyLastUsedDate(ctx context.Context, id string, date time.Time) daoErr.DaoError
+}
@@ -0,0 +1,68 @@
+package dbmodel
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+)
+
+type APIKey struct {
+	ID           uuid.UUID  `db:"id"`
+	Name         string     `db:"name"`
+	Prefix       string     `db:"prefix"`
+	HashedKey    string     `db:"hashed_key"`
+	Scopes       string     `db:"scopes"`
+	CreatedDate  time.Time  `db:"created_date"`
+	CreatedBy    string     `db:"created_by"`
+	ExpiresAt    *time.Time `db:"expires_at"`
+	LastUsedDate *time.Time `db:"last_used_date"`
+}
+
+// FromModelAPIKey converts a model.APIKey to a dbmodel.APIKey, the scopes are stored as a comma separated list.
+func FromModelAPIKey(key model.APIKey) (APIKey, error) {
+	id, err := uuid.Parse(key.ID)
+	if err != nil {
+		return APIKey{}, err
+	}
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return APIKey{
+		ID:           id,
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		HashedKey:    key.HashedKey,
+		Scopes:       strings.Join(scopes, ","),
+		CreatedDate:  key.CreatedDate,
+		CreatedBy:    key.CreatedBy,
+		ExpiresAt:    key.ExpiresAt,
+		LastUsedDate: key.LastUsedDate,
+	}, nil
+}
+
+func (k *APIKey) ToModelAPIKey() (model.APIKey, error) {
+	scopes := []model.APIKeyScope{}
+	if k.Scopes != "" {
+		for _, value := range strings.Split(k.Scopes, ",") {
+			scope, err := model.APIKeyScopeFromValue(value)
+			if err != nil {
+				return model.APIKey{}, err
+			}
+			scopes = append(scopes, scope)
+		}
+	}
+	return model.APIKey{
+		ID:           k.ID.String(),
+		Name:         k.Name,
+		Prefix:       k.Prefix,
+		HashedKey:    k.HashedKey,
+		Scopes:       scopes,
+		CreatedDate:  k.CreatedDate,
+		CreatedBy:    k.CreatedBy,
+		ExpiresAt:    k.ExpiresAt,
+		LastUsedDate: k.LastUsedDate,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package dbmodel_test
+
+import (
+	dbmodel2 "github.com/go-feature-flag/flag-management/server/dao/dbmodel"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromModelAPIKey(t *testing.T) {
+	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		apiKey  model.APIKey
+		want    dbmodel2.APIKey
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "should convert model.APIKey to dbmodel.APIKey",
+			apiKey: model.APIKey{
+				ID:          id.String(),
+				Name:        "ci",
+				Prefix:      "goff_abc",
+				HashedKey:   "hash",
+				Scopes:      []model.APIKeyScope{model.APIKeyScopeRead, model.APIKeyScopeWrite},
+				CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBy:   "foo",
+				ExpiresAt:   &expiresAt,
+			},
+			want: dbmodel2.APIKey{
+				ID:          id,
+				Name:        "ci",
+				Prefix:      "goff_abc",
+				HashedKey:   "hash",
+				Scopes:      "read,write",
+				CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBy:   "foo",
+				ExpiresAt:   &expiresAt,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "should return an error if the ID is not a UUID",
+			apiKey:  model.APIKey{ID: "invalid"},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dbmodel2.FromModelAPIKey(tt.apiKey)
+			tt.wantErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAPIKey_ToModelAPIKey(t *testing.T) {
+	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+	t.Run("should convert dbmodel.APIKey to model.APIKey", func(t *testing.T) {
+		dbKey := dbmodel2.APIKey{ID: id, Name: "ci", Scopes: "read", HashedKey: "hash"}
+		got, err := dbKey.ToModelAPIKey()
+		require.NoError(t, err)
+		assert.Equal(t, model.APIKey{
+			ID:        id.String(),
+			Name:      "ci",
+			HashedKey: "hash",
+			Scopes:    []model.APIKeyScope{model.APIKeyScopeRead},
+		}, got)
+	})
+
+	t.Run("should return an empty list of scopes", func(t *testing.T) {
+		dbKey := dbmodel2.APIKey{ID: id}
+		got, err := dbKey.ToModelAPIKey()
+		require.NoError(t, err)
+		assert.Empty(t, got.Scopes)
+	})
+
+	t.Run("should return an error with an unknown scope", func(t *testing.T) {
+		dbKey := dbmodel2.APIKey{ID: id, Scopes: "read,admin"}
+		_, err := dbKey.ToModelAPIKey()
+		assert.Error(t, err)
+	})
+}
@@ -9,19 +9,24 @@ import (
 )
 
 type FeatureFlag struct {
-	ID              uuid.UUID      `db:"id"`
-	Name            string         `db:"name"`
-	Description     *string        `db:"description"`
-	Variations      JSONB          `db:"variations"`
-	Type            model.FlagType `db:"type"`
-	BucketingKey    *string        `db:"bucketing_key"`
-	Metadata        JSONB          `db:"metadata"`
-	TrackEvents     *bool          `db:"track_events"`
-	Disable         *bool          `db:"disable"`
-	Version         *string        `db:"version"`
-	CreatedDate     time.Time      `db:"created_date"`
-	LastUpdatedDate time.Time      `db:"last_updated_date"`
-	LastModifiedBy  string         `db:"last_modified_by"`
+	ID                   uuid.UUID      `db:"id"`
+	Name                 string         `db:"name"`
+	Project              string         `db:"project"`
+	Environment          string         `db:"environment"`
+	Description          *string        `db:"description"`
+	Variations           JSONB          `db:"variations"`
+	Type                 model.FlagType `db:"type"`
+	BucketingKey         *string        `db:"bucketing_key"`
+	Metadata             JSONB          `db:"metadata"`
+	TrackEvents          *bool          `db:"track_events"`
+	Disable              *bool          `db:"disable"`
+	Version              *string        `db:"version"`
+	ExperimentationStart *time.Time     `db:"experimentation_start"`
+	ExperimentationEnd   *time.Time     `db:"experimentation_end"`
+	CreatedDate          time.Time      `db:"created_date"`
+	LastUpdatedDate      time.Time      `db:"last_updated_date"`
+	LastModifiedBy       string         `db:"last_modified_by"`
+	Revision             int64          `db:"revision"`
 }
 
 func FromModelFeatureFlag(mff model.FeatureFlag) (FeatureFlag, error) {
@@ -33,6 +38,8 @@ func FromModelFeatureFlag(mff model.FeatureFlag) (FeatureFlag, error) {
 	ff := FeatureFlag{
 		ID:              id,
 		Name:            mff.Name,
+		Project:         mff.GetProject(),
+		Environment:     mff.GetEnvironment(),
 		Description:     mff.Description,
 		Type:            mff.VariationType,
 		BucketingKey:    mff.BucketingKey,
@@ -42,6 +49,7 @@ func FromModelFeatureFlag(mff model.FeatureFlag) (FeatureFlag, error) {
 		CreatedDate:     mff.CreatedDate,
 		LastUpdatedDate: mff.LastUpdatedDate,
 		LastModifiedBy:  mff.LastModifiedBy,
+		Revision:        mff.Revision,
 	}
 	if mff.Variations != nil {
 		ff.Variations = JSONB(*mff.Variations)
@@ -49,6 +57,10 @@ func FromModelFeatureFlag(mff model.FeatureFlag) (FeatureFlag, error) {
 	if mff.Metadata != nil {
 		ff.Metadata = JSONB(*mff.Metadata)
 	}
+	if mff.Experimentation != nil {
+		ff.ExperimentationStart = mff.Experimentation.Start
+		ff.ExperimentationEnd = mff.Experimentation.End
+	}
 	return ff, nil
 }
 
@@ -77,9 +89,15 @@ func (ff *FeatureFlag) ToModelFeatureFlag(rules []Rule) (model.FeatureFlag, erro
 	if ff.Metadata != nil {
 		metadata = ff.Metadata
 	}
+	var experimentation *model.ExperimentationRollout
+	if ff.ExperimentationStart != nil || ff.ExperimentationEnd != nil {
+		experimentation = &model.ExperimentationRollout{Start: ff.ExperimentationStart, End: ff.ExperimentationEnd}
+	}
 	return model.FeatureFlag{
 		ID:              ff.ID.String(),
 		Name:            ff.Name,
+		Project:         ff.Project,
+		Environment:     ff.Environment,
 		Description:     ff.Description,
 		Variations:      &variations,
 		VariationType:   ff.Type,
@@ -88,10 +106,12 @@ func (ff *FeatureFlag) ToModelFeatureFlag(rules []Rule) (model.FeatureFlag, erro
 		TrackEvents:     ff.TrackEvents,
 		Disable:         ff.Disable,
 		Version:         ff.Version,
+		Experimentation: experimentation,
 		CreatedDate:     ff.CreatedDate,
 		LastUpdatedDate: ff.LastUpdatedDate,
 		Rules:           &apiRules,
 		DefaultRule:     defaultRule,
 		LastModifiedBy:  ff.LastModifiedBy,
+		Revision:        ff.Revision,
 	}, nil
 }
@@ -51,6 +51,8 @@ func TestFromModelFeatureFlag(t *testing.T) {
 			want: dbmodel2.FeatureFlag{
 				ID:          flagID,
 				Name:        "my-flag",
+				Project:     model.DefaultProject,
+				Environment: model.DefaultEnvironment,
 				Description: testutils.String("my flag description"),
 				Variations: dbmodel2.JSONB(map[string]interface{}{
 					"A": "a",
@@ -94,6 +96,8 @@ func TestFromModelFeatureFlag(t *testing.T) {
 			want: dbmodel2.FeatureFlag{
 				ID:          flagID,
 				Name:        "my-flag",
+				Project:     model.DefaultProject,
+				Environment: model.DefaultEnvironment,
 				Description: testutils.String("my flag description"),
 				Variations: dbmodel2.JSONB(map[string]interface{}{
 					"A": "a",
@@ -131,6 +135,8 @@ func TestFromModelFeatureFlag(t *testing.T) {
 			want: dbmodel2.FeatureFlag{
 				ID:              flagID,
 				Name:            "my-flag",
+				Project:         model.DefaultProject,
+				Environment:     model.DefaultEnvironment,
 				Description:     testutils.String("my flag description"),
 				Variations:      nil,
 				Type:            model.FlagTypeBoolean,
@@ -278,19 +284,29 @@ func TestToModelFeatureFlag(t *testing.T) {
 					OrderIndex:    6,
 				},
 				{
-					ID:                                  ruleId2,
-					Name:                                "rule 2",
-					FeatureFlagID:                       flagID,
-					Query:                               testutils.String(`targetingKey eq "bar"`),
-					Disable:                             true,
-					OrderIndex:                          10,
-					IsDefault:                           false,
-					ProgressiveRolloutInitialVariation:  testutils.String("A"),
-					ProgressiveRolloutEndVariation:      testutils.String("B"),
-					ProgressiveRolloutInitialPercentage: testutils.Float64(0),
-					ProgressiveRolloutEndPercentage:     testutils.Float64(100),
-					ProgressiveRolloutStartDate:         testutils.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
-					ProgressiveRolloutEndDate:           testutils.Time(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+					ID:            ruleId2,
+					Name:          "rule 2",
+					FeatureFlagID: flagID,
+					Query:         testutils.String(`targetingKey eq "bar"`),
+					Disable:       true,
+					OrderIndex:    10,
+					IsDefault:     false,
+					ProgressiveRolloutSteps: []dbmodel2.ProgressiveRolloutStep{
+						{
+							RuleID:     ruleId2,
+							StepIndex:  0,
+							Variation:  "A",
+							Percentage: testutils.Float64(0),
+							Date:       time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						},
+						{
+							RuleID:     ruleId2,
+							StepIndex:  1,
+							Variation:  "B",
+							Percentage: testutils.Float64(100),
+							Date:       time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+						},
+					},
 				},
 				{
 					ID:              ruleIdDefault,
@@ -363,3 +379,28 @@ func TestToModelFeatureFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestFeatureFlag_Experimentation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	mff := model.FeatureFlag{
+		ID:              "123e4567-e89b-12d3-a456-426614174000",
+		Name:            "my-flag",
+		Experimentation: &model.ExperimentationRollout{Start: &start, End: &end},
+	}
+
+	dbFlag, err := dbmodel2.FromModelFeatureFlag(mff)
+	assert.NoError(t, err)
+	assert.Equal(t, &start, dbFlag.ExperimentationStart)
+	assert.Equal(t, &end, dbFlag.ExperimentationEnd)
+
+	defaultRule := dbmodel2.Rule{ID: uuid.New(), IsDefault: true, VariationResult: testutils.String("A")}
+	got, err := dbFlag.ToModelFeatureFlag([]dbmodel2.Rule{defaultRule})
+	assert.NoError(t, err)
+	assert.Equal(t, mff.Experimentation, got.Experimentation)
+
+	dbFlag.ExperimentationStart, dbFlag.ExperimentationEnd = nil, nil
+	got, err = dbFlag.ToModelFeatureFlag([]dbmodel2.Rule{defaultRule})
+	assert.NoError(t, err)
+	assert.Nil(t, got.Experimentation)
+}
@@ -0,0 +1,73 @@
+package dbmodel
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+)
+
+type FlagHistory struct {
+	ID            uuid.UUID `db:"id"`
+	FeatureFlagID uuid.UUID `db:"feature_flag_id"`
+	VersionNumber int       `db:"version_number"`
+	Action        string    `db:"action"`
+	Snapshot      JSONB     `db:"snapshot"`
+	CreatedDate   time.Time `db:"created_date"`
+}
+
+func FromModelFlagVersion(version model.FlagVersion) (FlagHistory, error) {
+	flagID, err := uuid.Parse(version.FlagID)
+	if err != nil {
+		return FlagHistory{}, err
+	}
+
+	var id uuid.UUID
+	if version.ID != "" {
+		if id, err = uuid.Parse(version.ID); err != nil {
+			return FlagHistory{}, err
+		}
+	} else {
+		id = uuid.New()
+	}
+
+	// the snapshot is stored with the same JSON representation as the one exposed by the API
+	b, err := json.Marshal(version.Flag)
+	if err != nil {
+		return FlagHistory{}, err
+	}
+	var snapshot JSONB
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return FlagHistory{}, err
+	}
+
+	return FlagHistory{
+		ID:            id,
+		FeatureFlagID: flagID,
+		VersionNumber: version.VersionNumber,
+		Action:        string(version.Action),
+		Snapshot:      snapshot,
+		CreatedDate:   version.CreatedDate,
+	}, nil
+}
+
+func (h *FlagHistory) ToModelFlagVersion() (model.FlagVersion, error) {
+	b, err := json.Marshal(h.Snapshot)
+	if err != nil {
+		return model.FlagVersion{}, err
+	}
+	var flag model.FeatureFlag
+	if err := json.Unmarshal(b, &flag); err != nil {
+		return model.FlagVersion{}, err
+	}
+
+	return model.FlagVersion{
+		ID:            h.ID.String(),
+		FlagID:        h.FeatureFlagID.String(),
+		VersionNumber: h.VersionNumber,
+		Action:        model.FlagVersionAction(h.Action),
+		CreatedDate:   h.CreatedDate,
+		Flag:          flag,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package dbmodel_test
+
+import (
+	dbmodel2 "github.com/go-feature-flag/flag-management/server/dao/dbmodel"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromModelFlagVersion(t *testing.T) {
+	flagID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	versionID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174999")
+
+	tests := []struct {
+		name    string
+		version model.FlagVersion
+		want    dbmodel2.FlagHistory
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "should convert model.FlagVersion to dbmodel.FlagHistory",
+			version: model.FlagVersion{
+				ID:            versionID.String(),
+				FlagID:        flagID.String(),
+				VersionNumber: 3,
+				Action:        model.FlagVersionActionUpdated,
+				CreatedDate:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				Flag: model.FeatureFlag{
+					ID:              flagID.String(),
+					Name:            "my-flag",
+					VariationType:   model.FlagTypeString,
+					CreatedDate:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					LastUpdatedDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+					LastModifiedBy:  "foo",
+					Variations: &map[string]interface{}{
+						"A": "a",
+						"B": "b",
+					},
+					DefaultRule: &model.Rule{
+						ID:              "123e4567-e89b-12d3-a456-426614174111",
+						VariationResult: testutils.String("A"),
+					},
+				},
+			},
+			wantErr: assert.NoError,
+			want: dbmodel2.FlagHistory{
+				ID:            versionID,
+				FeatureFlagID: flagID,
+				VersionNumber: 3,
+				Action:        "updated",
+				CreatedDate:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				Snapshot: dbmodel2.JSONB{
+					"id":              flagID.String(),
+					"name":            "my-flag",
+					"type":            "string",
+					"createdDate":     "2021-01-01T00:00:00Z",
+					"lastUpdatedDate": "2022-01-01T00:00:00Z",
+					"LastModifiedBy":  "foo",
+					"description":     nil,
+					"variations": map[string]interface{}{
+						"A": "a",
+						"B": "b",
+					},
+					"defaultRule": map[string]interface{}{
+						"id":        "123e4567-e89b-12d3-a456-426614174111",
+						"variation": "A",
+					},
+				},
+			},
+		},
+		{
+			name: "should return an error if the flag ID is not a valid UUID",
+			version: model.FlagVersion{
+				FlagID: "invalid-uuid",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "should return an error if the version ID is not a valid UUID",
+			version: model.FlagVersion{
+				ID:     "invalid-uuid",
+				FlagID: flagID.String(),
+			},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dbmodel2.FromModelFlagVersion(tt.version)
+			tt.wantErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFromModelFlagVersionGenerateID(t *testing.T) {
+	got, err := dbmodel2.FromModelFlagVersion(model.FlagVersion{
+		FlagID: "123e4567-e89b-12d3-a456-426614174000",
+		Action: model.FlagVersionActionCreated,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, got.ID)
+}
+
+func TestFlagHistory_ToModelFlagVersion(t *testing.T) {
+	flagID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	version := model.FlagVersion{
+		ID:            "123e4567-e89b-12d3-a456-426614174999",
+		FlagID:        flagID.String(),
+		VersionNumber: 1,
+		Action:        model.FlagVersionActionCreated,
+		CreatedDate:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Flag: model.FeatureFlag{
+			ID:              flagID.String(),
+			Name:            "my-flag",
+			VariationType:   model.FlagTypeBoolean,
+			CreatedDate:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastModifiedBy:  "foo",
+			Variations: &map[string]interface{}{
+				"enabled":  true,
+				"disabled": false,
+			},
+			Disable: testutils.Bool(false),
+			DefaultRule: &model.Rule{
+				ID: "123e4567-e89b-12d3-a456-426614174111",
+				Percentages: &map[string]float64{
+					"enabled":  10,
+					"disabled": 90,
+				},
+			},
+			Rules: &[]model.Rule{
+				{
+					ID:              "123e4567-e89b-12d3-a456-426614174222",
+					Name:            "rule 1",
+					Query:           `targetingKey eq "1234"`,
+					VariationResult: testutils.String("enabled"),
+				},
+			},
+		},
+	}
+
+	history, err := dbmodel2.FromModelFlagVersion(version)
+	require.NoError(t, err)
+	got, err := history.ToModelFlagVersion()
+	require.NoError(t, err)
+	assert.Equal(t, version, got)
+}
@@ -12,11 +12,14 @@ func (a JSONB) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
 
+// Scan reads a JSON value, the drivers storing JSON as text (like SQLite) return a string instead of bytes.
 func (a *JSONB) Scan(value interface{}) error {
-	b, ok := value.([]byte)
-	if !ok {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, &a)
+	case string:
+		return json.Unmarshal([]byte(v), &a)
+	default:
 		return errors.New("type assertion to []byte failed")
 	}
-
-	return json.Unmarshal(b, &a)
 }
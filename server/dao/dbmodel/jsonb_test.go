@@ -35,6 +35,13 @@ func TestJSONBUnmarshalling(t *testing.T) {
 	assert.Equal(t, dbmodel.JSONB(theTestMap), theTest)
 }
 
+func TestJSONBUnmarshalling_string(t *testing.T) {
+	var theTest dbmodel.JSONB
+	err := theTest.Scan(theTestString)
+	assert.NoError(t, err)
+	assert.Equal(t, dbmodel.JSONB(theTestMap), theTest)
+}
+
 func TestJSONBUnmarshalling_wrongType(t *testing.T) {
 	var theTest dbmodel.JSONB
 	err := theTest.Scan(15)
@@ -0,0 +1,54 @@
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+type Project struct {
+	Name        string    `db:"name"`
+	Description *string   `db:"description"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+func FromModelProject(project model.Project) Project {
+	return Project{
+		Name:        project.Name,
+		Description: project.Description,
+		CreatedDate: project.CreatedDate,
+	}
+}
+
+func (p *Project) ToModelProject() model.Project {
+	return model.Project{
+		Name:        p.Name,
+		Description: p.Description,
+		CreatedDate: p.CreatedDate,
+	}
+}
+
+type Environment struct {
+	Project     string    `db:"project"`
+	Name        string    `db:"name"`
+	Description *string   `db:"description"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+func FromModelEnvironment(environment model.Environment) Environment {
+	return Environment{
+		Project:     environment.Project,
+		Name:        environment.Name,
+		Description: environment.Description,
+		CreatedDate: environment.CreatedDate,
+	}
+}
+
+func (e *Environment) ToModelEnvironment() model.Environment {
+	return model.Environment{
+		Project:     e.Project,
+		Name:        e.Name,
+		Description: e.Description,
+		CreatedDate: e.CreatedDate,
+	}
+}
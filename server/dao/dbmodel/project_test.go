@@ -0,0 +1,41 @@
+package dbmodel_test
+
+import (
+	dbmodel2 "github.com/go-feature-flag/flag-management/server/dao/dbmodel"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectConversion(t *testing.T) {
+	project := model.Project{
+		Name:        "checkout",
+		Description: testutils.String("checkout flow"),
+		CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	dbProject := dbmodel2.FromModelProject(project)
+	assert.Equal(t, dbmodel2.Project{
+		Name:        "checkout",
+		Description: testutils.String("checkout flow"),
+		CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, dbProject)
+	assert.Equal(t, project, dbProject.ToModelProject())
+}
+
+func TestEnvironmentConversion(t *testing.T) {
+	environment := model.Environment{
+		Project:     "checkout",
+		Name:        "production",
+		CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	dbEnvironment := dbmodel2.FromModelEnvironment(environment)
+	assert.Equal(t, dbmodel2.Environment{
+		Project:     "checkout",
+		Name:        "production",
+		CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, dbEnvironment)
+	assert.Equal(t, environment, dbEnvironment.ToModelEnvironment())
+}
@@ -8,21 +8,25 @@ import (
 )
 
 type Rule struct {
-	ID                                  uuid.UUID  `db:"id"`
-	FeatureFlagID                       uuid.UUID  `db:"feature_flag_id"`
-	IsDefault                           bool       `db:"is_default"`
-	Name                                string     `db:"name"`
-	Query                               *string    `db:"query"`
-	VariationResult                     *string    `db:"variation_result"`
-	Percentages                         *JSONB     `db:"percentages"` // JSONB is stored as string
-	Disable                             bool       `db:"disable"`
-	ProgressiveRolloutInitialVariation  *string    `db:"progressive_rollout_initial_variation"`
-	ProgressiveRolloutEndVariation      *string    `db:"progressive_rollout_end_variation"`
-	ProgressiveRolloutInitialPercentage *float64   `db:"progressive_rollout_initial_percentage"`
-	ProgressiveRolloutEndPercentage     *float64   `db:"progressive_rollout_end_percentage"`
-	ProgressiveRolloutStartDate         *time.Time `db:"progressive_rollout_start_date"`
-	ProgressiveRolloutEndDate           *time.Time `db:"progressive_rollout_end_date"`
-	OrderIndex                          int        `db:"order_index"`
+	ID              uuid.UUID `db:"id"`
+	FeatureFlagID   uuid.UUID `db:"feature_flag_id"`
+	IsDefault       bool      `db:"is_default"`
+	Name            string    `db:"name"`
+	Query           *string   `db:"query"`
+	VariationResult *string   `db:"variation_result"`
+	Percentages     *JSONB    `db:"percentages"` // JSONB is stored as string
+	Disable         bool      `db:"disable"`
+	OrderIndex      int       `db:"order_index"`
+	// ProgressiveRolloutSteps are stored in the progressive_rollout_steps table.
+	ProgressiveRolloutSteps []ProgressiveRolloutStep `db:"-"`
+}
+
+type ProgressiveRolloutStep struct {
+	RuleID     uuid.UUID `db:"rule_id"`
+	StepIndex  int       `db:"step_index"`
+	Variation  string    `db:"variation"`
+	Percentage *float64  `db:"percentage"`
+	Date       time.Time `db:"date"`
 }
 
 func FromModelRule(mr model.Rule, featureFlagID uuid.UUID, isDefault bool, orderIndex int) (Rule, error) {
@@ -67,12 +71,16 @@ func FromModelRule(mr model.Rule, featureFlagID uuid.UUID, isDefault bool, order
 	}
 
 	if mr.ProgressiveRollout != nil {
-		dbr.ProgressiveRolloutInitialVariation = mr.ProgressiveRollout.Initial.Variation
-		dbr.ProgressiveRolloutEndVariation = mr.ProgressiveRollout.End.Variation
-		dbr.ProgressiveRolloutInitialPercentage = mr.ProgressiveRollout.Initial.Percentage
-		dbr.ProgressiveRolloutEndPercentage = mr.ProgressiveRollout.End.Percentage
-		dbr.ProgressiveRolloutStartDate = mr.ProgressiveRollout.Initial.Date
-		dbr.ProgressiveRolloutEndDate = mr.ProgressiveRollout.End.Date
+		for i, step := range mr.ProgressiveRollout.GetSteps() {
+			dbStep := ProgressiveRolloutStep{RuleID: id, StepIndex: i, Percentage: step.Percentage}
+			if step.Variation != nil {
+				dbStep.Variation = *step.Variation
+			}
+			if step.Date != nil {
+				dbStep.Date = *step.Date
+			}
+			dbr.ProgressiveRolloutSteps = append(dbr.ProgressiveRolloutSteps, dbStep)
+		}
 	}
 	return dbr, nil
 }
@@ -98,19 +106,18 @@ func (rule *Rule) ToModelRule() model.Rule {
 		}
 	}
 
-	if rule.ProgressiveRolloutInitialVariation != nil || rule.ProgressiveRolloutEndVariation != nil {
-		apiRule.ProgressiveRollout = &model.ProgressiveRollout{
-			Initial: &model.ProgressiveRolloutStep{
-				Variation:  rule.ProgressiveRolloutInitialVariation,
-				Percentage: rule.ProgressiveRolloutInitialPercentage,
-				Date:       rule.ProgressiveRolloutStartDate,
-			},
-			End: &model.ProgressiveRolloutStep{
-				Variation:  rule.ProgressiveRolloutEndVariation,
-				Percentage: rule.ProgressiveRolloutEndPercentage,
-				Date:       rule.ProgressiveRolloutEndDate,
-			},
+	if len(rule.ProgressiveRolloutSteps) > 0 {
+		steps := make([]model.ProgressiveRolloutStep, 0, len(rule.ProgressiveRolloutSteps))
+		for _, step := range rule.ProgressiveRolloutSteps {
+			variation := step.Variation
+			date := step.Date
+			steps = append(steps, model.ProgressiveRolloutStep{
+				Variation:  &variation,
+				Percentage: step.Percentage,
+				Date:       &date,
+			})
 		}
+		apiRule.ProgressiveRollout = model.NewProgressiveRollout(steps)
 	}
 	return apiRule
 }
@@ -142,19 +142,29 @@ func TestFromModelRule(t *testing.T) {
 
 			wantErr: assert.NoError,
 			want: dbmodel.Rule{
-				ID:                                  ruleID,
-				Name:                                "rule 1",
-				FeatureFlagID:                       flagID,
-				Query:                               testutils.String(`targetingKey eq "foo"`),
-				Disable:                             true,
-				OrderIndex:                          10,
-				IsDefault:                           false,
-				ProgressiveRolloutInitialVariation:  testutils.String("A"),
-				ProgressiveRolloutEndVariation:      testutils.String("B"),
-				ProgressiveRolloutInitialPercentage: testutils.Float64(0),
-				ProgressiveRolloutEndPercentage:     testutils.Float64(100),
-				ProgressiveRolloutStartDate:         testutils.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
-				ProgressiveRolloutEndDate:           testutils.Time(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+				ID:            ruleID,
+				Name:          "rule 1",
+				FeatureFlagID: flagID,
+				Query:         testutils.String(`targetingKey eq "foo"`),
+				Disable:       true,
+				OrderIndex:    10,
+				IsDefault:     false,
+				ProgressiveRolloutSteps: []dbmodel.ProgressiveRolloutStep{
+					{
+						RuleID:     ruleID,
+						StepIndex:  0,
+						Variation:  "A",
+						Percentage: testutils.Float64(0),
+						Date:       time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+					{
+						RuleID:     ruleID,
+						StepIndex:  1,
+						Variation:  "B",
+						Percentage: testutils.Float64(100),
+						Date:       time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
 			},
 		},
 	}
@@ -218,3 +228,47 @@ func TestFromModelRuleErrorParsingUUID(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, "invalid UUID length: 12", err.Error())
 }
+
+func TestRuleProgressiveRolloutSteps(t *testing.T) {
+	steps := []model.ProgressiveRolloutStep{
+		{
+			Variation:  testutils.String("A"),
+			Percentage: testutils.Float64(0),
+			Date:       testutils.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			Variation:  testutils.String("B"),
+			Percentage: testutils.Float64(20),
+			Date:       testutils.Time(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			Variation: testutils.String("B"),
+			Date:      testutils.Time(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	rule := model.Rule{
+		ID:                 uuid.New().String(),
+		Name:               "rule 1",
+		ProgressiveRollout: &model.ProgressiveRollout{Steps: steps},
+	}
+
+	dbRule, err := dbmodel.FromModelRule(rule, uuid.New(), false, 1)
+	require.NoError(t, err)
+	require.Len(t, dbRule.ProgressiveRolloutSteps, 3)
+	for i, step := range dbRule.ProgressiveRolloutSteps {
+		assert.Equal(t, dbRule.ID, step.RuleID)
+		assert.Equal(t, i, step.StepIndex)
+	}
+	assert.Nil(t, dbRule.ProgressiveRolloutSteps[2].Percentage)
+
+	got := dbRule.ToModelRule()
+	assert.Equal(t, rule.ProgressiveRollout, got.ProgressiveRollout)
+
+	// a rollout of 2 steps is returned with the initial and end steps
+	dbRule.ProgressiveRolloutSteps = dbRule.ProgressiveRolloutSteps[1:]
+	got = dbRule.ToModelRule()
+	require.NotNil(t, got.ProgressiveRollout)
+	assert.Empty(t, got.ProgressiveRollout.Steps)
+	assert.Equal(t, &steps[1], got.ProgressiveRollout.Initial)
+	assert.Equal(t, &steps[2], got.ProgressiveRollout.End)
+}
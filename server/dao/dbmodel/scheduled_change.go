@@ -0,0 +1,93 @@
+package dbmodel
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+)
+
+type ScheduledChange struct {
+	ID              uuid.UUID  `db:"id"`
+	FeatureFlagID   uuid.UUID  `db:"feature_flag_id"`
+	ScheduledDate   time.Time  `db:"scheduled_date"`
+	Changes         JSONB      `db:"changes"`
+	Status          string     `db:"status"`
+	Error           *string    `db:"error"`
+	CreatedDate     time.Time  `db:"created_date"`
+	CreatedBy       string     `db:"created_by"`
+	LastUpdatedDate time.Time  `db:"last_updated_date"`
+	AppliedDate     *time.Time `db:"applied_date"`
+}
+
+// FromModelScheduledChange converts a model.ScheduledChange to a dbmodel.ScheduledChange, the changes are stored
+// with the same JSON representation as the one exposed by the API.
+func FromModelScheduledChange(change model.ScheduledChange) (ScheduledChange, error) {
+	id, err := uuid.Parse(change.ID)
+	if err != nil {
+		return ScheduledChange{}, err
+	}
+	flagID, err := uuid.Parse(change.FlagID)
+	if err != nil {
+		return ScheduledChange{}, err
+	}
+
+	b, err := json.Marshal(change.Changes)
+	if err != nil {
+		return ScheduledChange{}, err
+	}
+	var changes JSONB
+	if err := json.Unmarshal(b, &changes); err != nil {
+		return ScheduledChange{}, err
+	}
+
+	var changeError *string
+	if change.Error != "" {
+		changeError = &change.Error
+	}
+	return ScheduledChange{
+		ID:              id,
+		FeatureFlagID:   flagID,
+		ScheduledDate:   change.ScheduledDate,
+		Changes:         changes,
+		Status:          string(change.Status),
+		Error:           changeError,
+		CreatedDate:     change.CreatedDate,
+		CreatedBy:       change.CreatedBy,
+		LastUpdatedDate: change.LastUpdatedDate,
+		AppliedDate:     change.AppliedDate,
+	}, nil
+}
+
+func (s *ScheduledChange) ToModelScheduledChange() (model.ScheduledChange, error) {
+	b, err := json.Marshal(s.Changes)
+	if err != nil {
+		return model.ScheduledChange{}, err
+	}
+	var changes model.FlagChanges
+	if err := json.Unmarshal(b, &changes); err != nil {
+		return model.ScheduledChange{}, err
+	}
+	status, err := model.ScheduledChangeStatusFromValue(s.Status)
+	if err != nil {
+		return model.ScheduledChange{}, err
+	}
+
+	var changeError string
+	if s.Error != nil {
+		changeError = *s.Error
+	}
+	return model.ScheduledChange{
+		ID:              s.ID.String(),
+		FlagID:          s.FeatureFlagID.String(),
+		ScheduledDate:   s.ScheduledDate,
+		Changes:         changes,
+		Status:          status,
+		Error:           changeError,
+		CreatedDate:     s.CreatedDate,
+		CreatedBy:       s.CreatedBy,
+		LastUpdatedDate: s.LastUpdatedDate,
+		AppliedDate:     s.AppliedDate,
+	}, nil
+}
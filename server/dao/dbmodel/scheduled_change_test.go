@@ -0,0 +1,116 @@
+package dbmodel_test
+
+import (
+	dbmodel2 "github.com/go-feature-flag/flag-management/server/dao/dbmodel"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromModelScheduledChange(t *testing.T) {
+	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174999")
+	flagID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+	tests := []struct {
+		name    string
+		change  model.ScheduledChange
+		want    dbmodel2.ScheduledChange
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "should convert model.ScheduledChange to dbmodel.ScheduledChange",
+			change: model.ScheduledChange{
+				ID:            id.String(),
+				FlagID:        flagID.String(),
+				ScheduledDate: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+				Changes: model.FlagChanges{
+					DefaultRule: &model.Rule{
+						ID:              "123e4567-e89b-12d3-a456-426614174111",
+						VariationResult: testutils.String("B"),
+					},
+					Disable: testutils.Bool(false),
+				},
+				Status:          model.ScheduledChangeStatusPending,
+				CreatedDate:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBy:       "foo",
+				LastUpdatedDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: assert.NoError,
+			want: dbmodel2.ScheduledChange{
+				ID:            id,
+				FeatureFlagID: flagID,
+				ScheduledDate: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+				Changes: dbmodel2.JSONB{
+					"defaultRule": map[string]interface{}{
+						"id":        "123e4567-e89b-12d3-a456-426614174111",
+						"variation": "B",
+					},
+					"disable": false,
+				},
+				Status:          "pending",
+				CreatedDate:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBy:       "foo",
+				LastUpdatedDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "should return an error if the ID is not a valid UUID",
+			change:  model.ScheduledChange{ID: "invalid-uuid", FlagID: flagID.String()},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "should return an error if the flag ID is not a valid UUID",
+			change:  model.ScheduledChange{ID: id.String(), FlagID: "invalid-uuid"},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dbmodel2.FromModelScheduledChange(tt.change)
+			tt.wantErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScheduledChange_ToModelScheduledChange(t *testing.T) {
+	appliedDate := time.Date(2022, 2, 1, 0, 0, 1, 0, time.UTC)
+	change := model.ScheduledChange{
+		ID:            "123e4567-e89b-12d3-a456-426614174999",
+		FlagID:        "123e4567-e89b-12d3-a456-426614174000",
+		ScheduledDate: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		Changes: model.FlagChanges{
+			Rules: &[]model.Rule{
+				{
+					ID:          "123e4567-e89b-12d3-a456-426614174222",
+					Name:        "rule 1",
+					Query:       `targetingKey eq "1234"`,
+					Percentages: &map[string]float64{"A": 20, "B": 80},
+				},
+			},
+		},
+		Status:          model.ScheduledChangeStatusFailed,
+		Error:           "flag not found",
+		CreatedDate:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedBy:       "foo",
+		LastUpdatedDate: appliedDate,
+		AppliedDate:     &appliedDate,
+	}
+
+	dbChange, err := dbmodel2.FromModelScheduledChange(change)
+	require.NoError(t, err)
+	got, err := dbChange.ToModelScheduledChange()
+	require.NoError(t, err)
+	assert.Equal(t, change, got)
+
+	dbChange.Status = "unknown"
+	_, err = dbChange.ToModelScheduledChange()
+	assert.Error(t, err)
+}
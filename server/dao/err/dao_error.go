@@ -11,6 +11,9 @@ const (
 	DefaultRuleRequired    DaoErrorCode = "DEFAULT_RULE_REQUIRED"
 	UnknownError           DaoErrorCode = "UNKNOWN_ERROR"
 	DatabaseNotInitialized DaoErrorCode = "DATABASE_NOT_INITIALIZED"
+	StaleRevision          DaoErrorCode = "STALE_REVISION"
+	InUse                  DaoErrorCode = "IN_USE"
+	AlreadyExists          DaoErrorCode = "ALREADY_EXISTS"
 )
 
 type DaoError interface {
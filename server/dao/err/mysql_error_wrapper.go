@@ -0,0 +1,37 @@
+package daoerr
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+)
+
+const (
+	// mysqlDuplicateEntry is returned when inserting a row violating a unique or primary key constraint
+	mysqlDuplicateEntry = 1062
+	// mysqlRowIsReferenced is returned when deleting a row still referenced by a foreign key
+	mysqlRowIsReferenced = 1451
+	// mysqlNoReferencedRow is returned when inserting a row referencing a row that does not exist
+	mysqlNoReferencedRow = 1452
+)
+
+// WrapMySQLError wraps a MySQL error into a DaoError to have a DB agnostic error handling in the handlers
+func WrapMySQLError(err error) DaoError {
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return NewDaoError(NotFound, err)
+	case uuid.IsInvalidLengthError(err):
+		return NewDaoError(InvalidUUID, err)
+	case errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlRowIsReferenced:
+		return NewDaoError(InUse, err)
+	case errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlNoReferencedRow:
+		return NewDaoError(NotFound, err)
+	case errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry:
+		return NewDaoError(AlreadyExists, err)
+	default:
+		return NewDaoError(UnknownError, err)
+	}
+}
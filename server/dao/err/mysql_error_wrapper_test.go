@@ -0,0 +1,55 @@
+package daoerr_test
+
+import (
+	"database/sql"
+	"errors"
+	daoerr2 "github.com/go-feature-flag/flag-management/server/dao/err"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapMySQLError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want daoerr2.DaoError
+	}{
+		{
+			name: "should return not found error",
+			err:  sql.ErrNoRows,
+			want: daoerr2.NewDaoError(daoerr2.NotFound, sql.ErrNoRows),
+		},
+		{
+			name: "should return in use error when deleting a referenced row",
+			err:  &mysql.MySQLError{Number: 1451},
+			want: daoerr2.NewDaoError(daoerr2.InUse, &mysql.MySQLError{Number: 1451}),
+		},
+		{
+			name: "should return not found error when referencing a missing row",
+			err:  &mysql.MySQLError{Number: 1452},
+			want: daoerr2.NewDaoError(daoerr2.NotFound, &mysql.MySQLError{Number: 1452}),
+		},
+		{
+			name: "should return an already exists error for a duplicate entry",
+			err:  &mysql.MySQLError{Number: 1062},
+			want: daoerr2.NewDaoError(daoerr2.AlreadyExists, &mysql.MySQLError{Number: 1062}),
+		},
+		{
+			name: "should return an unknown error",
+			err:  &mysql.MySQLError{Number: 1205},
+			want: daoerr2.NewDaoError(daoerr2.UnknownError, &mysql.MySQLError{Number: 1205}),
+		},
+		{
+			name: "should return an unknown error if the error does not come from MySQL",
+			err:  errors.New("random error"),
+			want: daoerr2.NewDaoError(daoerr2.UnknownError, errors.New("random error")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, daoerr2.WrapMySQLError(tt.err))
+		})
+	}
+}
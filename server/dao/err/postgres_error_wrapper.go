@@ -3,11 +3,22 @@ package daoerr
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
+// postgresForeignKeyParents is the kind of row referenced by each foreign key constraint of the migrations.
+var postgresForeignKeyParents = map[string]string{
+	"environments_project_fkey":              "project",
+	"feature_flags_environment_fkey":         "environment",
+	"rules_feature_flag_id_fkey":             "flag",
+	"scheduled_changes_feature_flag_id_fkey": "flag",
+	"progressive_rollout_steps_rule_id_fkey": "rule",
+}
+
 // WrapPostgresError wraps a postgres error into a DaoError to have a DB agnostic error handling in the handlers
 func WrapPostgresError(err error) DaoError {
 	var pqErr *pq.Error
@@ -16,8 +27,26 @@ func WrapPostgresError(err error) DaoError {
 		return NewDaoError(NotFound, err)
 	case uuid.IsInvalidLengthError(err), errors.As(err, &pqErr) && pqErr.Code == "22P02":
 		return NewDaoError(InvalidUUID, err)
+	case errors.As(err, &pqErr) && pqErr.Code == "23503":
+		return wrapPostgresForeignKeyError(pqErr, err)
+	case errors.As(err, &pqErr) && pqErr.Code == "23505":
+		// unique_violation: a row with the same id or name already exists
+		return NewDaoError(AlreadyExists, err)
 	default:
 		return NewDaoError(UnknownError, err)
+	}
+}
 
+// wrapPostgresForeignKeyError converts a foreign_key_violation, postgres uses the same code when a row references
+// a row that does not exist (NotFound) and when a row still referenced by another table is deleted (InUse).
+// In both cases the table of the error is the referencing table, only the message tells which side failed.
+func wrapPostgresForeignKeyError(pqErr *pq.Error, err error) DaoError {
+	if !strings.HasPrefix(pqErr.Message, "insert or update on table") {
+		return NewDaoError(InUse, err)
+	}
+	parent, ok := postgresForeignKeyParents[pqErr.Constraint]
+	if !ok {
+		parent = "row"
 	}
+	return NewDaoError(NotFound, fmt.Errorf("%s referenced by %s not found: %w", parent, pqErr.Table, err))
 }
@@ -3,6 +3,7 @@ package daoerr_test
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	daoerr2 "github.com/go-feature-flag/flag-management/server/dao/err"
 	"testing"
 
@@ -11,6 +12,18 @@ import (
 )
 
 func TestWrapPostgresError(t *testing.T) {
+	deleteReferencedErr := &pq.Error{
+		Code:       "23503",
+		Message:    `update or delete on table "projects" violates foreign key constraint "environments_project_fkey" on table "environments"`, // nolint: lll
+		Table:      "environments",
+		Constraint: "environments_project_fkey",
+	}
+	missingReferenceErr := &pq.Error{
+		Code:       "23503",
+		Message:    `insert or update on table "environments" violates foreign key constraint "environments_project_fkey"`,
+		Table:      "environments",
+		Constraint: "environments_project_fkey",
+	}
 	tests := []struct {
 		name string
 		err  error
@@ -26,6 +39,22 @@ func TestWrapPostgresError(t *testing.T) {
 			err:  &pq.Error{Code: "22P02"},
 			want: daoerr2.NewDaoError(daoerr2.InvalidUUID, &pq.Error{Code: "22P02"}),
 		},
+		{
+			name: "should return in use error when deleting a referenced row",
+			err:  deleteReferencedErr,
+			want: daoerr2.NewDaoError(daoerr2.InUse, deleteReferencedErr),
+		},
+		{
+			name: "should return not found error when referencing a missing row",
+			err:  missingReferenceErr,
+			want: daoerr2.NewDaoError(daoerr2.NotFound,
+				fmt.Errorf("project referenced by environments not found: %w", missingReferenceErr)),
+		},
+		{
+			name: "should return already exists error for a unique violation",
+			err:  &pq.Error{Code: "23505"},
+			want: daoerr2.NewDaoError(daoerr2.AlreadyExists, &pq.Error{Code: "23505"}),
+		},
 		{
 			name: "should return an unknown error",
 			err:  errors.New("random error"),
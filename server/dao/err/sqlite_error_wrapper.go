@@ -0,0 +1,30 @@
+package daoerr
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// WrapSQLiteError wraps a SQLite error into a DaoError to have a DB agnostic error handling in the handlers
+func WrapSQLiteError(err error) DaoError {
+	var sqliteErr *sqlite.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return NewDaoError(NotFound, err)
+	case uuid.IsInvalidLengthError(err):
+		return NewDaoError(InvalidUUID, err)
+	case errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		// SQLite does not tell which side of the foreign key failed, the row is still referenced by another table
+		// when deleting it, an insert referencing a row that does not exist is checked by the storage.
+		return NewDaoError(InUse, err)
+	case errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY):
+		return NewDaoError(AlreadyExists, err)
+	default:
+		return NewDaoError(UnknownError, err)
+	}
+}
@@ -0,0 +1,62 @@
+package daoerr_test
+
+import (
+	"database/sql"
+	"errors"
+	daoerr2 "github.com/go-feature-flag/flag-management/server/dao/err"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // the SQLite errors can only be created by the driver
+)
+
+func TestWrapSQLiteError(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`CREATE TABLE parents (id TEXT PRIMARY KEY);
+		CREATE TABLE children (id TEXT PRIMARY KEY, parent_id TEXT REFERENCES parents (id));
+		INSERT INTO parents (id) VALUES ('parent');
+		INSERT INTO children (id, parent_id) VALUES ('child', 'parent');`)
+	require.NoError(t, err)
+
+	_, foreignKeyErr := db.Exec(`DELETE FROM parents WHERE id = 'parent'`)
+	require.Error(t, foreignKeyErr)
+	_, primaryKeyErr := db.Exec(`INSERT INTO parents (id) VALUES ('parent')`)
+	require.Error(t, primaryKeyErr)
+
+	tests := []struct {
+		name string
+		err  error
+		want daoerr2.DaoErrorCode
+	}{
+		{
+			name: "should return not found error",
+			err:  sql.ErrNoRows,
+			want: daoerr2.NotFound,
+		},
+		{
+			name: "should return in use error for a foreign key violation",
+			err:  foreignKeyErr,
+			want: daoerr2.InUse,
+		},
+		{
+			name: "should return already exists error for a duplicate primary key",
+			err:  primaryKeyErr,
+			want: daoerr2.AlreadyExists,
+		},
+		{
+			name: "should return an unknown error if the error does not come from SQLite",
+			err:  errors.New("random error"),
+			want: daoerr2.UnknownError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := daoerr2.WrapSQLiteError(tt.err)
+			assert.Equal(t, tt.want, got.Code())
+			assert.Equal(t, tt.err.Error(), got.Error())
+		})
+	}
+}
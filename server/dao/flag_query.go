@@ -0,0 +1,163 @@
+package dao
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+type FlagSortField string
+
+const (
+	FlagSortByName            FlagSortField = "name"
+	FlagSortByCreatedDate     FlagSortField = "createdDate"
+	FlagSortByLastUpdatedDate FlagSortField = "lastUpdatedDate"
+)
+
+// FlagSortFieldFromValue converts a string to a FlagSortField
+func FlagSortFieldFromValue(value string) (FlagSortField, error) {
+	switch FlagSortField(value) {
+	case FlagSortByName, FlagSortByCreatedDate, FlagSortByLastUpdatedDate:
+		return FlagSortField(value), nil
+	default:
+		return "", fmt.Errorf("sort field %s not supported", value)
+	}
+}
+
+// FlagQuery contains the options to select and order the flags returned by GetFlags.
+// All the filters are optional, an empty FlagQuery returns all the flags.
+type FlagQuery struct {
+	// Limit is the maximum number of flags to return, 0 means no limit
+	Limit int
+	// Offset is the number of flags to skip before starting to collect the result set
+	Offset int
+
+	// SortBy is the field used to order the flags (default: lastUpdatedDate)
+	SortBy FlagSortField
+	// SortDesc is ordering the flags in descending order
+	SortDesc bool
+
+	// Project keeps only the flags of this project
+	Project string
+	// Environment keeps only the flags of this environment
+	Environment string
+	// NamePrefix keeps only the flags with a name starting with this value
+	NamePrefix string
+	// Type keeps only the flags of this type
+	Type model.FlagType
+	// Disabled keeps only the flags disabled (true) or enabled (false)
+	Disabled *bool
+	// MetadataKey keeps only the flags having this key in their metadata
+	MetadataKey string
+	// MetadataValue keeps only the flags where the value of MetadataKey is this value,
+	// it is ignored if MetadataKey is empty
+	MetadataValue *string
+}
+
+// FlagsSummary describes the state of a list of flags, it changes every time a flag of the list is
+// created, updated or deleted.
+type FlagsSummary struct {
+	// Total is the number of flags matching the filters
+	Total int
+	// LastUpdatedDate is the most recent last updated date of the flags (zero if there is no flag)
+	LastUpdatedDate time.Time
+	// VersionCount is the number of versions ever recorded for all the flags, including the versions dropped
+	// from the history, so it increases on every write even if the last updated date is not moving forward.
+	VersionCount int
+}
+
+// Match returns true if the flag matches all the filters of the query
+func (q FlagQuery) Match(flag model.FeatureFlag) bool {
+	if q.Project != "" && flag.GetProject() != q.Project {
+		return false
+	}
+	if q.Environment != "" && flag.GetEnvironment() != q.Environment {
+		return false
+	}
+	if q.NamePrefix != "" && !strings.HasPrefix(flag.Name, q.NamePrefix) {
+		return false
+	}
+	if q.Type != "" && flag.VariationType != q.Type {
+		return false
+	}
+	if q.Disabled != nil && flag.IsDisable() != *q.Disabled {
+		return false
+	}
+	if q.MetadataKey != "" {
+		if flag.Metadata == nil {
+			return false
+		}
+		value, ok := (*flag.Metadata)[q.MetadataKey]
+		if !ok {
+			return false
+		}
+		if q.MetadataValue != nil && fmt.Sprint(value) != *q.MetadataValue {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyFlagQuery filters, sorts and paginates a list of flags in memory.
+// It returns the page of flags and the total number of flags matching the filters.
+func ApplyFlagQuery(flags []model.FeatureFlag, query FlagQuery) ([]model.FeatureFlag, int) {
+	res := make([]model.FeatureFlag, 0, len(flags))
+	for _, flag := range flags {
+		if query.Match(flag) {
+			res = append(res, flag)
+		}
+	}
+
+	// like the SQL storages, the flags are ordered by last updated date (most recent first) by default and
+	// by id when the sort field is equal, so the pages never overlap
+	desc := query.SortDesc || query.SortBy == ""
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if desc {
+			a, b = b, a
+		}
+		switch query.SortBy {
+		case FlagSortByName:
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		case FlagSortByCreatedDate:
+			if !a.CreatedDate.Equal(b.CreatedDate) {
+				return a.CreatedDate.Before(b.CreatedDate)
+			}
+		default:
+			if !a.LastUpdatedDate.Equal(b.LastUpdatedDate) {
+				return a.LastUpdatedDate.Before(b.LastUpdatedDate)
+			}
+		}
+		return a.ID < b.ID
+	})
+
+	total := len(res)
+	if query.Offset >= total {
+		return []model.FeatureFlag{}, total
+	}
+	end := total
+	if query.Limit > 0 && query.Offset+query.Limit < total {
+		end = query.Offset + query.Limit
+	}
+	return res[query.Offset:end], total
+}
+
+// SummarizeFlags returns the FlagsSummary of the flags matching the filters of the query.
+func SummarizeFlags(flags []model.FeatureFlag, query FlagQuery) FlagsSummary {
+	summary := FlagsSummary{}
+	for _, flag := range flags {
+		if !query.Match(flag) {
+			continue
+		}
+		summary.Total++
+		if flag.LastUpdatedDate.After(summary.LastUpdatedDate) {
+			summary.LastUpdatedDate = flag.LastUpdatedDate
+		}
+	}
+	return summary
+}
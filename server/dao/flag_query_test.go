@@ -0,0 +1,225 @@
+package dao_test
+
+import (
+	"github.com/go-feature-flag/flag-management/server/dao"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagSortFieldFromValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    dao.FlagSortField
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "name", value: "name", want: dao.FlagSortByName, wantErr: assert.NoError},
+		{name: "createdDate", value: "createdDate", want: dao.FlagSortByCreatedDate, wantErr: assert.NoError},
+		{name: "lastUpdatedDate", value: "lastUpdatedDate", want: dao.FlagSortByLastUpdatedDate, wantErr: assert.NoError},
+		{name: "unknown field", value: "description", wantErr: assert.Error},
+		{name: "empty field", value: "", wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dao.FlagSortFieldFromValue(tt.value)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApplyFlagQuery(t *testing.T) {
+	flags := []model.FeatureFlag{
+		{
+			Name:            "flag-b",
+			VariationType:   model.FlagTypeBoolean,
+			CreatedDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			Metadata:        &map[string]interface{}{"owner": "team-a", "priority": 1},
+		},
+		{
+			Name:            "flag-a",
+			VariationType:   model.FlagTypeString,
+			CreatedDate:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Disable:         testutils.Bool(true),
+			Metadata:        &map[string]interface{}{"owner": "team-b"},
+		},
+		{
+			Name:            "other",
+			Project:         "checkout",
+			Environment:     "production",
+			VariationType:   model.FlagTypeBoolean,
+			CreatedDate:     time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		query     dao.FlagQuery
+		wantNames []string
+		wantTotal int
+	}{
+		{
+			name:      "empty query should return all the flags, the most recently updated first",
+			query:     dao.FlagQuery{},
+			wantNames: []string{"flag-b", "flag-a", "other"},
+			wantTotal: 3,
+		},
+		{
+			name:      "should sort by name",
+			query:     dao.FlagQuery{SortBy: dao.FlagSortByName},
+			wantNames: []string{"flag-a", "flag-b", "other"},
+			wantTotal: 3,
+		},
+		{
+			name:      "should sort by created date descending",
+			query:     dao.FlagQuery{SortBy: dao.FlagSortByCreatedDate, SortDesc: true},
+			wantNames: []string{"other", "flag-a", "flag-b"},
+			wantTotal: 3,
+		},
+		{
+			name:      "should sort by last updated date",
+			query:     dao.FlagQuery{SortBy: dao.FlagSortByLastUpdatedDate},
+			wantNames: []string{"other", "flag-a", "flag-b"},
+			wantTotal: 3,
+		},
+		{
+			name:      "should paginate after sorting",
+			query:     dao.FlagQuery{SortBy: dao.FlagSortByName, Limit: 2, Offset: 1},
+			wantNames: []string{"flag-b", "other"},
+			wantTotal: 3,
+		},
+		{
+			name:      "should return an empty page if offset is too big",
+			query:     dao.FlagQuery{Offset: 3},
+			wantNames: []string{},
+			wantTotal: 3,
+		},
+		{
+			name:      "should filter by name prefix",
+			query:     dao.FlagQuery{NamePrefix: "flag-"},
+			wantNames: []string{"flag-b", "flag-a"},
+			wantTotal: 2,
+		},
+		{
+			name:      "should consider flags without scope in the default project",
+			query:     dao.FlagQuery{Project: model.DefaultProject, Environment: model.DefaultEnvironment},
+			wantNames: []string{"flag-b", "flag-a"},
+			wantTotal: 2,
+		},
+		{
+			name:      "should filter by project and environment",
+			query:     dao.FlagQuery{Project: "checkout", Environment: "production"},
+			wantNames: []string{"other"},
+			wantTotal: 1,
+		},
+		{
+			name:      "should filter by environment of another project",
+			query:     dao.FlagQuery{Project: "checkout", Environment: "staging"},
+			wantNames: []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "should filter by type",
+			query:     dao.FlagQuery{Type: model.FlagTypeString},
+			wantNames: []string{"flag-a"},
+			wantTotal: 1,
+		},
+		{
+			name:      "should consider flags without disable field as enabled",
+			query:     dao.FlagQuery{Disabled: testutils.Bool(false)},
+			wantNames: []string{"flag-b", "other"},
+			wantTotal: 2,
+		},
+		{
+			name:      "should filter by metadata key",
+			query:     dao.FlagQuery{MetadataKey: "owner"},
+			wantNames: []string{"flag-b", "flag-a"},
+			wantTotal: 2,
+		},
+		{
+			name:      "should filter by metadata key and value",
+			query:     dao.FlagQuery{MetadataKey: "owner", MetadataValue: testutils.String("team-b")},
+			wantNames: []string{"flag-a"},
+			wantTotal: 1,
+		},
+		{
+			name:      "should compare metadata values as strings",
+			query:     dao.FlagQuery{MetadataKey: "priority", MetadataValue: testutils.String("1")},
+			wantNames: []string{"flag-b"},
+			wantTotal: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := dao.ApplyFlagQuery(flags, tt.query)
+			names := make([]string, 0, len(got))
+			for _, flag := range got {
+				names = append(names, flag.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func TestApplyFlagQuery_PaginationWithEqualSortKeys(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	flags := []model.FeatureFlag{
+		{ID: "c", Name: "flag", CreatedDate: date, LastUpdatedDate: date},
+		{ID: "a", Name: "flag", CreatedDate: date, LastUpdatedDate: date},
+		{ID: "d", Name: "flag", CreatedDate: date, LastUpdatedDate: date},
+		{ID: "b", Name: "flag", CreatedDate: date, LastUpdatedDate: date},
+	}
+	tests := []struct {
+		name    string
+		query   dao.FlagQuery
+		wantIDs []string
+	}{
+		{name: "default sort", query: dao.FlagQuery{}, wantIDs: []string{"d", "c", "b", "a"}},
+		{name: "sort by name", query: dao.FlagQuery{SortBy: dao.FlagSortByName}, wantIDs: []string{"a", "b", "c", "d"}},
+		{
+			name:    "sort by created date descending",
+			query:   dao.FlagQuery{SortBy: dao.FlagSortByCreatedDate, SortDesc: true},
+			wantIDs: []string{"d", "c", "b", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []string{}
+			for offset := 0; offset < len(flags); offset += 2 {
+				query := tt.query
+				query.Limit, query.Offset = 2, offset
+				page, _ := dao.ApplyFlagQuery(flags, query)
+				for _, flag := range page {
+					ids = append(ids, flag.ID)
+				}
+			}
+			assert.Equal(t, tt.wantIDs, ids, "the pages should not overlap nor skip flags")
+		})
+	}
+}
+
+func TestSummarizeFlags(t *testing.T) {
+	flags := []model.FeatureFlag{
+		{Name: "flag-a", LastUpdatedDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Name: "flag-b", LastUpdatedDate: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{Name: "other", LastUpdatedDate: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+	}
+
+	assert.Equal(t, dao.FlagsSummary{
+		Total:           3,
+		LastUpdatedDate: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+	}, dao.SummarizeFlags(flags, dao.FlagQuery{Limit: 1}))
+	assert.Equal(t, dao.FlagsSummary{
+		Total:           2,
+		LastUpdatedDate: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}, dao.SummarizeFlags(flags, dao.FlagQuery{NamePrefix: "flag-"}))
+	assert.Equal(t, dao.FlagsSummary{}, dao.SummarizeFlags(flags, dao.FlagQuery{NamePrefix: "unknown"}))
+}
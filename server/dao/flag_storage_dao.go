@@ -8,14 +8,21 @@ import (
 )
 
 type FlagStorage interface {
-	// GetFlags return all the flags
-	GetFlags(ctx context.Context) ([]model.FeatureFlag, daoErr.DaoError)
+	ProjectStorage
+
+	// GetFlags return the flags matching the query and the total number of flags matching the filters
+	// (without the pagination).
+	GetFlags(ctx context.Context, query FlagQuery) ([]model.FeatureFlag, int, daoErr.DaoError)
+
+	// GetFlagsSummary return the number of flags matching the filters of the query and the most recent
+	// last updated date of those flags, without loading them.
+	GetFlagsSummary(ctx context.Context, query FlagQuery) (FlagsSummary, daoErr.DaoError)
 
 	// GetFlagByID return a flag by its ID
 	GetFlagByID(ctx context.Context, id string) (model.FeatureFlag, daoErr.DaoError)
 
-	// GetFlagByName return a flag by its name
-	GetFlagByName(ctx context.Context, name string) (model.FeatureFlag, daoErr.DaoError)
+	// GetFlagByName return a flag by its name inside an environment
+	GetFlagByName(ctx context.Context, project string, environment string, name string) (model.FeatureFlag, daoErr.DaoError)
 
 	// CreateFlag create a new flag, return the id of the flag
 	CreateFlag(ctx context.Context, flag model.FeatureFlag) (string, daoErr.DaoError)
@@ -23,9 +30,20 @@ type FlagStorage interface {
 	// UpdateFlag update a flag
 	UpdateFlag(ctx context.Context, flag model.FeatureFlag) daoErr.DaoError
 
+	// ImportFlags create and update a list of flags in a single transaction, if one of the changes fails
+	// none of them is stored.
+	ImportFlags(ctx context.Context, creations []model.FeatureFlag, updates []model.FeatureFlag) daoErr.DaoError
+
 	// DeleteFlagByID delete a flag
 	DeleteFlagByID(ctx context.Context, id string) daoErr.DaoError
 
+	// GetFlagVersions return the versions of a flag (the most recent first) and the total number of versions.
+	// Every call to CreateFlag, UpdateFlag and DeleteFlagByID creates a new version of the flag.
+	GetFlagVersions(ctx context.Context, flagID string, limit int, offset int) ([]model.FlagVersion, int, daoErr.DaoError)
+
+	// GetFlagVersion return a specific version of a flag
+	GetFlagVersion(ctx context.Context, flagID string, versionID string) (model.FlagVersion, daoErr.DaoError)
+
 	// Ping check that the data layer is available
 	Ping() daoErr.DaoError
 }
@@ -0,0 +1,123 @@
+package gitimpl
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/flagconfig"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+// scope is a project and one of its environments, it is a directory of the working copy.
+type scope struct {
+	project     string
+	environment string
+}
+
+// loadFlagFiles makes the memory storage match the flag files of the working copy: the flags of the files are
+// created or updated, and the flags without file are deleted. The memory storage can be behind the files
+// when the working copy has been changed outside the API or when the snapshot has been lost.
+func (g *gitFlagImpl) loadFlagFiles(ctx context.Context) error {
+	files, err := g.readFlagFiles()
+	if err != nil {
+		return err
+	}
+	scopes := make([]scope, 0, len(files))
+	for s := range files {
+		scopes = append(scopes, s)
+	}
+	slices.SortFunc(scopes, func(a, b scope) int {
+		return strings.Compare(a.project+"/"+a.environment, b.project+"/"+b.environment)
+	})
+	for _, s := range scopes {
+		if err := g.ensureScope(ctx, s); err != nil {
+			return err
+		}
+		plan, err := flagconfig.PlanImport(ctx, g, files[s], flagconfig.ImportOptions{
+			Project:     s.project,
+			Environment: s.environment,
+			Author:      defaultAuthor,
+			Now:         time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("invalid flags in %s/%s: %w", s.project, s.environment, err)
+		}
+		if err := plan.Apply(ctx, g); err != nil {
+			return err
+		}
+	}
+
+	flags, _, daoErr := g.GetFlags(ctx, dao.FlagQuery{})
+	if daoErr != nil {
+		return daoErr
+	}
+	for _, flag := range flags {
+		if _, found := files[scope{flag.GetProject(), flag.GetEnvironment()}][flag.Name]; found {
+			continue
+		}
+		if err := g.DeleteFlagByID(ctx, flag.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFlagFiles reads the flag files of the working copy (<project>/<environment>/<flag name>.yaml) by scope.
+func (g *gitFlagImpl) readFlagFiles() (map[scope]map[string]flagconfig.Flag, error) {
+	files, err := filepath.Glob(filepath.Join(g.repo.workingCopy, "*", "*", "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	res := map[scope]map[string]flagconfig.Flag{}
+	for _, file := range files {
+		path, _ := filepath.Rel(g.repo.workingCopy, file)
+		parts := strings.Split(filepath.ToSlash(path), "/")
+		if strings.HasPrefix(parts[0], ".") {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("impossible to read the file %s: %w", path, err)
+		}
+		flags, err := flagconfig.Unmarshal(content, flagconfig.FormatYAML)
+		if err != nil {
+			return nil, fmt.Errorf("impossible to parse the file %s: %w", path, err)
+		}
+		s := scope{project: parts[0], environment: parts[1]}
+		if res[s] == nil {
+			res[s] = map[string]flagconfig.Flag{}
+		}
+		for name, flag := range flags {
+			res[s][name] = flag
+		}
+	}
+	return res, nil
+}
+
+// ensureScope creates the project and the environment of a directory of the working copy if they do not exist.
+func (g *gitFlagImpl) ensureScope(ctx context.Context, s scope) error {
+	if _, err := g.GetProject(ctx, s.project); err != nil {
+		if err.Code() != daoerr.NotFound {
+			return err
+		}
+		if err := g.CreateProject(ctx, model.Project{Name: s.project}); err != nil {
+			return err
+		}
+	}
+	if _, err := g.GetEnvironment(ctx, s.project, s.environment); err != nil {
+		if err.Code() != daoerr.NotFound {
+			return err
+		}
+		if err := g.CreateEnvironment(ctx, model.Environment{Project: s.project, Name: s.environment}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package gitimpl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/dao/memoryimpl"
+	"github.com/go-feature-flag/flag-management/server/flagconfig"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"go.uber.org/zap"
+)
+
+// snapshotFileName is the file of the .git directory keeping the data of the API that are not in the flag files.
+const snapshotFileName = "goff-management.json"
+
+// Options configures the remote of the git storage.
+type Options struct {
+	// Remote is the name or the URL of the remote receiving the commits, nothing is pushed if it is empty.
+	Remote string
+	// Branch is the branch of the remote receiving the commits.
+	// Default is the current branch of the working copy
+	Branch string
+}
+
+// memoryStorage is the storage keeping the data of the API, the git storage only adds the flag files on top of it.
+type memoryStorage interface {
+	dao.FlagStorage
+	dao.APIKeyStorage
+	dao.ScheduledChangeStorage
+	dao.ProjectStorage
+}
+
+// actionKey is the key of the context.Context giving the action written in the commit message of a flag.
+type actionKey struct{}
+
+// loadingKey is the key of the context.Context of the changes made to load the flag files, they are already in
+// the working copy so nothing is written nor committed.
+type loadingKey struct{}
+
+// the git storage is added to the registry of dao when the package is imported,
+// "git:///path/to/working-copy?remote=origin&branch=main" commits the flags in the working copy and pushes them
+// to the branch of the remote.
+var _ = dao.MustRegister("git", func(connectionString string) (dao.FlagStorage, error) {
+	workingCopy, query, _ := strings.Cut(dao.TrimScheme(connectionString), "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection string: %w", err)
+	}
+	return NewGitDao(workingCopy, Options{Remote: params.Get("remote"), Branch: params.Get("branch")})
+})
+
+// NewGitDao creates a storage writing every flag as a YAML file of GO Feature Flag in a local git working copy,
+// each change of a flag is committed with the user who made it as author, so the git retriever of the relay
+// proxy reads exactly what has been edited in the API.
+// The file of a flag is <project>/<environment>/<flag name>.yaml, the data that are not part of the GO Feature
+// Flag format (ids, revisions, history, projects, API keys, ...) are kept in a snapshot file inside the .git
+// directory, so they are never committed nor pushed.
+// The flag files are the source of truth: they are loaded when the storage is created, so the changes
+// committed outside the API are taken into account.
+// If options.Remote is set, the commits are pushed to this remote in the background after every change, Close
+// waits for the last push.
+func NewGitDao(workingCopy string, options Options) (dao.FlagStorage, error) {
+	if workingCopy == "" {
+		return nil, fmt.Errorf("working copy is empty")
+	}
+	repo, err := openRepository(workingCopy, options)
+	if err != nil {
+		return nil, err
+	}
+	g := &gitFlagImpl{repo: repo}
+	if options.Remote != "" {
+		g.pushes = make(chan struct{}, 1)
+		g.pushed = make(chan struct{})
+		go g.pushLoop()
+	}
+	memory, err := memoryimpl.NewMemoryDaoWithOptions(memoryimpl.Options{
+		SnapshotFile: filepath.Join(repo.gitDir, snapshotFileName),
+		OnSave:       g.commit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	storage, ok := memory.(memoryStorage)
+	if !ok {
+		return nil, fmt.Errorf("memory storage does not support the API keys, the scheduled changes and the projects")
+	}
+	g.memoryStorage = storage
+	if err := g.loadFlagFiles(context.WithValue(context.Background(), loadingKey{}, true)); err != nil {
+		return nil, fmt.Errorf("impossible to load the flag files of %s: %w", workingCopy, err)
+	}
+	return g, nil
+}
+
+// gitFlagImpl is the memory storage committing the flag files before keeping a change, a change is kept only
+// if its files are committed.
+type gitFlagImpl struct {
+	memoryStorage
+
+	repo *repository
+	// pushes wakes up the goroutine pushing the commits, it is nil without remote. Its buffer of 1 coalesces
+	// the commits made during a push, they are all sent by the next push.
+	pushes chan struct{}
+	// pushed is closed when the goroutine pushing the commits stops
+	pushed chan struct{}
+	// mu protects pushErr and closed
+	mu sync.Mutex
+	// pushErr is the error of the last push, it is reported by Ping until a push succeeds
+	pushErr error
+	// closed is true once Close has been called, nothing is pushed anymore
+	closed bool
+}
+
+// ApplyScheduledChange update a flag with a scheduled change and commits its file
+func (g *gitFlagImpl) ApplyScheduledChange(
+	ctx context.Context, flag model.FeatureFlag, change model.ScheduledChange) daoerr.DaoError {
+	ctx = context.WithValue(ctx, actionKey{}, "Apply scheduled change to")
+	return g.memoryStorage.ApplyScheduledChange(ctx, flag, change)
+}
+
+// Ping check that the data layer is available, it returns an error if the last push failed
+func (g *gitFlagImpl) Ping() daoerr.DaoError {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.pushErr != nil {
+		return daoerr.NewDaoError(daoerr.UnknownError, fmt.Errorf("last push failed: %w", g.pushErr))
+	}
+	return g.memoryStorage.Ping()
+}
+
+// Close stops pushing the commits, it waits for the push in progress and returns its error.
+func (g *gitFlagImpl) Close() error {
+	g.mu.Lock()
+	if g.pushes == nil || g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	close(g.pushes)
+	g.mu.Unlock()
+
+	<-g.pushed
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.pushErr != nil {
+		return fmt.Errorf("last push failed: %w", g.pushErr)
+	}
+	return nil
+}
+
+// commit is called by the memory storage before keeping a change: it writes the files of the created and
+// updated flags, removes the files of the deleted and renamed flags and commits them with the principal of
+// the context as author. If the files cannot be committed they are restored and the change is discarded.
+// The push runs in the background so the other changes never wait for the remote, a failing push does not
+// fail the change, it is logged and the commits are pushed again with the next change, Ping reports the error
+// until then.
+func (g *gitFlagImpl) commit(ctx context.Context, previous []model.FeatureFlag, next []model.FeatureFlag) error {
+	changes := diffFlags(previous, next)
+	if changes.empty() || ctx.Value(loadingKey{}) != nil {
+		return nil
+	}
+	files := changes.files()
+	originals, err := g.readFiles(files)
+	if err != nil {
+		return err
+	}
+	if err := g.writeChanges(changes, files); err != nil {
+		return g.restore(originals, files, err)
+	}
+	if err := g.repo.commit(directories(files), changes.message(ctx), author(ctx)); err != nil {
+		return g.restore(originals, files, err)
+	}
+	g.requestPush()
+	return nil
+}
+
+// writeChanges removes the files of the removed flags and writes the files of the written flags.
+func (g *gitFlagImpl) writeChanges(changes flagChanges, files []string) error {
+	for _, flag := range changes.removed {
+		path := flagFile(flag)
+		if err := os.Remove(filepath.Join(g.repo.workingCopy, path)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("impossible to remove the file %s: %w", path, err)
+		}
+	}
+	for _, flag := range slices.Concat(changes.created, changes.updated) {
+		if err := g.writeFlagFile(flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFiles returns the content of the files before a change, nil for the files that do not exist.
+func (g *gitFlagImpl) readFiles(files []string) (map[string][]byte, error) {
+	originals := make(map[string][]byte, len(files))
+	for _, path := range files {
+		content, err := os.ReadFile(filepath.Join(g.repo.workingCopy, path))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("impossible to read the file %s: %w", path, err)
+		}
+		originals[path] = content
+	}
+	return originals, nil
+}
+
+// restore puts back the files as they were before a change that cannot be committed, and returns the error
+// of the change.
+func (g *gitFlagImpl) restore(originals map[string][]byte, files []string, changeErr error) error {
+	errs := []error{changeErr, g.repo.unstage(directories(files))}
+	for _, path := range files {
+		file := filepath.Join(g.repo.workingCopy, path)
+		if originals[path] == nil {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("impossible to restore the file %s: %w", path, err))
+			}
+			continue
+		}
+		if err := os.WriteFile(file, originals[path], 0o600); err != nil {
+			errs = append(errs, fmt.Errorf("impossible to restore the file %s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// requestPush wakes up the goroutine pushing the commits without waiting for the push, nothing is done if a
+// push is already requested because it also sends the new commits.
+func (g *gitFlagImpl) requestPush() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.pushes == nil || g.closed {
+		return
+	}
+	select {
+	case g.pushes <- struct{}{}:
+	default:
+	}
+}
+
+// pushLoop pushes the commits every time a push is requested, until Close is called.
+func (g *gitFlagImpl) pushLoop() {
+	defer close(g.pushed)
+	for range g.pushes {
+		g.push()
+	}
+}
+
+// push sends the commits to the remote, every failure is logged and kept for Ping.
+func (g *gitFlagImpl) push() {
+	err := g.repo.push()
+	if err != nil {
+		zap.L().Error("impossible to push the flags to the remote",
+			zap.String("remote", g.repo.remote), zap.Error(err))
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pushErr = err
+}
+
+// writeFlagFile writes the flag in its file in the format read by GO Feature Flag.
+func (g *gitFlagImpl) writeFlagFile(flag model.FeatureFlag) error {
+	path := flagFile(flag)
+	content, err := flagconfig.Marshal(flagconfig.FromModelFlags([]model.FeatureFlag{flag}), flagconfig.FormatYAML)
+	if err != nil {
+		return fmt.Errorf("impossible to serialize the flag %s: %w", flag.Name, err)
+	}
+	file := filepath.Join(g.repo.workingCopy, path)
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return fmt.Errorf("impossible to write the file %s: %w", path, err)
+	}
+	if err := os.WriteFile(file, content, 0o600); err != nil {
+		return fmt.Errorf("impossible to write the file %s: %w", path, err)
+	}
+	return nil
+}
+
+// flagChanges are the flags modified by a change of the memory storage.
+type flagChanges struct {
+	created []model.FeatureFlag
+	updated []model.FeatureFlag
+	deleted []model.FeatureFlag
+	// removed are the flags whose file should be removed, the deleted flags and the previous name of the
+	// renamed flags
+	removed []model.FeatureFlag
+}
+
+// diffFlags compares the flags before and after a change, the revision of a flag changes with every update.
+func diffFlags(previous []model.FeatureFlag, next []model.FeatureFlag) flagChanges {
+	changes := flagChanges{}
+	previousByID := make(map[string]model.FeatureFlag, len(previous))
+	for _, flag := range previous {
+		previousByID[flag.ID] = flag
+	}
+	nextIDs := make(map[string]bool, len(next))
+	for _, flag := range next {
+		nextIDs[flag.ID] = true
+		existing, found := previousByID[flag.ID]
+		switch {
+		case !found:
+			changes.created = append(changes.created, flag)
+		case existing.Revision != flag.Revision:
+			changes.updated = append(changes.updated, flag)
+			if flagFile(existing) != flagFile(flag) {
+				changes.removed = append(changes.removed, existing)
+			}
+		}
+	}
+	for _, flag := range previous {
+		if !nextIDs[flag.ID] {
+			changes.deleted = append(changes.deleted, flag)
+			changes.removed = append(changes.removed, flag)
+		}
+	}
+	return changes
+}
+
+func (c flagChanges) empty() bool {
+	return len(c.created)+len(c.updated)+len(c.deleted) == 0
+}
+
+// files returns the paths of all the files modified by the changes, relative to the working copy.
+func (c flagChanges) files() []string {
+	files := []string{}
+	for _, flag := range slices.Concat(c.created, c.updated, c.removed) {
+		files = append(files, flagFile(flag))
+	}
+	slices.Sort(files)
+	return slices.Compact(files)
+}
+
+// message returns the commit message of the changes, the action of a single flag can be given by the context.
+func (c flagChanges) message(ctx context.Context) string {
+	action, _ := ctx.Value(actionKey{}).(string)
+	switch {
+	case len(c.created) == 1 && len(c.updated)+len(c.deleted) == 0:
+		return commitMessage(cmp.Or(action, "Create"), c.created[0])
+	case len(c.updated) == 1 && len(c.created)+len(c.deleted) == 0:
+		return commitMessage(cmp.Or(action, "Update"), c.updated[0])
+	case len(c.deleted) == 1 && len(c.created)+len(c.updated) == 0:
+		return commitMessage(cmp.Or(action, "Delete"), c.deleted[0])
+	case len(c.deleted) == 0:
+		return fmt.Sprintf("Import %d flags", len(c.created)+len(c.updated))
+	default:
+		return fmt.Sprintf("Change %d flags", len(c.created)+len(c.updated)+len(c.deleted))
+	}
+}
+
+// author returns the user who made a change, it is the principal of the request (or of the scheduled change)
+// and not the LastModifiedBy of the flags because they come from the body of the requests.
+func author(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		return principal.String()
+	}
+	return ""
+}
+
+// directories returns the directories of the files, without duplicates.
+func directories(files []string) []string {
+	dirs := make([]string, 0, len(files))
+	for _, path := range files {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+	slices.Sort(dirs)
+	return slices.Compact(dirs)
+}
+
+// flagFile returns the path of the file of a flag relative to the working copy.
+// The names of the projects and environments are safe paths, the name of the flag is escaped because it can
+// contain any character.
+func flagFile(flag model.FeatureFlag) string {
+	return filepath.Join(flag.GetProject(), flag.GetEnvironment(), url.PathEscape(flag.Name)+".yaml")
+}
+
+func commitMessage(action string, flag model.FeatureFlag) string {
+	return fmt.Sprintf("%s flag %s in %s/%s", action, flag.Name, flag.GetProject(), flag.GetEnvironment())
+}
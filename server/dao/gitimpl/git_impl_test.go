@@ -0,0 +1,243 @@
+package gitimpl_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/dao/gitimpl"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newFlag(name string, user string) model.FeatureFlag {
+	return model.FeatureFlag{
+		Name:            name,
+		VariationType:   model.FlagTypeString,
+		Variations:      &map[string]interface{}{"A": "a", "B": "b"},
+		DefaultRule:     &model.Rule{VariationResult: testutils.String("A")},
+		CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastModifiedBy:  user,
+	}
+}
+
+// as returns a context with the principal making the changes
+func as(user string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Name: user})
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+	return strings.TrimSpace(string(output))
+}
+
+func skipWithoutGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+}
+
+func TestGitDao_Flags(t *testing.T) {
+	skipWithoutGit(t)
+	ctx := as("alice")
+	workingCopy := filepath.Join(t.TempDir(), "flags")
+	storage, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{})
+	require.NoError(t, err)
+
+	id, daoErr := storage.CreateFlag(as("alice@example.com"), newFlag("my-flag", "not the author"))
+	require.Nil(t, daoErr)
+	content, err := os.ReadFile(filepath.Join(workingCopy, "default", "default", "my-flag.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "my-flag:\n  variations:\n    A: a\n    B: b\n  defaultRule:\n    variation: A\n", string(content))
+
+	flag, daoErr := storage.GetFlagByID(ctx, id)
+	require.Nil(t, daoErr)
+	flag.Name = "renamed/flag"
+	require.Nil(t, storage.UpdateFlag(as("bob"), flag))
+	assert.NoFileExists(t, filepath.Join(workingCopy, "default", "default", "my-flag.yaml"))
+	assert.FileExists(t, filepath.Join(workingCopy, "default", "default", "renamed%2Fflag.yaml"),
+		"the name of the flag should be escaped")
+
+	require.Nil(t, storage.DeleteFlagByID(as("bob"), id))
+	assert.NoFileExists(t, filepath.Join(workingCopy, "default", "default", "renamed%2Fflag.yaml"))
+
+	log := git(t, workingCopy, "log", "--format=%an <%ae> %s")
+	assert.Equal(t, strings.Join([]string{
+		"bob <> Delete flag renamed/flag in default/default",
+		"bob <> Update flag renamed/flag in default/default",
+		"alice@example.com <alice@example.com> Create flag my-flag in default/default",
+	}, "\n"), log)
+	assert.Empty(t, git(t, workingCopy, "status", "--porcelain"), "every change should be committed")
+
+	// a change without any difference in the file does not create an empty commit
+	id, daoErr = storage.CreateFlag(ctx, newFlag("other-flag", "alice"))
+	require.Nil(t, daoErr)
+	flag, _ = storage.GetFlagByID(ctx, id)
+	flag.Description = testutils.String("not in the file")
+	require.Nil(t, storage.UpdateFlag(ctx, flag))
+	assert.Equal(t, "4", git(t, workingCopy, "rev-list", "--count", "HEAD"))
+}
+
+func TestGitDao_Reopen(t *testing.T) {
+	skipWithoutGit(t)
+	ctx := context.Background()
+	workingCopy := t.TempDir()
+	storage, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{})
+	require.NoError(t, err)
+	id, daoErr := storage.CreateFlag(ctx, newFlag("my-flag", "alice"))
+	require.Nil(t, daoErr)
+
+	reopened, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{})
+	require.NoError(t, err)
+	flag, daoErr := reopened.GetFlagByID(ctx, id)
+	require.Nil(t, daoErr)
+	assert.Equal(t, "my-flag", flag.Name)
+	assert.Equal(t, "default/default/my-flag.yaml", git(t, workingCopy, "ls-files"),
+		"only the flag files should be committed")
+}
+
+func TestGitDao_ImportFlags(t *testing.T) {
+	skipWithoutGit(t)
+	ctx := context.Background()
+	workingCopy := t.TempDir()
+	storage, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{})
+	require.NoError(t, err)
+
+	require.Nil(t, storage.ImportFlags(ctx,
+		[]model.FeatureFlag{newFlag("flag1", "alice"), newFlag("flag2", "alice")}, nil))
+	assert.Equal(t, "Import 2 flags", git(t, workingCopy, "log", "--format=%s"))
+	assert.Equal(t, "default/default/flag1.yaml\ndefault/default/flag2.yaml", git(t, workingCopy, "ls-files"))
+}
+
+func TestGitDao_Push(t *testing.T) {
+	skipWithoutGit(t)
+	ctx := context.Background()
+	remote := t.TempDir()
+	git(t, remote, "init", "--quiet", "--bare")
+	workingCopy := t.TempDir()
+	storage, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{Remote: remote, Branch: "flags"})
+	require.NoError(t, err)
+
+	_, daoErr := storage.CreateFlag(ctx, newFlag("my-flag", "alice"))
+	require.Nil(t, daoErr)
+	// Close waits for the push running in the background
+	require.NoError(t, storage.(io.Closer).Close())
+	assert.Equal(t, "Create flag my-flag in default/default", git(t, remote, "log", "--format=%s", "flags"))
+	assert.Nil(t, storage.Ping())
+}
+
+func TestGitDao_PushError(t *testing.T) {
+	skipWithoutGit(t)
+	core, logs := observer.New(zap.ErrorLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+	ctx := context.Background()
+	workingCopy := t.TempDir()
+	storage, err := gitimpl.NewGitDao(workingCopy,
+		gitimpl.Options{Remote: filepath.Join(t.TempDir(), "missing")})
+	require.NoError(t, err)
+
+	failedPushes := func() int { return logs.FilterMessage("impossible to push the flags to the remote").Len() }
+
+	_, daoErr := storage.CreateFlag(ctx, newFlag("my-flag", "alice"))
+	require.Nil(t, daoErr, "the flag should be saved even if the push fails")
+	assert.Equal(t, "1", git(t, workingCopy, "rev-list", "--count", "HEAD"))
+	require.Eventually(t, func() bool { return failedPushes() == 1 }, 5*time.Second, 10*time.Millisecond,
+		"every failed push should be logged")
+	daoErr = storage.Ping()
+	require.NotNil(t, daoErr)
+	assert.Contains(t, daoErr.Error(), "last push failed")
+
+	_, daoErr = storage.CreateFlag(ctx, newFlag("other-flag", "alice"))
+	require.Nil(t, daoErr)
+	err = storage.(io.Closer).Close()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "last push failed")
+	assert.Equal(t, 2, failedPushes())
+
+	_, daoErr = storage.CreateFlag(ctx, newFlag("last-flag", "alice"))
+	require.Nil(t, daoErr, "the changes should still be committed once the storage is closed")
+	assert.Equal(t, 2, failedPushes(), "nothing should be pushed once the storage is closed")
+}
+
+func TestGitDao_CommitError(t *testing.T) {
+	skipWithoutGit(t)
+	ctx := context.Background()
+	workingCopy := t.TempDir()
+	storage, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{})
+	require.NoError(t, err)
+	id, daoErr := storage.CreateFlag(ctx, newFlag("my-flag", "alice"))
+	require.Nil(t, daoErr)
+
+	// a hook refusing every commit
+	hook := filepath.Join(workingCopy, ".git", "hooks", "pre-commit")
+	require.NoError(t, os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0o700))
+
+	_, daoErr = storage.CreateFlag(ctx, newFlag("other-flag", "alice"))
+	require.NotNil(t, daoErr)
+	_, daoErr = storage.GetFlagByName(ctx, "default", "default", "other-flag")
+	require.NotNil(t, daoErr, "the flag should not be created if its file is not committed")
+	assert.NoFileExists(t, filepath.Join(workingCopy, "default", "default", "other-flag.yaml"))
+
+	flag, _ := storage.GetFlagByID(ctx, id)
+	flag.Name = "renamed-flag"
+	require.NotNil(t, storage.UpdateFlag(ctx, flag))
+	flag, daoErr = storage.GetFlagByID(ctx, id)
+	require.Nil(t, daoErr)
+	assert.Equal(t, "my-flag", flag.Name, "the flag should not be updated if its file is not committed")
+	assert.FileExists(t, filepath.Join(workingCopy, "default", "default", "my-flag.yaml"))
+	assert.Empty(t, git(t, workingCopy, "status", "--porcelain"), "the working copy should be restored")
+}
+
+func TestGitDao_LoadFlagFiles(t *testing.T) {
+	skipWithoutGit(t)
+	ctx := context.Background()
+	workingCopy := t.TempDir()
+	storage, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{})
+	require.NoError(t, err)
+	_, daoErr := storage.CreateFlag(ctx, newFlag("my-flag", "alice"))
+	require.Nil(t, daoErr)
+	_, daoErr = storage.CreateFlag(ctx, newFlag("removed-flag", "alice"))
+	require.Nil(t, daoErr)
+
+	// changes committed outside the API
+	require.NoError(t, os.WriteFile(filepath.Join(workingCopy, "default", "default", "my-flag.yaml"),
+		[]byte("my-flag:\n  variations:\n    A: a\n    B: b\n  defaultRule:\n    variation: B\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(workingCopy, "checkout", "production"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(workingCopy, "checkout", "production", "new-flag.yaml"),
+		[]byte("new-flag:\n  variations:\n    enabled: true\n    disabled: false\n  defaultRule:\n    variation: enabled\n"),
+		0o600))
+	require.NoError(t, os.Remove(filepath.Join(workingCopy, "default", "default", "removed-flag.yaml")))
+	git(t, workingCopy, "add", "--all")
+	git(t, workingCopy, "-c", "user.name=carol", "-c", "user.email=", "commit", "--quiet", "--message", "manual change")
+
+	reopened, err := gitimpl.NewGitDao(workingCopy, gitimpl.Options{})
+	require.NoError(t, err)
+	flag, daoErr := reopened.GetFlagByName(ctx, "default", "default", "my-flag")
+	require.Nil(t, daoErr)
+	assert.Equal(t, "B", *flag.DefaultRule.VariationResult)
+	_, daoErr = reopened.GetFlagByName(ctx, "checkout", "production", "new-flag")
+	assert.Nil(t, daoErr, "the flags of a new environment should be created")
+	_, daoErr = reopened.GetFlagByName(ctx, "default", "default", "removed-flag")
+	assert.NotNil(t, daoErr, "the flags without file should be deleted")
+	assert.Equal(t, "manual change", git(t, workingCopy, "log", "-1", "--format=%s"),
+		"loading the files should not create a commit")
+}
+
+func TestNewGitDao_EmptyWorkingCopy(t *testing.T) {
+	_, err := gitimpl.NewGitDao("", gitimpl.Options{})
+	assert.EqualError(t, err, "working copy is empty")
+}
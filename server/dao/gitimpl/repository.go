@@ -0,0 +1,112 @@
+package gitimpl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultAuthor is the author of the commits when the user who made the change is unknown.
+const defaultAuthor = "goff-management"
+
+// repository runs the git command line in a local working copy.
+type repository struct {
+	workingCopy string
+	// gitDir is the absolute path of the .git directory of the working copy
+	gitDir string
+	remote string
+	branch string
+}
+
+// openRepository returns the repository of the working copy, the directory and the repository are created
+// if they do not exist yet.
+func openRepository(workingCopy string, options Options) (*repository, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git storage needs the git command line: %w", err)
+	}
+	if err := os.MkdirAll(workingCopy, 0o750); err != nil {
+		return nil, fmt.Errorf("impossible to create the working copy %s: %w", workingCopy, err)
+	}
+	r := &repository{workingCopy: workingCopy, remote: options.Remote, branch: options.Branch}
+	if _, err := r.run(nil, "rev-parse", "--is-inside-work-tree"); err != nil {
+		if _, err := r.run(nil, "init", "--quiet"); err != nil {
+			return nil, err
+		}
+	}
+	gitDir, err := r.run(nil, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, err
+	}
+	r.gitDir = gitDir
+	return r, nil
+}
+
+// commit records the changes of the directories with the user as author and committer, nothing is committed
+// if the files did not change.
+// The directories are added instead of the files, so the files removed before being committed are not an error.
+func (r *repository) commit(dirs []string, message string, user string) error {
+	if _, err := r.run(nil, append([]string{"add", "--all", "--"}, dirs...)...); err != nil {
+		return err
+	}
+	// diff --quiet exits with 1 when something is staged
+	if _, err := r.run(nil, "diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	name, email := identity(user)
+	env := []string{
+		"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + email,
+		"GIT_COMMITTER_NAME=" + name, "GIT_COMMITTER_EMAIL=" + email,
+	}
+	_, err := r.run(env, "commit", "--quiet", "--message", message)
+	return err
+}
+
+// unstage removes the changes of the directories from the index, after a change that cannot be committed.
+func (r *repository) unstage(dirs []string) error {
+	_, err := r.run(nil, append([]string{"reset", "--quiet", "--"}, dirs...)...)
+	return err
+}
+
+// push sends the commits to the remote, it does nothing if no remote is configured.
+// The branch of the remote is the current branch if no branch is configured.
+func (r *repository) push() error {
+	if r.remote == "" {
+		return nil
+	}
+	refspec := "HEAD"
+	if r.branch != "" {
+		refspec = "HEAD:refs/heads/" + r.branch
+	}
+	_, err := r.run(nil, "push", "--quiet", r.remote, refspec)
+	return err
+}
+
+// run executes a git command in the working copy and returns its trimmed output.
+func (r *repository) run(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.workingCopy
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(output)))
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// identity returns the name and the email used in the commits of a user, the users are identified by
+// their name or their email in the API.
+func identity(user string) (name string, email string) {
+	if user == "" {
+		user = defaultAuthor
+	}
+	if strings.Contains(user, "@") {
+		return user, user
+	}
+	return user, ""
+}
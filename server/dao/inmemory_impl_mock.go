@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-feature-flag/flag-management/server/model"
 	_ "github.com/lib/pq" // we import the driver used by sqlx
@@ -11,25 +14,52 @@ import (
 
 func NewInMemoryMockDao() (*InMemoryMockDao, error) {
 	return &InMemoryMockDao{
-		flags: []model.FeatureFlag{},
+		flags:     []model.FeatureFlag{},
+		versions:  []model.FlagVersion{},
+		apiKeys:   []model.APIKey{},
+		schedules: []model.ScheduledChange{},
+		projects:  []model.Project{{Name: model.DefaultProject}},
+		environments: []model.Environment{
+			{Project: model.DefaultProject, Name: model.DefaultEnvironment},
+		},
 	}, nil
 }
 
 type InMemoryMockDao struct {
-	flags []model.FeatureFlag
+	flags     []model.FeatureFlag
+	versions  []model.FlagVersion
+	apiKeys   []model.APIKey
+	schedules []model.ScheduledChange
+
+	projects     []model.Project
+	environments []model.Environment
 
 	errorOnPing bool
 }
 
-// GetFlags return all the flags
-func (m *InMemoryMockDao) GetFlags(ctx context.Context) ([]model.FeatureFlag, daoErr.DaoError) {
+// GetFlags return the flags matching the query and the total number of flags matching the filters
+func (m *InMemoryMockDao) GetFlags(ctx context.Context, query FlagQuery) ([]model.FeatureFlag, int, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return nil, 0, daoErr.NewDaoError(err, fmt.Errorf("error on get flags"))
+		}
+		return nil, 0, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get flags"))
+	}
+	flags, total := ApplyFlagQuery(m.flags, query)
+	return flags, total, nil
+}
+
+// GetFlagsSummary return the number of flags matching the query and their most recent last updated date
+func (m *InMemoryMockDao) GetFlagsSummary(ctx context.Context, query FlagQuery) (FlagsSummary, daoErr.DaoError) {
 	if ctx.Value("error") != nil {
 		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
-			return nil, daoErr.NewDaoError(err, fmt.Errorf("error on get flags"))
+			return FlagsSummary{}, daoErr.NewDaoError(err, fmt.Errorf("error on get flags"))
 		}
-		return nil, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get flags"))
+		return FlagsSummary{}, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get flags"))
 	}
-	return m.flags, nil
+	summary := SummarizeFlags(m.flags, query)
+	summary.VersionCount = len(m.versions)
+	return summary, nil
 }
 
 // GetFlagByID return a flag by its ID
@@ -48,8 +78,9 @@ func (m *InMemoryMockDao) GetFlagByID(ctx context.Context, id string) (model.Fea
 	return model.FeatureFlag{}, daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("flag with id %s not found", id))
 }
 
-// GetFlagByName return a flag by its name
-func (m *InMemoryMockDao) GetFlagByName(ctx context.Context, name string) (model.FeatureFlag, daoErr.DaoError) {
+// GetFlagByName return a flag by its name inside an environment
+func (m *InMemoryMockDao) GetFlagByName(
+	ctx context.Context, project string, environment string, name string) (model.FeatureFlag, daoErr.DaoError) {
 	if ctx.Value("error") != nil {
 		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
 			return model.FeatureFlag{}, daoErr.NewDaoError(err, fmt.Errorf("error on get flag by name"))
@@ -57,7 +88,7 @@ func (m *InMemoryMockDao) GetFlagByName(ctx context.Context, name string) (model
 		return model.FeatureFlag{}, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get flag by name"))
 	}
 	for _, flag := range m.flags {
-		if flag.Name == name {
+		if flag.Name == name && flag.GetProject() == project && flag.GetEnvironment() == environment {
 			return flag, nil
 		}
 	}
@@ -73,7 +104,9 @@ func (m *InMemoryMockDao) CreateFlag(ctx context.Context, flag model.FeatureFlag
 		return "", daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error creating flag"))
 	}
 
+	flag.Revision = 1
 	m.flags = append(m.flags, flag)
+	m.addVersion(flag, model.FlagVersionActionCreated, flag.LastUpdatedDate)
 	return flag.ID, nil
 }
 
@@ -86,13 +119,45 @@ func (m *InMemoryMockDao) UpdateFlag(ctx context.Context, flag model.FeatureFlag
 	}
 	for index, f := range m.flags {
 		if f.ID == flag.ID {
+			if f.Revision != flag.Revision {
+				return daoErr.NewDaoError(daoErr.StaleRevision,
+					fmt.Errorf("flag %s has been modified since revision %d", flag.ID, flag.Revision))
+			}
+			flag.Revision++
 			m.flags[index] = flag
+			m.addVersion(flag, model.FlagVersionActionUpdated, flag.LastUpdatedDate)
 			return nil
 		}
 	}
 	return daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("flag with id %s not found", flag.ID))
 }
 
+// ImportFlags create and update a list of flags, the storage is left untouched if one of the changes fails
+func (m *InMemoryMockDao) ImportFlags(
+	ctx context.Context, creations []model.FeatureFlag, updates []model.FeatureFlag) daoErr.DaoError {
+	if ctx.Value("error_import") != nil {
+		if err, ok := ctx.Value("error_import").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error on import flags"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on import flags"))
+	}
+
+	flags, versions := slices.Clone(m.flags), slices.Clone(m.versions)
+	for _, flag := range creations {
+		if _, err := m.CreateFlag(ctx, flag); err != nil {
+			m.flags, m.versions = flags, versions
+			return err
+		}
+	}
+	for _, flag := range updates {
+		if err := m.UpdateFlag(ctx, flag); err != nil {
+			m.flags, m.versions = flags, versions
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *InMemoryMockDao) DeleteFlagByID(ctx context.Context, id string) daoErr.DaoError {
 	if ctx.Value("error_delete") != nil {
 		if err, ok := ctx.Value("error_delete").(daoErr.DaoErrorCode); ok {
@@ -105,12 +170,386 @@ func (m *InMemoryMockDao) DeleteFlagByID(ctx context.Context, id string) daoErr.
 	for _, f := range m.flags {
 		if f.ID != id {
 			newInmemoryFlagList = append(newInmemoryFlagList, f)
+			continue
 		}
+		m.addVersion(f, model.FlagVersionActionDeleted, time.Now())
 	}
 	m.flags = newInmemoryFlagList
+
+	// the scheduled changes are deleted with their flag
+	m.schedules = slices.DeleteFunc(m.schedules, func(change model.ScheduledChange) bool {
+		return change.FlagID == id
+	})
 	return nil
 }
 
+func (m *InMemoryMockDao) GetFlagVersions(
+	ctx context.Context, flagID string, limit int, offset int) ([]model.FlagVersion, int, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return nil, 0, daoErr.NewDaoError(err, fmt.Errorf("error on get flag versions"))
+		}
+		return nil, 0, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get flag versions"))
+	}
+
+	// versions are stored from the oldest to the most recent
+	flagVersions := []model.FlagVersion{}
+	for i := len(m.versions) - 1; i >= 0; i-- {
+		if m.versions[i].FlagID == flagID {
+			flagVersions = append(flagVersions, m.versions[i])
+		}
+	}
+	if len(flagVersions) == 0 {
+		return nil, 0, daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("no version found for flag %s", flagID))
+	}
+	if offset >= len(flagVersions) {
+		return []model.FlagVersion{}, len(flagVersions), nil
+	}
+	end := offset + limit
+	if end > len(flagVersions) {
+		end = len(flagVersions)
+	}
+	return flagVersions[offset:end], len(flagVersions), nil
+}
+
+func (m *InMemoryMockDao) GetFlagVersion(
+	ctx context.Context, flagID string, versionID string) (model.FlagVersion, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return model.FlagVersion{}, daoErr.NewDaoError(err, fmt.Errorf("error on get flag version"))
+		}
+		return model.FlagVersion{}, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get flag version"))
+	}
+	for _, v := range m.versions {
+		if v.FlagID == flagID && v.ID == versionID {
+			return v, nil
+		}
+	}
+	return model.FlagVersion{}, daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("version %s not found", versionID))
+}
+
+func (m *InMemoryMockDao) addVersion(flag model.FeatureFlag, action model.FlagVersionAction, date time.Time) {
+	versionNumber := 1
+	for _, v := range m.versions {
+		if v.FlagID == flag.ID {
+			versionNumber++
+		}
+	}
+	m.versions = append(m.versions, model.FlagVersion{
+		ID:            fmt.Sprintf("%s-%d", flag.ID, versionNumber),
+		FlagID:        flag.ID,
+		VersionNumber: versionNumber,
+		Action:        action,
+		CreatedDate:   date,
+		Flag:          flag,
+	})
+}
+
+// GetAPIKeys return all the API keys (the most recent first)
+func (m *InMemoryMockDao) GetAPIKeys(ctx context.Context) ([]model.APIKey, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return nil, daoErr.NewDaoError(err, fmt.Errorf("error on get api keys"))
+		}
+		return nil, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get api keys"))
+	}
+	apiKeys := make([]model.APIKey, 0, len(m.apiKeys))
+	for i := len(m.apiKeys) - 1; i >= 0; i-- {
+		apiKeys = append(apiKeys, m.apiKeys[i])
+	}
+	return apiKeys, nil
+}
+
+// GetAPIKeyByHashedKey return the API key matching the hash of the key
+func (m *InMemoryMockDao) GetAPIKeyByHashedKey(ctx context.Context, hashedKey string) (model.APIKey, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return model.APIKey{}, daoErr.NewDaoError(err, fmt.Errorf("error on get api key"))
+		}
+		return model.APIKey{}, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get api key"))
+	}
+	for _, apiKey := range m.apiKeys {
+		if apiKey.HashedKey == hashedKey {
+			return apiKey, nil
+		}
+	}
+	return model.APIKey{}, daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("api key not found"))
+}
+
+// CreateAPIKey create a new API key, return the id of the API key
+func (m *InMemoryMockDao) CreateAPIKey(ctx context.Context, apiKey model.APIKey) (string, daoErr.DaoError) {
+	if ctx.Value("error_create") != nil {
+		if err, ok := ctx.Value("error_create").(daoErr.DaoErrorCode); ok {
+			return "", daoErr.NewDaoError(err, fmt.Errorf("error creating api key"))
+		}
+		return "", daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error creating api key"))
+	}
+	m.apiKeys = append(m.apiKeys, apiKey)
+	return apiKey.ID, nil
+}
+
+// DeleteAPIKeyByID revoke an API key
+func (m *InMemoryMockDao) DeleteAPIKeyByID(ctx context.Context, id string) daoErr.DaoError {
+	if ctx.Value("error_delete") != nil {
+		if err, ok := ctx.Value("error_delete").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error on delete api key"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on delete api key"))
+	}
+	for index, apiKey := range m.apiKeys {
+		if apiKey.ID == id {
+			m.apiKeys = append(m.apiKeys[:index], m.apiKeys[index+1:]...)
+			return nil
+		}
+	}
+	return daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("api key with id %s not found", id))
+}
+
+// UpdateAPIKeyLastUsedDate record the last time the API key has been used
+func (m *InMemoryMockDao) UpdateAPIKeyLastUsedDate(ctx context.Context, id string, date time.Time) daoErr.DaoError {
+	if ctx.Value("error_update") != nil {
+		if err, ok := ctx.Value("error_update").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error on update api key"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on update api key"))
+	}
+	for index, apiKey := range m.apiKeys {
+		if apiKey.ID == id {
+			m.apiKeys[index].LastUsedDate = &date
+			return nil
+		}
+	}
+	return daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("api key with id %s not found", id))
+}
+
+// GetScheduledChanges return the scheduled changes matching the query, ordered by scheduled date
+func (m *InMemoryMockDao) GetScheduledChanges(
+	ctx context.Context, query ScheduledChangeQuery) ([]model.ScheduledChange, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return nil, daoErr.NewDaoError(err, fmt.Errorf("error on get scheduled changes"))
+		}
+		return nil, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get scheduled changes"))
+	}
+	changes := []model.ScheduledChange{}
+	for _, change := range m.schedules {
+		if query.Match(change) {
+			changes = append(changes, change)
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b model.ScheduledChange) int {
+		return a.ScheduledDate.Compare(b.ScheduledDate)
+	})
+	return changes, nil
+}
+
+// GetScheduledChangeByID return a scheduled change by its ID
+func (m *InMemoryMockDao) GetScheduledChangeByID(ctx context.Context, id string) (model.ScheduledChange, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return model.ScheduledChange{}, daoErr.NewDaoError(err, fmt.Errorf("error on get scheduled change"))
+		}
+		return model.ScheduledChange{}, daoErr.NewDaoError(daoErr.UnknownError,
+			fmt.Errorf("error on get scheduled change"))
+	}
+	for _, change := range m.schedules {
+		if change.ID == id {
+			return change, nil
+		}
+	}
+	return model.ScheduledChange{}, daoErr.NewDaoError(daoErr.NotFound,
+		fmt.Errorf("scheduled change with id %s not found", id))
+}
+
+// CreateScheduledChange create a new scheduled change, return the id of the scheduled change
+func (m *InMemoryMockDao) CreateScheduledChange(
+	ctx context.Context, change model.ScheduledChange) (string, daoErr.DaoError) {
+	if ctx.Value("error_create") != nil {
+		if err, ok := ctx.Value("error_create").(daoErr.DaoErrorCode); ok {
+			return "", daoErr.NewDaoError(err, fmt.Errorf("error creating scheduled change"))
+		}
+		return "", daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error creating scheduled change"))
+	}
+	m.schedules = append(m.schedules, change)
+	return change.ID, nil
+}
+
+// UpdateScheduledChange update a pending scheduled change
+func (m *InMemoryMockDao) UpdateScheduledChange(ctx context.Context, change model.ScheduledChange) daoErr.DaoError {
+	if ctx.Value("error_update") != nil {
+		if err, ok := ctx.Value("error_update").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error on update scheduled change"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on update scheduled change"))
+	}
+	for index, existing := range m.schedules {
+		if existing.ID == change.ID {
+			if !existing.IsPending() {
+				return daoErr.NewDaoError(daoErr.StaleRevision,
+					fmt.Errorf("scheduled change %s is %s", change.ID, existing.Status))
+			}
+			m.schedules[index] = change
+			return nil
+		}
+	}
+	return daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("scheduled change with id %s not found", change.ID))
+}
+
+// ApplyScheduledChange updates the flag and the change, the storage is left untouched if one of them fails
+func (m *InMemoryMockDao) ApplyScheduledChange(
+	ctx context.Context, flag model.FeatureFlag, change model.ScheduledChange) daoErr.DaoError {
+	flags, versions, schedules := slices.Clone(m.flags), slices.Clone(m.versions), slices.Clone(m.schedules)
+	if err := m.UpdateScheduledChange(ctx, change); err != nil {
+		return err
+	}
+	if err := m.UpdateFlag(ctx, flag); err != nil {
+		m.flags, m.versions, m.schedules = flags, versions, schedules
+		return err
+	}
+	return nil
+}
+
+// SetScheduledChanges replaces all the scheduled changes of the storage
+func (m *InMemoryMockDao) SetScheduledChanges(changes []model.ScheduledChange) {
+	m.schedules = changes
+}
+
+// GetProjects return all the projects ordered by name
+func (m *InMemoryMockDao) GetProjects(ctx context.Context) ([]model.Project, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return nil, daoErr.NewDaoError(err, fmt.Errorf("error on get projects"))
+		}
+		return nil, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get projects"))
+	}
+	projects := slices.Clone(m.projects)
+	slices.SortFunc(projects, func(a, b model.Project) int { return strings.Compare(a.Name, b.Name) })
+	return projects, nil
+}
+
+// GetProject return a project by its name
+func (m *InMemoryMockDao) GetProject(ctx context.Context, name string) (model.Project, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return model.Project{}, daoErr.NewDaoError(err, fmt.Errorf("error on get project"))
+		}
+		return model.Project{}, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get project"))
+	}
+	for _, project := range m.projects {
+		if project.Name == name {
+			return project, nil
+		}
+	}
+	return model.Project{}, daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("project %s not found", name))
+}
+
+// CreateProject create a new project
+func (m *InMemoryMockDao) CreateProject(ctx context.Context, project model.Project) daoErr.DaoError {
+	if ctx.Value("error_create") != nil {
+		if err, ok := ctx.Value("error_create").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error creating project"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error creating project"))
+	}
+	m.projects = append(m.projects, project)
+	return nil
+}
+
+// DeleteProject delete a project, it returns an InUse error if the project still has environments
+func (m *InMemoryMockDao) DeleteProject(ctx context.Context, name string) daoErr.DaoError {
+	if ctx.Value("error_delete") != nil {
+		if err, ok := ctx.Value("error_delete").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error on delete project"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on delete project"))
+	}
+	for _, environment := range m.environments {
+		if environment.Project == name {
+			return daoErr.NewDaoError(daoErr.InUse, fmt.Errorf("project %s still has environments", name))
+		}
+	}
+	for index, project := range m.projects {
+		if project.Name == name {
+			m.projects = append(m.projects[:index], m.projects[index+1:]...)
+			return nil
+		}
+	}
+	return daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("project %s not found", name))
+}
+
+// GetEnvironments return the environments of a project ordered by name
+func (m *InMemoryMockDao) GetEnvironments(ctx context.Context, project string) ([]model.Environment, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return nil, daoErr.NewDaoError(err, fmt.Errorf("error on get environments"))
+		}
+		return nil, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get environments"))
+	}
+	environments := []model.Environment{}
+	for _, environment := range m.environments {
+		if environment.Project == project {
+			environments = append(environments, environment)
+		}
+	}
+	slices.SortFunc(environments, func(a, b model.Environment) int { return strings.Compare(a.Name, b.Name) })
+	return environments, nil
+}
+
+// GetEnvironment return an environment of a project by its name
+func (m *InMemoryMockDao) GetEnvironment(
+	ctx context.Context, project string, name string) (model.Environment, daoErr.DaoError) {
+	if ctx.Value("error") != nil {
+		if err, ok := ctx.Value("error").(daoErr.DaoErrorCode); ok {
+			return model.Environment{}, daoErr.NewDaoError(err, fmt.Errorf("error on get environment"))
+		}
+		return model.Environment{}, daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on get environment"))
+	}
+	for _, environment := range m.environments {
+		if environment.Project == project && environment.Name == name {
+			return environment, nil
+		}
+	}
+	return model.Environment{}, daoErr.NewDaoError(daoErr.NotFound,
+		fmt.Errorf("environment %s not found in project %s", name, project))
+}
+
+// CreateEnvironment create a new environment in an existing project
+func (m *InMemoryMockDao) CreateEnvironment(ctx context.Context, environment model.Environment) daoErr.DaoError {
+	if ctx.Value("error_create") != nil {
+		if err, ok := ctx.Value("error_create").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error creating environment"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error creating environment"))
+	}
+	if _, err := m.GetProject(ctx, environment.Project); err != nil {
+		return err
+	}
+	m.environments = append(m.environments, environment)
+	return nil
+}
+
+// DeleteEnvironment delete an environment, it returns an InUse error if the environment still has flags
+func (m *InMemoryMockDao) DeleteEnvironment(ctx context.Context, project string, name string) daoErr.DaoError {
+	if ctx.Value("error_delete") != nil {
+		if err, ok := ctx.Value("error_delete").(daoErr.DaoErrorCode); ok {
+			return daoErr.NewDaoError(err, fmt.Errorf("error on delete environment"))
+		}
+		return daoErr.NewDaoError(daoErr.UnknownError, fmt.Errorf("error on delete environment"))
+	}
+	for _, flag := range m.flags {
+		if flag.GetProject() == project && flag.GetEnvironment() == name {
+			return daoErr.NewDaoError(daoErr.InUse, fmt.Errorf("environment %s still has flags", name))
+		}
+	}
+	for index, environment := range m.environments {
+		if environment.Project == project && environment.Name == name {
+			m.environments = append(m.environments[:index], m.environments[index+1:]...)
+			return nil
+		}
+	}
+	return daoErr.NewDaoError(daoErr.NotFound, fmt.Errorf("environment %s not found in project %s", name, project))
+}
+
 func (m *InMemoryMockDao) Ping() daoErr.DaoError {
 	if m.errorOnPing {
 		return daoErr.NewDaoError(daoErr.DatabaseNotInitialized, fmt.Errorf("error on ping"))
@@ -0,0 +1,477 @@
+package memoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+)
+
+// the memory storage is added to the registry of dao when the package is imported: "memory://" keeps the flags
+// in memory and "file://flags-snapshot.json" also persists them in the snapshot file.
+var (
+	_ = dao.MustRegister("memory", func(connectionString string) (dao.FlagStorage, error) {
+		return NewMemoryDao(dao.TrimScheme(connectionString))
+	})
+	_ = dao.MustRegister("file", func(connectionString string) (dao.FlagStorage, error) {
+		if dao.TrimScheme(connectionString) == "" {
+			return nil, fmt.Errorf("snapshot file is empty")
+		}
+		return NewMemoryDao(dao.TrimScheme(connectionString))
+	})
+)
+
+// NewMemoryDao creates a storage keeping all the data in memory, it is meant for the small installations
+// running a single instance of the API without any database.
+// If snapshotFile is set, the content of the storage is written in this JSON file after every change and
+// reloaded from it at startup, otherwise the data is lost when the server stops.
+func NewMemoryDao(snapshotFile string) (dao.FlagStorage, error) {
+	return NewMemoryDaoWithOptions(Options{SnapshotFile: snapshotFile})
+}
+
+// Options configures the memory storage.
+type Options struct {
+	// SnapshotFile is the JSON file where the content of the storage is persisted, nothing is persisted if it
+	// is empty.
+	SnapshotFile string
+	// OnSave is called with the flags before and after every change once the snapshot file is written, while
+	// the other changes wait. The change is kept only if it returns nil, otherwise the previous snapshot file
+	// is written back, so another storage can be written together with the memory.
+	OnSave func(ctx context.Context, previous []model.FeatureFlag, next []model.FeatureFlag) error
+}
+
+// NewMemoryDaoWithOptions creates a memory storage like NewMemoryDao with all the options.
+func NewMemoryDaoWithOptions(options Options) (dao.FlagStorage, error) {
+	data, err := loadSnapshot(options.SnapshotFile)
+	if err != nil {
+		return nil, err
+	}
+	return &memoryFlagImpl{data: data, snapshotFile: options.SnapshotFile, onSave: options.OnSave}, nil
+}
+
+type memoryFlagImpl struct {
+	// mu protects data, the readers get copies of the data and the writers replace it entirely
+	mu           sync.RWMutex
+	data         snapshot
+	snapshotFile string
+	onSave       func(ctx context.Context, previous []model.FeatureFlag, next []model.FeatureFlag) error
+}
+
+// update applies the changes on a copy of the data, the copy is kept only if the changes succeed, the snapshot
+// is written and onSave accepts them, so a failing change never leaves the storage half modified.
+func (m *memoryFlagImpl) update(ctx context.Context, changes func(s *snapshot) daoerr.DaoError) daoerr.DaoError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next := m.data.clone()
+	if err := changes(&next); err != nil {
+		return err
+	}
+	if err := writeSnapshot(m.snapshotFile, next); err != nil {
+		return daoerr.NewDaoError(daoerr.UnknownError, err)
+	}
+	if m.onSave != nil {
+		if err := m.onSave(ctx, m.data.Flags, next.Flags); err != nil {
+			return daoerr.NewDaoError(daoerr.UnknownError, errors.Join(err, writeSnapshot(m.snapshotFile, m.data)))
+		}
+	}
+	m.data = next
+	return nil
+}
+
+// GetFlags return the flags matching the query and the total number of flags matching the filters
+func (m *memoryFlagImpl) GetFlags(_ context.Context, query dao.FlagQuery) ([]model.FeatureFlag, int, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	flags, total := dao.ApplyFlagQuery(m.data.Flags, query)
+	return cloneValue(flags), total, nil
+}
+
+// GetFlagsSummary return the number of flags matching the query and their most recent last updated date
+func (m *memoryFlagImpl) GetFlagsSummary(_ context.Context, query dao.FlagQuery) (dao.FlagsSummary, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	summary := dao.SummarizeFlags(m.data.Flags, query)
+	summary.VersionCount = m.data.versionCount()
+	return summary, nil
+}
+
+// GetFlagByID return a flag by its ID
+func (m *memoryFlagImpl) GetFlagByID(_ context.Context, id string) (model.FeatureFlag, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	index := m.data.flagIndex(id)
+	if index < 0 {
+		return model.FeatureFlag{}, daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("flag with id %s not found", id))
+	}
+	return cloneValue(m.data.Flags[index]), nil
+}
+
+// GetFlagByName return a flag by its name inside an environment
+func (m *memoryFlagImpl) GetFlagByName(
+	_ context.Context, project string, environment string, name string) (model.FeatureFlag, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, flag := range m.data.Flags {
+		if flag.Name == name && flag.GetProject() == project && flag.GetEnvironment() == environment {
+			return cloneValue(flag), nil
+		}
+	}
+	return model.FeatureFlag{}, daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("flag with name %s not found", name))
+}
+
+// CreateFlag create a new flag, return the id of the flag
+func (m *memoryFlagImpl) CreateFlag(ctx context.Context, flag model.FeatureFlag) (string, daoerr.DaoError) {
+	var id string
+	err := m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		var err daoerr.DaoError
+		id, err = s.createFlag(flag)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// UpdateFlag update a flag, only if nobody has updated it since the revision of the flag
+func (m *memoryFlagImpl) UpdateFlag(ctx context.Context, flag model.FeatureFlag) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		return s.updateFlag(flag)
+	})
+}
+
+// ImportFlags create and update a list of flags, the storage is left untouched if one of the changes fails
+func (m *memoryFlagImpl) ImportFlags(
+	ctx context.Context, creations []model.FeatureFlag, updates []model.FeatureFlag) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		for _, flag := range creations {
+			if _, err := s.createFlag(flag); err != nil {
+				return err
+			}
+		}
+		for _, flag := range updates {
+			if err := s.updateFlag(flag); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteFlagByID delete a flag and its scheduled changes, a last version of the flag is kept in its history
+func (m *memoryFlagImpl) DeleteFlagByID(ctx context.Context, id string) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		index := s.flagIndex(id)
+		if index < 0 {
+			return nil
+		}
+		s.addVersion(s.Flags[index], model.FlagVersionActionDeleted, time.Now())
+		s.Flags = slices.Delete(s.Flags, index, index+1)
+		s.ScheduledChanges = slices.DeleteFunc(s.ScheduledChanges, func(change model.ScheduledChange) bool {
+			return change.FlagID == id
+		})
+		return nil
+	})
+}
+
+// GetFlagVersions return the versions of a flag (the most recent first) and the total number of versions.
+func (m *memoryFlagImpl) GetFlagVersions(
+	_ context.Context, flagID string, limit int, offset int) ([]model.FlagVersion, int, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	// versions are stored from the oldest to the most recent
+	versions := []model.FlagVersion{}
+	for i := len(m.data.Versions) - 1; i >= 0; i-- {
+		if m.data.Versions[i].FlagID == flagID {
+			versions = append(versions, m.data.Versions[i])
+		}
+	}
+	if len(versions) == 0 {
+		return nil, 0, daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("no version found for flag %s", flagID))
+	}
+	if offset >= len(versions) {
+		return []model.FlagVersion{}, len(versions), nil
+	}
+	end := min(offset+limit, len(versions))
+	return cloneValue(versions[offset:end]), len(versions), nil
+}
+
+// GetFlagVersion return a specific version of a flag
+func (m *memoryFlagImpl) GetFlagVersion(
+	_ context.Context, flagID string, versionID string) (model.FlagVersion, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, version := range m.data.Versions {
+		if version.FlagID == flagID && version.ID == versionID {
+			return cloneValue(version), nil
+		}
+	}
+	return model.FlagVersion{}, daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("version %s not found", versionID))
+}
+
+// GetAPIKeys return all the API keys (the most recent first)
+func (m *memoryFlagImpl) GetAPIKeys(_ context.Context) ([]model.APIKey, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	res := make([]model.APIKey, 0, len(m.data.APIKeys))
+	for _, key := range m.data.APIKeys {
+		res = append(res, key.toModel())
+	}
+	slices.SortStableFunc(res, func(a, b model.APIKey) int {
+		if c := b.CreatedDate.Compare(a.CreatedDate); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return res, nil
+}
+
+// GetAPIKeyByHashedKey return the API key matching the hash of the key
+func (m *memoryFlagImpl) GetAPIKeyByHashedKey(_ context.Context, hashedKey string) (model.APIKey, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, key := range m.data.APIKeys {
+		if key.HashedKey == hashedKey {
+			return key.toModel(), nil
+		}
+	}
+	return model.APIKey{}, daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("api key not found"))
+}
+
+// CreateAPIKey create a new API key, return the id of the API key
+func (m *memoryFlagImpl) CreateAPIKey(ctx context.Context, key model.APIKey) (string, daoerr.DaoError) {
+	if key.ID == "" {
+		key.ID = uuid.NewString()
+	}
+	err := m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		for _, existing := range s.APIKeys {
+			if existing.ID == key.ID || existing.HashedKey == key.HashedKey {
+				return daoerr.NewDaoError(daoerr.AlreadyExists, fmt.Errorf("api key %s already exists", key.ID))
+			}
+		}
+		s.APIKeys = append(s.APIKeys, newAPIKey(key))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return key.ID, nil
+}
+
+// DeleteAPIKeyByID revoke an API key
+func (m *memoryFlagImpl) DeleteAPIKeyByID(ctx context.Context, id string) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		index := slices.IndexFunc(s.APIKeys, func(key apiKey) bool { return key.ID == id })
+		if index < 0 {
+			return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("api key with id %s not found", id))
+		}
+		s.APIKeys = slices.Delete(s.APIKeys, index, index+1)
+		return nil
+	})
+}
+
+// UpdateAPIKeyLastUsedDate record the last time the API key has been used.
+// It is called on every authenticated request, so the date is only kept in memory and written in the snapshot
+// file with the next change.
+func (m *memoryFlagImpl) UpdateAPIKeyLastUsedDate(_ context.Context, id string, date time.Time) daoerr.DaoError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	index := slices.IndexFunc(m.data.APIKeys, func(key apiKey) bool { return key.ID == id })
+	if index >= 0 {
+		// the elements are never modified in place, see snapshot.clone
+		m.data.APIKeys = slices.Clone(m.data.APIKeys)
+		m.data.APIKeys[index].LastUsedDate = &date
+	}
+	return nil
+}
+
+// GetScheduledChanges return the scheduled changes matching the query, ordered by scheduled date
+func (m *memoryFlagImpl) GetScheduledChanges(
+	_ context.Context, query dao.ScheduledChangeQuery) ([]model.ScheduledChange, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	changes := []model.ScheduledChange{}
+	for _, change := range m.data.ScheduledChanges {
+		if query.Match(change) {
+			changes = append(changes, change)
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b model.ScheduledChange) int {
+		if c := a.ScheduledDate.Compare(b.ScheduledDate); c != 0 {
+			return c
+		}
+		return a.CreatedDate.Compare(b.CreatedDate)
+	})
+	return cloneValue(changes), nil
+}
+
+// GetScheduledChangeByID return a scheduled change by its ID
+func (m *memoryFlagImpl) GetScheduledChangeByID(_ context.Context, id string) (model.ScheduledChange, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	index := m.data.scheduledChangeIndex(id)
+	if index < 0 {
+		return model.ScheduledChange{}, daoerr.NewDaoError(daoerr.NotFound,
+			fmt.Errorf("scheduled change with id %s not found", id))
+	}
+	return cloneValue(m.data.ScheduledChanges[index]), nil
+}
+
+// CreateScheduledChange create a new scheduled change, return the id of the scheduled change
+func (m *memoryFlagImpl) CreateScheduledChange(
+	ctx context.Context, change model.ScheduledChange) (string, daoerr.DaoError) {
+	if change.ID == "" {
+		change.ID = uuid.NewString()
+	}
+	err := m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		if s.flagIndex(change.FlagID) < 0 {
+			return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("flag with id %s not found", change.FlagID))
+		}
+		if s.scheduledChangeIndex(change.ID) >= 0 {
+			return daoerr.NewDaoError(daoerr.AlreadyExists, fmt.Errorf("scheduled change %s already exists", change.ID))
+		}
+		s.ScheduledChanges = append(s.ScheduledChanges, cloneValue(change))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return change.ID, nil
+}
+
+// UpdateScheduledChange update a pending scheduled change, only the pending changes can be updated
+func (m *memoryFlagImpl) UpdateScheduledChange(ctx context.Context, change model.ScheduledChange) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		return s.updateScheduledChange(change)
+	})
+}
+
+// ApplyScheduledChange updates the flag and stores the change together, none of them is modified if one fails
+func (m *memoryFlagImpl) ApplyScheduledChange(
+	ctx context.Context, flag model.FeatureFlag, change model.ScheduledChange) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		if err := s.updateScheduledChange(change); err != nil {
+			return err
+		}
+		return s.updateFlag(flag)
+	})
+}
+
+// GetProjects return all the projects ordered by name
+func (m *memoryFlagImpl) GetProjects(_ context.Context) ([]model.Project, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	projects := cloneValue(m.data.Projects)
+	slices.SortFunc(projects, func(a, b model.Project) int { return strings.Compare(a.Name, b.Name) })
+	return projects, nil
+}
+
+// GetProject return a project by its name
+func (m *memoryFlagImpl) GetProject(_ context.Context, name string) (model.Project, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	index := m.data.projectIndex(name)
+	if index < 0 {
+		return model.Project{}, daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("project %s not found", name))
+	}
+	return cloneValue(m.data.Projects[index]), nil
+}
+
+// CreateProject create a new project
+func (m *memoryFlagImpl) CreateProject(ctx context.Context, project model.Project) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		if s.projectIndex(project.Name) >= 0 {
+			return daoerr.NewDaoError(daoerr.AlreadyExists, fmt.Errorf("project %s already exists", project.Name))
+		}
+		s.Projects = append(s.Projects, cloneValue(project))
+		return nil
+	})
+}
+
+// DeleteProject delete a project, it returns an InUse error if the project still has environments
+func (m *memoryFlagImpl) DeleteProject(ctx context.Context, name string) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		index := s.projectIndex(name)
+		if index < 0 {
+			return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("project %s not found", name))
+		}
+		if slices.ContainsFunc(s.Environments, func(e model.Environment) bool { return e.Project == name }) {
+			return daoerr.NewDaoError(daoerr.InUse, fmt.Errorf("project %s still has environments", name))
+		}
+		s.Projects = slices.Delete(s.Projects, index, index+1)
+		return nil
+	})
+}
+
+// GetEnvironments return the environments of a project ordered by name
+func (m *memoryFlagImpl) GetEnvironments(_ context.Context, project string) ([]model.Environment, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	environments := []model.Environment{}
+	for _, environment := range m.data.Environments {
+		if environment.Project == project {
+			environments = append(environments, environment)
+		}
+	}
+	slices.SortFunc(environments, func(a, b model.Environment) int { return strings.Compare(a.Name, b.Name) })
+	return cloneValue(environments), nil
+}
+
+// GetEnvironment return an environment of a project by its name
+func (m *memoryFlagImpl) GetEnvironment(
+	_ context.Context, project string, name string) (model.Environment, daoerr.DaoError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	index := m.data.environmentIndex(project, name)
+	if index < 0 {
+		return model.Environment{}, daoerr.NewDaoError(daoerr.NotFound,
+			fmt.Errorf("environment %s not found in project %s", name, project))
+	}
+	return cloneValue(m.data.Environments[index]), nil
+}
+
+// CreateEnvironment create a new environment in an existing project
+func (m *memoryFlagImpl) CreateEnvironment(ctx context.Context, environment model.Environment) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		if s.projectIndex(environment.Project) < 0 {
+			return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("project %s not found", environment.Project))
+		}
+		if s.environmentIndex(environment.Project, environment.Name) >= 0 {
+			return daoerr.NewDaoError(daoerr.AlreadyExists, fmt.Errorf(
+				"environment %s already exists in project %s", environment.Name, environment.Project))
+		}
+		s.Environments = append(s.Environments, cloneValue(environment))
+		return nil
+	})
+}
+
+// DeleteEnvironment delete an environment, it returns an InUse error if the environment still has flags
+func (m *memoryFlagImpl) DeleteEnvironment(ctx context.Context, project string, name string) daoerr.DaoError {
+	return m.update(ctx, func(s *snapshot) daoerr.DaoError {
+		index := s.environmentIndex(project, name)
+		if index < 0 {
+			return daoerr.NewDaoError(daoerr.NotFound,
+				fmt.Errorf("environment %s not found in project %s", name, project))
+		}
+		if slices.ContainsFunc(s.Flags, func(f model.FeatureFlag) bool {
+			return f.GetProject() == project && f.GetEnvironment() == name
+		}) {
+			return daoerr.NewDaoError(daoerr.InUse, fmt.Errorf("environment %s still has flags", name))
+		}
+		s.Environments = slices.Delete(s.Environments, index, index+1)
+		return nil
+	})
+}
+
+// Ping check that the data layer is available, the memory is always available
+func (m *memoryFlagImpl) Ping() daoerr.DaoError {
+	return nil
+}
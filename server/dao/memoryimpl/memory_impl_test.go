@@ -0,0 +1,394 @@
+package memoryimpl_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr2 "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/dao/memoryimpl"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFlag(id string, name string) model.FeatureFlag {
+	return model.FeatureFlag{
+		ID:              id,
+		Name:            name,
+		VariationType:   model.FlagTypeString,
+		Variations:      &map[string]interface{}{"A": "a", "B": "b"},
+		DefaultRule:     &model.Rule{VariationResult: testutils.String("A")},
+		CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastModifiedBy:  "foo",
+	}
+}
+
+func TestMemoryDao_Flags(t *testing.T) {
+	ctx := context.Background()
+	storage, err := memoryimpl.NewMemoryDao("")
+	require.NoError(t, err)
+
+	id, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	flag, daoErr := storage.GetFlagByID(ctx, id)
+	require.Nil(t, daoErr)
+	assert.Equal(t, int64(1), flag.Revision)
+	assert.Equal(t, model.DefaultProject, flag.Project)
+	assert.NotEmpty(t, flag.DefaultRule.ID, "the rules should get an ID")
+
+	// the flags returned can be modified without changing the storage
+	flag.Name = "modified"
+	stored, _ := storage.GetFlagByName(ctx, model.DefaultProject, model.DefaultEnvironment, "flag1")
+	assert.Equal(t, id, stored.ID)
+
+	_, daoErr = storage.CreateFlag(ctx, newFlag("2c5a6c4e-1c8b-4a6d-9f3e-5b7d8e9f0a1b", "flag1"))
+	require.NotNil(t, daoErr, "the name of a flag should be unique in its environment")
+	assert.Equal(t, daoErr2.AlreadyExists, daoErr.Code())
+
+	flag.Name = "flag1"
+	flag.Disable = testutils.Bool(true)
+	require.Nil(t, storage.UpdateFlag(ctx, flag))
+	daoErr = storage.UpdateFlag(ctx, flag)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+
+	flags, total, daoErr := storage.GetFlags(ctx, dao.FlagQuery{Disabled: testutils.Bool(true)})
+	require.Nil(t, daoErr)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, int64(2), flags[0].Revision)
+
+	versions, total, daoErr := storage.GetFlagVersions(ctx, id, 10, 0)
+	require.Nil(t, daoErr)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, model.FlagVersionActionUpdated, versions[0].Action)
+	version, daoErr := storage.GetFlagVersion(ctx, id, versions[1].ID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, model.FlagVersionActionCreated, version.Action)
+
+	require.Nil(t, storage.DeleteFlagByID(ctx, id))
+	_, daoErr = storage.GetFlagByID(ctx, id)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+	summary, daoErr := storage.GetFlagsSummary(ctx, dao.FlagQuery{})
+	require.Nil(t, daoErr)
+	assert.Equal(t, 0, summary.Total)
+	assert.Equal(t, 3, summary.VersionCount)
+}
+
+func TestMemoryDao_FlagVersionsLimit(t *testing.T) {
+	ctx := context.Background()
+	storage, err := memoryimpl.NewMemoryDao("")
+	require.NoError(t, err)
+
+	id, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	for i := 0; i < 120; i++ {
+		flag, daoErr := storage.GetFlagByID(ctx, id)
+		require.Nil(t, daoErr)
+		flag.Disable = testutils.Bool(i%2 == 0)
+		require.Nil(t, storage.UpdateFlag(ctx, flag))
+	}
+
+	versions, total, daoErr := storage.GetFlagVersions(ctx, id, 200, 0)
+	require.Nil(t, daoErr)
+	assert.Equal(t, 100, total, "only the last versions should be kept")
+	assert.Equal(t, 121, versions[0].VersionNumber)
+	assert.Equal(t, 22, versions[len(versions)-1].VersionNumber)
+
+	// the count of versions keeps changing once the oldest versions are dropped, it is part of the ETags
+	summary, daoErr := storage.GetFlagsSummary(ctx, dao.FlagQuery{})
+	require.Nil(t, daoErr)
+	assert.Equal(t, 121, summary.VersionCount)
+	require.Nil(t, storage.DeleteFlagByID(ctx, id))
+	summary, daoErr = storage.GetFlagsSummary(ctx, dao.FlagQuery{})
+	require.Nil(t, daoErr)
+	assert.Equal(t, 122, summary.VersionCount)
+}
+
+func TestMemoryDao_ImportFlags(t *testing.T) {
+	ctx := context.Background()
+	storage, err := memoryimpl.NewMemoryDao("")
+	require.NoError(t, err)
+
+	daoErr := storage.ImportFlags(ctx, []model.FeatureFlag{
+		newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"),
+		newFlag("2c5a6c4e-1c8b-4a6d-9f3e-5b7d8e9f0a1b", "flag1"),
+	}, nil)
+	require.NotNil(t, daoErr)
+	_, total, _ := storage.GetFlags(ctx, dao.FlagQuery{})
+	assert.Equal(t, 0, total, "no flag should be stored if one of the changes fails")
+
+	require.Nil(t, storage.ImportFlags(ctx, []model.FeatureFlag{
+		newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"),
+		newFlag("2c5a6c4e-1c8b-4a6d-9f3e-5b7d8e9f0a1b", "flag2"),
+	}, nil))
+	_, total, _ = storage.GetFlags(ctx, dao.FlagQuery{})
+	assert.Equal(t, 2, total)
+}
+
+func TestMemoryDao_ScheduledChanges(t *testing.T) {
+	ctx := context.Background()
+	storage, err := memoryimpl.NewMemoryDao("")
+	require.NoError(t, err)
+	changes, ok := storage.(dao.ScheduledChangeStorage)
+	require.True(t, ok)
+
+	flagID, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	change := model.ScheduledChange{
+		FlagID:        flagID,
+		ScheduledDate: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Changes:       model.FlagChanges{Disable: testutils.Bool(true)},
+		Status:        model.ScheduledChangeStatusPending,
+	}
+	_, daoErr = changes.CreateScheduledChange(ctx, model.ScheduledChange{FlagID: "unknown"})
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+	change.ID, daoErr = changes.CreateScheduledChange(ctx, change)
+	require.Nil(t, daoErr)
+
+	change.Status = model.ScheduledChangeStatusApplied
+	require.Nil(t, changes.UpdateScheduledChange(ctx, change))
+	daoErr = changes.UpdateScheduledChange(ctx, change)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+
+	list, daoErr := changes.GetScheduledChanges(ctx, dao.ScheduledChangeQuery{FlagID: flagID})
+	require.Nil(t, daoErr)
+	assert.Len(t, list, 1)
+
+	// the scheduled changes are deleted with their flag
+	require.Nil(t, storage.DeleteFlagByID(ctx, flagID))
+	_, daoErr = changes.GetScheduledChangeByID(ctx, change.ID)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+}
+
+func TestMemoryDao_ApplyScheduledChange(t *testing.T) {
+	ctx := context.Background()
+	storage, err := memoryimpl.NewMemoryDao("")
+	require.NoError(t, err)
+	changes, ok := storage.(dao.ScheduledChangeStorage)
+	require.True(t, ok)
+
+	flagID, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	change := model.ScheduledChange{
+		ID:              "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f",
+		FlagID:          flagID,
+		ScheduledDate:   time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Changes:         model.FlagChanges{Disable: testutils.Bool(true)},
+		Status:          model.ScheduledChangeStatusPending,
+		CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	_, daoErr = changes.CreateScheduledChange(ctx, change)
+	require.Nil(t, daoErr)
+	flag, daoErr := storage.GetFlagByID(ctx, flagID)
+	require.Nil(t, daoErr)
+	updatedFlag := change.Changes.ApplyTo(flag)
+	applied := change
+	applied.Status = model.ScheduledChangeStatusApplied
+
+	// nothing is modified if the flag has been modified since its revision
+	staleFlag := updatedFlag
+	staleFlag.Revision++
+	daoErr = changes.ApplyScheduledChange(ctx, staleFlag, applied)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+	got, daoErr := changes.GetScheduledChangeByID(ctx, change.ID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, model.ScheduledChangeStatusPending, got.Status)
+
+	require.Nil(t, changes.ApplyScheduledChange(ctx, updatedFlag, applied))
+	got, daoErr = changes.GetScheduledChangeByID(ctx, change.ID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, model.ScheduledChangeStatusApplied, got.Status)
+	flag, daoErr = storage.GetFlagByID(ctx, flagID)
+	require.Nil(t, daoErr)
+	assert.True(t, flag.IsDisable())
+
+	// a change already applied is not applied again
+	daoErr = changes.ApplyScheduledChange(ctx, change.Changes.ApplyTo(flag), applied)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+	reloaded, daoErr := storage.GetFlagByID(ctx, flagID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, flag.Revision, reloaded.Revision)
+}
+
+func TestMemoryDao_Projects(t *testing.T) {
+	ctx := context.Background()
+	storage, err := memoryimpl.NewMemoryDao("")
+	require.NoError(t, err)
+
+	_, daoErr := storage.GetEnvironment(ctx, model.DefaultProject, model.DefaultEnvironment)
+	require.Nil(t, daoErr, "the default environment should always exist")
+
+	daoErr = storage.CreateEnvironment(ctx, model.Environment{Project: "checkout", Name: "production"})
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+
+	require.Nil(t, storage.CreateProject(ctx, model.Project{Name: "checkout"}))
+	require.Nil(t, storage.CreateEnvironment(ctx, model.Environment{Project: "checkout", Name: "production"}))
+	daoErr = storage.CreateProject(ctx, model.Project{Name: "checkout"})
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.AlreadyExists, daoErr.Code())
+	daoErr = storage.CreateEnvironment(ctx, model.Environment{Project: "checkout", Name: "production"})
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.AlreadyExists, daoErr.Code())
+	flag := newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1")
+	flag.Project, flag.Environment = "checkout", "production"
+	_, daoErr = storage.CreateFlag(ctx, flag)
+	require.Nil(t, daoErr)
+
+	daoErr = storage.DeleteEnvironment(ctx, "checkout", "production")
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.InUse, daoErr.Code())
+	daoErr = storage.DeleteProject(ctx, "checkout")
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.InUse, daoErr.Code())
+
+	require.Nil(t, storage.DeleteFlagByID(ctx, flag.ID))
+	require.Nil(t, storage.DeleteEnvironment(ctx, "checkout", "production"))
+	require.Nil(t, storage.DeleteProject(ctx, "checkout"))
+	projects, daoErr := storage.GetProjects(ctx)
+	require.Nil(t, daoErr)
+	assert.Equal(t, []model.Project{{Name: model.DefaultProject}}, projects)
+}
+
+func TestMemoryDao_OnSave(t *testing.T) {
+	ctx := context.Background()
+	var refuse bool
+	var previousCount, nextCount int
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.json")
+	storage, err := memoryimpl.NewMemoryDaoWithOptions(memoryimpl.Options{
+		SnapshotFile: snapshotFile,
+		OnSave: func(_ context.Context, previous []model.FeatureFlag, next []model.FeatureFlag) error {
+			assert.FileExists(t, snapshotFile, "the snapshot should be written before OnSave")
+			previousCount, nextCount = len(previous), len(next)
+			if refuse {
+				return fmt.Errorf("refused")
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	_, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	assert.Equal(t, 0, previousCount)
+	assert.Equal(t, 1, nextCount)
+
+	refuse = true
+	_, daoErr = storage.CreateFlag(ctx, newFlag("c4d0e6a6-0c87-4c6d-a2a8-7d3f7a5e1a10", "flag2"))
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.UnknownError, daoErr.Code())
+	_, total, _ := storage.GetFlags(ctx, dao.FlagQuery{})
+	assert.Equal(t, 1, total, "the change should be discarded if OnSave fails")
+	reloaded, err := memoryimpl.NewMemoryDao(snapshotFile)
+	require.NoError(t, err)
+	_, total, _ = reloaded.GetFlags(ctx, dao.FlagQuery{})
+	assert.Equal(t, 1, total, "the previous snapshot should be written back if OnSave fails")
+}
+
+func TestMemoryDao_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.json")
+	storage, err := memoryimpl.NewMemoryDao(snapshotFile)
+	require.NoError(t, err)
+	_, err = os.Stat(snapshotFile)
+	assert.True(t, os.IsNotExist(err), "the snapshot should be written only after a change")
+
+	flagID, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	apiKeys, ok := storage.(dao.APIKeyStorage)
+	require.True(t, ok)
+	_, daoErr = apiKeys.CreateAPIKey(ctx, model.APIKey{
+		ID:        "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f",
+		Name:      "ci",
+		HashedKey: "hash",
+		Scopes:    []model.APIKeyScope{model.APIKeyScopeRead},
+	})
+	require.Nil(t, daoErr)
+
+	reloaded, err := memoryimpl.NewMemoryDao(snapshotFile)
+	require.NoError(t, err)
+	flag, daoErr := reloaded.GetFlagByID(ctx, flagID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, "flag1", flag.Name)
+	apiKey, daoErr := reloaded.(dao.APIKeyStorage).GetAPIKeyByHashedKey(ctx, "hash")
+	require.Nil(t, daoErr)
+	assert.Equal(t, "ci", apiKey.Name)
+
+	t.Run("should not write the snapshot when an API key is used", func(t *testing.T) {
+		before, err := os.ReadFile(snapshotFile)
+		require.NoError(t, err)
+		require.Nil(t, apiKeys.UpdateAPIKeyLastUsedDate(ctx, "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f", time.Now()))
+		apiKey, daoErr := apiKeys.GetAPIKeyByHashedKey(ctx, "hash")
+		require.Nil(t, daoErr)
+		assert.NotNil(t, apiKey.LastUsedDate)
+		after, err := os.ReadFile(snapshotFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("should continue the version numbers of a snapshot without version counts", func(t *testing.T) {
+		oldFile := filepath.Join(t.TempDir(), "snapshot.json")
+		content := `{"flags":[],"versions":[{"id":"a4c7d1e2-5b1a-4c1e-9f5e-0d2b8e6f7a10",` +
+			`"flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","versionNumber":7,"action":"deleted"}]}`
+		require.NoError(t, os.WriteFile(oldFile, []byte(content), 0o600))
+		storage, err := memoryimpl.NewMemoryDao(oldFile)
+		require.NoError(t, err)
+		_, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+		require.Nil(t, daoErr)
+		versions, _, daoErr := storage.GetFlagVersions(ctx, "926214f3-80c1-46e6-a913-b2d40b92a932", 10, 0)
+		require.Nil(t, daoErr)
+		assert.Equal(t, 8, versions[0].VersionNumber)
+	})
+
+	t.Run("should return an error if the snapshot is invalid", func(t *testing.T) {
+		invalidFile := filepath.Join(t.TempDir(), "snapshot.json")
+		require.NoError(t, os.WriteFile(invalidFile, []byte("not json"), 0o600))
+		_, err := memoryimpl.NewMemoryDao(invalidFile)
+		assert.Error(t, err)
+	})
+
+	t.Run("should keep the storage unchanged if the snapshot cannot be written", func(t *testing.T) {
+		storage, err := memoryimpl.NewMemoryDao(filepath.Join(t.TempDir(), "missing", "snapshot.json"))
+		require.NoError(t, err)
+		_, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+		require.NotNil(t, daoErr)
+		_, total, _ := storage.GetFlags(ctx, dao.FlagQuery{})
+		assert.Equal(t, 0, total)
+	})
+}
+
+func TestMemoryDao_ConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	storage, err := memoryimpl.NewMemoryDao(filepath.Join(t.TempDir(), "snapshot.json"))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("926214f3-80c1-46e6-a913-b2d40b92a9%02d", i)
+			_, daoErr := storage.CreateFlag(ctx, newFlag(id, fmt.Sprintf("flag%d", i)))
+			assert.Nil(t, daoErr)
+			_, _, _ = storage.GetFlags(ctx, dao.FlagQuery{})
+		}(i)
+	}
+	wg.Wait()
+	_, total, _ := storage.GetFlags(ctx, dao.FlagQuery{})
+	assert.Equal(t, 20, total)
+}
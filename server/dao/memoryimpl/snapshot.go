@@ -0,0 +1,318 @@
+package memoryimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+)
+
+// snapshot is the content of the storage, it is the format of the snapshot file.
+type snapshot struct {
+	Flags            []model.FeatureFlag     `json:"flags"`
+	Versions         []model.FlagVersion     `json:"versions"`
+	ScheduledChanges []model.ScheduledChange `json:"scheduledChanges"`
+	APIKeys          []apiKey                `json:"apiKeys"`
+	Projects         []model.Project         `json:"projects"`
+	Environments     []model.Environment     `json:"environments"`
+
+	// VersionCounts is the number of versions ever recorded for each flag, it keeps increasing when the oldest
+	// versions are dropped so the version numbers and the ETags always change.
+	VersionCounts map[string]int `json:"versionCounts"`
+}
+
+// apiKey is an API key with its hash, the hash is never serialized with model.APIKey.
+type apiKey struct {
+	model.APIKey
+	HashedKey string `json:"hashedKey"`
+}
+
+// newSnapshot returns an empty storage containing only the default project and its default environment.
+func newSnapshot() snapshot {
+	s := snapshot{VersionCounts: map[string]int{}}
+	s.ensureDefaultScope()
+	return s
+}
+
+// ensureDefaultScope adds the default project and environment if they are missing.
+func (s *snapshot) ensureDefaultScope() {
+	if !slices.ContainsFunc(s.Projects, func(p model.Project) bool { return p.Name == model.DefaultProject }) {
+		s.Projects = append(s.Projects, model.Project{Name: model.DefaultProject})
+	}
+	if !slices.ContainsFunc(s.Environments, func(e model.Environment) bool {
+		return e.Project == model.DefaultProject && e.Name == model.DefaultEnvironment
+	}) {
+		s.Environments = append(s.Environments,
+			model.Environment{Project: model.DefaultProject, Name: model.DefaultEnvironment})
+	}
+}
+
+// ensureVersionCounts counts the versions of the snapshot files written before VersionCounts existed.
+func (s *snapshot) ensureVersionCounts() {
+	if s.VersionCounts != nil {
+		return
+	}
+	s.VersionCounts = map[string]int{}
+	for _, v := range s.Versions {
+		s.VersionCounts[v.FlagID] = max(s.VersionCounts[v.FlagID], v.VersionNumber)
+	}
+}
+
+// versionCount returns the number of versions recorded for all the flags, including the dropped ones.
+func (s *snapshot) versionCount() int {
+	count := 0
+	for _, c := range s.VersionCounts {
+		count += c
+	}
+	return count
+}
+
+// clone returns a copy of the snapshot that can be modified without changing the original one.
+// The elements are never modified in place, so the lists are copied but not their elements.
+func (s snapshot) clone() snapshot {
+	return snapshot{
+		Flags:            slices.Clone(s.Flags),
+		Versions:         slices.Clone(s.Versions),
+		VersionCounts:    maps.Clone(s.VersionCounts),
+		ScheduledChanges: slices.Clone(s.ScheduledChanges),
+		APIKeys:          slices.Clone(s.APIKeys),
+		Projects:         slices.Clone(s.Projects),
+		Environments:     slices.Clone(s.Environments),
+	}
+}
+
+// loadSnapshot reads the snapshot file, an empty storage is returned if the file does not exist yet.
+func loadSnapshot(file string) (snapshot, error) {
+	if file == "" {
+		return newSnapshot(), nil
+	}
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return newSnapshot(), nil
+	}
+	if err != nil {
+		return snapshot{}, fmt.Errorf("impossible to read the snapshot file %s: %w", file, err)
+	}
+	s := snapshot{}
+	if err := json.Unmarshal(content, &s); err != nil {
+		return snapshot{}, fmt.Errorf("impossible to parse the snapshot file %s: %w", file, err)
+	}
+	s.ensureDefaultScope()
+	s.ensureVersionCounts()
+	return s, nil
+}
+
+// writeSnapshot replaces the snapshot file, the file is written next to it and renamed so a crash
+// during the write never leaves a partial snapshot.
+func writeSnapshot(file string, s snapshot) error {
+	if file == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("impossible to serialize the snapshot: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("impossible to write the snapshot file %s: %w", file, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("impossible to write the snapshot file %s: %w", file, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("impossible to write the snapshot file %s: %w", file, err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("impossible to write the snapshot file %s: %w", file, err)
+	}
+	return nil
+}
+
+// cloneValue returns a deep copy of a value by serializing it, the callers can then modify what they
+// receive from the storage without changing its content.
+func cloneValue[T any](value T) T {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var res T
+	if err := json.Unmarshal(content, &res); err != nil {
+		return value
+	}
+	return res
+}
+
+func newAPIKey(key model.APIKey) apiKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	return apiKey{APIKey: key, HashedKey: key.HashedKey}
+}
+
+func (k apiKey) toModel() model.APIKey {
+	key := k.APIKey
+	key.HashedKey = k.HashedKey
+	key.Scopes = slices.Clone(key.Scopes)
+	return key
+}
+
+func (s *snapshot) flagIndex(id string) int {
+	return slices.IndexFunc(s.Flags, func(f model.FeatureFlag) bool { return f.ID == id })
+}
+
+func (s *snapshot) scheduledChangeIndex(id string) int {
+	return slices.IndexFunc(s.ScheduledChanges, func(c model.ScheduledChange) bool { return c.ID == id })
+}
+
+func (s *snapshot) projectIndex(name string) int {
+	return slices.IndexFunc(s.Projects, func(p model.Project) bool { return p.Name == name })
+}
+
+func (s *snapshot) environmentIndex(project string, name string) int {
+	return slices.IndexFunc(s.Environments, func(e model.Environment) bool {
+		return e.Project == project && e.Name == name
+	})
+}
+
+// createFlag adds a new flag with its first version, the flag is stored in its scope like in the databases.
+func (s *snapshot) createFlag(flag model.FeatureFlag) (string, daoerr.DaoError) {
+	if flag.DefaultRule == nil {
+		return "", daoerr.NewDaoError(daoerr.DefaultRuleRequired, fmt.Errorf("default rule is required"))
+	}
+	if flag.ID == "" {
+		flag.ID = uuid.NewString()
+	} else if _, err := uuid.Parse(flag.ID); err != nil {
+		return "", daoerr.NewDaoError(daoerr.InvalidUUID, err)
+	}
+	if s.flagIndex(flag.ID) >= 0 {
+		return "", daoerr.NewDaoError(daoerr.AlreadyExists, fmt.Errorf("flag with id %s already exists", flag.ID))
+	}
+
+	flag = cloneValue(flag)
+	flag.Project, flag.Environment = flag.GetProject(), flag.GetEnvironment()
+	if s.environmentIndex(flag.Project, flag.Environment) < 0 {
+		return "", daoerr.NewDaoError(daoerr.NotFound,
+			fmt.Errorf("environment %s not found in project %s", flag.Environment, flag.Project))
+	}
+	if err := s.checkFlagName(flag); err != nil {
+		return "", err
+	}
+	setRuleIDs(&flag)
+	flag.Revision = 1
+	s.Flags = append(s.Flags, flag)
+	s.addVersion(flag, model.FlagVersionActionCreated, flag.LastUpdatedDate)
+	return flag.ID, nil
+}
+
+// updateFlag replaces a flag if nobody has updated it since its revision, the scope, the type and the
+// creation date of a flag cannot be modified.
+func (s *snapshot) updateFlag(flag model.FeatureFlag) daoerr.DaoError {
+	index := s.flagIndex(flag.ID)
+	if index < 0 {
+		return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("flag with id %s not found", flag.ID))
+	}
+	if flag.DefaultRule == nil {
+		return daoerr.NewDaoError(daoerr.DefaultRuleRequired, fmt.Errorf("default rule is required"))
+	}
+	existing := s.Flags[index]
+	if existing.Revision != flag.Revision {
+		return daoerr.NewDaoError(daoerr.StaleRevision,
+			fmt.Errorf("flag %s has been modified since revision %d", flag.ID, flag.Revision))
+	}
+
+	flag = cloneValue(flag)
+	flag.Project, flag.Environment = existing.Project, existing.Environment
+	flag.VariationType, flag.CreatedDate = existing.VariationType, existing.CreatedDate
+	if err := s.checkFlagName(flag); err != nil {
+		return err
+	}
+	setRuleIDs(&flag)
+	flag.Revision++
+	s.Flags[index] = flag
+	s.addVersion(flag, model.FlagVersionActionUpdated, flag.LastUpdatedDate)
+	return nil
+}
+
+// updateScheduledChange replaces a pending scheduled change, the flag and the creation of a change cannot be
+// modified.
+func (s *snapshot) updateScheduledChange(change model.ScheduledChange) daoerr.DaoError {
+	index := s.scheduledChangeIndex(change.ID)
+	if index < 0 {
+		return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("scheduled change with id %s not found", change.ID))
+	}
+	existing := s.ScheduledChanges[index]
+	if !existing.IsPending() {
+		return daoerr.NewDaoError(daoerr.StaleRevision,
+			fmt.Errorf("scheduled change %s is %s", change.ID, existing.Status))
+	}
+	change.FlagID, change.CreatedDate, change.CreatedBy = existing.FlagID, existing.CreatedDate, existing.CreatedBy
+	s.ScheduledChanges[index] = cloneValue(change)
+	return nil
+}
+
+// checkFlagName returns an error if another flag of the environment has the same name.
+func (s *snapshot) checkFlagName(flag model.FeatureFlag) daoerr.DaoError {
+	for _, f := range s.Flags {
+		if f.ID != flag.ID && f.Name == flag.Name &&
+			f.GetProject() == flag.GetProject() && f.GetEnvironment() == flag.GetEnvironment() {
+			return daoerr.NewDaoError(daoerr.AlreadyExists, fmt.Errorf("flag with name %s already exists", flag.Name))
+		}
+	}
+	return nil
+}
+
+// maxVersionsPerFlag is the number of versions kept in the history of a flag, the whole storage is kept in memory
+// and rewritten in the snapshot file after every change so the oldest versions are dropped.
+const maxVersionsPerFlag = 100
+
+// addVersion records a snapshot of the flag in its history, only the last maxVersionsPerFlag versions are kept.
+func (s *snapshot) addVersion(flag model.FeatureFlag, action model.FlagVersionAction, date time.Time) {
+	// the numbers keep increasing even when the oldest versions have been dropped
+	s.VersionCounts[flag.ID]++
+	versionNumber := s.VersionCounts[flag.ID]
+	count := 0
+	for _, v := range s.Versions {
+		if v.FlagID == flag.ID {
+			count++
+		}
+	}
+	if count >= maxVersionsPerFlag {
+		toDrop := count - maxVersionsPerFlag + 1
+		s.Versions = slices.DeleteFunc(s.Versions, func(v model.FlagVersion) bool {
+			if v.FlagID != flag.ID || toDrop == 0 {
+				return false
+			}
+			toDrop--
+			return true
+		})
+	}
+	s.Versions = append(s.Versions, model.FlagVersion{
+		ID:            uuid.NewString(),
+		FlagID:        flag.ID,
+		VersionNumber: versionNumber,
+		Action:        action,
+		CreatedDate:   date,
+		Flag:          flag,
+	})
+}
+
+// setRuleIDs generates an ID for the rules without one, like the databases do when inserting them.
+func setRuleIDs(flag *model.FeatureFlag) {
+	if flag.DefaultRule != nil && flag.DefaultRule.ID == "" {
+		flag.DefaultRule.ID = uuid.NewString()
+	}
+	if flag.Rules != nil {
+		for i := range *flag.Rules {
+			if (*flag.Rules)[i].ID == "" {
+				(*flag.Rules)[i].ID = uuid.NewString()
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package mysqlimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/dao/sqlimpl"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// likeEscaper escapes the special characters of a LIKE pattern, "!" is used as escape character because the
+// meaning of a backslash depends on the SQL mode of the server.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// dialect contains the MySQL specific parts of the queries.
+var dialect = sqlimpl.Dialect{
+	WrapError: daoerr.WrapMySQLError,
+	JSONParam: func(name string) string {
+		// the driver sends the JSON values as binary strings, MySQL accepts them in a JSON column only with a charset
+		return "CONVERT(:" + name + " USING utf8mb4)"
+	},
+	NamePrefixFilter: func(prefix string) (string, interface{}) {
+		return "name LIKE ? ESCAPE '!'", likeEscaper.Replace(prefix) + "%"
+	},
+	MetadataFilter: func(key string, value *string) (string, []interface{}) {
+		if value != nil {
+			// the values are compared as text like the ->> operator of postgres
+			return "JSON_UNQUOTE(JSON_EXTRACT(metadata, ?)) = ?", []interface{}{metadataPath(key), *value}
+		}
+		return "JSON_CONTAINS_PATH(metadata, 'one', ?) = 1", []interface{}{metadataPath(key)}
+	},
+	DueBeforeFilter: "scheduled_date <= ?",
+}
+
+// the MySQL storage is added to the registry of dao with the scheme "mysql" when the package is imported.
+var _ = dao.MustRegister("mysql", func(connectionString string) (dao.FlagStorage, error) {
+	return NewMySQLDao(dao.TrimScheme(connectionString))
+})
+
+// NewMySQLDao connects to a MySQL or MariaDB database, the tables are created by the migrations of
+// database_migration/mysql.
+// The connection string uses the format of the go-sql-driver (ex: "user:password@tcp(localhost:3306)/gofeatureflag").
+func NewMySQLDao(connectionString string) (dao.FlagStorage, error) {
+	if connectionString == "" {
+		return nil, fmt.Errorf("connection string is empty")
+	}
+	dsn, err := dataSourceName(connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection string: %w", err)
+	}
+
+	conn, err := sqlx.Connect("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to connect to the database: %w", err)
+	}
+	return sqlimpl.NewStorage(conn, dialect), nil
+}
+
+// dataSourceName adds the options needed by the storage to the connection string:
+// the dates are parsed and stored in UTC, and an UPDATE returns the number of matched rows (not only the
+// changed ones) so an update without change is not seen as a conflict.
+func dataSourceName(connectionString string) (string, error) {
+	cfg, err := mysql.ParseDSN(connectionString)
+	if err != nil {
+		return "", err
+	}
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	cfg.ClientFoundRows = true
+	return cfg.FormatDSN(), nil
+}
+
+// metadataPath returns the JSON path of a key of the metadata, the key is quoted to accept any character.
+func metadataPath(key string) string {
+	quotedKey, _ := json.Marshal(key)
+	return "$." + string(quotedKey)
+}
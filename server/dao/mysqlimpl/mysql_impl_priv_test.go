@@ -0,0 +1,30 @@
+package mysqlimpl
+
+import (
+	"github.com/go-feature-flag/flag-management/server/dao/sqlimpl"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingNoDb(t *testing.T) {
+	impl := sqlimpl.NewStorage(nil, dialect)
+	assert.Error(t, impl.Ping())
+}
+
+func TestDataSourceName(t *testing.T) {
+	dsn, err := dataSourceName("goff-user:my-secret-pw@tcp(localhost:3306)/gofeatureflag?timeout=5s")
+	require.NoError(t, err)
+	assert.Equal(t,
+		"goff-user:my-secret-pw@tcp(localhost:3306)/gofeatureflag?clientFoundRows=true&parseTime=true&timeout=5s",
+		dsn)
+
+	_, err = dataSourceName("postgres://localhost:5432/gofeatureflag")
+	assert.Error(t, err)
+}
+
+func TestMetadataPath(t *testing.T) {
+	assert.Equal(t, `$."team"`, metadataPath("team"))
+	assert.Equal(t, `$."my.\"key\""`, metadataPath(`my."key"`))
+}
@@ -1,6 +1,7 @@
 package pgimpl
 
 import (
+	"github.com/go-feature-flag/flag-management/server/dao/sqlimpl"
 	"testing"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -9,6 +10,6 @@ import (
 )
 
 func TestPingNoDb(t *testing.T) {
-	impl := pgFlagImpl{}
+	impl := sqlimpl.NewStorage(nil, dialect)
 	assert.Error(t, impl.Ping())
 }
@@ -142,6 +142,8 @@ func TestGetFlags(t *testing.T) {
 				{
 					ID:          "69aa10ec-ec3e-4139-8cdf-6902a5746e2d",
 					Name:        "my-feature-flag",
+					Project:     model.DefaultProject,
+					Environment: model.DefaultEnvironment,
 					Description: testutils.String("This is a feature flag"),
 					Variations: &map[string]interface{}{
 						"variationA": "valueA",
@@ -158,6 +160,7 @@ func TestGetFlags(t *testing.T) {
 					CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 					LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 					LastModifiedBy:  "admin",
+					Revision:        1,
 					DefaultRule: &model.Rule{
 						ID:              "1cb941f2-adb4-460f-9259-b4416c90e9e1",
 						Name:            "default-rule",
@@ -215,7 +218,7 @@ func TestGetFlags(t *testing.T) {
 			defer tearDownTest(t, pgContainer, conn)
 			pgDao := getPostgresDao(t, pgContainer)
 
-			flags, err := pgDao.GetFlags(context.TODO())
+			flags, _, err := pgDao.GetFlags(context.TODO(), dao.FlagQuery{})
 			tt.wantErr(t, err)
 			if err == nil {
 				assert.Equal(t, tt.want, flags)
@@ -225,6 +228,143 @@ func TestGetFlags(t *testing.T) {
 
 }
 
+func TestGetFlagsWithQuery(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+
+	newFlags := []model.FeatureFlag{
+		{
+			ID:              "6e0133ab-c262-4a0e-9eb1-79173c214001",
+			Name:            "checkout_button",
+			VariationType:   model.FlagTypeBoolean,
+			Variations:      &map[string]interface{}{"on": true, "off": false},
+			Metadata:        &map[string]interface{}{"team": "payment"},
+			CreatedDate:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			DefaultRule:     &model.Rule{ID: "6761c19f-1b74-49f1-9101-4c4aaa7e8001", VariationResult: testutils.String("on")},
+		},
+		{
+			ID:              "6e0133ab-c262-4a0e-9eb1-79173c214002",
+			Name:            "checkoutXcolor",
+			VariationType:   model.FlagTypeString,
+			Variations:      &map[string]interface{}{"red": "red", "blue": "blue"},
+			Disable:         testutils.Bool(true),
+			CreatedDate:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			DefaultRule:     &model.Rule{ID: "6761c19f-1b74-49f1-9101-4c4aaa7e8002", VariationResult: testutils.String("red")},
+			Rules: &[]model.Rule{
+				{
+					ID:              "8db65089-6785-4965-84a9-5d0554698002",
+					Name:            "rule 1",
+					Query:           "targetingKey eq \"1234\"",
+					VariationResult: testutils.String("blue"),
+				},
+			},
+		},
+	}
+	for _, flag := range newFlags {
+		_, err := pgDao.CreateFlag(context.TODO(), flag)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name      string
+		query     dao.FlagQuery
+		wantNames []string
+		wantTotal int
+	}{
+		{
+			name:      "should sort by last updated date descending by default",
+			query:     dao.FlagQuery{},
+			wantNames: []string{"checkoutXcolor", "checkout_button", "my-feature-flag"},
+			wantTotal: 3,
+		},
+		{
+			name:      "should sort by name and paginate",
+			query:     dao.FlagQuery{SortBy: dao.FlagSortByName, Limit: 1, Offset: 2},
+			wantNames: []string{"my-feature-flag"},
+			wantTotal: 3,
+		},
+		{
+			name:      "should escape the special characters of the name prefix",
+			query:     dao.FlagQuery{NamePrefix: "checkout_"},
+			wantNames: []string{"checkout_button"},
+			wantTotal: 1,
+		},
+		{
+			name:      "should filter by type",
+			query:     dao.FlagQuery{Type: model.FlagTypeString},
+			wantNames: []string{"checkoutXcolor", "my-feature-flag"},
+			wantTotal: 2,
+		},
+		{
+			name:      "should consider flags without disable field as enabled",
+			query:     dao.FlagQuery{Disabled: testutils.Bool(false)},
+			wantNames: []string{"checkout_button", "my-feature-flag"},
+			wantTotal: 2,
+		},
+		{
+			name:      "should filter by metadata key",
+			query:     dao.FlagQuery{MetadataKey: "team"},
+			wantNames: []string{"checkout_button"},
+			wantTotal: 1,
+		},
+		{
+			name:      "should filter by metadata key and value",
+			query:     dao.FlagQuery{MetadataKey: "key", MetadataValue: testutils.String("value")},
+			wantNames: []string{"my-feature-flag"},
+			wantTotal: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, total, err := pgDao.GetFlags(context.TODO(), tt.query)
+			require.NoError(t, err)
+			names := make([]string, 0, len(flags))
+			for _, flag := range flags {
+				names = append(names, flag.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+
+	// rules are loaded for every flag of the page
+	flags, _, err := pgDao.GetFlags(context.TODO(), dao.FlagQuery{})
+	require.NoError(t, err)
+	require.Len(t, flags, 3)
+	assert.Len(t, flags[0].GetRules(), 1)
+	assert.Len(t, flags[1].GetRules(), 0)
+	assert.Len(t, flags[2].GetRules(), 2)
+}
+
+func TestGetFlagsSummary(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+
+	summary, err := pgDao.GetFlagsSummary(context.TODO(), dao.FlagQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Total)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), summary.LastUpdatedDate.UTC())
+	assert.Equal(t, 0, summary.VersionCount)
+
+	summary, err = pgDao.GetFlagsSummary(context.TODO(), dao.FlagQuery{NamePrefix: "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, dao.FlagsSummary{}, summary)
+
+	flag, err := pgDao.GetFlagByID(context.TODO(), "69aa10ec-ec3e-4139-8cdf-6902a5746e2d")
+	require.NoError(t, err)
+	flag.Disable = testutils.Bool(true)
+	require.NoError(t, pgDao.UpdateFlag(context.TODO(), flag))
+
+	summary, err = pgDao.GetFlagsSummary(context.TODO(), dao.FlagQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Total)
+	assert.Equal(t, 1, summary.VersionCount)
+}
+
 func TestGetFlagByID(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -248,6 +388,8 @@ func TestGetFlagByID(t *testing.T) {
 			want: model.FeatureFlag{
 				ID:          "69aa10ec-ec3e-4139-8cdf-6902a5746e2d",
 				Name:        "my-feature-flag",
+				Project:     model.DefaultProject,
+				Environment: model.DefaultEnvironment,
 				Description: testutils.String("This is a feature flag"),
 				Variations: &map[string]interface{}{
 					"variationA": "valueA",
@@ -264,6 +406,7 @@ func TestGetFlagByID(t *testing.T) {
 				CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastModifiedBy:  "admin",
+				Revision:        1,
 				DefaultRule: &model.Rule{
 					ID:              "1cb941f2-adb4-460f-9259-b4416c90e9e1",
 					Name:            "default-rule",
@@ -356,6 +499,8 @@ func TestGetFlagByName(t *testing.T) {
 			want: model.FeatureFlag{
 				ID:          "69aa10ec-ec3e-4139-8cdf-6902a5746e2d",
 				Name:        "my-feature-flag",
+				Project:     model.DefaultProject,
+				Environment: model.DefaultEnvironment,
 				Description: testutils.String("This is a feature flag"),
 				Variations: &map[string]interface{}{
 					"variationA": "valueA",
@@ -372,6 +517,7 @@ func TestGetFlagByName(t *testing.T) {
 				CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastModifiedBy:  "admin",
+				Revision:        1,
 				DefaultRule: &model.Rule{
 					ID:              "1cb941f2-adb4-460f-9259-b4416c90e9e1",
 					Name:            "default-rule",
@@ -428,7 +574,8 @@ func TestGetFlagByName(t *testing.T) {
 			defer tearDownTest(t, pgContainer, conn)
 			pgDao := getPostgresDao(t, pgContainer)
 
-			got, err := pgDao.GetFlagByName(context.TODO(), tt.flagName)
+			got, err := pgDao.GetFlagByName(
+				context.TODO(), model.DefaultProject, model.DefaultEnvironment, tt.flagName)
 			if err != nil {
 				tt.wantErr(t, err)
 			} else {
@@ -659,6 +806,7 @@ func TestUpdateFlag(t *testing.T) {
 				CreatedDate:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastModifiedBy:  "bar",
+				Revision:        1,
 				DefaultRule: &model.Rule{
 					ID:   "1cb941f2-adb4-460f-9259-b4416c90e9e1",
 					Name: "default-rule",
@@ -690,6 +838,8 @@ func TestUpdateFlag(t *testing.T) {
 			want: model.FeatureFlag{
 				ID:          "69aa10ec-ec3e-4139-8cdf-6902a5746e2d",
 				Name:        "updated-name",
+				Project:     model.DefaultProject,
+				Environment: model.DefaultEnvironment,
 				Description: testutils.String("Updated description"),
 				Variations: &map[string]interface{}{
 					"variationC": "110",
@@ -708,6 +858,7 @@ func TestUpdateFlag(t *testing.T) {
 				CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastModifiedBy:  "bar",
+				Revision:        2,
 				DefaultRule: &model.Rule{
 					ID:   "1cb941f2-adb4-460f-9259-b4416c90e9e1",
 					Name: "default-rule",
@@ -790,6 +941,7 @@ func TestUpdateFlag(t *testing.T) {
 				CreatedDate:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastModifiedBy:  "bar",
+				Revision:        1,
 				DefaultRule: &model.Rule{
 					ID:   "1cb941f2-adb4-460f-9259-b4416c90e9e1",
 					Name: "default-rule",
@@ -818,6 +970,8 @@ func TestUpdateFlag(t *testing.T) {
 			want: model.FeatureFlag{
 				ID:          "69aa10ec-ec3e-4139-8cdf-6902a5746e2d",
 				Name:        "updated-name",
+				Project:     model.DefaultProject,
+				Environment: model.DefaultEnvironment,
 				Description: testutils.String("Updated description"),
 				Variations: &map[string]interface{}{
 					"variationC": "110",
@@ -836,6 +990,7 @@ func TestUpdateFlag(t *testing.T) {
 				CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
 				LastModifiedBy:  "bar",
+				Revision:        2,
 				DefaultRule: &model.Rule{
 					ID:   "1cb941f2-adb4-460f-9259-b4416c90e9e1",
 					Name: "default-rule",
@@ -883,9 +1038,485 @@ func TestUpdateFlag(t *testing.T) {
 	}
 }
 
+func TestUpdateFlagRevision(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+
+	flag, err := pgDao.GetFlagByID(context.TODO(), "69aa10ec-ec3e-4139-8cdf-6902a5746e2d")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), flag.Revision)
+
+	flag.Disable = testutils.Bool(true)
+	require.NoError(t, pgDao.UpdateFlag(context.TODO(), flag))
+
+	updated, err := pgDao.GetFlagByID(context.TODO(), flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Revision)
+	assert.True(t, *updated.Disable)
+
+	// second update with the revision we had before the first update
+	flag.Disable = testutils.Bool(false)
+	err = pgDao.UpdateFlag(context.TODO(), flag)
+	require.Error(t, err)
+	assert.Equal(t, daoerr.StaleRevision, err.Code())
+
+	notUpdated, err := pgDao.GetFlagByID(context.TODO(), flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), notUpdated.Revision)
+	assert.True(t, *notUpdated.Disable)
+
+	versions, total, err := pgDao.GetFlagVersions(context.TODO(), flag.ID, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total, "the failed update should not create a version")
+	assert.Equal(t, int64(2), versions[0].Flag.Revision)
+}
+
+func TestImportFlags(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+	ctx := context.TODO()
+
+	existing, err := pgDao.GetFlagByID(ctx, "69aa10ec-ec3e-4139-8cdf-6902a5746e2d")
+	require.NoError(t, err)
+	newFlag := model.FeatureFlag{
+		ID:            "6e0133ab-c262-4a0e-9eb1-79173c214921",
+		Name:          "imported-flag",
+		VariationType: model.FlagTypeString,
+		Variations:    &map[string]interface{}{"variationA": "A", "variationB": "B"},
+		DefaultRule: &model.Rule{
+			ID:              "6761c19f-1b74-49f1-9101-4c4aaa7e89e2",
+			VariationResult: testutils.String("variationA"),
+		},
+	}
+
+	// the update uses a stale revision, so the creation should be rolled back too
+	staleUpdate := existing
+	staleUpdate.Disable = testutils.Bool(true)
+	staleUpdate.Revision = existing.Revision + 1
+	err = pgDao.ImportFlags(ctx, []model.FeatureFlag{newFlag}, []model.FeatureFlag{staleUpdate})
+	require.Error(t, err)
+	assert.Equal(t, daoerr.StaleRevision, err.Code())
+	_, err = pgDao.GetFlagByID(ctx, newFlag.ID)
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+
+	update := existing
+	update.Disable = testutils.Bool(true)
+	require.NoError(t, pgDao.ImportFlags(ctx, []model.FeatureFlag{newFlag}, []model.FeatureFlag{update}))
+	created, err := pgDao.GetFlagByID(ctx, newFlag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "imported-flag", created.Name)
+	updated, err := pgDao.GetFlagByID(ctx, existing.ID)
+	require.NoError(t, err)
+	assert.True(t, *updated.Disable)
+	assert.Equal(t, existing.Revision+1, updated.Revision)
+}
+
 func TestPingSuccess(t *testing.T) {
 	pgContainer, conn := setupTest(t, []string{})
 	defer tearDownTest(t, pgContainer, conn)
 	pgDao := getPostgresDao(t, pgContainer)
 	assert.NoError(t, pgDao.Ping())
 }
+
+func TestGetFlagVersions(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+
+	flag := model.FeatureFlag{
+		ID:            "6e0133ab-c262-4a0e-9eb1-79173c214921",
+		Name:          "my-new-feature-flag",
+		Description:   testutils.String("This is a feature flag"),
+		VariationType: "string",
+		Variations: &map[string]interface{}{
+			"variationA": "A",
+			"variationB": "B",
+		},
+		CreatedDate:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUpdatedDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastModifiedBy:  "foo",
+		DefaultRule: &model.Rule{
+			ID:              "6761c19f-1b74-49f1-9101-4c4aaa7e89e2",
+			VariationResult: testutils.String("variationA"),
+		},
+		Rules: &[]model.Rule{
+			{
+				ID:              "8db65089-6785-4965-84a9-5d055469854b",
+				Name:            "rule 1",
+				Query:           "targetingKey eq \"valueA\"",
+				VariationResult: testutils.String("variationB"),
+			},
+		},
+	}
+	_, err := pgDao.CreateFlag(context.TODO(), flag)
+	require.NoError(t, err)
+
+	updatedFlag := flag
+	updatedFlag.Disable = testutils.Bool(true)
+	updatedFlag.LastUpdatedDate = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedFlag.LastModifiedBy = "bar"
+	require.NoError(t, pgDao.UpdateFlag(context.TODO(), updatedFlag))
+	require.NoError(t, pgDao.DeleteFlagByID(context.TODO(), flag.ID))
+
+	versions, total, err := pgDao.GetFlagVersions(context.TODO(), flag.ID, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, versions, 3)
+
+	assert.Equal(t, 3, versions[0].VersionNumber)
+	assert.Equal(t, model.FlagVersionActionDeleted, versions[0].Action)
+	assert.Equal(t, 2, versions[1].VersionNumber)
+	assert.Equal(t, model.FlagVersionActionUpdated, versions[1].Action)
+	assert.Equal(t, "bar", versions[1].Flag.LastModifiedBy)
+	assert.True(t, *versions[1].Flag.Disable)
+	assert.Equal(t, 1, versions[2].VersionNumber)
+	assert.Equal(t, model.FlagVersionActionCreated, versions[2].Action)
+	assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), versions[2].CreatedDate.UTC())
+	assert.Equal(t, "rule 1", versions[2].Flag.GetRules()[0].Name)
+
+	paginated, total, err := pgDao.GetFlagVersions(context.TODO(), flag.ID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, paginated, 1)
+	assert.Equal(t, 2, paginated[0].VersionNumber)
+
+	_, _, err = pgDao.GetFlagVersions(context.TODO(), "546939a9-6df8-4a0b-b9cf-1d69ff300eb5", 20, 0)
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+}
+
+func TestGetFlagVersion(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+
+	flag, err := pgDao.GetFlagByID(context.TODO(), "69aa10ec-ec3e-4139-8cdf-6902a5746e2d")
+	require.NoError(t, err)
+	flag.Description = testutils.String("Updated description")
+	flag.LastUpdatedDate = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, pgDao.UpdateFlag(context.TODO(), flag))
+
+	versions, _, err := pgDao.GetFlagVersions(context.TODO(), flag.ID, 20, 0)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+
+	got, err := pgDao.GetFlagVersion(context.TODO(), flag.ID, versions[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, versions[0], got)
+	assert.Equal(t, "Updated description", *got.Flag.Description)
+
+	_, err = pgDao.GetFlagVersion(context.TODO(), flag.ID, "546939a9-6df8-4a0b-b9cf-1d69ff300eb5")
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+
+	_, err = pgDao.GetFlagVersion(context.TODO(), flag.ID, "invalid-uuid")
+	require.Error(t, err)
+	assert.Equal(t, daoerr.InvalidUUID, err.Code())
+
+	// the history is append-only
+	_, errUpdate := conn.Exec(`UPDATE flag_history SET action = 'created'`)
+	assert.Error(t, errUpdate)
+	_, errDelete := conn.Exec(`DELETE FROM flag_history`)
+	assert.Error(t, errDelete)
+}
+
+func TestAPIKeys(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao, ok := getPostgresDao(t, pgContainer).(dao.APIKeyStorage)
+	require.True(t, ok, "the postgres dao should be able to store API keys")
+
+	// the dates are read back without timezone
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0))
+	apiKey := model.APIKey{
+		ID:          "a5b5d2b1-7c43-4a52-9b5e-0b1f4d6c8e01",
+		Name:        "ci",
+		Prefix:      "goff_abcdef",
+		HashedKey:   "0123456789abcdef",
+		Scopes:      []model.APIKeyScope{model.APIKeyScopeRead, model.APIKeyScopeWrite},
+		CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+		CreatedBy:   "foo",
+		ExpiresAt:   &expiresAt,
+	}
+	id, err := pgDao.CreateAPIKey(context.TODO(), apiKey)
+	require.NoError(t, err)
+	assert.Equal(t, apiKey.ID, id)
+
+	got, err := pgDao.GetAPIKeyByHashedKey(context.TODO(), "0123456789abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, apiKey, got)
+
+	_, err = pgDao.GetAPIKeyByHashedKey(context.TODO(), "unknown")
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+
+	lastUsedDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, pgDao.UpdateAPIKeyLastUsedDate(context.TODO(), apiKey.ID, lastUsedDate))
+	apiKeys, err := pgDao.GetAPIKeys(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 1)
+	require.NotNil(t, apiKeys[0].LastUsedDate)
+	assert.Equal(t, lastUsedDate, apiKeys[0].LastUsedDate.UTC())
+
+	require.NoError(t, pgDao.DeleteAPIKeyByID(context.TODO(), apiKey.ID))
+	err = pgDao.DeleteAPIKeyByID(context.TODO(), apiKey.ID)
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+
+	apiKeys, err = pgDao.GetAPIKeys(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, apiKeys)
+}
+
+func TestProjectsAndEnvironments(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+	ctx := context.TODO()
+
+	// the migration creates the default project and environment, the existing flags are moved in it
+	_, err := pgDao.GetEnvironment(ctx, model.DefaultProject, model.DefaultEnvironment)
+	require.NoError(t, err)
+	flag, err := pgDao.GetFlagByID(ctx, "69aa10ec-ec3e-4139-8cdf-6902a5746e2d")
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultProject, flag.Project)
+	assert.Equal(t, model.DefaultEnvironment, flag.Environment)
+
+	project := model.Project{
+		Name:        "checkout",
+		Description: testutils.String("checkout flow"),
+		CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+	}
+	require.NoError(t, pgDao.CreateProject(ctx, project))
+	got, err := pgDao.GetProject(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, project, got)
+	projects, err := pgDao.GetProjects(ctx)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "checkout", projects[0].Name)
+
+	err = pgDao.CreateEnvironment(ctx, model.Environment{Project: "unknown", Name: "production"})
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+
+	environment := model.Environment{
+		Project:     "checkout",
+		Name:        "production",
+		CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+	}
+	require.NoError(t, pgDao.CreateEnvironment(ctx, environment))
+	environments, err := pgDao.GetEnvironments(ctx, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Environment{environment}, environments)
+
+	// the same flag name can be used in another environment
+	flag.ID = "6e0133ab-c262-4a0e-9eb1-79173c214921"
+	flag.Project, flag.Environment = "checkout", "production"
+	flag.DefaultRule.ID = "6761c19f-1b74-49f1-9101-4c4aaa7e89e2"
+	flag.Rules = nil
+	_, err = pgDao.CreateFlag(ctx, flag)
+	require.NoError(t, err)
+	scopedFlag, err := pgDao.GetFlagByName(ctx, "checkout", "production", flag.Name)
+	require.NoError(t, err)
+	assert.Equal(t, flag.ID, scopedFlag.ID)
+	flags, total, err := pgDao.GetFlags(ctx, dao.FlagQuery{Project: "checkout", Environment: "production"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, flag.ID, flags[0].ID)
+
+	err = pgDao.DeleteEnvironment(ctx, "checkout", "production")
+	require.Error(t, err)
+	assert.Equal(t, daoerr.InUse, err.Code())
+	err = pgDao.DeleteProject(ctx, "checkout")
+	require.Error(t, err)
+	assert.Equal(t, daoerr.InUse, err.Code())
+
+	require.NoError(t, pgDao.DeleteFlagByID(ctx, flag.ID))
+	require.NoError(t, pgDao.DeleteEnvironment(ctx, "checkout", "production"))
+	require.NoError(t, pgDao.DeleteProject(ctx, "checkout"))
+	_, err = pgDao.GetProject(ctx, "checkout")
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+}
+
+func TestScheduledChanges(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao, ok := getPostgresDao(t, pgContainer).(dao.ScheduledChangeStorage)
+	require.True(t, ok, "the postgres dao should be able to store scheduled changes")
+	ctx := context.TODO()
+
+	// the dates are read back without timezone
+	change := model.ScheduledChange{
+		ID:              "0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+		FlagID:          "69aa10ec-ec3e-4139-8cdf-6902a5746e2d",
+		ScheduledDate:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+		Changes:         model.FlagChanges{Disable: testutils.Bool(true)},
+		Status:          model.ScheduledChangeStatusPending,
+		CreatedDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+		CreatedBy:       "foo",
+		LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+	}
+	id, err := pgDao.CreateScheduledChange(ctx, change)
+	require.NoError(t, err)
+	assert.Equal(t, change.ID, id)
+
+	got, err := pgDao.GetScheduledChangeByID(ctx, change.ID)
+	require.NoError(t, err)
+	assert.Equal(t, change, got)
+
+	_, err = pgDao.GetScheduledChangeByID(ctx, "1d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01")
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+
+	dueBefore := time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)
+	changes, err := pgDao.GetScheduledChanges(ctx, dao.ScheduledChangeQuery{DueBefore: &dueBefore})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	changes, err = pgDao.GetScheduledChanges(ctx, dao.ScheduledChangeQuery{
+		FlagID: change.FlagID,
+		Status: model.ScheduledChangeStatusPending,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []model.ScheduledChange{change}, changes)
+
+	appliedDate := time.Date(2030, 1, 1, 0, 1, 0, 0, time.FixedZone("", 0))
+	change.Status = model.ScheduledChangeStatusApplied
+	change.LastUpdatedDate = appliedDate
+	change.AppliedDate = &appliedDate
+	require.NoError(t, pgDao.UpdateScheduledChange(ctx, change))
+	got, err = pgDao.GetScheduledChangeByID(ctx, change.ID)
+	require.NoError(t, err)
+	assert.Equal(t, change, got)
+
+	// a change that is not pending anymore cannot be updated
+	change.Status = model.ScheduledChangeStatusCancelled
+	err = pgDao.UpdateScheduledChange(ctx, change)
+	require.Error(t, err)
+	assert.Equal(t, daoerr.StaleRevision, err.Code())
+
+	change.ID = "1d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01"
+	err = pgDao.UpdateScheduledChange(ctx, change)
+	require.Error(t, err)
+	assert.Equal(t, daoerr.NotFound, err.Code())
+}
+
+func TestApplyScheduledChange(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	storage := getPostgresDao(t, pgContainer)
+	pgDao, ok := storage.(dao.ScheduledChangeStorage)
+	require.True(t, ok)
+	ctx := context.TODO()
+
+	change := model.ScheduledChange{
+		ID:              "0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+		FlagID:          "69aa10ec-ec3e-4139-8cdf-6902a5746e2d",
+		ScheduledDate:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+		Changes:         model.FlagChanges{Disable: testutils.Bool(true)},
+		Status:          model.ScheduledChangeStatusPending,
+		CreatedDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+		LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0)),
+	}
+	_, err := pgDao.CreateScheduledChange(ctx, change)
+	require.NoError(t, err)
+	flag, err := storage.GetFlagByID(ctx, change.FlagID)
+	require.NoError(t, err)
+	applied := change
+	applied.Status = model.ScheduledChangeStatusApplied
+	require.NoError(t, pgDao.ApplyScheduledChange(ctx, change.Changes.ApplyTo(flag), applied))
+
+	flag, err = storage.GetFlagByID(ctx, change.FlagID)
+	require.NoError(t, err)
+	assert.True(t, flag.IsDisable())
+	got, err := pgDao.GetScheduledChangeByID(ctx, change.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ScheduledChangeStatusApplied, got.Status)
+
+	// the flag is not updated again if the change is not pending anymore
+	err = pgDao.ApplyScheduledChange(ctx, change.Changes.ApplyTo(flag), applied)
+	require.Error(t, err)
+	assert.Equal(t, daoerr.StaleRevision, err.Code())
+	reloaded, err := storage.GetFlagByID(ctx, change.FlagID)
+	require.NoError(t, err)
+	assert.Equal(t, flag.Revision, reloaded.Revision)
+}
+
+func TestFlagExperimentation(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+	ctx := context.TODO()
+
+	flag, err := pgDao.GetFlagByID(ctx, "69aa10ec-ec3e-4139-8cdf-6902a5746e2d")
+	require.NoError(t, err)
+	assert.Nil(t, flag.Experimentation)
+
+	// the dates are read back without timezone
+	flag.Experimentation = &model.ExperimentationRollout{
+		Start: testutils.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0))),
+		End:   testutils.Time(time.Date(2024, 1, 31, 0, 0, 0, 0, time.FixedZone("", 0))),
+	}
+	require.NoError(t, pgDao.UpdateFlag(ctx, flag))
+	got, err := pgDao.GetFlagByID(ctx, flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, flag.Experimentation, got.Experimentation)
+}
+
+func TestProgressiveRolloutSteps(t *testing.T) {
+	pgContainer, conn := setupTest(t, []string{"./testdata/initial_data.sql"})
+	defer tearDownTest(t, pgContainer, conn)
+	pgDao := getPostgresDao(t, pgContainer)
+	ctx := context.TODO()
+
+	flag, err := pgDao.GetFlagByID(ctx, "69aa10ec-ec3e-4139-8cdf-6902a5746e2d")
+	require.NoError(t, err)
+	require.NotNil(t, flag.Rules)
+	rules := *flag.Rules
+	require.Equal(t, "9f82fe80-b4b6-426a-869a-e4436de66d0d", rules[0].ID)
+	// the rollouts of 2 steps are returned with the initial and end steps
+	require.NotNil(t, rules[0].ProgressiveRollout)
+	assert.NotNil(t, rules[0].ProgressiveRollout.Initial)
+	assert.NotNil(t, rules[0].ProgressiveRollout.End)
+	assert.Empty(t, rules[0].ProgressiveRollout.Steps)
+
+	// the dates are read back without timezone
+	steps := []model.ProgressiveRolloutStep{
+		{
+			Variation: testutils.String("variationA"),
+			Date:      testutils.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("", 0))),
+		},
+		{
+			Variation:  testutils.String("variationB"),
+			Percentage: testutils.Float64(10),
+			Date:       testutils.Time(time.Date(2024, 1, 8, 0, 0, 0, 0, time.FixedZone("", 0))),
+		},
+		{
+			Variation:  testutils.String("variationB"),
+			Percentage: testutils.Float64(100),
+			Date:       testutils.Time(time.Date(2024, 1, 31, 0, 0, 0, 0, time.FixedZone("", 0))),
+		},
+	}
+	rules[0].ProgressiveRollout = &model.ProgressiveRollout{Steps: steps}
+	require.NoError(t, pgDao.UpdateFlag(ctx, flag))
+	got, err := pgDao.GetFlagByID(ctx, flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rules[0].ProgressiveRollout, (*got.Rules)[0].ProgressiveRollout)
+
+	flags, _, err := pgDao.GetFlags(ctx, dao.FlagQuery{})
+	require.NoError(t, err)
+	require.Len(t, flags, 1)
+	assert.Equal(t, rules[0].ProgressiveRollout, (*flags[0].Rules)[0].ProgressiveRollout)
+
+	// removing the rollout removes its steps
+	(*got.Rules)[0].ProgressiveRollout = nil
+	(*got.Rules)[0].VariationResult = testutils.String("variationA")
+	require.NoError(t, pgDao.UpdateFlag(ctx, got))
+	var count int
+	require.NoError(t, conn.Get(&count, `SELECT COUNT(*) FROM progressive_rollout_steps`))
+	assert.Equal(t, 0, count)
+}
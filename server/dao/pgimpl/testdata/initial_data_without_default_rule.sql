@@ -17,7 +17,7 @@ VALUES (
 );
 
 INSERT INTO rules
-(id, feature_flag_id, name, query, variation_result, percentages, disable, progressive_rollout_initial_variation, progressive_rollout_end_variation, progressive_rollout_initial_percentage, progressive_rollout_end_percentage, progressive_rollout_start_date, progressive_rollout_end_date, is_default, order_index)
+(id, feature_flag_id, name, query, variation_result, percentages, disable, is_default, order_index)
 VALUES (
    '546939a9-6df8-4a0b-b9cf-1d69ff300eb5',
    '69aa10ec-ec3e-4139-8cdf-6902a5746e2d',
@@ -26,18 +26,12 @@ VALUES (
    NULL,
    '{"variationA": 10, "variationB": 90}',
    FALSE,
-   NULL,
-   NULL,
-   NULL,
-   NULL,
-   NULL,
-   NULL,
    FALSE,
    1
 );
 
 INSERT INTO rules
-(id, feature_flag_id, name, query, variation_result, percentages, disable, progressive_rollout_initial_variation, progressive_rollout_end_variation, progressive_rollout_initial_percentage, progressive_rollout_end_percentage, progressive_rollout_start_date, progressive_rollout_end_date, is_default, order_index)
+(id, feature_flag_id, name, query, variation_result, percentages, disable, is_default, order_index)
 VALUES (
    '9f82fe80-b4b6-426a-869a-e4436de66d0d',
    '69aa10ec-ec3e-4139-8cdf-6902a5746e2d',
@@ -46,12 +40,12 @@ VALUES (
    NULL,
    NULL,
    TRUE,
-   'variationA',
-   'variationB',
-   0,
-   100,
-   '2023-01-01 00:00:00' AT TIME ZONE 'UTC',
-   '2024-01-01 00:00:00' AT TIME ZONE 'UTC',
    FALSE,
    0
-);
\ No newline at end of file
+);
+
+INSERT INTO progressive_rollout_steps
+(rule_id, step_index, variation, percentage, date)
+VALUES
+   ('9f82fe80-b4b6-426a-869a-e4436de66d0d', 0, 'variationA', 0, '2023-01-01 00:00:00' AT TIME ZONE 'UTC'),
+   ('9f82fe80-b4b6-426a-869a-e4436de66d0d', 1, 'variationB', 100, '2024-01-01 00:00:00' AT TIME ZONE 'UTC');
\ No newline at end of file
@@ -0,0 +1,37 @@
+package dao
+
+import (
+	"context"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+// ProjectStorage persists the projects and environments used to scope the flags.
+// The storage should always contain the default project and its default environment, they are used for the flags
+// created without any scope.
+type ProjectStorage interface {
+	// GetProjects return all the projects ordered by name
+	GetProjects(ctx context.Context) ([]model.Project, daoErr.DaoError)
+
+	// GetProject return a project by its name
+	GetProject(ctx context.Context, name string) (model.Project, daoErr.DaoError)
+
+	// CreateProject create a new project
+	CreateProject(ctx context.Context, project model.Project) daoErr.DaoError
+
+	// DeleteProject delete a project, it returns an InUse error if the project still has environments
+	DeleteProject(ctx context.Context, name string) daoErr.DaoError
+
+	// GetEnvironments return the environments of a project ordered by name
+	GetEnvironments(ctx context.Context, project string) ([]model.Environment, daoErr.DaoError)
+
+	// GetEnvironment return an environment of a project by its name
+	GetEnvironment(ctx context.Context, project string, name string) (model.Environment, daoErr.DaoError)
+
+	// CreateEnvironment create a new environment in an existing project
+	CreateEnvironment(ctx context.Context, environment model.Environment) daoErr.DaoError
+
+	// DeleteEnvironment delete an environment, it returns an InUse error if the environment still has flags
+	DeleteEnvironment(ctx context.Context, project string, name string) daoErr.DaoError
+}
@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"context"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+// ScheduledChangeQuery contains the options to select the scheduled changes returned by GetScheduledChanges.
+// All the filters are optional, an empty ScheduledChangeQuery returns all the scheduled changes.
+type ScheduledChangeQuery struct {
+	// FlagID keeps only the changes of this flag
+	FlagID string
+	// Status keeps only the changes with this status
+	Status model.ScheduledChangeStatus
+	// DueBefore keeps only the changes scheduled at or before this date
+	DueBefore *time.Time
+}
+
+// Match returns true if the scheduled change matches all the filters of the query
+func (q ScheduledChangeQuery) Match(change model.ScheduledChange) bool {
+	return (q.FlagID == "" || change.FlagID == q.FlagID) &&
+		(q.Status == "" || change.Status == q.Status) &&
+		(q.DueBefore == nil || !change.ScheduledDate.After(*q.DueBefore))
+}
+
+// ScheduledChangeStorage is implemented by the storages able to persist the scheduled changes of the flags.
+// It is optional, the scheduled changes endpoints and the worker applying them are only available
+// when the storage used implements it.
+type ScheduledChangeStorage interface {
+	// GetScheduledChanges return the scheduled changes matching the query, ordered by scheduled date
+	GetScheduledChanges(ctx context.Context, query ScheduledChangeQuery) ([]model.ScheduledChange, daoErr.DaoError)
+
+	// GetScheduledChangeByID return a scheduled change by its ID
+	GetScheduledChangeByID(ctx context.Context, id string) (model.ScheduledChange, daoErr.DaoError)
+
+	// CreateScheduledChange create a new scheduled change, return the id of the scheduled change
+	CreateScheduledChange(ctx context.Context, change model.ScheduledChange) (string, daoErr.DaoError)
+
+	// UpdateScheduledChange update a pending scheduled change (its date, its changes or its status).
+	// It returns a NotFound error if the change does not exist and a StaleRevision error if the change is not
+	// pending anymore, so a change cannot be edited while it is applied.
+	UpdateScheduledChange(ctx context.Context, change model.ScheduledChange) daoErr.DaoError
+
+	// ApplyScheduledChange updates the flag and stores the change (with its new status) in a single transaction,
+	// so a change is never applied without being marked as applied.
+	// It returns a StaleRevision error if the flag has been modified since its revision or if the change is not
+	// pending anymore, in both cases nothing is modified.
+	ApplyScheduledChange(ctx context.Context, flag model.FeatureFlag, change model.ScheduledChange) daoErr.DaoError
+}
@@ -0,0 +1,27 @@
+package sqlimpl
+
+import (
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+)
+
+// Dialect contains what differs between the SQL databases, the rest of the queries is shared by all of them.
+// The queries of the dialect use "?" placeholders, they are rebound to the format of the driver.
+type Dialect struct {
+	// WrapError converts an error of the database into a DaoError.
+	WrapError func(err error) daoerr.DaoError
+
+	// JSONParam returns the named parameter of a JSON column, some databases need a conversion to accept
+	// the values sent by the driver (ex: "CAST(:metadata AS TEXT)").
+	JSONParam func(name string) string
+
+	// NamePrefixFilter returns the condition matching the flags with a name starting with the prefix, and its
+	// argument. The prefix is case-sensitive.
+	NamePrefixFilter func(prefix string) (string, interface{})
+
+	// MetadataFilter returns the condition matching the flags having the metadata key, and its arguments.
+	// If the value is not nil, the metadata should also have this value, the values are compared as text.
+	MetadataFilter func(key string, value *string) (string, []interface{})
+
+	// DueBeforeFilter is the condition matching the scheduled changes due before the date in argument.
+	DueBeforeFilter string
+}
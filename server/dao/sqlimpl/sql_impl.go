@@ -0,0 +1,1041 @@
+package sqlimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	dbmodel2 "github.com/go-feature-flag/flag-management/server/dao/dbmodel"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/model"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewStorage returns a dao.FlagStorage using the connection, the dialect contains what is specific to the database.
+func NewStorage(conn *sqlx.DB, dialect Dialect) dao.FlagStorage {
+	return &sqlFlagImpl{
+		conn:    conn,
+		dialect: dialect,
+	}
+}
+
+type sqlFlagImpl struct {
+	conn    *sqlx.DB
+	dialect Dialect
+}
+
+// GetFlags return the flags matching the query and the total number of flags matching the filters
+func (m *sqlFlagImpl) GetFlags(
+	ctx context.Context, query dao.FlagQuery) ([]model.FeatureFlag, int, daoerr.DaoError) {
+	where, args := m.buildFlagQueryFilters(query)
+
+	var total int
+	err := m.conn.GetContext(ctx, &total, m.conn.Rebind(`SELECT COUNT(*) FROM feature_flags`+where), args...)
+	if err != nil {
+		return []model.FeatureFlag{}, 0, m.dialect.WrapError(err)
+	}
+
+	selectQuery := `SELECT * FROM feature_flags` + where + buildFlagQueryOrderBy(query)
+	if query.Limit > 0 || query.Offset > 0 {
+		// not all the databases accept an OFFSET without LIMIT
+		limit := math.MaxInt64
+		if query.Limit > 0 {
+			limit = query.Limit
+		}
+		args = append(args, limit, query.Offset)
+		selectQuery += " LIMIT ? OFFSET ?"
+	}
+
+	var f []dbmodel2.FeatureFlag
+	err = m.conn.SelectContext(ctx, &f, m.conn.Rebind(selectQuery), args...)
+	if err != nil {
+		return []model.FeatureFlag{}, 0, m.dialect.WrapError(err)
+	}
+	if len(f) == 0 {
+		return []model.FeatureFlag{}, total, nil
+	}
+
+	// we retrieve the rules of all the flags of the page in a single query
+	flagIDs := make([]string, 0, len(f))
+	for _, flag := range f {
+		flagIDs = append(flagIDs, flag.ID.String())
+	}
+	rulesQuery, rulesArgs, err := sqlx.In(
+		`SELECT * FROM rules WHERE feature_flag_id IN (?) ORDER BY order_index`, flagIDs)
+	if err != nil {
+		return []model.FeatureFlag{}, 0, m.dialect.WrapError(err)
+	}
+	var rules []dbmodel2.Rule
+	if err := m.conn.SelectContext(ctx, &rules, m.conn.Rebind(rulesQuery), rulesArgs...); err != nil {
+		return []model.FeatureFlag{}, 0, m.dialect.WrapError(err)
+	}
+	if err := m.loadProgressiveRolloutSteps(ctx, m.conn, rules); err != nil {
+		return []model.FeatureFlag{}, 0, m.dialect.WrapError(err)
+	}
+	rulesByFlag := make(map[uuid.UUID][]dbmodel2.Rule, len(f))
+	for _, rule := range rules {
+		rulesByFlag[rule.FeatureFlagID] = append(rulesByFlag[rule.FeatureFlagID], rule)
+	}
+
+	res := make([]model.FeatureFlag, 0, len(f))
+	for _, flag := range f {
+		convertedFlag, err := flag.ToModelFeatureFlag(rulesByFlag[flag.ID])
+		if err != nil {
+			return []model.FeatureFlag{}, 0, m.dialect.WrapError(err)
+		}
+		res = append(res, convertedFlag)
+	}
+	return res, total, nil
+}
+
+// GetFlagsSummary return the number of flags matching the query and their most recent last updated date
+func (m *sqlFlagImpl) GetFlagsSummary(
+	ctx context.Context, query dao.FlagQuery) (dao.FlagsSummary, daoerr.DaoError) {
+	where, args := m.buildFlagQueryFilters(query)
+	var total, versionCount int
+	err := m.conn.QueryRowxContext(ctx,
+		m.conn.Rebind(`SELECT COUNT(*), (SELECT COUNT(*) FROM flag_history) FROM feature_flags`+where),
+		args...).Scan(&total, &versionCount)
+	if err != nil {
+		return dao.FlagsSummary{}, m.dialect.WrapError(err)
+	}
+	if total == 0 {
+		return dao.FlagsSummary{Total: total, VersionCount: versionCount}, nil
+	}
+
+	// the result of MAX() has no column type in SQLite, the driver would return it as a string instead of a date
+	var lastUpdatedDate time.Time
+	err = m.conn.GetContext(ctx, &lastUpdatedDate, m.conn.Rebind(
+		`SELECT last_updated_date FROM feature_flags`+where+` ORDER BY last_updated_date DESC LIMIT 1`), args...)
+	if err != nil {
+		return dao.FlagsSummary{}, m.dialect.WrapError(err)
+	}
+	return dao.FlagsSummary{Total: total, LastUpdatedDate: lastUpdatedDate, VersionCount: versionCount}, nil
+}
+
+// buildFlagQueryFilters returns the WHERE clause matching the filters of the query and its arguments.
+func (m *sqlFlagImpl) buildFlagQueryFilters(query dao.FlagQuery) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	if query.Project != "" {
+		args = append(args, query.Project)
+		conditions = append(conditions, "project = ?")
+	}
+	if query.Environment != "" {
+		args = append(args, query.Environment)
+		conditions = append(conditions, "environment = ?")
+	}
+	if query.NamePrefix != "" {
+		condition, arg := m.dialect.NamePrefixFilter(query.NamePrefix)
+		args = append(args, arg)
+		conditions = append(conditions, condition)
+	}
+	if query.Type != "" {
+		args = append(args, query.Type)
+		conditions = append(conditions, "type = ?")
+	}
+	if query.Disabled != nil {
+		args = append(args, *query.Disabled)
+		conditions = append(conditions, "COALESCE(disable, false) = ?")
+	}
+	if query.MetadataKey != "" {
+		condition, metadataArgs := m.dialect.MetadataFilter(query.MetadataKey, query.MetadataValue)
+		args = append(args, metadataArgs...)
+		conditions = append(conditions, condition)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// buildFlagQueryOrderBy returns the ORDER BY clause of the query, the id is used to keep a stable order.
+func buildFlagQueryOrderBy(query dao.FlagQuery) string {
+	column := "last_updated_date"
+	switch query.SortBy {
+	case dao.FlagSortByName:
+		column = "name"
+	case dao.FlagSortByCreatedDate:
+		column = "created_date"
+	}
+
+	direction := "ASC"
+	if query.SortDesc || query.SortBy == "" {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+}
+
+// GetFlagByID return a flag by its ID
+func (m *sqlFlagImpl) GetFlagByID(ctx context.Context, id string) (model.FeatureFlag, daoerr.DaoError) {
+	return m.getFlagByID(ctx, m.conn, id)
+}
+
+// getFlagByID return a flag by its ID, it can be called inside or outside a transaction
+func (m *sqlFlagImpl) getFlagByID(
+	ctx context.Context, q sqlx.QueryerContext, id string) (model.FeatureFlag, daoerr.DaoError) {
+	if daoErr := checkUUID(id); daoErr != nil {
+		return model.FeatureFlag{}, daoErr
+	}
+	var f dbmodel2.FeatureFlag
+	err := sqlx.GetContext(ctx, q, &f, m.conn.Rebind(`SELECT * FROM feature_flags WHERE id = ?`), id)
+	if err != nil {
+		return model.FeatureFlag{}, m.dialect.WrapError(err)
+	}
+	return m.getFlagRules(ctx, q, f)
+}
+
+// GetFlagByName return a flag by its name inside an environment
+func (m *sqlFlagImpl) GetFlagByName(
+	ctx context.Context, project string, environment string, name string) (model.FeatureFlag, daoerr.DaoError) {
+	var f dbmodel2.FeatureFlag
+	err := m.conn.GetContext(ctx, &f,
+		m.conn.Rebind(`SELECT * FROM feature_flags WHERE project = ? AND environment = ? AND name = ?`),
+		project, environment, name)
+	if err != nil {
+		return model.FeatureFlag{}, m.dialect.WrapError(err)
+	}
+	return m.getFlagRules(ctx, m.conn, f)
+}
+
+// getFlagRules retrieves the rules of a flag and converts it into a model.FeatureFlag
+func (m *sqlFlagImpl) getFlagRules(
+	ctx context.Context, q sqlx.QueryerContext, f dbmodel2.FeatureFlag) (model.FeatureFlag, daoerr.DaoError) {
+	var rules []dbmodel2.Rule
+	err := sqlx.SelectContext(ctx, q, &rules,
+		m.conn.Rebind(`SELECT * FROM rules WHERE feature_flag_id = ? ORDER BY order_index`), f.ID)
+	if err != nil {
+		return model.FeatureFlag{}, m.dialect.WrapError(err)
+	}
+	if err := m.loadProgressiveRolloutSteps(ctx, q, rules); err != nil {
+		return model.FeatureFlag{}, m.dialect.WrapError(err)
+	}
+	convertedFlag, err := f.ToModelFeatureFlag(rules)
+	if err != nil {
+		return model.FeatureFlag{}, m.dialect.WrapError(err)
+	}
+	return convertedFlag, nil
+}
+
+// CreateFlag create a new flag, return the id of the flag
+func (m *sqlFlagImpl) CreateFlag(ctx context.Context, flag model.FeatureFlag) (string, daoerr.DaoError) {
+	tx, err := m.conn.Beginx()
+	if err != nil {
+		return "", m.dialect.WrapError(err)
+	}
+	id, daoErr := m.createFlag(ctx, tx, flag)
+	if daoErr != nil {
+		_ = tx.Rollback()
+		return "", daoErr
+	}
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return "", m.dialect.WrapError(err)
+	}
+	return id, nil
+}
+
+func (m *sqlFlagImpl) UpdateFlag(ctx context.Context, flag model.FeatureFlag) daoerr.DaoError {
+	tx, err := m.conn.Beginx()
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	if daoErr := m.updateFlag(ctx, tx, flag); daoErr != nil {
+		_ = tx.Rollback()
+		return daoErr
+	}
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+// ImportFlags create and update a list of flags in a single transaction
+func (m *sqlFlagImpl) ImportFlags(
+	ctx context.Context, creations []model.FeatureFlag, updates []model.FeatureFlag) daoerr.DaoError {
+	tx, err := m.conn.Beginx()
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	for _, flag := range creations {
+		if _, daoErr := m.createFlag(ctx, tx, flag); daoErr != nil {
+			_ = tx.Rollback()
+			return daoErr
+		}
+	}
+	for _, flag := range updates {
+		if daoErr := m.updateFlag(ctx, tx, flag); daoErr != nil {
+			_ = tx.Rollback()
+			return daoErr
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+// createFlag inserts the flag and its rules in the transaction, it is the caller responsibility
+// to commit or rollback the transaction.
+func (m *sqlFlagImpl) createFlag(ctx context.Context, tx *sqlx.Tx, flag model.FeatureFlag) (string, daoerr.DaoError) {
+	dbFeatureFlag, err := dbmodel2.FromModelFeatureFlag(flag)
+	if err != nil {
+		return "", m.dialect.WrapError(err)
+	}
+
+	_, err = tx.NamedExecContext(
+		ctx,
+		`INSERT INTO feature_flags (
+                           id,
+                           name,
+                           project,
+                           environment,
+                           description,
+                           variations,
+                           type,
+                           bucketing_key,
+                           metadata,
+                           track_events,
+                           disable,
+                           version,
+                           experimentation_start,
+                           experimentation_end,
+                           created_date,
+                           last_updated_date,
+                           last_modified_by,
+                           revision)
+				VALUES (
+				        :id,
+				        :name,
+				        :project,
+				        :environment,
+				        :description,
+				        `+m.dialect.JSONParam("variations")+`,
+				        :type,
+				        :bucketing_key,
+				        `+m.dialect.JSONParam("metadata")+`,
+				        :track_events,
+				        :disable,
+				        :version,
+				        :experimentation_start,
+				        :experimentation_end,
+				        :created_date,
+				        :last_updated_date,
+				        :last_modified_by,
+				        1)`,
+		dbFeatureFlag)
+	if err != nil {
+		return "", m.wrapInsertError(err)
+	}
+
+	if flag.DefaultRule == nil {
+		return "", daoerr.NewDaoError(daoerr.DefaultRuleRequired, fmt.Errorf("default rule is required"))
+	}
+	err = m.insertRule(ctx, *flag.DefaultRule, true, dbFeatureFlag.ID, tx, -1)
+	if err != nil {
+		return "", m.dialect.WrapError(err)
+	}
+
+	if flag.Rules != nil {
+		for index, rule := range *flag.Rules {
+			err = m.insertRule(ctx, rule, false, dbFeatureFlag.ID, tx, index)
+			if err != nil {
+				return "", m.dialect.WrapError(err)
+			}
+		}
+	}
+
+	if daoErr := m.insertFlagHistory(
+		ctx, tx, dbFeatureFlag.ID.String(), model.FlagVersionActionCreated, flag.LastUpdatedDate); daoErr != nil {
+		return "", daoErr
+	}
+	return dbFeatureFlag.ID.String(), nil
+}
+
+// updateFlag updates the flag and its rules in the transaction, it is the caller responsibility
+// to commit or rollback the transaction.
+func (m *sqlFlagImpl) updateFlag(ctx context.Context, tx *sqlx.Tx, flag model.FeatureFlag) daoerr.DaoError {
+	dbQuery, errConv := dbmodel2.FromModelFeatureFlag(flag)
+	if errConv != nil {
+		return m.dialect.WrapError(errConv)
+	}
+
+	dbFF, getFlagErr := m.getFlagByID(ctx, tx, flag.ID)
+	if getFlagErr != nil {
+		return getFlagErr
+	}
+
+	if flag.DefaultRule == nil {
+		return daoerr.NewDaoError(daoerr.DefaultRuleRequired, fmt.Errorf("default rule is required"))
+	}
+	if err := m.updateRule(ctx, flag.GetDefaultRule(), true, dbQuery.ID, tx, -1); err != nil {
+		return m.dialect.WrapError(err)
+	}
+	if err := m.updateTargetingRules(ctx, tx, dbQuery.ID, dbFF.GetRules(), flag.GetRules()); err != nil {
+		return m.dialect.WrapError(err)
+	}
+
+	// Update the flag, only if nobody has updated it since the revision we got
+	res, errTx := tx.NamedExecContext(
+		ctx,
+		`UPDATE feature_flags SET
+				 name=:name,
+				 description=:description,
+				 variations=`+m.dialect.JSONParam("variations")+`,
+				 bucketing_key=:bucketing_key,
+				 metadata=`+m.dialect.JSONParam("metadata")+`,
+				 track_events=:track_events,
+				 disable=:disable,
+				 version=:version,
+				 experimentation_start=:experimentation_start,
+				 experimentation_end=:experimentation_end,
+				 last_updated_date=:last_updated_date,
+				 last_modified_by=:last_modified_by,
+				 revision=revision + 1
+				WHERE id = :id AND revision = :revision`,
+		dbQuery)
+	if errTx != nil {
+		return m.dialect.WrapError(errTx)
+	}
+	if updatedRows, err := res.RowsAffected(); err != nil || updatedRows == 0 {
+		return daoerr.NewDaoError(daoerr.StaleRevision,
+			fmt.Errorf("flag %s has been modified since revision %d", flag.ID, flag.Revision))
+	}
+
+	return m.insertFlagHistory(ctx, tx, flag.ID, model.FlagVersionActionUpdated, flag.LastUpdatedDate)
+}
+
+// updateTargetingRules deletes, creates and updates the targeting rules of a flag to match the new rules.
+func (m *sqlFlagImpl) updateTargetingRules(
+	ctx context.Context, tx *sqlx.Tx, featureFlagID uuid.UUID, existingRules []model.Rule, newRules []model.Rule) error {
+	existing := make(map[string]bool, len(existingRules))
+	for _, rule := range existingRules {
+		existing[rule.ID] = true
+	}
+	kept := make(map[string]bool, len(newRules))
+	for _, rule := range newRules {
+		kept[rule.ID] = true
+	}
+
+	for id := range existing {
+		if kept[id] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM rules WHERE id = ?`), id); err != nil {
+			return err
+		}
+	}
+	for index, rule := range newRules {
+		save := m.insertRule
+		if existing[rule.ID] {
+			save = m.updateRule
+		}
+		if err := save(ctx, rule, false, featureFlagID, tx, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *sqlFlagImpl) DeleteFlagByID(ctx context.Context, id string) daoerr.DaoError {
+	tx, err := m.conn.Beginx()
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+
+	// we keep a last version of the flag to be able to see what it was before the deletion
+	_, getFlagErr := m.getFlagByID(ctx, tx, id)
+	switch {
+	case getFlagErr == nil:
+		if daoErr := m.insertFlagHistory(ctx, tx, id, model.FlagVersionActionDeleted, time.Now()); daoErr != nil {
+			_ = tx.Rollback()
+			return daoErr
+		}
+	case getFlagErr.Code() != daoerr.NotFound:
+		_ = tx.Rollback()
+		return getFlagErr
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`DELETE FROM rules WHERE feature_flag_id = ?`), id)
+	if err != nil {
+		_ = tx.Rollback()
+		return m.dialect.WrapError(err)
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`DELETE FROM feature_flags WHERE id = ?`), id)
+	if err != nil {
+		_ = tx.Rollback()
+		return m.dialect.WrapError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+// GetFlagVersions return the versions of a flag (the most recent first) and the total number of versions.
+func (m *sqlFlagImpl) GetFlagVersions(
+	ctx context.Context, flagID string, limit int, offset int) ([]model.FlagVersion, int, daoerr.DaoError) {
+	if daoErr := checkUUID(flagID); daoErr != nil {
+		return nil, 0, daoErr
+	}
+	var total int
+	err := m.conn.GetContext(ctx, &total,
+		m.conn.Rebind(`SELECT COUNT(*) FROM flag_history WHERE feature_flag_id = ?`), flagID)
+	if err != nil {
+		return nil, 0, m.dialect.WrapError(err)
+	}
+	if total == 0 {
+		return nil, 0, daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("no version found for flag %s", flagID))
+	}
+
+	var history []dbmodel2.FlagHistory
+	err = m.conn.SelectContext(ctx, &history, m.conn.Rebind(
+		`SELECT * FROM flag_history WHERE feature_flag_id = ? ORDER BY version_number DESC LIMIT ? OFFSET ?`),
+		flagID, limit, offset)
+	if err != nil {
+		return nil, 0, m.dialect.WrapError(err)
+	}
+
+	res := make([]model.FlagVersion, 0, len(history))
+	for _, h := range history {
+		version, err := h.ToModelFlagVersion()
+		if err != nil {
+			return nil, 0, daoerr.NewDaoError(daoerr.ConversionError, err)
+		}
+		res = append(res, version)
+	}
+	return res, total, nil
+}
+
+// GetFlagVersion return a specific version of a flag
+func (m *sqlFlagImpl) GetFlagVersion(
+	ctx context.Context, flagID string, versionID string) (model.FlagVersion, daoerr.DaoError) {
+	for _, id := range []string{flagID, versionID} {
+		if daoErr := checkUUID(id); daoErr != nil {
+			return model.FlagVersion{}, daoErr
+		}
+	}
+	var h dbmodel2.FlagHistory
+	err := m.conn.GetContext(ctx, &h,
+		m.conn.Rebind(`SELECT * FROM flag_history WHERE feature_flag_id = ? AND id = ?`), flagID, versionID)
+	if err != nil {
+		return model.FlagVersion{}, m.dialect.WrapError(err)
+	}
+	version, err := h.ToModelFlagVersion()
+	if err != nil {
+		return model.FlagVersion{}, daoerr.NewDaoError(daoerr.ConversionError, err)
+	}
+	return version, nil
+}
+
+// insertFlagHistory stores a snapshot of the flag as it is in the transaction.
+// It should be called after all the changes on the flag and its rules are done.
+func (m *sqlFlagImpl) insertFlagHistory(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	flagID string,
+	action model.FlagVersionAction,
+	date time.Time) daoerr.DaoError {
+	snapshot, daoErr := m.getFlagByID(ctx, tx, flagID)
+	if daoErr != nil {
+		return daoErr
+	}
+
+	var versionNumber int
+	err := tx.GetContext(ctx, &versionNumber,
+		tx.Rebind(`SELECT COALESCE(MAX(version_number), 0) + 1 FROM flag_history WHERE feature_flag_id = ?`), flagID)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+
+	h, err := dbmodel2.FromModelFlagVersion(model.FlagVersion{
+		FlagID:        flagID,
+		VersionNumber: versionNumber,
+		Action:        action,
+		CreatedDate:   date,
+		Flag:          snapshot,
+	})
+	if err != nil {
+		return daoerr.NewDaoError(daoerr.ConversionError, err)
+	}
+
+	_, err = tx.NamedExecContext(ctx,
+		`INSERT INTO flag_history (id, feature_flag_id, version_number, action, snapshot, created_date)
+				VALUES (:id, :feature_flag_id, :version_number, :action, `+m.dialect.JSONParam("snapshot")+`,
+				:created_date)`,
+		h)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+func (m *sqlFlagImpl) insertRule(
+	ctx context.Context,
+	rule model.Rule,
+	isDefault bool,
+	featureFlagID uuid.UUID,
+	tx *sqlx.Tx,
+	orderIndex int) error {
+	r, err := dbmodel2.FromModelRule(rule, featureFlagID, isDefault, orderIndex)
+	if err != nil {
+		return err
+	}
+
+	_, errTx := tx.NamedExecContext(
+		ctx,
+		`INSERT INTO rules (
+					id,
+					feature_flag_id,
+					is_default,
+					name,
+					query,
+					variation_result,
+					percentages,
+					disable,
+					order_index)
+				VALUES (
+					:id,
+					:feature_flag_id,
+					:is_default,
+					:name,
+					:query,
+					:variation_result,
+					`+m.dialect.JSONParam("percentages")+`,
+					:disable,
+					:order_index)`,
+		r)
+	if errTx != nil {
+		return errTx
+	}
+	return m.saveProgressiveRolloutSteps(ctx, tx, r)
+}
+
+func (m *sqlFlagImpl) updateRule(
+	ctx context.Context,
+	rule model.Rule,
+	isDefault bool,
+	featureFlagID uuid.UUID,
+	tx *sqlx.Tx, orderIndex int) error {
+	r, err := dbmodel2.FromModelRule(rule, featureFlagID, isDefault, orderIndex)
+	if err != nil {
+		return err
+	}
+
+	_, errTx := tx.NamedExecContext(ctx,
+		`UPDATE rules SET
+                 name=:name,
+                 query=:query,
+                 variation_result=:variation_result,
+                 percentages=`+m.dialect.JSONParam("percentages")+`,
+                 disable=:disable,
+                 order_index=:order_index
+             WHERE id=:id`, r)
+	if errTx != nil {
+		return errTx
+	}
+	return m.saveProgressiveRolloutSteps(ctx, tx, r)
+}
+
+// saveProgressiveRolloutSteps replaces the steps of the progressive rollout of a rule.
+func (m *sqlFlagImpl) saveProgressiveRolloutSteps(ctx context.Context, tx *sqlx.Tx, rule dbmodel2.Rule) error {
+	if _, err := tx.ExecContext(ctx,
+		tx.Rebind(`DELETE FROM progressive_rollout_steps WHERE rule_id = ?`), rule.ID); err != nil {
+		return err
+	}
+	if len(rule.ProgressiveRolloutSteps) == 0 {
+		return nil
+	}
+	_, err := tx.NamedExecContext(ctx,
+		`INSERT INTO progressive_rollout_steps (rule_id, step_index, variation, percentage, date)
+			VALUES (:rule_id, :step_index, :variation, :percentage, :date)`,
+		rule.ProgressiveRolloutSteps)
+	return err
+}
+
+// loadProgressiveRolloutSteps retrieves the steps of the progressive rollouts of the rules in a single query.
+func (m *sqlFlagImpl) loadProgressiveRolloutSteps(
+	ctx context.Context, q sqlx.QueryerContext, rules []dbmodel2.Rule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	ruleIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, rule.ID.String())
+	}
+	query, args, err := sqlx.In(
+		`SELECT * FROM progressive_rollout_steps WHERE rule_id IN (?) ORDER BY rule_id, step_index`, ruleIDs)
+	if err != nil {
+		return err
+	}
+	var steps []dbmodel2.ProgressiveRolloutStep
+	if err := sqlx.SelectContext(ctx, q, &steps, m.conn.Rebind(query), args...); err != nil {
+		return err
+	}
+	stepsByRule := make(map[uuid.UUID][]dbmodel2.ProgressiveRolloutStep, len(rules))
+	for _, step := range steps {
+		stepsByRule[step.RuleID] = append(stepsByRule[step.RuleID], step)
+	}
+	for i := range rules {
+		rules[i].ProgressiveRolloutSteps = stepsByRule[rules[i].ID]
+	}
+	return nil
+}
+
+// GetAPIKeys return all the API keys (the most recent first)
+func (m *sqlFlagImpl) GetAPIKeys(ctx context.Context) ([]model.APIKey, daoerr.DaoError) {
+	var apiKeys []dbmodel2.APIKey
+	err := m.conn.SelectContext(ctx, &apiKeys, `SELECT * FROM api_keys ORDER BY created_date DESC, id`)
+	if err != nil {
+		return nil, m.dialect.WrapError(err)
+	}
+	res := make([]model.APIKey, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		converted, err := apiKey.ToModelAPIKey()
+		if err != nil {
+			return nil, daoerr.NewDaoError(daoerr.ConversionError, err)
+		}
+		res = append(res, converted)
+	}
+	return res, nil
+}
+
+// GetAPIKeyByHashedKey return the API key matching the hash of the key
+func (m *sqlFlagImpl) GetAPIKeyByHashedKey(ctx context.Context, hashedKey string) (model.APIKey, daoerr.DaoError) {
+	var apiKey dbmodel2.APIKey
+	err := m.conn.GetContext(ctx, &apiKey, m.conn.Rebind(`SELECT * FROM api_keys WHERE hashed_key = ?`), hashedKey)
+	if err != nil {
+		return model.APIKey{}, m.dialect.WrapError(err)
+	}
+	converted, err := apiKey.ToModelAPIKey()
+	if err != nil {
+		return model.APIKey{}, daoerr.NewDaoError(daoerr.ConversionError, err)
+	}
+	return converted, nil
+}
+
+// CreateAPIKey create a new API key, return the id of the API key
+func (m *sqlFlagImpl) CreateAPIKey(ctx context.Context, apiKey model.APIKey) (string, daoerr.DaoError) {
+	dbAPIKey, err := dbmodel2.FromModelAPIKey(apiKey)
+	if err != nil {
+		return "", daoerr.NewDaoError(daoerr.ConversionError, err)
+	}
+	_, err = m.conn.NamedExecContext(ctx,
+		`INSERT INTO api_keys (id, name, prefix, hashed_key, scopes, created_date, created_by, expires_at, last_used_date)
+				VALUES (:id, :name, :prefix, :hashed_key, :scopes, :created_date, :created_by, :expires_at, :last_used_date)`,
+		dbAPIKey)
+	if err != nil {
+		return "", m.dialect.WrapError(err)
+	}
+	return dbAPIKey.ID.String(), nil
+}
+
+// DeleteAPIKeyByID revoke an API key
+func (m *sqlFlagImpl) DeleteAPIKeyByID(ctx context.Context, id string) daoerr.DaoError {
+	if daoErr := checkUUID(id); daoErr != nil {
+		return daoErr
+	}
+	res, err := m.conn.ExecContext(ctx, m.conn.Rebind(`DELETE FROM api_keys WHERE id = ?`), id)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("api key with id %s not found", id))
+	}
+	return nil
+}
+
+// UpdateAPIKeyLastUsedDate record the last time the API key has been used
+func (m *sqlFlagImpl) UpdateAPIKeyLastUsedDate(ctx context.Context, id string, date time.Time) daoerr.DaoError {
+	_, err := m.conn.ExecContext(ctx, m.conn.Rebind(`UPDATE api_keys SET last_used_date = ? WHERE id = ?`), date, id)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+// GetScheduledChanges return the scheduled changes matching the query, ordered by scheduled date
+func (m *sqlFlagImpl) GetScheduledChanges(
+	ctx context.Context, query dao.ScheduledChangeQuery) ([]model.ScheduledChange, daoerr.DaoError) {
+	conditions := []string{}
+	args := []interface{}{}
+	if query.FlagID != "" {
+		if daoErr := checkUUID(query.FlagID); daoErr != nil {
+			return nil, daoErr
+		}
+		args = append(args, query.FlagID)
+		conditions = append(conditions, "feature_flag_id = ?")
+	}
+	if query.Status != "" {
+		args = append(args, query.Status)
+		conditions = append(conditions, "status = ?")
+	}
+	if query.DueBefore != nil {
+		args = append(args, *query.DueBefore)
+		conditions = append(conditions, m.dialect.DueBeforeFilter)
+	}
+	selectQuery := `SELECT * FROM scheduled_changes`
+	if len(conditions) > 0 {
+		selectQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var changes []dbmodel2.ScheduledChange
+	err := m.conn.SelectContext(ctx, &changes,
+		m.conn.Rebind(selectQuery+` ORDER BY scheduled_date, created_date, id`), args...)
+	if err != nil {
+		return nil, m.dialect.WrapError(err)
+	}
+	res := make([]model.ScheduledChange, 0, len(changes))
+	for _, change := range changes {
+		converted, err := change.ToModelScheduledChange()
+		if err != nil {
+			return nil, daoerr.NewDaoError(daoerr.ConversionError, err)
+		}
+		res = append(res, converted)
+	}
+	return res, nil
+}
+
+// GetScheduledChangeByID return a scheduled change by its ID
+func (m *sqlFlagImpl) GetScheduledChangeByID(
+	ctx context.Context, id string) (model.ScheduledChange, daoerr.DaoError) {
+	if daoErr := checkUUID(id); daoErr != nil {
+		return model.ScheduledChange{}, daoErr
+	}
+	var change dbmodel2.ScheduledChange
+	err := m.conn.GetContext(ctx, &change, m.conn.Rebind(`SELECT * FROM scheduled_changes WHERE id = ?`), id)
+	if err != nil {
+		return model.ScheduledChange{}, m.dialect.WrapError(err)
+	}
+	converted, err := change.ToModelScheduledChange()
+	if err != nil {
+		return model.ScheduledChange{}, daoerr.NewDaoError(daoerr.ConversionError, err)
+	}
+	return converted, nil
+}
+
+// CreateScheduledChange create a new scheduled change, return the id of the scheduled change
+func (m *sqlFlagImpl) CreateScheduledChange(
+	ctx context.Context, change model.ScheduledChange) (string, daoerr.DaoError) {
+	dbChange, err := dbmodel2.FromModelScheduledChange(change)
+	if err != nil {
+		return "", daoerr.NewDaoError(daoerr.ConversionError, err)
+	}
+	_, err = m.conn.NamedExecContext(ctx,
+		`INSERT INTO scheduled_changes (id, feature_flag_id, scheduled_date, changes, status, error, created_date,
+				created_by, last_updated_date, applied_date)
+				VALUES (:id, :feature_flag_id, :scheduled_date, `+m.dialect.JSONParam("changes")+`, :status, :error,
+				:created_date, :created_by, :last_updated_date, :applied_date)`,
+		dbChange)
+	if err != nil {
+		return "", m.wrapInsertError(err)
+	}
+	return dbChange.ID.String(), nil
+}
+
+// UpdateScheduledChange update a pending scheduled change, only the pending changes can be updated
+func (m *sqlFlagImpl) UpdateScheduledChange(ctx context.Context, change model.ScheduledChange) daoerr.DaoError {
+	return m.updateScheduledChange(ctx, m.conn, change)
+}
+
+// ApplyScheduledChange updates the flag and stores the change in a single transaction.
+// The change is updated first, so if two instances apply the same change, the second one waits for the
+// transaction of the first one and fails because the change is not pending anymore.
+func (m *sqlFlagImpl) ApplyScheduledChange(
+	ctx context.Context, flag model.FeatureFlag, change model.ScheduledChange) daoerr.DaoError {
+	tx, err := m.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	if daoErr := m.updateScheduledChange(ctx, tx, change); daoErr != nil {
+		_ = tx.Rollback()
+		return daoErr
+	}
+	if daoErr := m.updateFlag(ctx, tx, flag); daoErr != nil {
+		_ = tx.Rollback()
+		return daoErr
+	}
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+// updateScheduledChange updates a pending scheduled change with the connection or the transaction.
+func (m *sqlFlagImpl) updateScheduledChange(
+	ctx context.Context, q sqlx.ExtContext, change model.ScheduledChange) daoerr.DaoError {
+	dbChange, err := dbmodel2.FromModelScheduledChange(change)
+	if err != nil {
+		return daoerr.NewDaoError(daoerr.ConversionError, err)
+	}
+	res, err := sqlx.NamedExecContext(ctx, q,
+		`UPDATE scheduled_changes SET
+				 scheduled_date=:scheduled_date,
+				 changes=`+m.dialect.JSONParam("changes")+`,
+				 status=:status,
+				 error=:error,
+				 last_updated_date=:last_updated_date,
+				 applied_date=:applied_date
+				WHERE id = :id AND status = 'pending'`,
+		dbChange)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		var status string
+		if err := sqlx.GetContext(ctx, q, &status, q.Rebind(`SELECT status FROM scheduled_changes WHERE id = ?`),
+			change.ID); err != nil {
+			return m.dialect.WrapError(err)
+		}
+		return daoerr.NewDaoError(daoerr.StaleRevision, fmt.Errorf("scheduled change %s is %s", change.ID, status))
+	}
+	return nil
+}
+
+// GetProjects return all the projects ordered by name
+func (m *sqlFlagImpl) GetProjects(ctx context.Context) ([]model.Project, daoerr.DaoError) {
+	var projects []dbmodel2.Project
+	err := m.conn.SelectContext(ctx, &projects, `SELECT * FROM projects ORDER BY name`)
+	if err != nil {
+		return nil, m.dialect.WrapError(err)
+	}
+	res := make([]model.Project, 0, len(projects))
+	for _, project := range projects {
+		res = append(res, project.ToModelProject())
+	}
+	return res, nil
+}
+
+// GetProject return a project by its name
+func (m *sqlFlagImpl) GetProject(ctx context.Context, name string) (model.Project, daoerr.DaoError) {
+	var project dbmodel2.Project
+	err := m.conn.GetContext(ctx, &project, m.conn.Rebind(`SELECT * FROM projects WHERE name = ?`), name)
+	if err != nil {
+		return model.Project{}, m.dialect.WrapError(err)
+	}
+	return project.ToModelProject(), nil
+}
+
+// CreateProject create a new project
+func (m *sqlFlagImpl) CreateProject(ctx context.Context, project model.Project) daoerr.DaoError {
+	_, err := m.conn.NamedExecContext(ctx,
+		`INSERT INTO projects (name, description, created_date) VALUES (:name, :description, :created_date)`,
+		dbmodel2.FromModelProject(project))
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+// DeleteProject delete a project, it returns an InUse error if the project still has environments
+func (m *sqlFlagImpl) DeleteProject(ctx context.Context, name string) daoerr.DaoError {
+	res, err := m.conn.ExecContext(ctx, m.conn.Rebind(`DELETE FROM projects WHERE name = ?`), name)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("project %s not found", name))
+	}
+	return nil
+}
+
+// GetEnvironments return the environments of a project ordered by name
+func (m *sqlFlagImpl) GetEnvironments(ctx context.Context, project string) ([]model.Environment, daoerr.DaoError) {
+	var environments []dbmodel2.Environment
+	err := m.conn.SelectContext(ctx, &environments,
+		m.conn.Rebind(`SELECT * FROM environments WHERE project = ? ORDER BY name`), project)
+	if err != nil {
+		return nil, m.dialect.WrapError(err)
+	}
+	res := make([]model.Environment, 0, len(environments))
+	for _, environment := range environments {
+		res = append(res, environment.ToModelEnvironment())
+	}
+	return res, nil
+}
+
+// GetEnvironment return an environment of a project by its name
+func (m *sqlFlagImpl) GetEnvironment(
+	ctx context.Context, project string, name string) (model.Environment, daoerr.DaoError) {
+	var environment dbmodel2.Environment
+	err := m.conn.GetContext(ctx, &environment,
+		m.conn.Rebind(`SELECT * FROM environments WHERE project = ? AND name = ?`), project, name)
+	if err != nil {
+		return model.Environment{}, m.dialect.WrapError(err)
+	}
+	return environment.ToModelEnvironment(), nil
+}
+
+// CreateEnvironment create a new environment in an existing project
+func (m *sqlFlagImpl) CreateEnvironment(ctx context.Context, environment model.Environment) daoerr.DaoError {
+	_, err := m.conn.NamedExecContext(ctx,
+		`INSERT INTO environments (project, name, description, created_date)
+				VALUES (:project, :name, :description, :created_date)`,
+		dbmodel2.FromModelEnvironment(environment))
+	if err != nil {
+		return m.wrapInsertError(err)
+	}
+	return nil
+}
+
+// DeleteEnvironment delete an environment, it returns an InUse error if the environment still has flags
+func (m *sqlFlagImpl) DeleteEnvironment(ctx context.Context, project string, name string) daoerr.DaoError {
+	res, err := m.conn.ExecContext(ctx,
+		m.conn.Rebind(`DELETE FROM environments WHERE project = ? AND name = ?`), project, name)
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return daoerr.NewDaoError(daoerr.NotFound, fmt.Errorf("environment %s not found in project %s", name, project))
+	}
+	return nil
+}
+
+// Close closes the connections to the database
+func (m *sqlFlagImpl) Close() error {
+	if m.conn == nil {
+		return nil
+	}
+	return m.conn.Close()
+}
+
+func (m *sqlFlagImpl) Ping() daoerr.DaoError {
+	if m.conn == nil {
+		return daoerr.NewDaoError(daoerr.DatabaseNotInitialized, errors.New("database connection is nil"))
+	}
+	err := m.conn.Ping()
+	if err != nil {
+		return m.dialect.WrapError(err)
+	}
+	return nil
+}
+
+// wrapInsertError wraps the error of an INSERT, a foreign key violation can only mean that the row references
+// a row that does not exist, even if the database does not tell which side of the foreign key failed.
+func (m *sqlFlagImpl) wrapInsertError(err error) daoerr.DaoError {
+	daoErr := m.dialect.WrapError(err)
+	if daoErr.Code() == daoerr.InUse {
+		return daoerr.NewDaoError(daoerr.NotFound, err)
+	}
+	return daoErr
+}
+
+// checkUUID returns an InvalidUUID error if the id is not a UUID, postgres checks it when casting the
+// parameters but the other databases store the ids as text and would only return a NotFound error.
+func checkUUID(id string) daoerr.DaoError {
+	if _, err := uuid.Parse(id); err != nil {
+		return daoerr.NewDaoError(daoerr.InvalidUUID, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package sqlimpl
+
+import (
+	"errors"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDialect = Dialect{
+	WrapError: func(err error) daoerr.DaoError {
+		return daoerr.NewDaoError(daoerr.InUse, err)
+	},
+	JSONParam: func(name string) string {
+		return ":" + name
+	},
+	NamePrefixFilter: func(prefix string) (string, interface{}) {
+		return "name LIKE ?", prefix + "%"
+	},
+	MetadataFilter: func(key string, value *string) (string, []interface{}) {
+		if value != nil {
+			return "metadata ->> ? = ?", []interface{}{key, *value}
+		}
+		return "metadata -> ? IS NOT NULL", []interface{}{key}
+	},
+	DueBeforeFilter: "scheduled_date <= ?",
+}
+
+func TestPingNoDb(t *testing.T) {
+	impl := NewStorage(nil, testDialect)
+	assert.Error(t, impl.Ping())
+}
+
+func TestBuildFlagQueryFilters(t *testing.T) {
+	impl := &sqlFlagImpl{dialect: testDialect}
+
+	where, args := impl.buildFlagQueryFilters(dao.FlagQuery{})
+	assert.Equal(t, "", where)
+	assert.Empty(t, args)
+
+	where, args = impl.buildFlagQueryFilters(dao.FlagQuery{
+		Project:       "checkout",
+		NamePrefix:    "beta",
+		Disabled:      testutils.Bool(false),
+		MetadataKey:   "team",
+		MetadataValue: testutils.String("payments"),
+	})
+	assert.Equal(t,
+		" WHERE project = ? AND name LIKE ? AND COALESCE(disable, false) = ? AND metadata ->> ? = ?", where)
+	assert.Equal(t, []interface{}{"checkout", "beta%", false, "team", "payments"}, args)
+}
+
+func TestBuildFlagQueryOrderBy(t *testing.T) {
+	assert.Equal(t, " ORDER BY last_updated_date DESC, id DESC", buildFlagQueryOrderBy(dao.FlagQuery{}))
+	assert.Equal(t, " ORDER BY name ASC, id ASC", buildFlagQueryOrderBy(dao.FlagQuery{SortBy: dao.FlagSortByName}))
+}
+
+func TestWrapInsertError(t *testing.T) {
+	impl := &sqlFlagImpl{dialect: testDialect}
+	err := impl.wrapInsertError(errors.New("foreign key constraint failed"))
+	assert.Equal(t, daoerr.NotFound, err.Code(), "an insert can only fail because the referenced row is missing")
+}
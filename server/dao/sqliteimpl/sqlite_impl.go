@@ -0,0 +1,78 @@
+package sqliteimpl
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoerr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/dao/sqlimpl"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite" // pure Go driver, the binary can be built without cgo
+)
+
+// dialect contains the SQLite specific parts of the queries, the JSON values are stored as text.
+var dialect = sqlimpl.Dialect{
+	WrapError: daoerr.WrapSQLiteError,
+	JSONParam: func(name string) string {
+		// the JSON values are cast to keep them readable as text in the database
+		return "CAST(:" + name + " AS TEXT)"
+	},
+	NamePrefixFilter: func(prefix string) (string, interface{}) {
+		// LIKE is case-insensitive in SQLite, instr keeps the prefix case-sensitive like in postgres
+		return "instr(name, ?) = 1", prefix
+	},
+	MetadataFilter: func(key string, value *string) (string, []interface{}) {
+		if value != nil {
+			// the values are compared as text like the ->> operator of postgres
+			return `EXISTS (SELECT 1 FROM json_each(feature_flags.metadata) AS m WHERE m.key = ?
+				AND (CASE m.type WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(m.value AS TEXT) END) = ?)`,
+				[]interface{}{key, *value}
+		}
+		return "EXISTS (SELECT 1 FROM json_each(feature_flags.metadata) AS m WHERE m.key = ?)", []interface{}{key}
+	},
+	// the dates are stored as text, julianday compares them even if they are not in the same time zone
+	DueBeforeFilter: "julianday(scheduled_date) <= julianday(?)",
+}
+
+// the SQLite storage is added to the registry of dao with the scheme "sqlite" ("sqlite://flags.db") when the
+// package is imported.
+var _ = dao.MustRegister("sqlite", func(connectionString string) (dao.FlagStorage, error) {
+	return NewSQLiteDao(dao.TrimScheme(connectionString))
+})
+
+// NewSQLiteDao opens the SQLite database file, the tables are created by the migrations of database_migration/sqlite.
+// The connection string is the path of the file, it can contain the options of the modernc.org/sqlite driver
+// (ex: "flags.db?_pragma=busy_timeout(5000)"), use ":memory:" to keep the flags in memory.
+func NewSQLiteDao(connectionString string) (dao.FlagStorage, error) {
+	if connectionString == "" {
+		return nil, fmt.Errorf("connection string is empty")
+	}
+
+	conn, err := sqlx.Connect("sqlite", dataSourceName(connectionString))
+	if err != nil {
+		return nil, fmt.Errorf("impossible to connect to the database: %w", err)
+	}
+	// SQLite allows only one writer at a time, a single connection avoids the "database is locked" errors
+	// between our own transactions and keeps a ":memory:" database alive.
+	conn.SetMaxOpenConns(1)
+	// SQLite creates an empty file if it does not exist, we fail at startup instead of at the first request
+	var tables int
+	err = conn.Get(&tables, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'feature_flags'`)
+	if err != nil || tables == 0 {
+		_ = conn.Close()
+		return nil, fmt.Errorf("schema not migrated, run the migrations of database_migration/sqlite on %s",
+			connectionString)
+	}
+	return sqlimpl.NewStorage(conn, dialect), nil
+}
+
+// dataSourceName enables the foreign keys, they are disabled by default in SQLite, and stores the dates in a
+// format understood by the date functions of SQLite.
+func dataSourceName(connectionString string) string {
+	separator := "?"
+	if strings.Contains(connectionString, "?") {
+		separator = "&"
+	}
+	return connectionString + separator + "_pragma=foreign_keys(1)&_time_format=sqlite"
+}
@@ -0,0 +1,376 @@
+package sqliteimpl_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr2 "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/dao/sqliteimpl"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFlag(id string, name string) model.FeatureFlag {
+	return model.FeatureFlag{
+		ID:              id,
+		Name:            name,
+		VariationType:   model.FlagTypeString,
+		Variations:      &map[string]interface{}{"A": "a", "B": "b"},
+		Metadata:        &map[string]interface{}{"team": "checkout", "beta": true},
+		DefaultRule:     &model.Rule{VariationResult: testutils.String("A")},
+		CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastModifiedBy:  "foo",
+	}
+}
+
+// newDatabase creates a database file with the migrations of database_migration/sqlite applied.
+func newDatabase(t *testing.T) string {
+	file := filepath.Join(t.TempDir(), "flags.db")
+	m, err := migrate.New("file://../../../database_migration/sqlite", "sqlite://"+file)
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	srcErr, dbErr := m.Close()
+	require.NoError(t, srcErr)
+	require.NoError(t, dbErr)
+	return file
+}
+
+func newStorage(t *testing.T) dao.FlagStorage {
+	storage, err := sqliteimpl.NewSQLiteDao(newDatabase(t))
+	require.NoError(t, err)
+	return storage
+}
+
+func TestNewSQLiteDao(t *testing.T) {
+	t.Run("should return an error if the connection string is empty", func(t *testing.T) {
+		_, err := sqliteimpl.NewSQLiteDao("")
+		assert.Error(t, err)
+	})
+
+	t.Run("should keep the flags when the file is opened again", func(t *testing.T) {
+		ctx := context.Background()
+		file := newDatabase(t)
+		storage, err := sqliteimpl.NewSQLiteDao(file)
+		require.NoError(t, err)
+		require.Nil(t, storage.Ping())
+		id, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+		require.Nil(t, daoErr)
+
+		reopened, err := sqliteimpl.NewSQLiteDao(file)
+		require.NoError(t, err)
+		flag, daoErr := reopened.GetFlagByID(ctx, id)
+		require.Nil(t, daoErr)
+		assert.Equal(t, "flag1", flag.Name)
+	})
+
+	t.Run("should return an error if the migrations have not been run", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "flags.db")
+		_, err := sqliteimpl.NewSQLiteDao(file)
+		assert.EqualError(t, err, "schema not migrated, run the migrations of database_migration/sqlite on "+file)
+	})
+}
+
+func TestSQLiteDao_Flags(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorage(t)
+
+	flag := newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1")
+	flag.Rules = &[]model.Rule{
+		{Name: "rule1", Query: `beta eq true`, Percentages: &map[string]float64{"A": 20, "B": 80}},
+		{Name: "rule2", Query: `country eq "fr"`, ProgressiveRollout: &model.ProgressiveRollout{
+			Initial: &model.ProgressiveRolloutStep{
+				Variation: testutils.String("A"), Date: testutils.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))},
+			End: &model.ProgressiveRolloutStep{
+				Variation: testutils.String("B"), Date: testutils.Time(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))},
+		}},
+	}
+	id, daoErr := storage.CreateFlag(ctx, flag)
+	require.Nil(t, daoErr)
+
+	created, daoErr := storage.GetFlagByID(ctx, id)
+	require.Nil(t, daoErr)
+	assert.Equal(t, int64(1), created.Revision)
+	assert.Equal(t, flag.Variations, created.Variations)
+	assert.Equal(t, flag.Metadata, created.Metadata)
+	require.Len(t, created.GetRules(), 2)
+	assert.Equal(t, map[string]float64{"A": 20, "B": 80}, *created.GetRules()[0].Percentages)
+	assert.Equal(t, "B", *created.GetRules()[1].ProgressiveRollout.End.Variation)
+
+	_, daoErr = storage.CreateFlag(ctx, newFlag("2c5a6c4e-1c8b-4a6d-9f3e-5b7d8e9f0a1b", "flag1"))
+	assert.NotNil(t, daoErr, "the name of a flag should be unique in its environment")
+
+	created.Disable = testutils.Bool(true)
+	created.Rules = &[]model.Rule{created.GetRules()[1], {Name: "rule3", Query: `beta eq false`,
+		VariationResult: testutils.String("B")}}
+	require.Nil(t, storage.UpdateFlag(ctx, created))
+	daoErr = storage.UpdateFlag(ctx, created)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+
+	updated, daoErr := storage.GetFlagByName(ctx, model.DefaultProject, model.DefaultEnvironment, "flag1")
+	require.Nil(t, daoErr)
+	assert.Equal(t, int64(2), updated.Revision)
+	require.Len(t, updated.GetRules(), 2)
+	assert.Equal(t, "rule2", updated.GetRules()[0].Name)
+	assert.Equal(t, "rule3", updated.GetRules()[1].Name)
+
+	versions, total, daoErr := storage.GetFlagVersions(ctx, id, 10, 0)
+	require.Nil(t, daoErr)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, model.FlagVersionActionUpdated, versions[0].Action)
+	version, daoErr := storage.GetFlagVersion(ctx, id, versions[1].ID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, model.FlagVersionActionCreated, version.Action)
+
+	require.Nil(t, storage.DeleteFlagByID(ctx, id))
+	_, daoErr = storage.GetFlagByID(ctx, id)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+	summary, daoErr := storage.GetFlagsSummary(ctx, dao.FlagQuery{})
+	require.Nil(t, daoErr)
+	assert.Equal(t, 0, summary.Total)
+	assert.Equal(t, 3, summary.VersionCount)
+
+	_, daoErr = storage.GetFlagByID(ctx, "invalid-uuid")
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.InvalidUUID, daoErr.Code())
+}
+
+func TestSQLiteDao_GetFlagsWithQuery(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorage(t)
+	flag1 := newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "checkout-button")
+	flag2 := newFlag("2c5a6c4e-1c8b-4a6d-9f3e-5b7d8e9f0a1b", "Checkout-banner")
+	flag2.Metadata = &map[string]interface{}{"team": "growth"}
+	flag2.Disable = testutils.Bool(true)
+	flag2.LastUpdatedDate = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	flag3 := newFlag("6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f", "search")
+	flag3.VariationType = model.FlagTypeJSON
+	flag3.Variations = &map[string]interface{}{"A": map[string]interface{}{"a": 1}, "B": []interface{}{"b"}}
+	require.Nil(t, storage.ImportFlags(ctx, []model.FeatureFlag{flag1, flag2, flag3}, nil))
+
+	tests := []struct {
+		name  string
+		query dao.FlagQuery
+		want  []string
+		total int
+	}{
+		{
+			name:  "should order by last updated date by default",
+			query: dao.FlagQuery{Limit: 1},
+			want:  []string{"Checkout-banner"},
+			total: 3,
+		},
+		{
+			name:  "should use an offset without limit",
+			query: dao.FlagQuery{SortBy: dao.FlagSortByName, Offset: 1},
+			want:  []string{"checkout-button", "search"},
+			total: 3,
+		},
+		{
+			name:  "should filter by name prefix with case sensitivity",
+			query: dao.FlagQuery{NamePrefix: "check"},
+			want:  []string{"checkout-button"},
+			total: 1,
+		},
+		{
+			name:  "should filter by type and disabled",
+			query: dao.FlagQuery{Type: model.FlagTypeString, Disabled: testutils.Bool(false), SortBy: dao.FlagSortByName},
+			want:  []string{"checkout-button"},
+			total: 1,
+		},
+		{
+			name:  "should filter by metadata value",
+			query: dao.FlagQuery{MetadataKey: "team", MetadataValue: testutils.String("growth")},
+			want:  []string{"Checkout-banner"},
+			total: 1,
+		},
+		{
+			name:  "should compare the boolean metadata as text",
+			query: dao.FlagQuery{MetadataKey: "beta", MetadataValue: testutils.String("true"), SortBy: dao.FlagSortByName},
+			want:  []string{"checkout-button", "search"},
+			total: 2,
+		},
+		{
+			name:  "should filter by metadata key",
+			query: dao.FlagQuery{MetadataKey: "beta", Project: model.DefaultProject, Environment: "unknown"},
+			want:  []string{},
+			total: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, total, daoErr := storage.GetFlags(ctx, tt.query)
+			require.Nil(t, daoErr)
+			assert.Equal(t, tt.total, total)
+			names := make([]string, 0, len(flags))
+			for _, flag := range flags {
+				names = append(names, flag.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+
+	summary, daoErr := storage.GetFlagsSummary(ctx, dao.FlagQuery{})
+	require.Nil(t, daoErr)
+	assert.Equal(t, 3, summary.Total)
+	assert.True(t, summary.LastUpdatedDate.Equal(flag2.LastUpdatedDate))
+}
+
+func TestSQLiteDao_ScheduledChanges(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorage(t)
+	changes, ok := storage.(dao.ScheduledChangeStorage)
+	require.True(t, ok)
+
+	flagID, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	change := model.ScheduledChange{
+		ID:              "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f",
+		FlagID:          flagID,
+		ScheduledDate:   time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Changes:         model.FlagChanges{Disable: testutils.Bool(true)},
+		Status:          model.ScheduledChangeStatusPending,
+		CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	_, daoErr = changes.CreateScheduledChange(ctx, change)
+	require.Nil(t, daoErr)
+
+	dueBefore := time.Date(2020, 1, 2, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	list, daoErr := changes.GetScheduledChanges(ctx, dao.ScheduledChangeQuery{DueBefore: &dueBefore})
+	require.Nil(t, daoErr)
+	assert.Empty(t, list, "the dates should be compared in the same time zone")
+	dueBefore = dueBefore.Add(2 * time.Hour)
+	list, daoErr = changes.GetScheduledChanges(ctx, dao.ScheduledChangeQuery{DueBefore: &dueBefore})
+	require.Nil(t, daoErr)
+	require.Len(t, list, 1)
+	assert.True(t, *list[0].Changes.Disable)
+
+	change.Status = model.ScheduledChangeStatusApplied
+	require.Nil(t, changes.UpdateScheduledChange(ctx, change))
+	daoErr = changes.UpdateScheduledChange(ctx, change)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+
+	// the scheduled changes are deleted with their flag
+	require.Nil(t, storage.DeleteFlagByID(ctx, flagID))
+	_, daoErr = changes.GetScheduledChangeByID(ctx, change.ID)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+}
+
+func TestSQLiteDao_ApplyScheduledChange(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorage(t)
+	changes, ok := storage.(dao.ScheduledChangeStorage)
+	require.True(t, ok)
+
+	flagID, daoErr := storage.CreateFlag(ctx, newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1"))
+	require.Nil(t, daoErr)
+	change := model.ScheduledChange{
+		ID:              "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f",
+		FlagID:          flagID,
+		ScheduledDate:   time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Changes:         model.FlagChanges{Disable: testutils.Bool(true)},
+		Status:          model.ScheduledChangeStatusPending,
+		CreatedDate:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUpdatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	_, daoErr = changes.CreateScheduledChange(ctx, change)
+	require.Nil(t, daoErr)
+	flag, daoErr := storage.GetFlagByID(ctx, flagID)
+	require.Nil(t, daoErr)
+	updatedFlag := change.Changes.ApplyTo(flag)
+	applied := change
+	applied.Status = model.ScheduledChangeStatusApplied
+
+	// nothing is modified if the flag has been modified since its revision
+	staleFlag := updatedFlag
+	staleFlag.Revision++
+	daoErr = changes.ApplyScheduledChange(ctx, staleFlag, applied)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+	got, daoErr := changes.GetScheduledChangeByID(ctx, change.ID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, model.ScheduledChangeStatusPending, got.Status)
+
+	require.Nil(t, changes.ApplyScheduledChange(ctx, updatedFlag, applied))
+	got, daoErr = changes.GetScheduledChangeByID(ctx, change.ID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, model.ScheduledChangeStatusApplied, got.Status)
+	flag, daoErr = storage.GetFlagByID(ctx, flagID)
+	require.Nil(t, daoErr)
+	assert.True(t, flag.IsDisable())
+
+	// a change already applied is not applied again
+	daoErr = changes.ApplyScheduledChange(ctx, change.Changes.ApplyTo(flag), applied)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.StaleRevision, daoErr.Code())
+	reloaded, daoErr := storage.GetFlagByID(ctx, flagID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, flag.Revision, reloaded.Revision)
+}
+
+func TestSQLiteDao_APIKeys(t *testing.T) {
+	ctx := context.Background()
+	apiKeys, ok := newStorage(t).(dao.APIKeyStorage)
+	require.True(t, ok)
+
+	id, daoErr := apiKeys.CreateAPIKey(ctx, model.APIKey{
+		ID:          "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f",
+		Name:        "ci",
+		HashedKey:   "hash",
+		Scopes:      []model.APIKeyScope{model.APIKeyScopeRead},
+		CreatedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.Nil(t, daoErr)
+	apiKey, daoErr := apiKeys.GetAPIKeyByHashedKey(ctx, "hash")
+	require.Nil(t, daoErr)
+	assert.Equal(t, "ci", apiKey.Name)
+
+	require.Nil(t, apiKeys.DeleteAPIKeyByID(ctx, id))
+	daoErr = apiKeys.DeleteAPIKeyByID(ctx, id)
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+}
+
+func TestSQLiteDao_Projects(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorage(t)
+
+	daoErr := storage.CreateEnvironment(ctx, model.Environment{Project: "checkout", Name: "production"})
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.NotFound, daoErr.Code())
+
+	require.Nil(t, storage.CreateProject(ctx, model.Project{Name: "checkout"}))
+	require.Nil(t, storage.CreateEnvironment(ctx, model.Environment{Project: "checkout", Name: "production"}))
+	flag := newFlag("926214f3-80c1-46e6-a913-b2d40b92a932", "flag1")
+	flag.Project, flag.Environment = "checkout", "production"
+	_, daoErr = storage.CreateFlag(ctx, flag)
+	require.Nil(t, daoErr)
+
+	daoErr = storage.DeleteEnvironment(ctx, "checkout", "production")
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.InUse, daoErr.Code())
+	daoErr = storage.DeleteProject(ctx, "checkout")
+	require.NotNil(t, daoErr)
+	assert.Equal(t, daoErr2.InUse, daoErr.Code())
+
+	require.Nil(t, storage.DeleteFlagByID(ctx, flag.ID))
+	require.Nil(t, storage.DeleteEnvironment(ctx, "checkout", "production"))
+	require.Nil(t, storage.DeleteProject(ctx, "checkout"))
+	projects, daoErr := storage.GetProjects(ctx)
+	require.Nil(t, daoErr)
+	require.Len(t, projects, 1)
+	assert.Equal(t, model.DefaultProject, projects[0].Name)
+}
@@ -49,23 +49,304 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/apikeys": {
+            "get": {
+                "description": "GET all the API keys, the most recent first. The keys themselves are never returned.",
+                "tags": [
+                    "API Key management API"
+                ],
+                "summary": "Return all the API keys",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.apiKeyListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST - Create a new API key, the key is returned only in this response so store it safely.\nUse it in the header \"Authorization: Bearer \u003ckey\u003e\" to call the API.",
+                "tags": [
+                    "API Key management API"
+                ],
+                "summary": "Create a new API key",
+                "parameters": [
+                    {
+                        "description": "Payload which represents the API key to create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.apiKeyCreationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.apiKeyCreationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/apikeys/{id}": {
+            "delete": {
+                "description": "DELETE - Revoke the API key with the given ID, it cannot be used anymore.",
+                "tags": [
+                    "API Key management API"
+                ],
+                "summary": "Revoke the API key with the given ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the API key",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/export": {
+            "get": {
+                "description": "GET all the flags of an environment keyed by name, in the format read by the GO Feature Flag\nrelay proxy and retrievers.\nThe format is selected with the format query parameter or with the Accept header (JSON by default).",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/toml"
+                ],
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Export the flags in the GO Feature Flag configuration format",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project of the flags to export (default: default)",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment of the flags to export (default: default)",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the file (json, yaml or toml), it takes precedence over the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/flagconfig.Flag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - the environment does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable - none of the media types of the Accept header is supported",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/flags": {
             "get": {
-                "description": "GET request to get all the flags available.",
+                "description": "GET request to get a paginated list of the flags available.\nThe flags can be filtered by name prefix, type, status and metadata, and sorted by\nname, createdDate or lastUpdatedDate (prefix the field with \"-\" for a descending order).",
                 "tags": [
                     "Feature Flag management API"
                 ],
                 "summary": "Return all the flags available",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of flags to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of flags to skip before starting to collect the result set",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field used to sort the flags (default -lastUpdatedDate)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags of this project",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags of this environment",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags with a name starting with this value",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return only the disabled (true) or enabled (false) flags",
+                        "name": "disabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags having this key in their metadata",
+                        "name": "metadataKey",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags where metadataKey has this value",
+                        "name": "metadataValue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response, if nothing changed we return a 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.FeatureFlag"
+                            "$ref": "#/definitions/handler.flagListResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the list of flags"
                             }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified - nothing changed since the ETag provided in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -75,7 +356,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "POST will insert in the database the new feature flag with all his properties,\nand it will add all the associated rules too.",
+                "description": "POST will insert in the database the new feature flag with all his properties,\nand it will add all the associated rules too.\nThe flag is created in the project and environment of the payload, or in the default ones if not set.",
                 "tags": [
                     "Feature Flag management API"
                 ],
@@ -99,7 +380,13 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Bad Request - invalid flag or unknown project and environment",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
                         "schema": {
                             "$ref": "#/definitions/api.CustomErr"
                         }
@@ -133,6 +420,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response, if the flag didn't change we return a 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -140,6 +433,21 @@ const docTemplate = `{
                         "description": "Success",
                         "schema": {
                             "$ref": "#/definitions/model.FeatureFlag"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the flag"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified - the flag didn't change since the ETag provided in If-None-Match"
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
                         }
                     },
                     "404": {
@@ -157,7 +465,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "PUT - Updates the flag with the given ID with what is in the payload. It will replace completely the feature flag.",
+                "description": "PUT - Updates the flag with the given ID with what is in the payload. It will replace completely the feature flag.\nThe project and environment of a flag cannot be changed.",
                 "tags": [
                     "Feature Flag management API"
                 ],
@@ -170,6 +478,12 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag of the flag you have retrieved, we return a 412 if the flag has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
                     {
                         "description": "Payload which represents the flag to update",
                         "name": "data",
@@ -193,12 +507,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.CustomErr"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/api.CustomErr"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed - the flag has been modified since you retrieved it",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -235,6 +561,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.CustomErr"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -250,13 +582,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/flags/{id}/status": {
-            "patch": {
-                "description": "PATCH - Update the status of the flag with the given ID",
+        "/v1/flags/{id}/evaluate": {
+            "post": {
+                "description": "POST an evaluation context (targeting key and attributes) to know which rule matches, which variation\nand value the user gets and why. The stored flag is evaluated like in the relay proxy, with the\npercentages of the progressive rollouts computed at the current date. Nothing is modified.",
                 "tags": [
                     "Feature Flag management API"
                 ],
-                "summary": "Update the status of the flag with the given ID",
+                "summary": "Preview the evaluation of a flag for a user",
                 "parameters": [
                     {
                         "type": "string",
@@ -266,12 +598,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "The patch query to update the flag status",
+                        "description": "Evaluation context of the user",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.FeatureFlagStatusUpdate"
+                            "$ref": "#/definitions/evaluation.Context"
                         }
                     }
                 ],
@@ -279,7 +611,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/model.FeatureFlag"
+                            "$ref": "#/definitions/evaluation.Result"
                         }
                     },
                     "400": {
@@ -288,6 +620,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.CustomErr"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -302,33 +640,2598 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.CustomErr": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "errorDetails": {
-                    "type": "string"
-                }
-            }
         },
-        "handler.successResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 200
-                },
-                "message": {
+        "/v1/flags/{id}/schedules": {
+            "get": {
+                "description": "GET the scheduled changes of the flag with the given ID, ordered by scheduled date.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Return the scheduled changes of a flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return the changes with this status (pending, applied, failed or cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.scheduledChangeListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST - Schedule a change of the targeting rules, the default rule or the status of the flag.\nThe change is applied by the server once the scheduled date is reached, in the name of the\nuser who scheduled it. The flag should still be valid with the change applied.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Schedule a change of a flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload which represents the change to schedule",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.scheduledChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/flags/{id}/status": {
+            "patch": {
+                "description": "PATCH - Update the status of the flag with the given ID",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Update the status of the flag with the given ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the flag you have retrieved, we return a 412 if the flag has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "The patch query to update the flag status",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlagStatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed - the flag has been modified since you retrieved it",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/flags/{id}/versions": {
+            "get": {
+                "description": "GET all the recorded versions of a flag, the most recent first.\nA new version is recorded every time the flag is created, updated, or deleted.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Get a paginated list of available versions for a feature flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of versions to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of versions to skip before starting to collect the result set",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.flagVersionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/flags/{id}/versions/{versionId}/restore": {
+            "post": {
+                "description": "POST - Rebuild the flag from the given version and save it as the current state of the flag.\nThe restoration is a new write on the flag, so it creates a new version in the history.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Restore a feature flag to a previous version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the version to restore",
+                        "name": "versionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the flag you have retrieved, we return a 412 if the flag has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - when another flag is already using the name of the version",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed - the flag has been modified since you retrieved it",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/import": {
+            "post": {
+                "description": "POST a configuration file read by the GO Feature Flag relay proxy (JSON, YAML or TOML).\nThe flags of the file are created, or updated if a flag with the same name already exists in the\nenvironment. All the flags are stored in a single transaction, if one is invalid nothing is stored.\nThe format is selected with the format query parameter or with the Content-Type header.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/toml"
+                ],
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Import a GO Feature Flag configuration file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project of the flags to import (default: default)",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment of the flags to import (default: default)",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the file (json, yaml or toml), it takes precedence over the Content-Type header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "If true, nothing is stored and the response describes what would change",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "description": "GO Feature Flag configuration file",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/flagconfig.Flag"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/flagconfig.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - the file or one of its flags is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - the environment does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed - one of the flags has been modified during the import",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - the format of the file is not supported",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects": {
+            "get": {
+                "description": "GET all the projects ordered by name.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Return all the projects",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.projectListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST - Create a new project, add environments to the project to be able to create flags in it.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Create a new project",
+                "parameters": [
+                    {
+                        "description": "Payload which represents the project to create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.scopeCreationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - a project with the same name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}": {
+            "get": {
+                "description": "GET the project with the given name.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Return a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.Project"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DELETE - Delete the project with the given name, all its environments should be deleted first.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Delete a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - the project still has environments",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments": {
+            "get": {
+                "description": "GET all the environments of the project ordered by name.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Return the environments of a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.environmentListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST - Create a new environment in the project.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Create a new environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload which represents the environment to create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.scopeCreationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Environment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - the project does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - an environment with the same name already exists in the project",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}": {
+            "get": {
+                "description": "GET the environment of the project with the given name.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Return an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.Environment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DELETE - Delete the environment of the project, all its flags should be deleted first.",
+                "tags": [
+                    "Project management API"
+                ],
+                "summary": "Delete an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - the environment still has flags",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}/flags": {
+            "get": {
+                "description": "GET request to get a paginated list of the flags of an environment.\nIt accepts the same filters and sort options as /v1/flags.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Return the flags of an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of flags to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of flags to skip before starting to collect the result set",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field used to sort the flags (default -lastUpdatedDate)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags with a name starting with this value",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return only the disabled (true) or enabled (false) flags",
+                        "name": "disabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags having this key in their metadata",
+                        "name": "metadataKey",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return only the flags where metadataKey has this value",
+                        "name": "metadataValue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response, if nothing changed we return a 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.flagListResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the list of flags"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified - nothing changed since the ETag provided in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - the environment does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST will insert the new feature flag in the environment of the path,\nthe project and environment of the payload are ignored.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Create a new feature flag in an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload which represents the flag to insert",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - the environment does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - when a flag with the same name already exists in the environment",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}/flags/{id}": {
+            "get": {
+                "description": "GET all the information about a flag, we return a 404 if the flag is not in this environment.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Return all the information about a flag of an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response, if the flag didn't change we return a 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the flag"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified - the flag didn't change since the ETag provided in If-None-Match"
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "PUT - Updates the flag with the given ID with what is in the payload. It will replace completely the feature flag.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Updates the flag with the given ID in an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the flag you have retrieved, we return a 412 if the flag has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Payload which represents the flag to update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed - the flag has been modified since you retrieved it",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DELETE - Delete the flag with the given ID, we return a 404 if the flag is not in this environment.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Delete the flag with the given ID in an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}/flags/{id}/evaluate": {
+            "post": {
+                "description": "POST an evaluation context (targeting key and attributes) to know which rule matches, which variation\nand value the user gets and why. Nothing is modified.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Preview the evaluation of a flag of an environment for a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Evaluation context of the user",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/evaluation.Context"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/evaluation.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}/flags/{id}/schedules": {
+            "get": {
+                "description": "GET the scheduled changes of the flag with the given ID, ordered by scheduled date.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Return the scheduled changes of a flag of an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return the changes with this status (pending, applied, failed or cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.scheduledChangeListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST - Schedule a change of the targeting rules, the default rule or the status of the flag.\nThe change is applied by the server once the scheduled date is reached.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Schedule a change of a flag of an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload which represents the change to schedule",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.scheduledChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}/flags/{id}/status": {
+            "patch": {
+                "description": "PATCH - Update the status of the flag with the given ID",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Update the status of the flag with the given ID in an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the flag you have retrieved, we return a 412 if the flag has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "The patch query to update the flag status",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlagStatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed - the flag has been modified since you retrieved it",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}/flags/{id}/versions": {
+            "get": {
+                "description": "GET all the recorded versions of a flag, the most recent first.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Get a paginated list of available versions for a feature flag of an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of versions to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of versions to skip before starting to collect the result set",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.flagVersionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project}/environments/{environment}/flags/{id}/versions/{versionId}/restore": {
+            "post": {
+                "description": "POST - Rebuild the flag from the given version and save it as the current state of the flag.",
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Restore a feature flag of an environment to a previous version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the environment",
+                        "name": "environment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the feature flag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the version to restore",
+                        "name": "versionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the flag you have retrieved, we return a 412 if the flag has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - when another flag is already using the name of the version",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed - the flag has been modified since you retrieved it",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/schedules": {
+            "get": {
+                "description": "GET all the scheduled changes of all the flags, ordered by scheduled date.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Return all the scheduled changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only return the changes with this status (pending, applied, failed or cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/handler.scheduledChangeListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/schedules/{scheduleId}": {
+            "get": {
+                "description": "GET the scheduled change with the given ID, with its status and the error if it has failed.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Return the scheduled change with the given ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the scheduled change",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "PUT - Replace the date and the changes of a pending scheduled change.\nThe changes already applied, failed or cancelled cannot be edited anymore.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Edit a pending scheduled change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the scheduled change",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload which represents the new change",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.scheduledChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - the change is not pending anymore",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DELETE - Cancel a pending scheduled change, it will not be applied.\nThe change is kept with the status cancelled.",
+                "tags": [
+                    "Scheduled change management API"
+                ],
+                "summary": "Cancel a pending scheduled change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the scheduled change",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - the change is not pending anymore",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/simulate": {
+            "post": {
+                "description": "POST a list of evaluation contexts to know how many users get each variation of the flags of an\nenvironment, rule by rule. Each rule also returns the split it is configured to serve, with the\npercentage of its progressive rollout at the date of the simulation (now by default).\nThe contexts can be a JSON array, JSON lines or a CSV file with a header (targetingKey column and\none column per attribute). The file can be uploaded in the field \"file\" of a multipart form.\nThe format is selected with the format query parameter, the extension of the uploaded file or the\nContent-Type header. Nothing is modified.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Feature Flag management API"
+                ],
+                "summary": "Simulate the evaluation of the flags for a sample of users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project of the flags to simulate (default: default)",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment of the flags to simulate (default: default)",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Names of the flags to simulate (default: all the flags of the environment)",
+                        "name": "flag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the simulation in RFC3339 format (default: now)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the contexts (json, jsonl or csv), it takes precedence over the Content-Type header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Evaluation contexts of the users",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/evaluation.Context"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/evaluation.Simulation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - your role does not allow this operation",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - the environment or one of the flags does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - the format of the contexts is not supported",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustomErr"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.CustomErr": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorDetails": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors contains all the invalid fields when a flag is rejected by the validation",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldError"
+                    }
+                }
+            }
+        },
+        "evaluation.Context": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes are the other properties of the user used by the queries of the rules.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "targetingKey": {
+                    "description": "TargetingKey identifies the user, it is used to compute the percentage splits.\nIn the queries it is available as key.",
+                    "type": "string",
+                    "example": "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"
+                }
+            }
+        },
+        "evaluation.FlagSimulation": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "Errors is the number of contexts for each error code, when the reason is ERROR",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "flag": {
+                    "type": "string",
+                    "example": "my-feature-flag"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "926214f3-80c1-46e6-a913-b2d40b92a932"
+                },
+                "reasons": {
+                    "description": "Reasons is the number of contexts for each evaluation reason (TARGETING_MATCH, SPLIT, ERROR, ...)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "rules": {
+                    "description": "Rules describes what each rule serves, in the order of evaluation with the default rule at the end",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/evaluation.RuleSimulation"
+                    }
+                },
+                "variations": {
+                    "description": "Variations is the number and the percentage of contexts getting each variation",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/evaluation.VariationCount"
+                    }
+                }
+            }
+        },
+        "evaluation.Result": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "description": "Bucket is the position of the user in the percentage splits (between 0 and 100), it is only set when the\nvariation depends on a percentage",
+                    "type": "number",
+                    "example": 42.123
+                },
+                "errorCode": {
+                    "description": "ErrorCode is set when the reason is ERROR (TARGETING_KEY_MISSING or GENERAL)",
+                    "type": "string"
+                },
+                "explanation": {
+                    "description": "Explanation describes in plain words why the user gets this variation",
+                    "type": "string",
+                    "example": "the rule beta matched the query beta eq true"
+                },
+                "flag": {
+                    "description": "Flag is the name of the evaluated flag",
+                    "type": "string",
+                    "example": "my-feature-flag"
+                },
+                "reason": {
+                    "description": "Reason explains how the variation has been selected (TARGETING_MATCH, TARGETING_MATCH_SPLIT, SPLIT,\nSTATIC, DEFAULT, DISABLED or ERROR)",
+                    "type": "string",
+                    "example": "TARGETING_MATCH"
+                },
+                "ruleId": {
+                    "description": "RuleID and RuleName are the ones of the rule applied, it can be the default rule",
+                    "type": "string"
+                },
+                "ruleName": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value is the value of the variation served"
+                },
+                "variation": {
+                    "description": "Variation is the name of the variation served, empty if the flag is disabled or on error",
+                    "type": "string",
+                    "example": "variation_A"
+                }
+            }
+        },
+        "evaluation.RuleSimulation": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "Default is true for the default rule of the flag",
+                    "type": "boolean"
+                },
+                "disabled": {
+                    "description": "Disabled is true if the rule is disabled, it does not serve any context",
+                    "type": "boolean"
+                },
+                "expectedPercentages": {
+                    "description": "ExpectedPercentages is the split the rule is configured to serve at the date of the simulation, it is the\npercentages of the rule or the split of the progressive rollout",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "matched": {
+                    "description": "Matched is the number of contexts served by the rule",
+                    "type": "integer",
+                    "example": 120
+                },
+                "rolloutPercentage": {
+                    "description": "RolloutPercentage is the percentage of users getting the end variation of the progressive rollout at the\ndate of the simulation",
+                    "type": "number",
+                    "example": 25
+                },
+                "ruleId": {
+                    "type": "string"
+                },
+                "ruleName": {
+                    "type": "string"
+                },
+                "variations": {
+                    "description": "Variations is the number of contexts getting each variation, the percentages are relative to Matched",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/evaluation.VariationCount"
+                    }
+                }
+            }
+        },
+        "evaluation.Simulation": {
+            "type": "object",
+            "properties": {
+                "contexts": {
+                    "description": "Contexts is the number of evaluation contexts of the sample",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "date": {
+                    "description": "Date is the date used to compute the percentages of the progressive rollouts",
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "flags": {
+                    "description": "Flags is the distribution of the variations of each flag",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/evaluation.FlagSimulation"
+                    }
+                }
+            }
+        },
+        "evaluation.VariationCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 480
+                },
+                "percentage": {
+                    "description": "Percentage is the share of the contexts getting the variation (between 0 and 100)",
+                    "type": "number",
+                    "example": 48
+                }
+            }
+        },
+        "flagconfig.Flag": {
+            "type": "object",
+            "properties": {
+                "bucketingKey": {
+                    "description": "BucketingKey defines a source for a dynamic targeting key",
+                    "type": "string"
+                },
+                "defaultRule": {
+                    "description": "DefaultRule is the rule applied when no targeting rule is matching.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/flagconfig.Rule"
+                        }
+                    ]
+                },
+                "disable": {
+                    "description": "Disable is true if the flag is disabled.",
+                    "type": "boolean"
+                },
+                "experimentation": {
+                    "description": "Experimentation is the time window of an experimentation, outside of it the flag is considered disabled.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ExperimentationRollout"
+                        }
+                    ]
+                },
+                "metadata": {
+                    "description": "Metadata is a field containing information about your flag such as an issue tracker link, a description, etc ...",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "targeting": {
+                    "description": "Rules is the list of targeting rules of the flag, they are evaluated in order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/flagconfig.Rule"
+                    }
+                },
+                "trackEvents": {
+                    "description": "TrackEvents is false if you don't want to export the data in your data exporter.",
+                    "type": "boolean"
+                },
+                "variations": {
+                    "description": "Variations are all the variations available for this flag.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "version": {
+                    "description": "Version (optional) This field contains the version of the flag.",
+                    "type": "string"
+                }
+            }
+        },
+        "flagconfig.ImportResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Created contains the names of the flags created by the import",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dryRun": {
+                    "description": "DryRun is true if nothing has been stored",
+                    "type": "boolean",
+                    "example": false
+                },
+                "unchanged": {
+                    "description": "Unchanged contains the names of the existing flags that are identical to the ones of the file",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated": {
+                    "description": "Updated contains the names of the existing flags modified by the import",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "flagconfig.Rule": {
+            "type": "object",
+            "properties": {
+                "disable": {
+                    "description": "Disable indicates that this rule is disabled.",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Name of the rule, it is not used for the default rule",
+                    "type": "string"
+                },
+                "percentage": {
+                    "description": "Percentages represents the percentage we should give to each variation.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "progressiveRollout": {
+                    "description": "ProgressiveRollout ramps up the percentage of a variation over time.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ProgressiveRollout"
+                        }
+                    ]
+                },
+                "query": {
+                    "description": "Query represents an antlr query in the nikunjy/rules format, it is not used for the default rule",
+                    "type": "string"
+                },
+                "variation": {
+                    "description": "VariationResult represents the variation name to use if the rule apply for the user.",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.apiKeyCreationRequest": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "description": "ExpiresAt is the date after which the key is not valid anymore, the key never expires if not set",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is a human-readable name to know where the key is used",
+                    "type": "string",
+                    "example": "github-actions"
+                },
+                "scopes": {
+                    "description": "Scopes are the permissions granted to the key (read, write or delete)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read"
+                    ]
+                }
+            }
+        },
+        "handler.apiKeyCreationResponse": {
+            "type": "object",
+            "properties": {
+                "createdBy": {
+                    "description": "CreatedBy is the principal who created the key",
+                    "type": "string"
+                },
+                "createdDate": {
+                    "description": "CreatedDate is the date when the key has been created",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is the date after which the key is not valid anymore, nil means the key never expires",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier of the API key",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is the API key to use in the Authorization header, it is only returned once",
+                    "type": "string",
+                    "example": "goff_VGhpcyBpcyBub3QgYSByZWFsIGtleQ"
+                },
+                "lastUsedDate": {
+                    "description": "LastUsedDate is the last time the key has been used to call the API",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is a human-readable name to know where the key is used",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the beginning of the key, it helps to identify the key without exposing it",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Scopes are the permissions granted to the key",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APIKeyScope"
+                    }
+                }
+            }
+        },
+        "handler.apiKeyListResponse": {
+            "type": "object",
+            "properties": {
+                "apiKeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APIKey"
+                    }
+                }
+            }
+        },
+        "handler.environmentListResponse": {
+            "type": "object",
+            "properties": {
+                "environments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Environment"
+                    }
+                }
+            }
+        },
+        "handler.flagListResponse": {
+            "type": "object",
+            "properties": {
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FeatureFlag"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.flagVersionListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FlagVersion"
+                    }
+                }
+            }
+        },
+        "handler.projectListResponse": {
+            "type": "object",
+            "properties": {
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Project"
+                    }
+                }
+            }
+        },
+        "handler.scheduledChangeListResponse": {
+            "type": "object",
+            "properties": {
+                "scheduledChanges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ScheduledChange"
+                    }
+                }
+            }
+        },
+        "handler.scheduledChangeRequest": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Changes are the fields of the flag replaced when the change is applied",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FlagChanges"
+                        }
+                    ]
+                },
+                "scheduledDate": {
+                    "description": "ScheduledDate is the date from which the change is applied, it should be in the future",
+                    "type": "string",
+                    "example": "2024-06-01T08:00:00Z"
+                }
+            }
+        },
+        "handler.scopeCreationRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description (optional) explains what it is used for",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the identifier used in the URLs, it can only contain letters, digits, '.', '_' or '-'",
+                    "type": "string",
+                    "example": "production"
+                }
+            }
+        },
+        "handler.successResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "message": {
                     "type": "string",
                     "example": "API is up and running"
                 }
             }
         },
+        "model.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdBy": {
+                    "description": "CreatedBy is the principal who created the key",
+                    "type": "string"
+                },
+                "createdDate": {
+                    "description": "CreatedDate is the date when the key has been created",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is the date after which the key is not valid anymore, nil means the key never expires",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier of the API key",
+                    "type": "string"
+                },
+                "lastUsedDate": {
+                    "description": "LastUsedDate is the last time the key has been used to call the API",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is a human-readable name to know where the key is used",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the beginning of the key, it helps to identify the key without exposing it",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Scopes are the permissions granted to the key",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APIKeyScope"
+                    }
+                }
+            }
+        },
+        "model.APIKeyScope": {
+            "type": "string",
+            "enum": [
+                "read",
+                "write",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "APIKeyScopeRead",
+                "APIKeyScopeWrite",
+                "APIKeyScopeDelete"
+            ]
+        },
+        "model.Environment": {
+            "type": "object",
+            "properties": {
+                "createdDate": {
+                    "description": "CreatedDate is the date when the environment has been created",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description (optional) explains what the environment is used for",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the identifier of the environment inside its project, it is used in the URLs",
+                    "type": "string",
+                    "example": "production"
+                },
+                "project": {
+                    "description": "Project is the name of the project of the environment",
+                    "type": "string",
+                    "example": "checkout"
+                }
+            }
+        },
+        "model.ExperimentationRollout": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "End is the date after which the experimentation is over.",
+                    "type": "string"
+                },
+                "start": {
+                    "description": "Start is the date from which the experimentation is running.",
+                    "type": "string"
+                }
+            }
+        },
         "model.FeatureFlag": {
             "type": "object",
             "properties": {
@@ -357,6 +3260,18 @@ const docTemplate = `{
                     "description": "Disable is true if the flag is disabled.",
                     "type": "boolean"
                 },
+                "environment": {
+                    "description": "default environment if not set",
+                    "type": "string"
+                },
+                "experimentation": {
+                    "description": "Experimentation is your struct to configure an experimentation.\nIt will allow you to configure a start date and an end date for your flag.\nWhen the experimentation is not running, the flag will serve the default value.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ExperimentationRollout"
+                        }
+                    ]
+                },
                 "id": {
                     "type": "string"
                 },
@@ -371,6 +3286,14 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "project": {
+                    "description": "default project if not set",
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Revision is incremented every time the flag is updated, it is used to detect concurrent modifications.\nWhen updating a flag, the revision should be the one of the flag you have retrieved.",
+                    "type": "integer"
+                },
                 "targeting": {
                     "description": "Rules is the list of Rule for this flag.\nThis an optional field.",
                     "type": "array",
@@ -404,6 +3327,50 @@ const docTemplate = `{
                 }
             }
         },
+        "model.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the path of the invalid field in the JSON representation of the flag (ex: targeting[0].variation)",
+                    "type": "string",
+                    "example": "targeting[0].variation"
+                },
+                "message": {
+                    "description": "Message explains why the field is invalid",
+                    "type": "string",
+                    "example": "variation C does not exist"
+                },
+                "ruleId": {
+                    "description": "RuleID is the ID of the rule containing the invalid field, if any",
+                    "type": "string",
+                    "example": "a4c7d1e2-5b1a-4c1e-9f5e-0d2b8e6f7a10"
+                }
+            }
+        },
+        "model.FlagChanges": {
+            "type": "object",
+            "properties": {
+                "defaultRule": {
+                    "description": "DefaultRule replaces the default rule of the flag",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Rule"
+                        }
+                    ]
+                },
+                "disable": {
+                    "description": "Disable enables (false) or disables (true) the flag",
+                    "type": "boolean"
+                },
+                "targeting": {
+                    "description": "Rules replaces all the targeting rules of the flag",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Rule"
+                    }
+                }
+            }
+        },
         "model.FlagType": {
             "type": "string",
             "enum": [
@@ -421,6 +3388,56 @@ const docTemplate = `{
                 "FlagTypeJSON"
             ]
         },
+        "model.FlagVersion": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is the write operation that produced this version",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FlagVersionAction"
+                        }
+                    ]
+                },
+                "createdDate": {
+                    "description": "CreatedDate is the date when this version became active",
+                    "type": "string"
+                },
+                "flag": {
+                    "description": "Flag is the full state of the flag at this version",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FeatureFlag"
+                        }
+                    ]
+                },
+                "flagId": {
+                    "description": "FlagID is the ID of the feature flag this version belongs to",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier of this version",
+                    "type": "string"
+                },
+                "versionNumber": {
+                    "description": "VersionNumber is incremented by one every time the flag is written, the first version is 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.FlagVersionAction": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "deleted"
+            ],
+            "x-enum-varnames": [
+                "FlagVersionActionCreated",
+                "FlagVersionActionUpdated",
+                "FlagVersionActionDeleted"
+            ]
+        },
         "model.ProgressiveRollout": {
             "type": "object",
             "properties": {
@@ -439,6 +3456,13 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.ProgressiveRolloutStep"
                         }
                     ]
+                },
+                "steps": {
+                    "description": "Steps contains the ordered steps of a rollout with more than 2 steps, it replaces Initial and End.\nThe first step describes the variation served before the rollout and the following steps the percentage\nof the users getting the variation of the step at their date (see Segments).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProgressiveRolloutStep"
+                    }
                 }
             }
         },
@@ -450,7 +3474,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "percentage": {
-                    "description": "Percentage is the percentage (initial or end) for the progressive rollout",
+                    "description": "Percentage is the percentage of the users getting the rolled out variation at the date of the step",
                     "type": "number"
                 },
                 "variation": {
@@ -459,6 +3483,24 @@ const docTemplate = `{
                 }
             }
         },
+        "model.Project": {
+            "type": "object",
+            "properties": {
+                "createdDate": {
+                    "description": "CreatedDate is the date when the project has been created",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description (optional) explains what the project is about",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the unique identifier of the project, it is used in the URLs",
+                    "type": "string",
+                    "example": "checkout"
+                }
+            }
+        },
         "model.Rule": {
             "type": "object",
             "properties": {
@@ -498,6 +3540,74 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "model.ScheduledChange": {
+            "type": "object",
+            "properties": {
+                "appliedDate": {
+                    "description": "AppliedDate is the date when the change has been applied to the flag",
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Changes are the fields of the flag replaced when the change is applied",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FlagChanges"
+                        }
+                    ]
+                },
+                "createdBy": {
+                    "description": "CreatedBy is the principal who scheduled the change, the flag is modified in their name",
+                    "type": "string"
+                },
+                "createdDate": {
+                    "description": "CreatedDate is the date when the change has been scheduled",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error explains why the change has not been applied when the status is failed",
+                    "type": "string"
+                },
+                "flagId": {
+                    "description": "FlagID is the ID of the flag to change",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier of the scheduled change",
+                    "type": "string"
+                },
+                "lastUpdatedDate": {
+                    "description": "LastUpdatedDate is the last time the change has been edited, applied or cancelled",
+                    "type": "string"
+                },
+                "scheduledDate": {
+                    "description": "ScheduledDate is the date from which the change is applied",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the state of the change (pending, applied, failed or cancelled)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ScheduledChangeStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.ScheduledChangeStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "applied",
+                "failed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "ScheduledChangeStatusPending",
+                "ScheduledChangeStatusApplied",
+                "ScheduledChangeStatusFailed",
+                "ScheduledChangeStatusCancelled"
+            ]
         }
     }
 }`
@@ -0,0 +1,185 @@
+package evaluation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ContextFormat is the format of a list of evaluation contexts.
+type ContextFormat string
+
+const (
+	// ContextFormatJSON is a JSON array of evaluation contexts
+	ContextFormatJSON ContextFormat = "json"
+	// ContextFormatJSONL is one JSON evaluation context per line
+	ContextFormatJSONL ContextFormat = "jsonl"
+	// ContextFormatCSV has a header with the name of the attributes and one evaluation context per line
+	ContextFormatCSV ContextFormat = "csv"
+)
+
+// ContextFormatFromValue converts a string to a ContextFormat
+func ContextFormatFromValue(value string) (ContextFormat, error) {
+	switch strings.ToLower(value) {
+	case "json":
+		return ContextFormatJSON, nil
+	case "jsonl", "ndjson":
+		return ContextFormatJSONL, nil
+	case "csv":
+		return ContextFormatCSV, nil
+	case "":
+		return "", errors.New("format is required")
+	default:
+		return "", fmt.Errorf("format %s not supported, use json, jsonl or csv", value)
+	}
+}
+
+// ContextFormatFromFileName returns the ContextFormat associated to the extension of a file (ex: users.csv).
+// It returns false if the extension is not supported.
+func ContextFormatFromFileName(fileName string) (ContextFormat, bool) {
+	extension := strings.TrimPrefix(filepath.Ext(fileName), ".")
+	if extension == "" {
+		return "", false
+	}
+	format, err := ContextFormatFromValue(extension)
+	return format, err == nil
+}
+
+// ContextFormatFromMediaType returns the ContextFormat associated to a media type (ex: text/csv).
+// The parameters of the media type are ignored, it returns false if the media type is not supported.
+func ContextFormatFromMediaType(mediaType string) (ContextFormat, bool) {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "application/json", "text/json":
+		return ContextFormatJSON, true
+	case "application/jsonl", "application/x-jsonlines", "application/x-ndjson", "application/ndjson":
+		return ContextFormatJSONL, true
+	case "text/csv", "application/csv":
+		return ContextFormatCSV, true
+	default:
+		return "", false
+	}
+}
+
+// ReadContexts decodes a list of evaluation contexts in the given format.
+// It returns an error if the list has more than limit contexts, a limit of 0 means no limit.
+func ReadContexts(r io.Reader, format ContextFormat, limit int) ([]Context, error) {
+	var contexts []Context
+	var err error
+	switch format {
+	case ContextFormatJSON:
+		err = json.NewDecoder(r).Decode(&contexts)
+	case ContextFormatJSONL:
+		contexts, err = readJSONLContexts(r)
+	case ContextFormatCSV:
+		contexts, err = readCSVContexts(r)
+	default:
+		return nil, fmt.Errorf("format %s not supported", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(contexts) > limit {
+		return nil, fmt.Errorf("too many evaluation contexts (%d), the maximum is %d", len(contexts), limit)
+	}
+	return contexts, nil
+}
+
+// readJSONLContexts reads one evaluation context per line, the empty lines are ignored.
+func readJSONLContexts(r io.Reader) ([]Context, error) {
+	var contexts []Context
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+		var evaluationContext Context
+		if err := json.Unmarshal(content, &evaluationContext); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		contexts = append(contexts, evaluationContext)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return contexts, nil
+}
+
+// readCSVContexts reads a CSV file with a header, the column targetingKey (or key) is the targeting key and the
+// other columns are the attributes. A column named with dots (ex: company.name) is a nested attribute.
+// The values true and false are booleans, the numbers are converted to float64 and the empty values are ignored.
+func readCSVContexts(r io.Reader) ([]Context, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var contexts []Context
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return contexts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		evaluationContext := Context{Attributes: map[string]interface{}{}}
+		for i, value := range record {
+			switch {
+			case value == "":
+				continue
+			case header[i] == "targetingKey" || header[i] == "key":
+				evaluationContext.TargetingKey = value
+			default:
+				setAttribute(evaluationContext.Attributes, strings.Split(header[i], "."), csvValue(value))
+			}
+		}
+		contexts = append(contexts, evaluationContext)
+	}
+}
+
+// setAttribute sets a nested attribute, creating the intermediate objects.
+func setAttribute(attributes map[string]interface{}, path []string, value interface{}) {
+	for _, name := range path[:len(path)-1] {
+		child, ok := attributes[name].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			attributes[name] = child
+		}
+		attributes = child
+	}
+	attributes[path[len(path)-1]] = value
+}
+
+func csvValue(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	// ParseFloat also accepts values like NaN or Inf, only the values starting like a number are converted
+	if strings.ContainsAny(value[:1], "0123456789-+.") {
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	}
+	return value
+}
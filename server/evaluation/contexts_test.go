@@ -0,0 +1,124 @@
+package evaluation_test
+
+import (
+	"github.com/go-feature-flag/flag-management/server/evaluation"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadContexts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  evaluation.ContextFormat
+		limit   int
+		want    []evaluation.Context
+		wantErr string
+	}{
+		{
+			name:    "should read a JSON array",
+			content: `[{"targetingKey":"user-1","attributes":{"beta":true}},{"targetingKey":"user-2"}]`,
+			format:  evaluation.ContextFormatJSON,
+			want: []evaluation.Context{
+				{TargetingKey: "user-1", Attributes: map[string]interface{}{"beta": true}},
+				{TargetingKey: "user-2"},
+			},
+		},
+		{
+			name:    "should read JSON lines and ignore the empty lines",
+			content: "{\"targetingKey\":\"user-1\",\"attributes\":{\"age\":32}}\n\n{\"targetingKey\":\"user-2\"}\n",
+			format:  evaluation.ContextFormatJSONL,
+			want: []evaluation.Context{
+				{TargetingKey: "user-1", Attributes: map[string]interface{}{"age": float64(32)}},
+				{TargetingKey: "user-2"},
+			},
+		},
+		{
+			name:    "should return the line of an invalid JSON line",
+			content: "{\"targetingKey\":\"user-1\"}\n{\"targetingKey\":",
+			format:  evaluation.ContextFormatJSONL,
+			wantErr: "line 2: unexpected end of JSON input",
+		},
+		{
+			name: "should read a CSV file with typed and nested attributes",
+			content: "targetingKey,beta,age,country,company.name,version\n" +
+				"user-1,true,32,FR,GO Feature Flag,1.2.3\n" +
+				"user-2,false,,NaN,,\n",
+			format: evaluation.ContextFormatCSV,
+			want: []evaluation.Context{
+				{TargetingKey: "user-1", Attributes: map[string]interface{}{
+					"beta":    true,
+					"age":     float64(32),
+					"country": "FR",
+					"company": map[string]interface{}{"name": "GO Feature Flag"},
+					"version": "1.2.3",
+				}},
+				{TargetingKey: "user-2", Attributes: map[string]interface{}{"beta": false, "country": "NaN"}},
+			},
+		},
+		{
+			name:    "should accept key as the targeting key column",
+			content: "key,plan\nuser-1,premium\n",
+			format:  evaluation.ContextFormatCSV,
+			want: []evaluation.Context{
+				{TargetingKey: "user-1", Attributes: map[string]interface{}{"plan": "premium"}},
+			},
+		},
+		{
+			name:    "should return an error if a CSV line has the wrong number of fields",
+			content: "targetingKey,plan\nuser-1\n",
+			format:  evaluation.ContextFormatCSV,
+			wantErr: "record on line 2: wrong number of fields",
+		},
+		{
+			name:    "should return an error if there are more contexts than the limit",
+			content: `[{"targetingKey":"user-1"},{"targetingKey":"user-2"}]`,
+			format:  evaluation.ContextFormatJSON,
+			limit:   1,
+			wantErr: "too many evaluation contexts (2), the maximum is 1",
+		},
+		{
+			name:    "should return an error if the format is not supported",
+			content: `[]`,
+			format:  "xml",
+			wantErr: "format xml not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluation.ReadContexts(strings.NewReader(tt.content), tt.format, tt.limit)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContextFormat(t *testing.T) {
+	format, err := evaluation.ContextFormatFromValue("NDJSON")
+	require.NoError(t, err)
+	assert.Equal(t, evaluation.ContextFormatJSONL, format)
+
+	_, err = evaluation.ContextFormatFromValue("yaml")
+	assert.EqualError(t, err, "format yaml not supported, use json, jsonl or csv")
+
+	format, ok := evaluation.ContextFormatFromFileName("users.csv")
+	assert.True(t, ok)
+	assert.Equal(t, evaluation.ContextFormatCSV, format)
+
+	_, ok = evaluation.ContextFormatFromFileName("users")
+	assert.False(t, ok)
+
+	format, ok = evaluation.ContextFormatFromMediaType("application/x-ndjson; charset=utf-8")
+	assert.True(t, ok)
+	assert.Equal(t, evaluation.ContextFormatJSONL, format)
+
+	_, ok = evaluation.ContextFormatFromMediaType("application/yaml")
+	assert.False(t, ok)
+}
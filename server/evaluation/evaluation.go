@@ -0,0 +1,302 @@
+// Package evaluation computes the variation a user gets for a flag, with the same algorithm as the
+// GO Feature Flag relay proxy (rules in order, percentage buckets and progressive rollouts).
+package evaluation
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/rulequery"
+	"sort"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+// Reasons explaining how the variation has been selected, they are the ones returned by the relay proxy.
+const (
+	ReasonTargetingMatch      = "TARGETING_MATCH"
+	ReasonTargetingMatchSplit = "TARGETING_MATCH_SPLIT"
+	ReasonSplit               = "SPLIT"
+	ReasonStatic              = "STATIC"
+	ReasonDefault             = "DEFAULT"
+	ReasonDisabled            = "DISABLED"
+	ReasonError               = "ERROR"
+)
+
+// Error codes returned with ReasonError when the flag cannot be evaluated for a context.
+const (
+	ErrorTargetingKeyMissing = "TARGETING_KEY_MISSING"
+	ErrorGeneral             = "GENERAL"
+)
+
+// Context is the evaluation context of a user.
+type Context struct {
+	// TargetingKey identifies the user, it is used to compute the percentage splits.
+	// In the queries it is available as key.
+	TargetingKey string `json:"targetingKey" example:"9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`
+	// Attributes are the other properties of the user used by the queries of the rules.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// queryAttributes returns the attributes available in the queries of the rules, they are built like in the relay
+// proxy: the attributes of the context with the targeting key as key and the anonymous flag of the user.
+func (c Context) queryAttributes() map[string]interface{} {
+	attributes := make(map[string]interface{}, len(c.Attributes)+2)
+	for name, value := range c.Attributes {
+		attributes[name] = value
+	}
+	anonymous, _ := c.Attributes["anonymous"].(bool)
+	attributes["anonymous"] = anonymous
+	attributes["key"] = c.TargetingKey
+	return attributes
+}
+
+// Result describes the variation served to a user and why.
+type Result struct {
+	// Flag is the name of the evaluated flag
+	Flag string `json:"flag" example:"my-feature-flag"`
+	// Variation is the name of the variation served, empty if the flag is disabled or on error
+	Variation string `json:"variation,omitempty" example:"variation_A"`
+	// Value is the value of the variation served
+	Value interface{} `json:"value"`
+	// Reason explains how the variation has been selected (TARGETING_MATCH, TARGETING_MATCH_SPLIT, SPLIT,
+	// STATIC, DEFAULT, DISABLED or ERROR)
+	Reason string `json:"reason" example:"TARGETING_MATCH"`
+	// ErrorCode is set when the reason is ERROR (TARGETING_KEY_MISSING or GENERAL)
+	ErrorCode string `json:"errorCode,omitempty"`
+	// RuleID and RuleName are the ones of the rule applied, it can be the default rule
+	RuleID   string `json:"ruleId,omitempty"`
+	RuleName string `json:"ruleName,omitempty"`
+	// Bucket is the position of the user in the percentage splits (between 0 and 100), it is only set when the
+	// variation depends on a percentage
+	Bucket *float64 `json:"bucket,omitempty" example:"42.123"`
+	// Explanation describes in plain words why the user gets this variation
+	Explanation string `json:"explanation" example:"the rule beta matched the query beta eq true"`
+}
+
+// Evaluate returns the variation of the flag for the evaluation context.
+// now is the date of the evaluation, it is used to compute the percentages of the progressive rollouts.
+func Evaluate(flag model.FeatureFlag, evaluationContext Context, now time.Time) Result {
+	result, _ := evaluate(flag, evaluationContext, now)
+	return result
+}
+
+// evaluate returns the result of the evaluation and the index of the rule applied, the default rule has the
+// index len(flag.GetRules()) and the index is -1 if no rule has been applied.
+func evaluate(flag model.FeatureFlag, evaluationContext Context, now time.Time) (Result, int) {
+	result := Result{Flag: flag.Name}
+	if flag.IsDisable() {
+		result.Reason = ReasonDisabled
+		result.Explanation = "the flag is disabled, the SDK returns its default value"
+		return result, -1
+	}
+	if flag.IsExperimentationOver(now) {
+		result.Reason = ReasonDisabled
+		result.Explanation = "the experimentation of the flag is not running, the SDK returns its default value"
+		return result, -1
+	}
+
+	attributes := evaluationContext.queryAttributes()
+	for i, rule := range flag.GetRules() {
+		if rule.Disable {
+			continue
+		}
+		// like in the relay proxy, a query that cannot be evaluated does not match
+		if !rulequery.Match(rule.Query, attributes) {
+			continue
+		}
+		matched := fmt.Sprintf("the rule %s matched the query %s", ruleLabel(rule, i), rule.Query)
+		return applyRule(flag, rule, evaluationContext, now, matched, true), i
+	}
+
+	explanation := "no targeting rule matched, the default rule is applied"
+	if len(flag.GetRules()) == 0 {
+		explanation = "the flag has no targeting rule, the default rule is applied"
+	}
+	return applyRule(flag, flag.GetDefaultRule(), evaluationContext, now, explanation, false), len(flag.GetRules())
+}
+
+// applyRule selects the variation of a rule, a progressive rollout takes precedence over the percentages
+// and the percentages take precedence over the variation like in the relay proxy.
+func applyRule(
+	flag model.FeatureFlag, rule model.Rule, evaluationContext Context, now time.Time, explanation string,
+	isTargeting bool) Result {
+	result := Result{Flag: flag.Name, RuleID: rule.ID, RuleName: rule.Name}
+	split := rule.ProgressiveRollout != nil || (rule.Percentages != nil && len(*rule.Percentages) > 0)
+	if split {
+		b, errorCode, err := userBucket(flag, evaluationContext)
+		if err != nil {
+			return errorResult(result, errorCode, err.Error())
+		}
+		userPercentage := float64(b) / percentageMultiplier
+		result.Bucket = &userPercentage
+
+		if rule.ProgressiveRollout != nil {
+			segment, err := currentSegment(*rule.ProgressiveRollout, now)
+			if err != nil {
+				return errorResult(result, ErrorGeneral, err.Error())
+			}
+			rolloutPercentage := segmentPercentage(segment, now)
+			initialVariation, rolledOutVariation := *segment.Initial.Variation, *segment.End.Variation
+			result.Variation = initialVariation
+			if userPercentage < rolloutPercentage {
+				result.Variation = rolledOutVariation
+			}
+			explanation = fmt.Sprintf("%s, the progressive rollout serves %s to %.3f%% of the users at this date "+
+				"and the user is at %.3f%%", explanation, rolledOutVariation, rolloutPercentage, userPercentage)
+		} else {
+			if result.Variation, err = percentageVariation(*rule.Percentages, b); err != nil {
+				return errorResult(result, ErrorGeneral, err.Error())
+			}
+			explanation = fmt.Sprintf("%s, the user is at %.3f%% of the percentage split", explanation, userPercentage)
+		}
+	} else {
+		if rule.VariationResult == nil || *rule.VariationResult == "" {
+			return errorResult(result, ErrorGeneral, "the rule applied has no variation")
+		}
+		result.Variation = *rule.VariationResult
+	}
+
+	result.Reason = reason(flag, isTargeting, split)
+	result.Explanation = explanation
+	value, ok := variationValue(flag, result.Variation)
+	if !ok {
+		return errorResult(result, ErrorGeneral, fmt.Sprintf("the variation %s does not exist", result.Variation))
+	}
+	result.Value = value
+	return result
+}
+
+func reason(flag model.FeatureFlag, isTargeting bool, split bool) string {
+	switch {
+	case isTargeting && split:
+		return ReasonTargetingMatchSplit
+	case isTargeting:
+		return ReasonTargetingMatch
+	case split:
+		return ReasonSplit
+	case len(flag.GetRules()) == 0:
+		return ReasonStatic
+	default:
+		return ReasonDefault
+	}
+}
+
+func errorResult(result Result, errorCode string, explanation string) Result {
+	result.Variation = ""
+	result.Value = nil
+	result.Reason = ReasonError
+	result.ErrorCode = errorCode
+	result.Explanation = explanation
+	return result
+}
+
+// userBucket returns the position of the user in the percentage splits, computed from the targeting key or from
+// the attribute named by the bucketing key of the flag.
+func userBucket(flag model.FeatureFlag, evaluationContext Context) (uint32, string, error) {
+	if flag.BucketingKey != nil && *flag.BucketingKey != "" {
+		value, ok := evaluationContext.Attributes[*flag.BucketingKey].(string)
+		if !ok || value == "" {
+			return 0, ErrorTargetingKeyMissing,
+				fmt.Errorf("the attribute %s used as bucketing key is missing", *flag.BucketingKey)
+		}
+		return bucket(flag.Name, value), "", nil
+	}
+	if evaluationContext.TargetingKey == "" {
+		return 0, ErrorTargetingKeyMissing, fmt.Errorf("a targeting key is required to compute the percentage split")
+	}
+	return bucket(flag.Name, evaluationContext.TargetingKey), "", nil
+}
+
+// percentageVariation returns the variation of the bucket, the variations are sorted by name to always give the
+// same range of buckets to each variation.
+func percentageVariation(percentages map[string]float64, b uint32) (string, error) {
+	names := make([]string, 0, len(percentages))
+	for name := range percentages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	start := 0.0
+	for _, name := range names {
+		end := start + percentages[name]*percentageMultiplier
+		if float64(b) >= start && float64(b) < end {
+			return name, nil
+		}
+		start = end
+	}
+	return "", fmt.Errorf("the percentages of the rule do not cover the bucket of the user")
+}
+
+// RolloutPercentage returns the percentage of users getting the variation rolled out at a date by a progressive
+// rollout, in the segment of the rollout running at this date (see model.ProgressiveRollout.Segments).
+// Before the date of the first step nobody gets the rolled out variation and after the date of the last step
+// everybody gets it, within a segment the percentage increases linearly from its initial percentage to its end
+// percentage. Like in the relay proxy, the initial percentage defaults to 0 and the end one to 100.
+func RolloutPercentage(rollout model.ProgressiveRollout, now time.Time) (float64, error) {
+	segment, err := currentSegment(rollout, now)
+	if err != nil {
+		return 0, err
+	}
+	return segmentPercentage(segment, now), nil
+}
+
+// currentSegment returns the segment of a progressive rollout running at a date, the first segment before the
+// rollout and the last one after it.
+func currentSegment(rollout model.ProgressiveRollout, now time.Time) (model.ProgressiveRollout, error) {
+	invalid := fmt.Errorf("the progressive rollout is missing its variations or has invalid dates")
+	steps := rollout.GetSteps()
+	if len(steps) < 2 {
+		return model.ProgressiveRollout{}, invalid
+	}
+	for i, step := range steps {
+		if step.Variation == nil || step.Date == nil || (i > 0 && !step.Date.After(*steps[i-1].Date)) {
+			return model.ProgressiveRollout{}, invalid
+		}
+	}
+	segments := rollout.Segments()
+	for _, segment := range segments {
+		if !now.After(*segment.End.Date) {
+			return segment, nil
+		}
+	}
+	return segments[len(segments)-1], nil
+}
+
+// segmentPercentage returns the percentage of users getting the end variation of a segment at a date.
+func segmentPercentage(segment model.ProgressiveRollout, now time.Time) float64 {
+	if now.Before(*segment.Initial.Date) {
+		return 0
+	}
+	if now.After(*segment.End.Date) {
+		return 100
+	}
+	startPercentage := 0.0
+	if segment.Initial.Percentage != nil {
+		startPercentage = *segment.Initial.Percentage
+	}
+	endPercentage := 100.0
+	if p := segment.End.Percentage; p != nil && *p > 0 && *p <= 100 {
+		endPercentage = *p
+	}
+	elapsed := now.Unix() - segment.Initial.Date.Unix()
+	duration := segment.End.Date.Unix() - segment.Initial.Date.Unix()
+	return startPercentage + (endPercentage-startPercentage)*float64(elapsed)/float64(duration)
+}
+
+func variationValue(flag model.FeatureFlag, name string) (interface{}, bool) {
+	if flag.Variations == nil {
+		return nil, false
+	}
+	value, ok := (*flag.Variations)[name]
+	if pointer, isPointer := value.(*interface{}); isPointer && pointer != nil {
+		value = *pointer
+	}
+	return value, ok
+}
+
+func ruleLabel(rule model.Rule, index int) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return fmt.Sprintf("#%d", index)
+}
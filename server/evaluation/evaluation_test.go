@@ -0,0 +1,346 @@
+package evaluation_test
+
+import (
+	"github.com/go-feature-flag/flag-management/server/evaluation"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	newFlag := func() model.FeatureFlag {
+		return model.FeatureFlag{
+			Name:          "flag1",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			Rules: &[]model.Rule{
+				{ID: "rule-1", Name: "beta", Query: `beta eq true`, VariationResult: testutils.String("B")},
+				{ID: "rule-2", Name: "french", Query: `country eq "FR"`, Percentages: &map[string]float64{"A": 50, "B": 50}},
+			},
+			DefaultRule: &model.Rule{ID: "default", VariationResult: testutils.String("A")},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		update  func(flag *model.FeatureFlag)
+		context evaluation.Context
+		want    evaluation.Result
+	}{
+		{
+			name:    "should return the variation of the first matching rule",
+			context: evaluation.Context{TargetingKey: "user-1", Attributes: map[string]interface{}{"beta": true, "country": "FR"}},
+			want: evaluation.Result{Flag: "flag1", Variation: "B", Value: "b", Reason: evaluation.ReasonTargetingMatch,
+				RuleID: "rule-1", RuleName: "beta", Explanation: "the rule beta matched the query beta eq true"},
+		},
+		{
+			name:    "should use the percentage split of the matching rule",
+			context: evaluation.Context{TargetingKey: "user-1", Attributes: map[string]interface{}{"country": "FR"}},
+			want: evaluation.Result{Flag: "flag1", Variation: "B", Value: "b", Reason: evaluation.ReasonTargetingMatchSplit,
+				RuleID: "rule-2", RuleName: "french", Bucket: testutils.Float64(88.394),
+				Explanation: `the rule french matched the query country eq "FR", the user is at 88.394% of the percentage split`},
+		},
+		{
+			name:    "should use the default rule if no rule matches",
+			context: evaluation.Context{TargetingKey: "user-1"},
+			want: evaluation.Result{Flag: "flag1", Variation: "A", Value: "a", Reason: evaluation.ReasonDefault,
+				RuleID: "default", Explanation: "no targeting rule matched, the default rule is applied"},
+		},
+		{
+			name: "should skip the disabled rules",
+			update: func(flag *model.FeatureFlag) {
+				(*flag.Rules)[0].Disable = true
+			},
+			context: evaluation.Context{TargetingKey: "user-1", Attributes: map[string]interface{}{"beta": true}},
+			want: evaluation.Result{Flag: "flag1", Variation: "A", Value: "a", Reason: evaluation.ReasonDefault,
+				RuleID: "default", Explanation: "no targeting rule matched, the default rule is applied"},
+		},
+		{
+			name: "should return a static reason for a flag without rules",
+			update: func(flag *model.FeatureFlag) {
+				flag.Rules = nil
+			},
+			context: evaluation.Context{TargetingKey: "user-1"},
+			want: evaluation.Result{Flag: "flag1", Variation: "A", Value: "a", Reason: evaluation.ReasonStatic,
+				RuleID: "default", Explanation: "the flag has no targeting rule, the default rule is applied"},
+		},
+		{
+			name: "should not serve any variation if the flag is disabled",
+			update: func(flag *model.FeatureFlag) {
+				flag.Disable = testutils.Bool(true)
+			},
+			context: evaluation.Context{TargetingKey: "user-1"},
+			want: evaluation.Result{Flag: "flag1", Reason: evaluation.ReasonDisabled,
+				Explanation: "the flag is disabled, the SDK returns its default value"},
+		},
+		{
+			name: "should not serve any variation outside of the experimentation",
+			update: func(flag *model.FeatureFlag) {
+				flag.Experimentation = &model.ExperimentationRollout{
+					Start: testutils.Time(now.Add(-48 * time.Hour)),
+					End:   testutils.Time(now.Add(-24 * time.Hour)),
+				}
+			},
+			context: evaluation.Context{TargetingKey: "user-1"},
+			want: evaluation.Result{Flag: "flag1", Reason: evaluation.ReasonDisabled,
+				Explanation: "the experimentation of the flag is not running, the SDK returns its default value"},
+		},
+		{
+			name: "should evaluate the flag during the experimentation",
+			update: func(flag *model.FeatureFlag) {
+				flag.Experimentation = &model.ExperimentationRollout{
+					Start: testutils.Time(now.Add(-24 * time.Hour)),
+					End:   testutils.Time(now.Add(24 * time.Hour)),
+				}
+			},
+			context: evaluation.Context{TargetingKey: "user-1"},
+			want: evaluation.Result{Flag: "flag1", Variation: "A", Value: "a", Reason: evaluation.ReasonDefault,
+				RuleID: "default", Explanation: "no targeting rule matched, the default rule is applied"},
+		},
+		{
+			name:    "should return an error if the targeting key is required for the split",
+			context: evaluation.Context{Attributes: map[string]interface{}{"country": "FR"}},
+			want: evaluation.Result{Flag: "flag1", Reason: evaluation.ReasonError,
+				ErrorCode: evaluation.ErrorTargetingKeyMissing, RuleID: "rule-2", RuleName: "french",
+				Explanation: "a targeting key is required to compute the percentage split"},
+		},
+		{
+			name: "should use the bucketing key instead of the targeting key",
+			update: func(flag *model.FeatureFlag) {
+				flag.BucketingKey = testutils.String("teamId")
+				flag.Rules = nil
+				flag.DefaultRule = &model.Rule{ID: "default", Percentages: &map[string]float64{"A": 50, "B": 50}}
+			},
+			context: evaluation.Context{TargetingKey: "user-1", Attributes: map[string]interface{}{"teamId": "user-4"}},
+			want: evaluation.Result{Flag: "flag1", Variation: "A", Value: "a", Reason: evaluation.ReasonSplit,
+				RuleID: "default", Bucket: testutils.Float64(18.243),
+				Explanation: "the flag has no targeting rule, the default rule is applied, the user is at 18.243% of the percentage split"},
+		},
+		{
+			name: "should use the percentages before the variation of a rule",
+			update: func(flag *model.FeatureFlag) {
+				flag.Rules = nil
+				flag.DefaultRule = &model.Rule{ID: "default", VariationResult: testutils.String("B"),
+					Percentages: &map[string]float64{"A": 50, "B": 50}}
+			},
+			context: evaluation.Context{TargetingKey: "user-4"},
+			want: evaluation.Result{Flag: "flag1", Variation: "A", Value: "a", Reason: evaluation.ReasonSplit,
+				RuleID: "default", Bucket: testutils.Float64(18.243),
+				Explanation: "the flag has no targeting rule, the default rule is applied, the user is at 18.243% of the percentage split"},
+		},
+		{
+			name: "should use the variation of a rule with empty percentages",
+			update: func(flag *model.FeatureFlag) {
+				flag.Rules = nil
+				flag.DefaultRule = &model.Rule{ID: "default", VariationResult: testutils.String("B"),
+					Percentages: &map[string]float64{}}
+			},
+			context: evaluation.Context{TargetingKey: "user-4"},
+			want: evaluation.Result{Flag: "flag1", Variation: "B", Value: "b", Reason: evaluation.ReasonStatic,
+				RuleID: "default", Explanation: "the flag has no targeting rule, the default rule is applied"},
+		},
+		{
+			name: "should compute the percentage of the progressive rollout at the evaluation date",
+			update: func(flag *model.FeatureFlag) {
+				flag.Rules = nil
+				flag.DefaultRule = &model.Rule{ID: "default", ProgressiveRollout: &model.ProgressiveRollout{
+					Initial: &model.ProgressiveRolloutStep{
+						Variation: testutils.String("A"), Date: testutils.Time(now.Add(-10 * time.Hour))},
+					End: &model.ProgressiveRolloutStep{
+						Variation: testutils.String("B"), Date: testutils.Time(now.Add(30 * time.Hour))},
+				}}
+			},
+			context: evaluation.Context{TargetingKey: "user-4"},
+			want: evaluation.Result{Flag: "flag1", Variation: "B", Value: "b", Reason: evaluation.ReasonSplit,
+				RuleID: "default", Bucket: testutils.Float64(18.243),
+				Explanation: "the flag has no targeting rule, the default rule is applied, the progressive rollout " +
+					"serves B to 25.000% of the users at this date and the user is at 18.243%"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := newFlag()
+			if tt.update != nil {
+				tt.update(&flag)
+			}
+			assert.Equal(t, tt.want, evaluation.Evaluate(flag, tt.context, now))
+		})
+	}
+}
+
+func TestRolloutPercentage(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rollout := model.ProgressiveRollout{
+		Initial: &model.ProgressiveRolloutStep{
+			Variation: testutils.String("A"), Percentage: testutils.Float64(10), Date: testutils.Time(start)},
+		End: &model.ProgressiveRolloutStep{
+			Variation: testutils.String("B"), Percentage: testutils.Float64(90), Date: testutils.Time(start.Add(100 * time.Hour))},
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		want float64
+	}{
+		{name: "before the initial date", now: start.Add(-time.Hour), want: 0},
+		{name: "at the initial date", now: start, want: 10},
+		{name: "in the middle of the rollout", now: start.Add(50 * time.Hour), want: 50},
+		{name: "after the end date", now: start.Add(101 * time.Hour), want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluation.RolloutPercentage(rollout, tt.now)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 0.0001)
+		})
+	}
+
+	t.Run("should return an error if the rollout has no dates", func(t *testing.T) {
+		_, err := evaluation.RolloutPercentage(model.ProgressiveRollout{Initial: rollout.Initial}, start)
+		assert.Error(t, err)
+	})
+}
+
+func TestRolloutPercentage_steps(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rollout := model.ProgressiveRollout{Steps: []model.ProgressiveRolloutStep{
+		{Variation: testutils.String("A"), Date: testutils.Time(start)},
+		{Variation: testutils.String("B"), Percentage: testutils.Float64(20), Date: testutils.Time(start.Add(10 * time.Hour))},
+		{Variation: testutils.String("B"), Percentage: testutils.Float64(20), Date: testutils.Time(start.Add(20 * time.Hour))},
+		{Variation: testutils.String("B"), Date: testutils.Time(start.Add(40 * time.Hour))},
+	}}
+	tests := []struct {
+		name string
+		now  time.Time
+		want float64
+	}{
+		{name: "before the first step", now: start.Add(-time.Hour), want: 0},
+		{name: "between the first and the second step", now: start.Add(5 * time.Hour), want: 10},
+		{name: "at the second step", now: start.Add(10 * time.Hour), want: 20},
+		{name: "on a plateau", now: start.Add(15 * time.Hour), want: 20},
+		{name: "between the third and the last step", now: start.Add(30 * time.Hour), want: 60},
+		{name: "after the last step", now: start.Add(41 * time.Hour), want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluation.RolloutPercentage(rollout, tt.now)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 0.0001)
+		})
+	}
+
+	t.Run("should roll out the variation of a step over the variation of the previous step", func(t *testing.T) {
+		flag := model.FeatureFlag{
+			Name:          "flag1",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b", "C": "c"},
+			DefaultRule: &model.Rule{ID: "default", ProgressiveRollout: &model.ProgressiveRollout{
+				Steps: []model.ProgressiveRolloutStep{
+					{Variation: testutils.String("A"), Date: testutils.Time(start)},
+					{Variation: testutils.String("B"), Percentage: testutils.Float64(100), Date: testutils.Time(start.Add(10 * time.Hour))},
+					{Variation: testutils.String("C"), Date: testutils.Time(start.Add(20 * time.Hour))},
+				},
+			}},
+		}
+		// the bucket of user-4 is 18.243%
+		context := evaluation.Context{TargetingKey: "user-4"}
+		assert.Equal(t, "A", evaluation.Evaluate(flag, context, start.Add(1*time.Hour)).Variation)
+		assert.Equal(t, "B", evaluation.Evaluate(flag, context, start.Add(10*time.Hour)).Variation)
+		assert.Equal(t, "B", evaluation.Evaluate(flag, context, start.Add(11*time.Hour)).Variation)
+		assert.Equal(t, "C", evaluation.Evaluate(flag, context, start.Add(12*time.Hour)).Variation)
+
+		got, err := evaluation.RolloutPercentage(*flag.DefaultRule.ProgressiveRollout, start.Add(15*time.Hour))
+		require.NoError(t, err)
+		assert.InDelta(t, 50, got, 0.0001, "C should be rolled out from 0%")
+	})
+
+	t.Run("should return an error if the steps are not in the order of their dates", func(t *testing.T) {
+		steps := append([]model.ProgressiveRolloutStep{}, rollout.Steps...)
+		steps[1].Date = testutils.Time(start.Add(50 * time.Hour))
+		_, err := evaluation.RolloutPercentage(model.ProgressiveRollout{Steps: steps}, start)
+		assert.Error(t, err)
+	})
+}
+
+// TestEvaluate_relayProxyQueries uses the queries of the tests of the relay proxy and of the nikunjy/rules library
+// to check that a rule matches the same users in the preview and in the relay proxy.
+func TestEvaluate_relayProxyQueries(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	nested := map[string]interface{}{"x": map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2.0}}}
+	tests := []struct {
+		query   string
+		context evaluation.Context
+		match   bool
+	}{
+		{query: `key eq "user-key"`, context: evaluation.Context{TargetingKey: "user-key"}, match: true},
+		{query: `key eq "not-user-key"`, context: evaluation.Context{TargetingKey: "user-key"}, match: false},
+		{
+			query:   `company eq "go-feature-flag"`,
+			context: evaluation.Context{TargetingKey: "user-key", Attributes: map[string]interface{}{"company": "go-feature-flag"}},
+			match:   true,
+		},
+		{
+			query:   `env == "development"`,
+			context: evaluation.Context{TargetingKey: "1", Attributes: map[string]interface{}{"env": "development"}},
+			match:   true,
+		},
+		{
+			query:   `(env == "production") or (env == "staging") or (env == "qa")`,
+			context: evaluation.Context{TargetingKey: "1", Attributes: map[string]interface{}{"env": "staging"}},
+			match:   true,
+		},
+		{
+			query:   `(env == "production") or (env == "staging") or (env == "qa")`,
+			context: evaluation.Context{TargetingKey: "1", Attributes: map[string]interface{}{"env": "development"}},
+			match:   false,
+		},
+		{query: `x.a eq 1 and x.b.c eq 2`, context: evaluation.Context{TargetingKey: "1", Attributes: nested}, match: true},
+		{query: `x.a eq 1 or x.b.c eq 2`, context: evaluation.Context{TargetingKey: "1"}, match: false},
+		{
+			query:   `app_version ge 1.2.3`,
+			context: evaluation.Context{TargetingKey: "1", Attributes: map[string]interface{}{"app_version": "1.10.0"}},
+			match:   true,
+		},
+		{
+			query:   "key eq \"user-key\" \n    and company eq \"go-feature-flag\"",
+			context: evaluation.Context{TargetingKey: "user-key", Attributes: map[string]interface{}{"company": "go-feature-flag"}},
+			match:   true,
+		},
+		{query: `anonymous eq false`, context: evaluation.Context{TargetingKey: "1"}, match: true},
+		{
+			query:   `anonymous eq true`,
+			context: evaluation.Context{TargetingKey: "1", Attributes: map[string]interface{}{"anonymous": true}},
+			match:   true,
+		},
+		{
+			// the relay proxy only considers a user anonymous if the attribute is a boolean
+			query:   `anonymous eq "true"`,
+			context: evaluation.Context{TargetingKey: "1", Attributes: map[string]interface{}{"anonymous": "true"}},
+			match:   false,
+		},
+		{
+			// the relay proxy does not add the targeting key as targetingKey
+			query:   `targetingKey eq "user-key"`,
+			context: evaluation.Context{TargetingKey: "user-key"},
+			match:   false,
+		},
+		{query: `invalid`, context: evaluation.Context{TargetingKey: "1"}, match: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			flag := model.FeatureFlag{
+				Name:          "flag1",
+				VariationType: model.FlagTypeString,
+				Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+				Rules:         &[]model.Rule{{ID: "rule-1", Query: tt.query, VariationResult: testutils.String("B")}},
+				DefaultRule:   &model.Rule{ID: "default", VariationResult: testutils.String("A")},
+			}
+			result := evaluation.Evaluate(flag, tt.context, now)
+			assert.Equal(t, tt.match, result.Reason == evaluation.ReasonTargetingMatch, result.Explanation)
+		})
+	}
+}
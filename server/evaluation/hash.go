@@ -0,0 +1,63 @@
+package evaluation
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	// percentageMultiplier is used to have 3 decimals of precision in the percentages, like the relay proxy
+	percentageMultiplier = 1000
+	// maxPercentage is 100% with the percentageMultiplier
+	maxPercentage = 100 * percentageMultiplier
+)
+
+// bucket returns the position of a user in the percentage splits of a flag, between 0 and maxPercentage.
+// It is computed the same way as the relay proxy, so a user gets the same variation in the preview.
+func bucket(flagName string, key string) uint32 {
+	return murmur3Sum32([]byte(flagName+key)) % maxPercentage
+}
+
+// murmur3Sum32 is the 32 bits MurmurHash3 with a seed of 0.
+func murmur3Sum32(data []byte) uint32 {
+	const (
+		c1 uint32 = 0xcc9e2d51
+		c2 uint32 = 0x1b873593
+	)
+	var h uint32
+	blocks := len(data) / 4
+	for i := 0; i < blocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	tail := data[blocks*4:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
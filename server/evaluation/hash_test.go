@@ -0,0 +1,28 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMurmur3Sum32(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint32
+	}{
+		{input: "", want: 0},
+		{input: "hello", want: 0x248bfa47},
+		{input: "The quick brown fox jumps over the lazy dog", want: 0x2e4ff723},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, murmur3Sum32([]byte(tt.input)))
+		})
+	}
+}
+
+func TestBucket(t *testing.T) {
+	assert.Equal(t, uint32(88394), bucket("flag1", "user-1"))
+	assert.Equal(t, uint32(18243), bucket("flag1", "user-4"))
+}
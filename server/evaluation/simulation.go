@@ -0,0 +1,160 @@
+package evaluation
+
+import (
+	"github.com/go-feature-flag/flag-management/server/util"
+	"math"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+// Simulation is the distribution of the variations of the flags for a sample of evaluation contexts.
+type Simulation struct {
+	// Date is the date used to compute the percentages of the progressive rollouts
+	Date time.Time `json:"date" example:"2024-06-01T00:00:00Z"`
+	// Contexts is the number of evaluation contexts of the sample
+	Contexts int `json:"contexts" example:"1000"`
+	// Flags is the distribution of the variations of each flag
+	Flags []FlagSimulation `json:"flags"`
+}
+
+// FlagSimulation is the distribution of the variations of a flag for a sample of evaluation contexts.
+type FlagSimulation struct {
+	ID   string `json:"id" example:"926214f3-80c1-46e6-a913-b2d40b92a932"`
+	Flag string `json:"flag" example:"my-feature-flag"`
+	// Variations is the number and the percentage of contexts getting each variation
+	Variations map[string]VariationCount `json:"variations"`
+	// Reasons is the number of contexts for each evaluation reason (TARGETING_MATCH, SPLIT, ERROR, ...)
+	Reasons map[string]int `json:"reasons"`
+	// Errors is the number of contexts for each error code, when the reason is ERROR
+	Errors map[string]int `json:"errors,omitempty"`
+	// Rules describes what each rule serves, in the order of evaluation with the default rule at the end
+	Rules []RuleSimulation `json:"rules"`
+}
+
+// VariationCount is the number of evaluation contexts getting a variation.
+type VariationCount struct {
+	Count int `json:"count" example:"480"`
+	// Percentage is the share of the contexts getting the variation (between 0 and 100)
+	Percentage float64 `json:"percentage" example:"48"`
+}
+
+// RuleSimulation describes the contexts served by a rule and the split it is configured to serve.
+type RuleSimulation struct {
+	RuleID   string `json:"ruleId,omitempty"`
+	RuleName string `json:"ruleName,omitempty"`
+	// Default is true for the default rule of the flag
+	Default bool `json:"default,omitempty"`
+	// Disabled is true if the rule is disabled, it does not serve any context
+	Disabled bool `json:"disabled,omitempty"`
+	// Matched is the number of contexts served by the rule
+	Matched int `json:"matched" example:"120"`
+	// Variations is the number of contexts getting each variation, the percentages are relative to Matched
+	Variations map[string]VariationCount `json:"variations"`
+	// ExpectedPercentages is the split the rule is configured to serve at the date of the simulation, it is the
+	// percentages of the rule or the split of the progressive rollout
+	ExpectedPercentages map[string]float64 `json:"expectedPercentages,omitempty"`
+	// RolloutPercentage is the percentage of users getting the end variation of the progressive rollout at the
+	// date of the simulation
+	RolloutPercentage *float64 `json:"rolloutPercentage,omitempty" example:"25"`
+}
+
+// Simulate evaluates the flags for every evaluation context and returns the distribution of the variations.
+// The date of the clock is used for all the evaluations, so a progressive rollout can be checked at a future date.
+func Simulate(flags []model.FeatureFlag, contexts []Context, clock util.Clock) Simulation {
+	now := clock.Now()
+	simulation := Simulation{Date: now, Contexts: len(contexts), Flags: make([]FlagSimulation, 0, len(flags))}
+	for _, flag := range flags {
+		simulation.Flags = append(simulation.Flags, simulateFlag(flag, contexts, now))
+	}
+	return simulation
+}
+
+func simulateFlag(flag model.FeatureFlag, contexts []Context, now time.Time) FlagSimulation {
+	rules := append(append([]model.Rule{}, flag.GetRules()...), flag.GetDefaultRule())
+	ruleCounts := make([]map[string]int, len(rules))
+	matched := make([]int, len(rules))
+	for i := range rules {
+		ruleCounts[i] = map[string]int{}
+	}
+	counts := map[string]int{}
+	simulation := FlagSimulation{ID: flag.ID, Flag: flag.Name, Reasons: map[string]int{}}
+
+	for _, evaluationContext := range contexts {
+		result, index := evaluate(flag, evaluationContext, now)
+		simulation.Reasons[result.Reason]++
+		if result.ErrorCode != "" {
+			if simulation.Errors == nil {
+				simulation.Errors = map[string]int{}
+			}
+			simulation.Errors[result.ErrorCode]++
+		}
+		if index >= 0 {
+			matched[index]++
+		}
+		if result.Variation == "" {
+			continue
+		}
+		counts[result.Variation]++
+		if index >= 0 {
+			ruleCounts[index][result.Variation]++
+		}
+	}
+
+	simulation.Variations = variationCounts(counts, len(contexts))
+	simulation.Rules = make([]RuleSimulation, 0, len(rules))
+	for i, rule := range rules {
+		ruleSimulation := RuleSimulation{
+			RuleID:     rule.ID,
+			RuleName:   rule.Name,
+			Default:    i == len(rules)-1,
+			Disabled:   rule.Disable,
+			Matched:    matched[i],
+			Variations: variationCounts(ruleCounts[i], matched[i]),
+		}
+		ruleSimulation.ExpectedPercentages, ruleSimulation.RolloutPercentage = expectedPercentages(rule, now)
+		simulation.Rules = append(simulation.Rules, ruleSimulation)
+	}
+	return simulation
+}
+
+// expectedPercentages returns the split a rule is configured to serve at a date, and the percentage of the
+// progressive rollout if the rule has one. It returns nil if the rule is invalid.
+func expectedPercentages(rule model.Rule, now time.Time) (map[string]float64, *float64) {
+	switch {
+	case rule.ProgressiveRollout != nil:
+		segment, err := currentSegment(*rule.ProgressiveRollout, now)
+		if err != nil {
+			return nil, nil
+		}
+		rolloutPercentage := roundPercentage(segmentPercentage(segment, now))
+		initialVariation, rolledOutVariation := *segment.Initial.Variation, *segment.End.Variation
+		percentages := map[string]float64{}
+		percentages[initialVariation] += roundPercentage(100 - rolloutPercentage)
+		percentages[rolledOutVariation] += rolloutPercentage
+		return percentages, &rolloutPercentage
+	case rule.VariationResult != nil && *rule.VariationResult != "":
+		return map[string]float64{*rule.VariationResult: 100}, nil
+	case rule.Percentages != nil:
+		percentages := make(map[string]float64, len(*rule.Percentages))
+		for name, percentage := range *rule.Percentages {
+			percentages[name] = percentage
+		}
+		return percentages, nil
+	default:
+		return nil, nil
+	}
+}
+
+func variationCounts(counts map[string]int, total int) map[string]VariationCount {
+	variations := make(map[string]VariationCount, len(counts))
+	for name, count := range counts {
+		variations[name] = VariationCount{Count: count, Percentage: roundPercentage(float64(count) * 100 / float64(total))}
+	}
+	return variations
+}
+
+// roundPercentage keeps 3 decimals like the percentages of the flags.
+func roundPercentage(percentage float64) float64 {
+	return math.Round(percentage*percentageMultiplier) / percentageMultiplier
+}
@@ -0,0 +1,122 @@
+package evaluation_test
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/evaluation"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"github.com/go-feature-flag/flag-management/server/util"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	flag := model.FeatureFlag{
+		ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+		Name:          "flag1",
+		VariationType: model.FlagTypeString,
+		Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+		Rules: &[]model.Rule{
+			{ID: "rule-1", Name: "beta", Query: `beta eq true`, VariationResult: testutils.String("B")},
+			{ID: "rule-2", Name: "french", Query: `country eq "FR"`, Percentages: &map[string]float64{"A": 30, "B": 70}},
+			{ID: "rule-3", Name: "disabled", Query: `country eq "DE"`, VariationResult: testutils.String("B"), Disable: true},
+		},
+		DefaultRule: &model.Rule{ID: "default", ProgressiveRollout: &model.ProgressiveRollout{
+			Initial: &model.ProgressiveRolloutStep{Variation: testutils.String("A"), Date: testutils.Time(now.Add(-10 * time.Hour))},
+			End:     &model.ProgressiveRolloutStep{Variation: testutils.String("B"), Date: testutils.Time(now.Add(30 * time.Hour))},
+		}},
+	}
+
+	// 1 user out of 4 is in beta, half of the others are in France and the rest goes to the default rule
+	contexts := make([]evaluation.Context, 0, 4001)
+	for i := 0; i < 4000; i++ {
+		attributes := map[string]interface{}{"beta": i%4 == 0, "country": "DE"}
+		if i%2 == 1 {
+			attributes["country"] = "FR"
+		}
+		contexts = append(contexts, evaluation.Context{TargetingKey: fmt.Sprintf("user-%d", i), Attributes: attributes})
+	}
+	contexts = append(contexts, evaluation.Context{Attributes: map[string]interface{}{"country": "FR"}})
+
+	simulation := evaluation.Simulate([]model.FeatureFlag{flag}, contexts, util.FixedClock{Date: now})
+	assert.Equal(t, now, simulation.Date)
+	assert.Equal(t, 4001, simulation.Contexts)
+	require.Len(t, simulation.Flags, 1)
+
+	got := simulation.Flags[0]
+	assert.Equal(t, "flag1", got.Flag)
+	assert.Equal(t, map[string]int{
+		evaluation.ReasonTargetingMatch:      1000,
+		evaluation.ReasonTargetingMatchSplit: 2000,
+		evaluation.ReasonSplit:               1000,
+		evaluation.ReasonError:               1,
+	}, got.Reasons)
+	assert.Equal(t, map[string]int{evaluation.ErrorTargetingKeyMissing: 1}, got.Errors)
+	assert.Equal(t, 4000, got.Variations["A"].Count+got.Variations["B"].Count)
+	require.Len(t, got.Rules, 4)
+
+	beta := got.Rules[0]
+	assert.Equal(t, "rule-1", beta.RuleID)
+	assert.Equal(t, 1000, beta.Matched)
+	assert.Equal(t, map[string]evaluation.VariationCount{"B": {Count: 1000, Percentage: 100}}, beta.Variations)
+	assert.Equal(t, map[string]float64{"B": 100}, beta.ExpectedPercentages)
+
+	french := got.Rules[1]
+	assert.Equal(t, 2001, french.Matched, "the context without targeting key is matched but not served")
+	assert.Equal(t, map[string]float64{"A": 30, "B": 70}, french.ExpectedPercentages)
+	assert.InDelta(t, 30, french.Variations["A"].Percentage, 3)
+	assert.InDelta(t, 70, french.Variations["B"].Percentage, 3)
+
+	disabled := got.Rules[2]
+	assert.True(t, disabled.Disabled)
+	assert.Equal(t, 0, disabled.Matched)
+
+	defaultRule := got.Rules[3]
+	assert.True(t, defaultRule.Default)
+	assert.Equal(t, 1000, defaultRule.Matched)
+	assert.Equal(t, testutils.Float64(25), defaultRule.RolloutPercentage)
+	assert.Equal(t, map[string]float64{"A": 75, "B": 25}, defaultRule.ExpectedPercentages)
+	assert.InDelta(t, 25, defaultRule.Variations["B"].Percentage, 3)
+}
+
+func TestSimulate_rolloutAtAnotherDate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	flag := model.FeatureFlag{
+		Name:          "flag1",
+		VariationType: model.FlagTypeString,
+		Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+		DefaultRule: &model.Rule{ProgressiveRollout: &model.ProgressiveRollout{
+			Initial: &model.ProgressiveRolloutStep{Variation: testutils.String("A"), Date: testutils.Time(now)},
+			End:     &model.ProgressiveRolloutStep{Variation: testutils.String("B"), Date: testutils.Time(now.Add(time.Hour))},
+		}},
+	}
+	contexts := []evaluation.Context{{TargetingKey: "user-1"}, {TargetingKey: "user-2"}}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want map[string]evaluation.VariationCount
+	}{
+		{
+			name: "before the rollout",
+			date: now.Add(-time.Hour),
+			want: map[string]evaluation.VariationCount{"A": {Count: 2, Percentage: 100}},
+		},
+		{
+			name: "after the rollout",
+			date: now.Add(2 * time.Hour),
+			want: map[string]evaluation.VariationCount{"B": {Count: 2, Percentage: 100}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulation := evaluation.Simulate([]model.FeatureFlag{flag}, contexts, util.FixedClock{Date: tt.date})
+			assert.Equal(t, tt.want, simulation.Flags[0].Variations)
+			assert.Equal(t, map[string]int{evaluation.ReasonSplit: 2}, simulation.Flags[0].Reasons)
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package flagconfig
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+)
+
+// Flag is the representation of a flag in the configuration file read by GO Feature Flag
+// (relay proxy and retrievers). In the file the flags are keyed by their name.
+type Flag struct {
+	// Variations are all the variations available for this flag.
+	Variations *map[string]interface{} `json:"variations,omitempty" yaml:"variations,omitempty" toml:"variations,omitempty"` // nolint: lll
+
+	// Rules is the list of targeting rules of the flag, they are evaluated in order.
+	Rules *[]Rule `json:"targeting,omitempty" yaml:"targeting,omitempty" toml:"targeting,omitempty"`
+
+	// BucketingKey defines a source for a dynamic targeting key
+	BucketingKey *string `json:"bucketingKey,omitempty" yaml:"bucketingKey,omitempty" toml:"bucketingKey,omitempty"`
+
+	// DefaultRule is the rule applied when no targeting rule is matching.
+	DefaultRule *Rule `json:"defaultRule,omitempty" yaml:"defaultRule,omitempty" toml:"defaultRule,omitempty"`
+
+	// Experimentation is the time window of an experimentation, outside of it the flag is considered disabled.
+	Experimentation *model.ExperimentationRollout `json:"experimentation,omitempty" yaml:"experimentation,omitempty" toml:"experimentation,omitempty"` // nolint: lll
+
+	// Metadata is a field containing information about your flag such as an issue tracker link, a description, etc ...
+	Metadata *map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty" toml:"metadata,omitempty"`
+
+	// Disable is true if the flag is disabled.
+	Disable *bool `json:"disable,omitempty" yaml:"disable,omitempty" toml:"disable,omitempty"`
+
+	// Version (optional) This field contains the version of the flag.
+	Version *string `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+
+	// TrackEvents is false if you don't want to export the data in your data exporter.
+	TrackEvents *bool `json:"trackEvents,omitempty" yaml:"trackEvents,omitempty" toml:"trackEvents,omitempty"`
+
+	// ScheduledRollout contains the changes applied to the flag at their date, it describes the progressive
+	// rollouts of more than 2 steps.
+	ScheduledRollout *[]ScheduledStep `json:"scheduledRollout,omitempty" yaml:"scheduledRollout,omitempty" toml:"scheduledRollout,omitempty"` // nolint: lll
+}
+
+// ScheduledStep is a change of the flag applied by GO Feature Flag from its date.
+// The API only uses it to change the progressive rollout of a rule, the other changes of a scheduled rollout
+// are not supported.
+type ScheduledStep struct {
+	// Rules contains the changes of the targeting rules, a rule is found by its name.
+	Rules *[]Rule `json:"targeting,omitempty" yaml:"targeting,omitempty" toml:"targeting,omitempty"`
+	// DefaultRule contains the changes of the default rule.
+	DefaultRule *Rule `json:"defaultRule,omitempty" yaml:"defaultRule,omitempty" toml:"defaultRule,omitempty"`
+	// Date is the date from which the change is applied.
+	Date *time.Time `json:"date,omitempty" yaml:"date,omitempty" toml:"date,omitempty"`
+}
+
+// Rule is the representation of a targeting rule or of the default rule in the configuration file.
+type Rule struct {
+	// Name of the rule, it is not used for the default rule
+	Name *string `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+	// Query represents an antlr query in the nikunjy/rules format, it is not used for the default rule
+	Query *string `json:"query,omitempty" yaml:"query,omitempty" toml:"query,omitempty"`
+	// VariationResult represents the variation name to use if the rule apply for the user.
+	VariationResult *string `json:"variation,omitempty" yaml:"variation,omitempty" toml:"variation,omitempty"`
+	// Percentages represents the percentage we should give to each variation.
+	Percentages *map[string]float64 `json:"percentage,omitempty" yaml:"percentage,omitempty" toml:"percentage,omitempty"`
+	// ProgressiveRollout ramps up the percentage of a variation over time.
+	ProgressiveRollout *model.ProgressiveRollout `json:"progressiveRollout,omitempty" yaml:"progressiveRollout,omitempty" toml:"progressiveRollout,omitempty"` // nolint: lll
+	// Disable indicates that this rule is disabled.
+	Disable *bool `json:"disable,omitempty" yaml:"disable,omitempty" toml:"disable,omitempty"`
+}
+
+// FromModel converts a flag of the API to its representation in the configuration file.
+// The fields that only exist in the management API (ID, dates, scope, ...) are dropped.
+// A progressive rollout of more than 2 steps is exported as its first segment, the next segments replace it
+// in a scheduled rollout at the date of their initial step.
+func FromModel(flag model.FeatureFlag) Flag {
+	res := Flag{
+		Variations:      flag.Variations,
+		BucketingKey:    flag.BucketingKey,
+		Experimentation: flag.Experimentation,
+		Metadata:        flag.Metadata,
+		Disable:         flag.Disable,
+		Version:         flag.Version,
+		TrackEvents:     flag.TrackEvents,
+	}
+	var scheduled []ScheduledStep
+	if flag.Rules != nil && len(*flag.Rules) > 0 {
+		rules := make([]Rule, 0, len(*flag.Rules))
+		for _, rule := range *flag.Rules {
+			exported, segments := fromModelRule(rule, false)
+			rules = append(rules, exported)
+			for _, segment := range segments {
+				scheduled = append(scheduled, ScheduledStep{
+					Rules: &[]Rule{{Name: exported.Name, ProgressiveRollout: &segment}},
+					Date:  segment.Initial.Date,
+				})
+			}
+		}
+		res.Rules = &rules
+	}
+	if flag.DefaultRule != nil {
+		defaultRule, segments := fromModelRule(*flag.DefaultRule, true)
+		res.DefaultRule = &defaultRule
+		for _, segment := range segments {
+			scheduled = append(scheduled, ScheduledStep{
+				DefaultRule: &Rule{ProgressiveRollout: &segment},
+				Date:        segment.Initial.Date,
+			})
+		}
+	}
+	if len(scheduled) > 0 {
+		// the relay proxy applies the steps in their order, so the last step reached sets the rollout of a rule
+		sort.SliceStable(scheduled, func(i, j int) bool { return scheduled[i].Date.Before(*scheduled[j].Date) })
+		res.ScheduledRollout = &scheduled
+	}
+	return res
+}
+
+// FromModelFlags converts a list of flags to the content of a configuration file, keyed by flag name.
+func FromModelFlags(flags []model.FeatureFlag) map[string]Flag {
+	res := make(map[string]Flag, len(flags))
+	for _, flag := range flags {
+		res[flag.Name] = FromModel(flag)
+	}
+	return res
+}
+
+// fromModelRule converts a rule, if its progressive rollout has more than 2 steps the rule gets the first segment
+// and the next segments are returned to be scheduled.
+func fromModelRule(rule model.Rule, isDefault bool) (Rule, []model.ProgressiveRollout) {
+	res := Rule{
+		VariationResult:    rule.VariationResult,
+		Percentages:        rule.Percentages,
+		ProgressiveRollout: rule.ProgressiveRollout,
+	}
+	if !isDefault {
+		if rule.Name != "" {
+			res.Name = &rule.Name
+		}
+		if rule.Query != "" {
+			res.Query = &rule.Query
+		}
+	}
+	if rule.Disable {
+		res.Disable = &rule.Disable
+	}
+
+	var scheduled []model.ProgressiveRollout
+	if rule.ProgressiveRollout != nil && len(rule.ProgressiveRollout.Steps) > 0 {
+		segments := rule.ProgressiveRollout.Segments()
+		if len(segments) > 0 {
+			res.ProgressiveRollout = &segments[0]
+		}
+		for _, segment := range segments[min(1, len(segments)):] {
+			// the steps are merged in the rollout of the rule, the percentages are set to override the
+			// ones of the previous segment
+			initial, end := *segment.Initial, *segment.End
+			initial.Percentage = percentageOrDefault(initial.Percentage, 0)
+			end.Percentage = percentageOrDefault(end.Percentage, 100)
+			scheduled = append(scheduled, model.ProgressiveRollout{Initial: &initial, End: &end})
+		}
+	}
+	return res, scheduled
+}
+
+func percentageOrDefault(percentage *float64, defaultValue float64) *float64 {
+	if percentage == nil {
+		return &defaultValue
+	}
+	return percentage
+}
+
+// ToModel converts a flag of the configuration file to a flag of the API.
+// The configuration file does not contain the type of the flag, it is inferred from the variations.
+// The IDs of the flag and of its rules are not set.
+func (f Flag) ToModel(name string) (model.FeatureFlag, error) {
+	flagType, err := InferFlagType(f.Variations)
+	if err != nil {
+		return model.FeatureFlag{}, fmt.Errorf("flag %s: %w", name, err)
+	}
+	res := model.FeatureFlag{
+		Name:            name,
+		VariationType:   flagType,
+		Variations:      f.Variations,
+		BucketingKey:    f.BucketingKey,
+		Experimentation: f.Experimentation,
+		Metadata:        f.Metadata,
+		Disable:         f.Disable,
+		Version:         f.Version,
+		TrackEvents:     f.TrackEvents,
+	}
+	if f.Rules != nil {
+		rules := make([]model.Rule, 0, len(*f.Rules))
+		for _, rule := range *f.Rules {
+			rules = append(rules, rule.toModel())
+		}
+		res.Rules = &rules
+	}
+	if f.DefaultRule != nil {
+		defaultRule := f.DefaultRule.toModel()
+		res.DefaultRule = &defaultRule
+	}
+	if err := f.toModelScheduledRollout(&res); err != nil {
+		return model.FeatureFlag{}, fmt.Errorf("flag %s: %w", name, err)
+	}
+	return res, nil
+}
+
+// toModelScheduledRollout adds the segments of the scheduled rollout to the progressive rollouts of the rules,
+// it is the reverse of the export of the progressive rollouts of more than 2 steps.
+func (f Flag) toModelScheduledRollout(flag *model.FeatureFlag) error {
+	if f.ScheduledRollout == nil {
+		return nil
+	}
+	steps := slices.Clone(*f.ScheduledRollout)
+	for i, step := range steps {
+		if step.Date == nil {
+			return fmt.Errorf("scheduledRollout[%d]: date is required", i)
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Date.Before(*steps[j].Date) })
+	for _, step := range steps {
+		if step.DefaultRule != nil {
+			if err := addSegment(flag.DefaultRule, *step.DefaultRule); err != nil {
+				return fmt.Errorf("scheduledRollout of the default rule: %w", err)
+			}
+		}
+		if step.Rules == nil {
+			continue
+		}
+		for _, change := range *step.Rules {
+			index := -1
+			if change.Name != nil {
+				index = slices.IndexFunc(flag.GetRules(), func(r model.Rule) bool { return r.Name == *change.Name })
+			}
+			if index < 0 {
+				return fmt.Errorf("scheduledRollout: the rules are found by their name, no rule is named %q",
+					change.GetName())
+			}
+			if err := addSegment(&(*flag.Rules)[index], change); err != nil {
+				return fmt.Errorf("scheduledRollout of the rule %s: %w", *change.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// addSegment adds the end of a segment of a scheduled rollout as the next step of the progressive rollout
+// of the rule.
+func addSegment(rule *model.Rule, change Rule) error {
+	rollout := change.ProgressiveRollout
+	if rule == nil || rule.ProgressiveRollout == nil || rollout == nil || rollout.End == nil ||
+		change.Query != nil || change.VariationResult != nil || change.Percentages != nil || change.Disable != nil {
+		return fmt.Errorf("only the changes of the progressive rollout of a rule are supported")
+	}
+	steps := slices.Clone(rule.ProgressiveRollout.GetSteps())
+	rule.ProgressiveRollout = model.NewProgressiveRollout(append(steps, *rollout.End))
+	return nil
+}
+
+// GetName returns the name of the rule or an empty string.
+func (r Rule) GetName() string {
+	if r.Name == nil {
+		return ""
+	}
+	return *r.Name
+}
+
+func (r Rule) toModel() model.Rule {
+	res := model.Rule{
+		VariationResult:    r.VariationResult,
+		Percentages:        r.Percentages,
+		ProgressiveRollout: r.ProgressiveRollout,
+	}
+	if r.Name != nil {
+		res.Name = *r.Name
+	}
+	if r.Query != nil {
+		res.Query = *r.Query
+	}
+	if r.Disable != nil {
+		res.Disable = *r.Disable
+	}
+	return res
+}
+
+// InferFlagType returns the type of flag matching all the variations.
+// Numbers are integers if all of them are integral, and JSON is used for objects and arrays.
+func InferFlagType(variations *map[string]interface{}) (model.FlagType, error) {
+	if variations == nil || len(*variations) == 0 {
+		return "", fmt.Errorf("variations are required to infer the type of the flag")
+	}
+
+	// we iterate in a stable order to always report the same variation in the errors
+	names := make([]string, 0, len(*variations))
+	for name := range *variations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var res model.FlagType
+	for _, name := range names {
+		current, err := variationType((*variations)[name])
+		if err != nil {
+			return "", fmt.Errorf("variation %s: %w", name, err)
+		}
+		switch {
+		case res == "" || res == current:
+			res = current
+		case isNumber(res) && isNumber(current):
+			res = model.FlagTypeDouble
+		default:
+			return "", fmt.Errorf("variation %s is a %s but other variations are %s", name, current, res)
+		}
+	}
+	return res, nil
+}
+
+func variationType(value interface{}) (model.FlagType, error) {
+	switch v := value.(type) {
+	case bool:
+		return model.FlagTypeBoolean, nil
+	case string:
+		return model.FlagTypeString, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return model.FlagTypeInteger, nil
+	case float32:
+		return floatType(float64(v)), nil
+	case float64:
+		return floatType(v), nil
+	case map[string]interface{}, []interface{}, []map[string]interface{}:
+		return model.FlagTypeJSON, nil
+	default:
+		return "", fmt.Errorf("type %T is not supported", value)
+	}
+}
+
+func floatType(value float64) model.FlagType {
+	if value == math.Trunc(value) {
+		return model.FlagTypeInteger
+	}
+	return model.FlagTypeDouble
+}
+
+func isNumber(flagType model.FlagType) bool {
+	return flagType == model.FlagTypeInteger || flagType == model.FlagTypeDouble
+}
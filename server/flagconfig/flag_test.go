@@ -0,0 +1,295 @@
+package flagconfig_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/flagconfig"
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromModel(t *testing.T) {
+	start := time.Date(2024, 10, 25, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		input model.FeatureFlag
+		want  flagconfig.Flag
+	}{
+		{
+			name: "should drop the fields only used by the management API",
+			input: model.FeatureFlag{
+				ID:             "926214f3-80c1-46e6-a913-b2d40b92a932",
+				Name:           "flag1",
+				Project:        "checkout",
+				Environment:    "production",
+				CreatedDate:    start,
+				LastModifiedBy: "foo",
+				Description:    testutils.String("description"),
+				VariationType:  model.FlagTypeBoolean,
+				Variations:     &map[string]interface{}{"enabled": true, "disabled": false},
+				DefaultRule:    &model.Rule{ID: "default-rule", Name: "defaultRule", VariationResult: testutils.String("disabled")},
+				Revision:       3,
+			},
+			want: flagconfig.Flag{
+				Variations:  &map[string]interface{}{"enabled": true, "disabled": false},
+				DefaultRule: &flagconfig.Rule{VariationResult: testutils.String("disabled")},
+			},
+		},
+		{
+			name: "should convert the targeting rules and keep their order",
+			input: model.FeatureFlag{
+				Name:         "flag1",
+				Variations:   &map[string]interface{}{"A": "a", "B": "b"},
+				BucketingKey: testutils.String("teamId"),
+				Rules: &[]model.Rule{
+					{ID: "rule-1", Name: "beta", Query: `beta eq true`, VariationResult: testutils.String("B")},
+					{ID: "rule-2", Query: `country eq "FR"`, Percentages: &map[string]float64{"A": 10, "B": 90}, Disable: true},
+				},
+				DefaultRule: &model.Rule{
+					ProgressiveRollout: &model.ProgressiveRollout{
+						Initial: &model.ProgressiveRolloutStep{Variation: testutils.String("A"), Date: &start},
+						End:     &model.ProgressiveRolloutStep{Variation: testutils.String("B"), Date: &end},
+					},
+				},
+				Experimentation: &model.ExperimentationRollout{Start: &start, End: &end},
+				Metadata:        &map[string]interface{}{"issue": "JIRA-1"},
+				Disable:         testutils.Bool(true),
+				Version:         testutils.String("1.0.0"),
+				TrackEvents:     testutils.Bool(false),
+			},
+			want: flagconfig.Flag{
+				Variations:   &map[string]interface{}{"A": "a", "B": "b"},
+				BucketingKey: testutils.String("teamId"),
+				Rules: &[]flagconfig.Rule{
+					{Name: testutils.String("beta"), Query: testutils.String(`beta eq true`), VariationResult: testutils.String("B")},
+					{
+						Query:       testutils.String(`country eq "FR"`),
+						Percentages: &map[string]float64{"A": 10, "B": 90},
+						Disable:     testutils.Bool(true),
+					},
+				},
+				DefaultRule: &flagconfig.Rule{
+					ProgressiveRollout: &model.ProgressiveRollout{
+						Initial: &model.ProgressiveRolloutStep{Variation: testutils.String("A"), Date: &start},
+						End:     &model.ProgressiveRolloutStep{Variation: testutils.String("B"), Date: &end},
+					},
+				},
+				Experimentation: &model.ExperimentationRollout{Start: &start, End: &end},
+				Metadata:        &map[string]interface{}{"issue": "JIRA-1"},
+				Disable:         testutils.Bool(true),
+				Version:         testutils.String("1.0.0"),
+				TrackEvents:     testutils.Bool(false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, flagconfig.FromModel(tt.input))
+		})
+	}
+}
+
+func TestFromModelFlags(t *testing.T) {
+	flags := flagconfig.FromModelFlags([]model.FeatureFlag{
+		{Name: "flag1", Version: testutils.String("1")},
+		{Name: "flag2", Version: testutils.String("2")},
+	})
+	assert.Equal(t, map[string]flagconfig.Flag{
+		"flag1": {Version: testutils.String("1")},
+		"flag2": {Version: testutils.String("2")},
+	}, flags)
+}
+
+func TestFlag_ToModel(t *testing.T) {
+	start := time.Date(2024, 10, 25, 0, 0, 0, 0, time.UTC)
+	flag := flagconfig.Flag{
+		Variations:   &map[string]interface{}{"A": "a", "B": "b"},
+		BucketingKey: testutils.String("teamId"),
+		Rules: &[]flagconfig.Rule{
+			{Name: testutils.String("beta"), Query: testutils.String(`beta eq true`), VariationResult: testutils.String("B")},
+			{Query: testutils.String(`country eq "FR"`), Percentages: &map[string]float64{"A": 10, "B": 90}, Disable: testutils.Bool(true)},
+		},
+		DefaultRule: &flagconfig.Rule{
+			ProgressiveRollout: &model.ProgressiveRollout{
+				Initial: &model.ProgressiveRolloutStep{Variation: testutils.String("A"), Date: &start},
+			},
+		},
+		Experimentation: &model.ExperimentationRollout{Start: &start},
+		Disable:         testutils.Bool(false),
+		Version:         testutils.String("1.0.0"),
+	}
+
+	got, err := flag.ToModel("flag1")
+	assert.NoError(t, err)
+	assert.Equal(t, model.FeatureFlag{
+		Name:          "flag1",
+		VariationType: model.FlagTypeString,
+		Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+		BucketingKey:  testutils.String("teamId"),
+		Rules: &[]model.Rule{
+			{Name: "beta", Query: `beta eq true`, VariationResult: testutils.String("B")},
+			{Query: `country eq "FR"`, Percentages: &map[string]float64{"A": 10, "B": 90}, Disable: true},
+		},
+		DefaultRule: &model.Rule{
+			ProgressiveRollout: &model.ProgressiveRollout{
+				Initial: &model.ProgressiveRolloutStep{Variation: testutils.String("A"), Date: &start},
+			},
+		},
+		Experimentation: &model.ExperimentationRollout{Start: &start},
+		Disable:         testutils.Bool(false),
+		Version:         testutils.String("1.0.0"),
+	}, got)
+
+	_, err = flagconfig.Flag{}.ToModel("flag2")
+	assert.EqualError(t, err, "flag flag2: variations are required to infer the type of the flag")
+}
+
+func TestInferFlagType(t *testing.T) {
+	tests := []struct {
+		name       string
+		variations map[string]interface{}
+		want       model.FlagType
+		wantErr    string
+	}{
+		{name: "boolean", variations: map[string]interface{}{"on": true, "off": false}, want: model.FlagTypeBoolean},
+		{name: "string", variations: map[string]interface{}{"A": "a", "B": "b"}, want: model.FlagTypeString},
+		{name: "integer from YAML", variations: map[string]interface{}{"A": 1, "B": 2}, want: model.FlagTypeInteger},
+		{name: "integer from JSON", variations: map[string]interface{}{"A": 1.0, "B": 2.0}, want: model.FlagTypeInteger},
+		{name: "double", variations: map[string]interface{}{"A": 1, "B": 2.5}, want: model.FlagTypeDouble},
+		{
+			name:       "json",
+			variations: map[string]interface{}{"A": map[string]interface{}{"a": 1}, "B": []interface{}{"b"}},
+			want:       model.FlagTypeJSON,
+		},
+		{
+			name:       "mixed types",
+			variations: map[string]interface{}{"A": "a", "B": true},
+			wantErr:    "variation B is a boolean but other variations are string",
+		},
+		{
+			name:       "unsupported type",
+			variations: map[string]interface{}{"A": nil},
+			wantErr:    "variation A: type <nil> is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := flagconfig.InferFlagType(&tt.variations)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromModel_progressiveRolloutSteps(t *testing.T) {
+	start := time.Date(2024, 10, 25, 0, 0, 0, 0, time.UTC)
+	steps := []model.ProgressiveRolloutStep{
+		{Variation: testutils.String("A"), Date: testutils.Time(start)},
+		{Variation: testutils.String("B"), Percentage: testutils.Float64(20), Date: testutils.Time(start.Add(24 * time.Hour))},
+		{Variation: testutils.String("B"), Percentage: testutils.Float64(50), Date: testutils.Time(start.Add(48 * time.Hour))},
+		{Variation: testutils.String("C"), Date: testutils.Time(start.Add(72 * time.Hour))},
+	}
+	flag := model.FeatureFlag{
+		Name:          "flag1",
+		VariationType: model.FlagTypeString,
+		Variations:    &map[string]interface{}{"A": "a", "B": "b", "C": "c"},
+		Rules: &[]model.Rule{
+			{Name: "beta", Query: `beta eq true`, ProgressiveRollout: &model.ProgressiveRollout{Steps: steps}},
+		},
+		DefaultRule: &model.Rule{ProgressiveRollout: &model.ProgressiveRollout{Steps: steps[:3]}},
+	}
+
+	content, err := flagconfig.Marshal(flagconfig.FromModelFlags([]model.FeatureFlag{flag}), flagconfig.FormatYAML)
+	assert.NoError(t, err)
+	assert.Equal(t, `flag1:
+  variations:
+    A: a
+    B: b
+    C: c
+  targeting:
+    - name: beta
+      query: beta eq true
+      progressiveRollout:
+        initial:
+          variation: A
+          date: 2024-10-25T00:00:00Z
+        end:
+          variation: B
+          percentage: 20
+          date: 2024-10-26T00:00:00Z
+  defaultRule:
+    progressiveRollout:
+      initial:
+        variation: A
+        date: 2024-10-25T00:00:00Z
+      end:
+        variation: B
+        percentage: 20
+        date: 2024-10-26T00:00:00Z
+  scheduledRollout:
+    - targeting:
+        - name: beta
+          progressiveRollout:
+            initial:
+              variation: A
+              percentage: 20
+              date: 2024-10-26T00:00:00Z
+            end:
+              variation: B
+              percentage: 50
+              date: 2024-10-27T00:00:00Z
+      date: 2024-10-26T00:00:00Z
+    - defaultRule:
+        progressiveRollout:
+          initial:
+            variation: A
+            percentage: 20
+            date: 2024-10-26T00:00:00Z
+          end:
+            variation: B
+            percentage: 50
+            date: 2024-10-27T00:00:00Z
+      date: 2024-10-26T00:00:00Z
+    - targeting:
+        - name: beta
+          progressiveRollout:
+            initial:
+              variation: B
+              percentage: 0
+              date: 2024-10-27T00:00:00Z
+            end:
+              variation: C
+              percentage: 100
+              date: 2024-10-28T00:00:00Z
+      date: 2024-10-27T00:00:00Z
+`, string(content))
+
+	// the scheduled rollout is read back as the steps of the rollouts
+	flags, err := flagconfig.Unmarshal(content, flagconfig.FormatYAML)
+	assert.NoError(t, err)
+	got, err := flags["flag1"].ToModel("flag1")
+	assert.NoError(t, err)
+	gotSteps := (*got.Rules)[0].ProgressiveRollout.Steps
+	assert.Len(t, gotSteps, 4)
+	assert.Equal(t, "C", *gotSteps[3].Variation)
+	assert.Equal(t, 100.0, *gotSteps[3].Percentage)
+	assert.Len(t, got.DefaultRule.ProgressiveRollout.Steps, 3)
+
+	_, err = flagconfig.Flag{
+		Variations:  &map[string]interface{}{"A": "a"},
+		DefaultRule: &flagconfig.Rule{VariationResult: testutils.String("A")},
+		ScheduledRollout: &[]flagconfig.ScheduledStep{{
+			Rules: &[]flagconfig.Rule{{Name: testutils.String("beta"), VariationResult: testutils.String("A")}},
+			Date:  testutils.Time(start),
+		}},
+	}.ToModel("flag2")
+	assert.EqualError(t, err, `flag flag2: scheduledRollout: the rules are found by their name, no rule is named "beta"`)
+}
@@ -0,0 +1,126 @@
+package flagconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// FormatFromValue converts a string to a Format
+func FormatFromValue(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	case "":
+		return "", errors.New("format is required")
+	default:
+		return "", fmt.Errorf("format %s not supported, use json, yaml or toml", value)
+	}
+}
+
+// FormatFromFileName returns the Format associated to the extension of a file (ex: flags.yaml).
+// It returns false if the extension is not supported.
+func FormatFromFileName(fileName string) (Format, bool) {
+	extension := strings.TrimPrefix(filepath.Ext(fileName), ".")
+	if extension == "" {
+		return "", false
+	}
+	format, err := FormatFromValue(extension)
+	return format, err == nil
+}
+
+// FormatFromMediaType returns the Format associated to a media type (ex: application/yaml).
+// The parameters of the media type are ignored, it returns false if the media type is not supported.
+func FormatFromMediaType(mediaType string) (Format, bool) {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "application/json", "text/json":
+		return FormatJSON, true
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, true
+	case "application/toml", "text/toml":
+		return FormatTOML, true
+	default:
+		return "", false
+	}
+}
+
+// ContentType returns the media type used to serve a file in this format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatYAML:
+		return "application/yaml"
+	case FormatTOML:
+		return "application/toml"
+	default:
+		return "application/json"
+	}
+}
+
+// Marshal encodes the flags in the given format, the flags are ordered by name.
+func Marshal(flags map[string]Flag, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(flags, "", "  ")
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(flags); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(flags); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("format %s not supported", format)
+	}
+}
+
+// Unmarshal decodes a configuration file in the given format, the flags are keyed by name.
+func Unmarshal(content []byte, format Format) (map[string]Flag, error) {
+	flags := map[string]Flag{}
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(content, &flags)
+	case FormatYAML:
+		err = yaml.Unmarshal(content, &flags)
+	case FormatTOML:
+		err = toml.Unmarshal(content, &flags)
+	default:
+		return nil, fmt.Errorf("format %s not supported", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
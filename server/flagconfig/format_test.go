@@ -0,0 +1,232 @@
+package flagconfig_test
+
+import (
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/flagconfig"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFromValue(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      flagconfig.Format
+		expectErr bool
+	}{
+		{input: "json", want: flagconfig.FormatJSON},
+		{input: "YAML", want: flagconfig.FormatYAML},
+		{input: "yml", want: flagconfig.FormatYAML},
+		{input: "toml", want: flagconfig.FormatTOML},
+		{input: "", expectErr: true},
+		{input: "xml", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := flagconfig.FormatFromValue(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatFromMediaType(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   flagconfig.Format
+		wantOk bool
+	}{
+		{input: "application/json", want: flagconfig.FormatJSON, wantOk: true},
+		{input: "application/json; charset=UTF-8", want: flagconfig.FormatJSON, wantOk: true},
+		{input: " application/x-yaml", want: flagconfig.FormatYAML, wantOk: true},
+		{input: "application/toml;q=0.9", want: flagconfig.FormatTOML, wantOk: true},
+		{input: "text/html", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := flagconfig.FormatFromMediaType(tt.input)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatFromFileName(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   flagconfig.Format
+		wantOk bool
+	}{
+		{input: "flags.json", want: flagconfig.FormatJSON, wantOk: true},
+		{input: "/tmp/flags.YML", want: flagconfig.FormatYAML, wantOk: true},
+		{input: "config/flags.toml", want: flagconfig.FormatTOML, wantOk: true},
+		{input: "flags.xml", wantOk: false},
+		{input: "flags", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := flagconfig.FormatFromFileName(tt.input)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	flags := map[string]flagconfig.Flag{
+		"flag1": {
+			Variations: &map[string]interface{}{"A": "a", "B": "b"},
+			Rules: &[]flagconfig.Rule{
+				{Name: testutils.String("beta"), Query: testutils.String(`beta eq true`), VariationResult: testutils.String("B")},
+			},
+			DefaultRule: &flagconfig.Rule{Percentages: &map[string]float64{"A": 10, "B": 90}},
+			Disable:     testutils.Bool(true),
+		},
+	}
+
+	tests := []struct {
+		format flagconfig.Format
+		want   string
+	}{
+		{
+			format: flagconfig.FormatJSON,
+			want: `{
+  "flag1": {
+    "variations": {
+      "A": "a",
+      "B": "b"
+    },
+    "targeting": [
+      {
+        "name": "beta",
+        "query": "beta eq true",
+        "variation": "B"
+      }
+    ],
+    "defaultRule": {
+      "percentage": {
+        "A": 10,
+        "B": 90
+      }
+    },
+    "disable": true
+  }
+}`,
+		},
+		{
+			format: flagconfig.FormatYAML,
+			want: `flag1:
+  variations:
+    A: a
+    B: b
+  targeting:
+    - name: beta
+      query: beta eq true
+      variation: B
+  defaultRule:
+    percentage:
+      A: 10
+      B: 90
+  disable: true
+`,
+		},
+		{
+			format: flagconfig.FormatTOML,
+			want: `[flag1]
+  disable = true
+  [flag1.variations]
+    A = "a"
+    B = "b"
+
+  [[flag1.targeting]]
+    name = "beta"
+    query = "beta eq true"
+    variation = "B"
+  [flag1.defaultRule]
+    [flag1.defaultRule.percentage]
+      A = 10.0
+      B = 90.0
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := flagconfig.Marshal(flags, tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := flagconfig.Marshal(flags, "xml")
+		assert.Error(t, err)
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	want := map[string]flagconfig.Flag{
+		"flag1": {
+			Variations: &map[string]interface{}{"A": "a", "B": "b"},
+			Rules: &[]flagconfig.Rule{
+				{Name: testutils.String("beta"), Query: testutils.String(`beta eq true`), VariationResult: testutils.String("B")},
+			},
+			DefaultRule: &flagconfig.Rule{Percentages: &map[string]float64{"A": 10, "B": 90}},
+			Disable:     testutils.Bool(true),
+		},
+	}
+
+	tests := []struct {
+		format  flagconfig.Format
+		content string
+	}{
+		{
+			format: flagconfig.FormatJSON,
+			content: `{"flag1": {"variations": {"A": "a", "B": "b"}, "disable": true,
+				"targeting": [{"name": "beta", "query": "beta eq true", "variation": "B"}],
+				"defaultRule": {"percentage": {"A": 10, "B": 90}}}}`,
+		},
+		{
+			format: flagconfig.FormatYAML,
+			content: `flag1:
+  variations:
+    A: a
+    B: b
+  targeting:
+    - name: beta
+      query: beta eq true
+      variation: B
+  defaultRule:
+    percentage:
+      A: 10
+      B: 90
+  disable: true
+`,
+		},
+		{
+			format: flagconfig.FormatTOML,
+			content: `[flag1]
+disable = true
+variations = { A = "a", B = "b" }
+targeting = [{ name = "beta", query = "beta eq true", variation = "B" }]
+defaultRule = { percentage = { A = 10, B = 90 } }
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := flagconfig.Unmarshal([]byte(tt.content), tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	t.Run("invalid file", func(t *testing.T) {
+		_, err := flagconfig.Unmarshal([]byte(`flag1: [`), flagconfig.FormatYAML)
+		assert.Error(t, err)
+	})
+}
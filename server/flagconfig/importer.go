@@ -0,0 +1,260 @@
+package flagconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"sort"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+)
+
+// ImportOptions describes where and how the flags of a configuration file are imported
+type ImportOptions struct {
+	// Project and Environment where the flags are imported
+	Project     string
+	Environment string
+	// Author is stored as LastModifiedBy of the flags created or updated
+	Author string
+	// Now is the date of the import
+	Now time.Time
+}
+
+// ImportResult describes the changes made (or that would be made) by an import
+type ImportResult struct {
+	// DryRun is true if nothing has been stored
+	DryRun bool `json:"dryRun" example:"false"`
+	// Created contains the names of the flags created by the import
+	Created []string `json:"created"`
+	// Updated contains the names of the existing flags modified by the import
+	Updated []string `json:"updated"`
+	// Unchanged contains the names of the existing flags that are identical to the ones of the file
+	Unchanged []string `json:"unchanged"`
+}
+
+// ImportPlan contains the changes to apply to the storage to import a configuration file
+type ImportPlan struct {
+	Creations []model.FeatureFlag
+	Updates   []model.FeatureFlag
+	Result    ImportResult
+}
+
+// Apply stores all the changes of the plan in a single transaction
+func (p ImportPlan) Apply(ctx context.Context, storage dao.FlagStorage) daoErr.DaoError {
+	return storage.ImportFlags(ctx, p.Creations, p.Updates)
+}
+
+// InvalidFlagsError is returned when some flags of a configuration file are invalid,
+// it contains one error per invalid flag.
+type InvalidFlagsError struct {
+	Errors []error
+}
+
+func (e InvalidFlagsError) Error() string {
+	return errors.Join(e.Errors...).Error()
+}
+
+// Unwrap returns the invalid fields of all the flags as model.ValidationErrors, each field is prefixed by the
+// name of its flag (ex: my-flag.defaultRule). It returns nil if the errors don't contain any invalid field.
+func (e InvalidFlagsError) Unwrap() error {
+	var res model.ValidationErrors
+	for _, err := range e.Errors {
+		var invalidFlag invalidFlagError
+		var fields model.ValidationErrors
+		if !errors.As(err, &invalidFlag) || !errors.As(invalidFlag.err, &fields) {
+			continue
+		}
+		for _, field := range fields {
+			field.Field = invalidFlag.name + "." + field.Field
+			res = append(res, field)
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// invalidFlagError is the error of a flag of a configuration file that cannot be stored
+type invalidFlagError struct {
+	name string
+	err  error
+}
+
+func (e invalidFlagError) Error() string {
+	return fmt.Sprintf("flag %s: %v", e.name, e.err)
+}
+
+func (e invalidFlagError) Unwrap() error {
+	return e.err
+}
+
+// PlanImport compares the flags of the file with the flags of the environment to know which ones should be
+// created or updated. It returns an InvalidFlagsError with the errors of all the invalid flags of the file.
+func PlanImport(
+	ctx context.Context, storage dao.FlagStorage, file map[string]Flag, options ImportOptions) (ImportPlan, error) {
+	plan := ImportPlan{Result: ImportResult{Created: []string{}, Updated: []string{}, Unchanged: []string{}}}
+	var invalidFlags []error
+	for _, name := range sortedNames(file) {
+		flag, err := file[name].ToModel(name)
+		if err != nil {
+			invalidFlags = append(invalidFlags, err)
+			continue
+		}
+		flag.Project, flag.Environment = options.Project, options.Environment
+		flag.LastUpdatedDate = options.Now
+		flag.LastModifiedBy = options.Author
+
+		existing, getErr := storage.GetFlagByName(ctx, options.Project, options.Environment, name)
+		switch {
+		case getErr == nil:
+			if err := keepFlagType(&flag, existing); err != nil {
+				invalidFlags = append(invalidFlags, invalidFlagError{name: name, err: err})
+				continue
+			}
+			if sameFlagConfig(existing, flag) {
+				plan.Result.Unchanged = append(plan.Result.Unchanged, name)
+				continue
+			}
+			mergeImportedFlag(&flag, existing)
+			plan.Updates = append(plan.Updates, flag)
+			plan.Result.Updated = append(plan.Result.Updated, name)
+		case getErr.Code() == daoErr.NotFound:
+			assignImportedFlagIDs(&flag)
+			flag.CreatedDate = options.Now
+			flag.Revision = 1
+			plan.Creations = append(plan.Creations, flag)
+			plan.Result.Created = append(plan.Result.Created, name)
+		default:
+			return ImportPlan{}, getErr
+		}
+
+		if err := flag.Validate(); err != nil {
+			invalidFlags = append(invalidFlags, invalidFlagError{name: name, err: err})
+		}
+	}
+	if len(invalidFlags) > 0 {
+		return ImportPlan{}, InvalidFlagsError{Errors: invalidFlags}
+	}
+	return plan, nil
+}
+
+// ValidateFile checks all the flags of a configuration file without any storage.
+// It returns an InvalidFlagsError with the errors of all the invalid flags of the file.
+func ValidateFile(file map[string]Flag) error {
+	var invalidFlags []error
+	for _, name := range sortedNames(file) {
+		flag, err := file[name].ToModel(name)
+		if err == nil {
+			err = flag.Validate()
+			if err != nil {
+				err = invalidFlagError{name: name, err: err}
+			}
+		}
+		if err != nil {
+			invalidFlags = append(invalidFlags, err)
+		}
+	}
+	if len(invalidFlags) > 0 {
+		return InvalidFlagsError{Errors: invalidFlags}
+	}
+	return nil
+}
+
+// Export returns the flags of an environment keyed by name, as they are written in a configuration file.
+func Export(
+	ctx context.Context, storage dao.FlagStorage, project string, environment string) (map[string]Flag, daoErr.DaoError) {
+	flags, _, err := storage.GetFlags(ctx, dao.FlagQuery{
+		Project:     project,
+		Environment: environment,
+		SortBy:      dao.FlagSortByName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FromModelFlags(flags), nil
+}
+
+func sortedNames(file map[string]Flag) []string {
+	names := make([]string, 0, len(file))
+	for name := range file {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keepFlagType checks that the imported flag does not change the type of the existing flag, the storages
+// never change the type of a flag. The numbers of a file without decimal part are inferred as integers,
+// so they keep the type of an existing double flag.
+func keepFlagType(flag *model.FeatureFlag, existing model.FeatureFlag) error {
+	switch {
+	case flag.VariationType == existing.VariationType:
+		return nil
+	case flag.VariationType == model.FlagTypeInteger && existing.VariationType == model.FlagTypeDouble:
+		flag.VariationType = existing.VariationType
+		return nil
+	default:
+		return model.ValidationErrors{{
+			Field: "variations",
+			Message: fmt.Sprintf("the variations are %s but the flag is %s, the type of a flag cannot be changed",
+				flag.VariationType, existing.VariationType),
+		}}
+	}
+}
+
+// sameFlagConfig returns true if the imported flag would not change the configuration of the existing flag.
+func sameFlagConfig(existing model.FeatureFlag, imported model.FeatureFlag) bool {
+	if existing.VariationType != imported.VariationType {
+		return false
+	}
+	// the values are compared once encoded because the numbers of a file and of the storage don't
+	// always have the same Go type
+	a, errA := json.Marshal(FromModel(existing))
+	b, errB := json.Marshal(FromModel(imported))
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// mergeImportedFlag keeps the fields of the existing flag that are not part of the configuration file.
+// The IDs of the rules are reused, so the storage updates the rules instead of recreating them.
+func mergeImportedFlag(flag *model.FeatureFlag, existing model.FeatureFlag) {
+	flag.ID = existing.ID
+	flag.CreatedDate = existing.CreatedDate
+	flag.Description = existing.Description
+	flag.Revision = existing.Revision
+
+	ruleIDs := map[string]string{}
+	for _, rule := range existing.GetRules() {
+		if rule.Name != "" {
+			ruleIDs[rule.Name] = rule.ID
+		}
+	}
+	for i, rule := range flag.GetRules() {
+		if id, ok := ruleIDs[rule.Name]; ok && rule.Name != "" {
+			(*flag.Rules)[i].ID = id
+			delete(ruleIDs, rule.Name)
+			continue
+		}
+		(*flag.Rules)[i].ID = uuid.NewString()
+	}
+	if flag.DefaultRule != nil {
+		flag.DefaultRule.ID = existing.GetDefaultRule().ID
+	}
+}
+
+// assignImportedFlagIDs generates the IDs of a new flag and of its rules.
+func assignImportedFlagIDs(flag *model.FeatureFlag) {
+	flag.ID = uuid.NewString()
+	for i := range flag.GetRules() {
+		(*flag.Rules)[i].ID = uuid.NewString()
+	}
+	if flag.DefaultRule != nil {
+		flag.DefaultRule.ID = uuid.NewString()
+	}
+}
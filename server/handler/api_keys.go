@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/util"
+	"net/http"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandlerOptions struct {
+	Clock util.Clock
+}
+
+type APIKeyHandler struct {
+	dao     dao.APIKeyStorage
+	options *APIKeyHandlerOptions
+}
+
+// NewAPIKeyHandler creates a new instance of the APIKeyHandler handler
+// It is a controller class to manage the API keys used to call the API
+func NewAPIKeyHandler(dao dao.APIKeyStorage, options *APIKeyHandlerOptions) APIKeyHandler {
+	if options == nil {
+		options = &APIKeyHandlerOptions{}
+	}
+	if options.Clock == nil {
+		options.Clock = util.DefaultClock{}
+	}
+	return APIKeyHandler{dao: dao, options: options}
+}
+
+type apiKeyListResponse struct {
+	APIKeys []model.APIKey `json:"apiKeys"`
+}
+
+type apiKeyCreationRequest struct {
+	// Name is a human-readable name to know where the key is used
+	Name string `json:"name" example:"github-actions"`
+	// Scopes are the permissions granted to the key (read, write or delete)
+	Scopes []string `json:"scopes" example:"read"`
+	// ExpiresAt is the date after which the key is not valid anymore, the key never expires if not set
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type apiKeyCreationResponse struct {
+	model.APIKey
+	// Key is the API key to use in the Authorization header, it is only returned once
+	Key string `json:"key" example:"goff_VGhpcyBpcyBub3QgYSByZWFsIGtleQ"`
+}
+
+// GetAPIKeys is returning the list of the API keys
+// @Summary      Return all the API keys
+// @Tags API Key management API
+// @Description  GET all the API keys, the most recent first. The keys themselves are never returned.
+// @Success      200  {object} apiKeyListResponse "Success"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/apikeys [get]
+func (h APIKeyHandler) GetAPIKeys(c echo.Context) error {
+	apiKeys, err := h.dao.GetAPIKeys(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, apiKeyListResponse{APIKeys: apiKeys})
+}
+
+// CreateAPIKey is creating a new API key
+// @Summary      Create a new API key
+// @Tags API Key management API
+// @Description  POST - Create a new API key, the key is returned only in this response so store it safely.
+// @Description  Use it in the header "Authorization: Bearer <key>" to call the API.
+// @Param 		 data body apiKeyCreationRequest true "Payload which represents the API key to create"
+// @Success      201  {object} apiKeyCreationResponse "Created"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/apikeys [post]
+func (h APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	var request apiKeyCreationRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	scopes, err := h.validateAPIKeyCreation(request)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	key, prefix, hashedKey, err := auth.GenerateAPIKey()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	apiKey := model.APIKey{
+		ID:          uuid.NewString(),
+		Name:        request.Name,
+		Prefix:      prefix,
+		HashedKey:   hashedKey,
+		Scopes:      scopes,
+		CreatedDate: h.options.Clock.Now(),
+		CreatedBy:   auth.PrincipalFromContext(c).String(),
+		ExpiresAt:   request.ExpiresAt,
+	}
+	if _, err := h.dao.CreateAPIKey(c.Request().Context(), apiKey); err != nil {
+		if err.Code() == daoErr.AlreadyExists {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusCreated, apiKeyCreationResponse{APIKey: apiKey, Key: key})
+}
+
+func (h APIKeyHandler) validateAPIKeyCreation(request apiKeyCreationRequest) ([]model.APIKeyScope, error) {
+	if request.Name == "" {
+		return nil, errors.New("api key name is required")
+	}
+	if len(request.Scopes) == 0 {
+		return nil, errors.New("api key should have at least one scope")
+	}
+	scopes := make([]model.APIKeyScope, 0, len(request.Scopes))
+	for _, value := range request.Scopes {
+		scope, err := model.APIKeyScopeFromValue(value)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(h.options.Clock.Now()) {
+		return nil, errors.New("api key expiration date should be in the future")
+	}
+	return scopes, nil
+}
+
+// DeleteAPIKeyByID is revoking the API key with the given ID
+// @Summary      Revoke the API key with the given ID
+// @Tags API Key management API
+// @Description  DELETE - Revoke the API key with the given ID, it cannot be used anymore.
+// @Param        id path string true "ID of the API key"
+// @Success      204 "No Content"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/apikeys/{id} [delete]
+func (h APIKeyHandler) DeleteAPIKeyByID(c echo.Context) error {
+	err := h.dao.DeleteAPIKeyByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		switch err.Code() {
+		case daoErr.NotFound:
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("api key not found"))
+		case daoErr.InvalidUUID:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid UUID format"))
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,241 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-feature-flag/flag-management/server/api"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/handler"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAPIKeyTestServer(t *testing.T, mockDao *dao.InMemoryMockDao) *api.Server {
+	hf := handler.NewFlagAPIHandler(mockDao, nil)
+	hh := handler.NewHealthHandler(mockDao)
+	ha := handler.NewAPIKeyHandler(mockDao, &handler.APIKeyHandlerOptions{Clock: testutils2.ClockMock{}})
+	s, err := api.New(&config.Configuration{
+		Mode: "development",
+	}, handler.Handlers{
+		FlagAPIHandler: &hf,
+		HealthHandler:  &hh,
+		APIKeyHandler:  &ha,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		body             string
+		expectedHTTPCode int
+		expectedBody     string
+	}{
+		{
+			name:             "should create an API key",
+			ctx:              context.Background(),
+			body:             `{"name":"ci","scopes":["read","write"],"expiresAt":"2021-01-01T00:00:00Z"}`,
+			expectedHTTPCode: http.StatusCreated,
+		},
+		{
+			name:             "should return a 400 if the name is missing",
+			ctx:              context.Background(),
+			body:             `{"scopes":["read"]}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"api key name is required","code":400}`,
+		},
+		{
+			name:             "should return a 400 if there is no scope",
+			ctx:              context.Background(),
+			body:             `{"name":"ci","scopes":[]}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"api key should have at least one scope","code":400}`,
+		},
+		{
+			name:             "should return a 400 if the scope is unknown",
+			ctx:              context.Background(),
+			body:             `{"name":"ci","scopes":["admin"]}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"api key scope admin not supported","code":400}`,
+		},
+		{
+			name:             "should return a 400 if the expiration date is in the past",
+			ctx:              context.Background(),
+			body:             `{"name":"ci","scopes":["read"],"expiresAt":"2019-01-01T00:00:00Z"}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"api key expiration date should be in the future","code":400}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails",
+			ctx:              context.WithValue(context.Background(), "error_create", daoErr.UnknownError),
+			body:             `{"name":"ci","scopes":["read"]}`,
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error creating api key","code":500}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			s := newAPIKeyTestServer(t, mockDao)
+
+			req := httptest.NewRequestWithContext(tt.ctx, http.MethodPost, "/v1/apikeys", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+				return
+			}
+
+			var created struct {
+				model.APIKey
+				Key string `json:"key"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+			assert.True(t, auth.IsAPIKey(created.Key))
+			assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+			assert.Equal(t, "ci", created.Name)
+			assert.Equal(t, "anonymous", created.CreatedBy)
+			assert.Equal(t, testutils2.ClockMock{}.Now(), created.CreatedDate)
+			assert.NotContains(t, rec.Body.String(), auth.HashAPIKey(created.Key))
+
+			stored, daoErr := mockDao.GetAPIKeyByHashedKey(context.Background(), auth.HashAPIKey(created.Key))
+			require.Nil(t, daoErr)
+			assert.Equal(t, created.ID, stored.ID)
+			assert.Equal(t, []model.APIKeyScope{model.APIKeyScopeRead, model.APIKeyScopeWrite}, stored.Scopes)
+		})
+	}
+}
+
+func TestAPIKeyHandler_GetAPIKeys(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		expectedHTTPCode int
+		expectedBody     string
+	}{
+		{
+			name:             "should return the API keys without their hash",
+			ctx:              context.Background(),
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"apiKeys":[{"id":"926214f3-80c1-46e6-a913-b2d40b92a002","name":"deploy","prefix":"goff_def","scopes":["write"],"createdDate":"2024-10-26T11:50:27Z","createdBy":"foo"},{"id":"926214f3-80c1-46e6-a913-b2d40b92a001","name":"ci","prefix":"goff_abc","scopes":["read"],"createdDate":"2024-10-25T11:50:27Z","createdBy":"foo","expiresAt":"2025-10-25T11:50:27Z"}]}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails",
+			ctx:              context.WithValue(context.Background(), "error", daoErr.UnknownError),
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on get api keys","code":500}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			expiresAt := time.Date(2025, 10, 25, 11, 50, 27, 0, time.UTC)
+			_, _ = mockDao.CreateAPIKey(context.Background(), model.APIKey{
+				ID:          "926214f3-80c1-46e6-a913-b2d40b92a001",
+				Name:        "ci",
+				Prefix:      "goff_abc",
+				HashedKey:   "hash1",
+				Scopes:      []model.APIKeyScope{model.APIKeyScopeRead},
+				CreatedDate: time.Date(2024, 10, 25, 11, 50, 27, 0, time.UTC),
+				CreatedBy:   "foo",
+				ExpiresAt:   &expiresAt,
+			})
+			_, _ = mockDao.CreateAPIKey(context.Background(), model.APIKey{
+				ID:          "926214f3-80c1-46e6-a913-b2d40b92a002",
+				Name:        "deploy",
+				Prefix:      "goff_def",
+				HashedKey:   "hash2",
+				Scopes:      []model.APIKeyScope{model.APIKeyScopeWrite},
+				CreatedDate: time.Date(2024, 10, 26, 11, 50, 27, 0, time.UTC),
+				CreatedBy:   "foo",
+			})
+			s := newAPIKeyTestServer(t, mockDao)
+
+			req := httptest.NewRequestWithContext(tt.ctx, http.MethodGet, "/v1/apikeys", nil)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestAPIKeyHandler_DeleteAPIKeyByID(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		id               string
+		expectedHTTPCode int
+		expectedBody     string
+	}{
+		{
+			name:             "should revoke the API key",
+			ctx:              context.Background(),
+			id:               "926214f3-80c1-46e6-a913-b2d40b92a001",
+			expectedHTTPCode: http.StatusNoContent,
+		},
+		{
+			name:             "should return a 404 if the API key does not exist",
+			ctx:              context.Background(),
+			id:               "926214f3-80c1-46e6-a913-b2d40b92a999",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"api key not found","code":404}`,
+		},
+		{
+			name:             "should return a 400 if the ID is not a UUID",
+			ctx:              context.WithValue(context.Background(), "error_delete", daoErr.InvalidUUID),
+			id:               "invalid",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"invalid UUID format","code":400}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails",
+			ctx:              context.WithValue(context.Background(), "error_delete", daoErr.UnknownError),
+			id:               "926214f3-80c1-46e6-a913-b2d40b92a001",
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on delete api key","code":500}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			_, _ = mockDao.CreateAPIKey(context.Background(), model.APIKey{
+				ID:        "926214f3-80c1-46e6-a913-b2d40b92a001",
+				Name:      "ci",
+				HashedKey: "hash1",
+				Scopes:    []model.APIKeyScope{model.APIKeyScopeRead},
+			})
+			s := newAPIKeyTestServer(t, mockDao)
+
+			req := httptest.NewRequestWithContext(tt.ctx, http.MethodDelete, "/v1/apikeys/"+tt.id, nil)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			if tt.expectedBody == "" {
+				assert.Empty(t, rec.Body.String())
+				apiKeys, _ := mockDao.GetAPIKeys(context.Background())
+				assert.Empty(t, apiKeys)
+				return
+			}
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+	headerIfMatch     = "If-Match"
+)
+
+var errFlagModified = errors.New("the flag has been modified since you retrieved it")
+
+// flagETag computes the entity tag of a flag based on its content, it changes every time the flag is updated.
+func flagETag(flag model.FeatureFlag) string {
+	content, err := json.Marshal(flag)
+	if err != nil {
+		return computeETag(flag.ID, flag.LastUpdatedDate.UTC().Format(time.RFC3339Nano))
+	}
+	return computeETag(string(content))
+}
+
+// flagListETag computes the entity tag of a list of flags.
+// It is based on the request parameters and on the summary of the flags matching the filters, so we
+// don't have to load the flags to know if something has changed.
+func flagListETag(c echo.Context, summary dao.FlagsSummary) string {
+	return computeETag(
+		c.QueryParams().Encode(),
+		fmt.Sprintf("%d", summary.Total),
+		summary.LastUpdatedDate.UTC().Format(time.RFC3339Nano),
+		fmt.Sprintf("%d", summary.VersionCount))
+}
+
+func computeETag(values ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(values, "|")))
+	return `"` + hex.EncodeToString(h[:16]) + `"`
+}
+
+// notModified sets the ETag header of the response and returns true if the If-None-Match header of the
+// request matches the ETag, in that case the caller should answer with a 304 Not Modified.
+func notModified(c echo.Context, etag string) bool {
+	c.Response().Header().Set(headerETag, etag)
+	return etagMatch(c.Request().Header.Get(headerIfNoneMatch), etag)
+}
+
+// etagMatch checks if the etag is part of the list of entity tags of a conditional header.
+// Weak entity tags are compared as strong ones, as it is done for If-None-Match.
+func etagMatch(header string, etag string) bool {
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch verifies that the If-Match header of the request (if any) matches the current flag,
+// otherwise it returns a 412 Precondition Failed.
+func checkIfMatch(c echo.Context, current model.FeatureFlag) error {
+	ifMatch := c.Request().Header.Get(headerIfMatch)
+	if ifMatch != "" && !etagMatch(ifMatch, flagETag(current)) {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, errFlagModified)
+	}
+	return nil
+}
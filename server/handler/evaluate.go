@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/go-feature-flag/flag-management/server/evaluation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EvaluateFlag is returning the variation a user would get for the flag with the given ID
+// @Summary      Preview the evaluation of a flag for a user
+// @Tags Feature Flag management API
+// @Description  POST an evaluation context (targeting key and attributes) to know which rule matches, which variation
+// @Description  and value the user gets and why. The stored flag is evaluated like in the relay proxy, with the
+// @Description  percentages of the progressive rollouts computed at the current date. Nothing is modified.
+// @Param        id path string true "ID of the feature flag"
+// @Param 		 data body evaluation.Context true "Evaluation context of the user"
+// @Success      200  {object} evaluation.Result "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/flags/{id}/evaluate [post]
+func (f FlagAPIHandler) EvaluateFlag(c echo.Context) error {
+	var evaluationContext evaluation.Context
+	if err := c.Bind(&evaluationContext); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	flag, err := f.dao.GetFlagByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return f.handleDaoError(c, err)
+	}
+	if !inRouteScope(c, flag) {
+		return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+	}
+	return c.JSON(http.StatusOK, evaluation.Evaluate(flag, evaluationContext, f.options.Clock.Now()))
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"github.com/go-feature-flag/flag-management/server/model"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagsHandler_EvaluateFlag(t *testing.T) {
+	flags := []model.FeatureFlag{
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+			Name:          "flag1",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			Rules: &[]model.Rule{
+				{
+					ID:              "926214f3-80c1-46e6-a913-b2d40b92a100",
+					Name:            "beta",
+					Query:           `beta eq true`,
+					VariationResult: testutils2.String("B"),
+				},
+			},
+			DefaultRule: &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils2.String("A")},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		path             string
+		body             string
+		expectedHTTPCode int
+		expectedBody     string
+	}{
+		{
+			name:             "should return the variation of the matching rule",
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/evaluate",
+			body:             `{"targetingKey":"user-1","attributes":{"beta":true}}`,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody: `{"flag":"flag1","variation":"B","value":"b","reason":"TARGETING_MATCH",
+				"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a100","ruleName":"beta",
+				"explanation":"the rule beta matched the query beta eq true"}`,
+		},
+		{
+			name:             "should return the default variation if no rule matches",
+			path:             "/v1/projects/default/environments/default/flags/926214f3-80c1-46e6-a913-b2d40b92a932/evaluate",
+			body:             `{"targetingKey":"user-1"}`,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody: `{"flag":"flag1","variation":"A","value":"a","reason":"DEFAULT",
+				"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a000",
+				"explanation":"no targeting rule matched, the default rule is applied"}`,
+		},
+		{
+			name:             "should return a 404 if the flag is not in the environment of the route",
+			path:             "/v1/projects/checkout/environments/staging/flags/926214f3-80c1-46e6-a913-b2d40b92a932/evaluate",
+			body:             `{"targetingKey":"user-1"}`,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+		{
+			name:             "should return a 404 if the flag does not exist",
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a999/evaluate",
+			body:             `{"targetingKey":"user-1"}`,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+		{
+			name:             "should return a 400 if the body is invalid",
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/evaluate",
+			body:             `{"targetingKey":`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"code=400, message=unexpected EOF, internal=unexpected EOF","code":400}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			mockDao.SetFlags(append([]model.FeatureFlag{}, flags...))
+			s := newProjectTestServer(t, mockDao)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code, rec.Body.String())
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
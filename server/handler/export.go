@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/flagconfig"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ExportFlags is returning all the flags of an environment in the GO Feature Flag configuration format
+// @Summary      Export the flags in the GO Feature Flag configuration format
+// @Tags Feature Flag management API
+// @Description  GET all the flags of an environment keyed by name, in the format read by the GO Feature Flag
+// @Description  relay proxy and retrievers.
+// @Description  The format is selected with the format query parameter or with the Accept header (JSON by default).
+// @Produce      json
+// @Produce      application/yaml
+// @Produce      application/toml
+// @Param        project query string false "Project of the flags to export (default: default)"
+// @Param        environment query string false "Environment of the flags to export (default: default)"
+// @Param        format query string false "Format of the file (json, yaml or toml), it takes precedence over the Accept header"
+// @Success      200  {object} map[string]flagconfig.Flag "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found - the environment does not exist"
+// @Failure      406 {object} api.CustomErr "Not Acceptable - none of the media types of the Accept header is supported"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/export [get]
+func (f FlagAPIHandler) ExportFlags(c echo.Context) error {
+	format, err := exportFormat(c)
+	if err != nil {
+		return err
+	}
+
+	project, environment := queryScope(c)
+	if err := f.checkEnvironmentExists(c, project, environment); err != nil {
+		return err
+	}
+
+	flags, daoErr := flagconfig.Export(c.Request().Context(), f.dao, project, environment)
+	if daoErr != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, daoErr)
+	}
+
+	content, err := flagconfig.Marshal(flags, format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.Blob(http.StatusOK, format.ContentType(), content)
+}
+
+// exportFormat selects the format of the export, the format query parameter takes precedence over the
+// Accept header. If nothing is specified we use JSON.
+func exportFormat(c echo.Context) (flagconfig.Format, error) {
+	if value := c.QueryParam("format"); value != "" {
+		format, err := flagconfig.FormatFromValue(value)
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+		return format, nil
+	}
+
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+	if strings.TrimSpace(accept) == "" {
+		return flagconfig.FormatJSON, nil
+	}
+	for _, mediaType := range strings.Split(accept, ",") {
+		if format, ok := flagconfig.FormatFromMediaType(mediaType); ok {
+			return format, nil
+		}
+		mediaType = strings.TrimSpace(strings.Split(mediaType, ";")[0])
+		if mediaType == "*/*" || mediaType == "application/*" {
+			return flagconfig.FormatJSON, nil
+		}
+	}
+	return "", echo.NewHTTPError(http.StatusNotAcceptable,
+		fmt.Errorf("media type %s not supported, use application/json, application/yaml or application/toml", accept))
+}
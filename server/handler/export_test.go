@@ -0,0 +1,167 @@
+package handler_test
+
+import (
+	"context"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagsHandler_ExportFlags(t *testing.T) {
+	flags := []model.FeatureFlag{
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+			Name:          "flag2",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			Rules: &[]model.Rule{
+				{ID: "rule-1", Name: "beta", Query: `beta eq true`, VariationResult: testutils2.String("B")},
+			},
+			DefaultRule: &model.Rule{ID: "rule-2", VariationResult: testutils2.String("A")},
+		},
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a933",
+			Name:          "flag1",
+			VariationType: model.FlagTypeBoolean,
+			Variations:    &map[string]interface{}{"on": true, "off": false},
+			DefaultRule:   &model.Rule{ID: "rule-3", VariationResult: testutils2.String("off")},
+			Disable:       testutils2.Bool(true),
+		},
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a934",
+			Name:          "flag1",
+			Project:       "checkout",
+			Environment:   "production",
+			VariationType: model.FlagTypeBoolean,
+			Variations:    &map[string]interface{}{"on": true, "off": false},
+			DefaultRule:   &model.Rule{ID: "rule-4", VariationResult: testutils2.String("on")},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		ctx                 context.Context
+		path                string
+		accept              string
+		expectedHTTPCode    int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "should export the flags of the default environment in JSON by default",
+			path:                "/v1/export",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: `{
+  "flag1": {"variations": {"off": false, "on": true}, "defaultRule": {"variation": "off"}, "disable": true},
+  "flag2": {
+    "variations": {"A": "a", "B": "b"},
+    "targeting": [{"name": "beta", "query": "beta eq true", "variation": "B"}],
+    "defaultRule": {"variation": "A"}
+  }
+}`,
+		},
+		{
+			name:                "should export the flags of the environment in the query",
+			path:                "/v1/export?project=checkout&environment=production",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `{"flag1": {"variations": {"off": false, "on": true}, "defaultRule": {"variation": "on"}}}`,
+		},
+		{
+			name:                "should use the format of the Accept header",
+			path:                "/v1/export?project=checkout&environment=production",
+			accept:              "text/html, application/yaml;q=0.9",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: "application/yaml",
+			expectedBody: `flag1:
+  variations:
+    "off": false
+    "on": true
+  defaultRule:
+    variation: "on"
+`,
+		},
+		{
+			name:                "should use the format query parameter before the Accept header",
+			path:                "/v1/export?project=checkout&environment=production&format=toml",
+			accept:              "application/yaml",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: "application/toml",
+			expectedBody: `[flag1]
+  [flag1.variations]
+    off = false
+    on = true
+  [flag1.defaultRule]
+    variation = "on"
+`,
+		},
+		{
+			name:                "should use JSON if the client accepts anything",
+			path:                "/v1/export?project=checkout&environment=production",
+			accept:              "*/*",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `{"flag1": {"variations": {"off": false, "on": true}, "defaultRule": {"variation": "on"}}}`,
+		},
+		{
+			name:             "should return a 400 if the format is not supported",
+			path:             "/v1/export?format=xml",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"format xml not supported, use json, yaml or toml","code":400}`,
+		},
+		{
+			name:             "should return a 406 if no media type of the Accept header is supported",
+			path:             "/v1/export",
+			accept:           "text/html",
+			expectedHTTPCode: http.StatusNotAcceptable,
+			expectedBody:     `{"errorDetails":"media type text/html not supported, use application/json, application/yaml or application/toml","code":406}`,
+		},
+		{
+			name:             "should return a 404 if the environment does not exist",
+			path:             "/v1/export?project=checkout&environment=qa",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"environment qa not found in project checkout","code":404}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails",
+			ctx:              context.WithValue(context.Background(), "error", daoErr.UnknownError),
+			path:             "/v1/export",
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on get environment","code":500}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			mockDao.SetFlags(flags)
+			s := newProjectTestServer(t, mockDao)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req := httptest.NewRequestWithContext(ctx, http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code, rec.Body.String())
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+			}
+			switch tt.expectedContentType {
+			case "application/json", "":
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			default:
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/labstack/echo/v4"
+)
+
+// parseFlagQuery reads the pagination, sort and filter query parameters of the request.
+// By default, the flags are sorted by last updated date, the most recent first.
+func parseFlagQuery(c echo.Context) (dao.FlagQuery, error) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return dao.FlagQuery{}, err
+	}
+	query := dao.FlagQuery{
+		Limit:      limit,
+		Offset:     offset,
+		SortBy:     dao.FlagSortByLastUpdatedDate,
+		SortDesc:   true,
+		NamePrefix: c.QueryParam("name"),
+	}
+
+	// on the scoped routes, the filters on the project and environment come from the path
+	query.Project, query.Environment = routeScope(c)
+	if query.Project == "" {
+		query.Project, query.Environment = c.QueryParam("project"), c.QueryParam("environment")
+	}
+
+	if value := c.QueryParam("sort"); value != "" {
+		query.SortDesc = strings.HasPrefix(value, "-")
+		query.SortBy, err = dao.FlagSortFieldFromValue(strings.TrimPrefix(value, "-"))
+		if err != nil {
+			return dao.FlagQuery{}, echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+	}
+
+	if value := c.QueryParam("type"); value != "" {
+		query.Type, err = model.FlagTypeFromValue(value)
+		if err != nil {
+			return dao.FlagQuery{}, echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+	}
+
+	if value := c.QueryParam("disabled"); value != "" {
+		disabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return dao.FlagQuery{}, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Errorf("disabled should be a boolean"))
+		}
+		query.Disabled = &disabled
+	}
+
+	query.MetadataKey = c.QueryParam("metadataKey")
+	if c.QueryParams().Has("metadataValue") {
+		if query.MetadataKey == "" {
+			return dao.FlagQuery{}, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Errorf("metadataValue can only be used with metadataKey"))
+		}
+		value := c.QueryParam("metadataValue")
+		query.MetadataValue = &value
+	}
+	return query, nil
+}
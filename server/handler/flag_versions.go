@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"net/http"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/labstack/echo/v4"
+)
+
+type flagVersionListResponse struct {
+	Versions []model.FlagVersion `json:"versions"`
+	Total    int                 `json:"total" example:"42"`
+	Limit    int                 `json:"limit" example:"20"`
+	Offset   int                 `json:"offset" example:"0"`
+}
+
+// GetFlagVersions is returning the history of the flag with the given ID
+// @Summary      Get a paginated list of available versions for a feature flag
+// @Tags Feature Flag management API
+// @Description  GET all the recorded versions of a flag, the most recent first.
+// @Description  A new version is recorded every time the flag is created, updated, or deleted.
+// @Param        id path string true "ID of the feature flag"
+// @Param        limit query int false "Maximum number of versions to return (default 20, max 100)"
+// @Param        offset query int false "Number of versions to skip before starting to collect the result set"
+// @Success      200  {object} flagVersionListResponse "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/flags/{id}/versions [get]
+func (f FlagAPIHandler) GetFlagVersions(c echo.Context) error {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return err
+	}
+
+	versions, total, daoErr := f.dao.GetFlagVersions(c.Request().Context(), c.Param("id"), limit, offset)
+	if daoErr != nil {
+		return f.handleDaoError(c, daoErr)
+	}
+	// the versions of a flag are always recorded in the same environment, we check it with the first one
+	if len(versions) > 0 && !inRouteScope(c, versions[0].Flag) {
+		return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+	}
+	return c.JSON(http.StatusOK, flagVersionListResponse{
+		Versions: versions,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	})
+}
+
+// RestoreFlagVersion is restoring the flag with the given ID to a previous version
+// @Summary      Restore a feature flag to a previous version
+// @Tags Feature Flag management API
+// @Description  POST - Rebuild the flag from the given version and save it as the current state of the flag.
+// @Description  The restoration is a new write on the flag, so it creates a new version in the history.
+// @Param        id path string true "ID of the feature flag"
+// @Param        versionId path string true "ID of the version to restore"
+// @Param        If-Match header string false "ETag of the flag you have retrieved, we return a 412 if the flag has changed since"
+// @Success      200  {object} model.FeatureFlag "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      409 {object} api.CustomErr "Conflict - when another flag is already using the name of the version"
+// @Failure      412 {object} api.CustomErr "Precondition Failed - the flag has been modified since you retrieved it"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/flags/{id}/versions/{versionId}/restore [post]
+func (f FlagAPIHandler) RestoreFlagVersion(c echo.Context) error {
+	ctx := c.Request().Context()
+	version, err := f.dao.GetFlagVersion(ctx, c.Param("id"), c.Param("versionId"))
+	if err != nil {
+		if err.Code() == daoErr.NotFound {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("version not found"))
+		}
+		return f.handleDaoError(c, err)
+	}
+
+	currentFlag, err := f.dao.GetFlagByID(ctx, version.FlagID)
+	if err != nil {
+		return f.handleDaoError(c, err)
+	}
+	if !inRouteScope(c, currentFlag) {
+		return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+	}
+	if err := checkIfMatch(c, currentFlag); err != nil {
+		return err
+	}
+
+	if version.Flag.Name != currentFlag.Name {
+		flagWithSameName, err := f.dao.GetFlagByName(
+			ctx, currentFlag.GetProject(), currentFlag.GetEnvironment(), version.Flag.Name)
+		if err == nil && flagWithSameName.ID != currentFlag.ID {
+			return echo.NewHTTPError(http.StatusConflict,
+				fmt.Errorf("flag with name %s already exists", version.Flag.Name))
+		}
+		if err != nil && err.Code() != daoErr.NotFound {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+	}
+
+	restoredFlag := version.Flag
+	restoredFlag.ID = currentFlag.ID
+	restoredFlag.Project = currentFlag.Project
+	restoredFlag.Environment = currentFlag.Environment
+	restoredFlag.CreatedDate = currentFlag.CreatedDate
+	restoredFlag.LastUpdatedDate = f.options.Clock.Now()
+	restoredFlag.LastModifiedBy = auth.PrincipalFromContext(c).String()
+	restoredFlag.Revision = currentFlag.Revision
+	if code, err := validateFlag(restoredFlag); err != nil {
+		return echo.NewHTTPError(code, err)
+	}
+
+	if err := f.dao.UpdateFlag(ctx, restoredFlag); err != nil {
+		return f.handleDaoError(c, err)
+	}
+	restoredFlag.Revision++
+	c.Response().Header().Set(headerETag, flagETag(restoredFlag))
+	return c.JSON(http.StatusOK, restoredFlag)
+}
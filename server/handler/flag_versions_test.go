@@ -0,0 +1,283 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/api"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/handler"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagsHandler_GetFlagVersions(t *testing.T) {
+	flagID := "926214f3-80c1-46e6-a913-b2d40b92a932"
+	flagV1 := testutils2.DefaultInMemoryFlags()[0]
+	flagV2 := testutils2.DefaultInMemoryFlags()[0]
+	flagV2.Disable = testutils2.Bool(true)
+	flagV2.LastUpdatedDate = time.Date(2024, 10, 26, 11, 50, 27, 0, time.UTC)
+	flagV2.Revision = 1
+
+	type test struct {
+		name             string
+		ctx              context.Context
+		query            string
+		id               string
+		expectedHTTPCode int
+		expectedBody     string
+	}
+	tests := []test{
+		{
+			name:             "should return all the versions of the flag, the most recent first",
+			ctx:              context.Background(),
+			id:               flagID,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"versions":[{"id":"926214f3-80c1-46e6-a913-b2d40b92a932-2","flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","versionNumber":2,"action":"updated","createdDate":"2024-10-26T11:50:27Z","flag":{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-26T11:50:27Z","revision":2,"LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a932-1","flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","versionNumber":1,"action":"created","createdDate":"2024-10-25T11:50:27Z","flag":{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","revision":1,"LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}}],"total":2,"limit":20,"offset":0}`,
+		},
+		{
+			name:             "should paginate the versions",
+			ctx:              context.Background(),
+			id:               flagID,
+			query:            "?limit=1&offset=1",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"versions":[{"id":"926214f3-80c1-46e6-a913-b2d40b92a932-1","flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","versionNumber":1,"action":"created","createdDate":"2024-10-25T11:50:27Z","flag":{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","revision":1,"LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}}],"total":2,"limit":1,"offset":1}`,
+		},
+		{
+			name:             "should return an empty list if the offset is after the last version",
+			ctx:              context.Background(),
+			id:               flagID,
+			query:            "?offset=10",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"versions":[],"total":2,"limit":20,"offset":10}`,
+		},
+		{
+			name:             "should return a 404 if the flag has no version",
+			ctx:              context.Background(),
+			id:               "926214f3-80c1-46e6-a913-b2d40b92aaaa",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+		{
+			name:             "should return a 400 if limit is not a number",
+			ctx:              context.Background(),
+			id:               flagID,
+			query:            "?limit=abc",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"limit should be an integer between 1 and 100","code":400}`,
+		},
+		{
+			name:             "should return a 400 if limit is too big",
+			ctx:              context.Background(),
+			id:               flagID,
+			query:            "?limit=101",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"limit should be an integer between 1 and 100","code":400}`,
+		},
+		{
+			name:             "should return a 400 if offset is negative",
+			ctx:              context.Background(),
+			id:               flagID,
+			query:            "?offset=-1",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"offset should be a positive integer","code":400}`,
+		},
+		{
+			name:             "should return a 400 if the id is not a valid UUID",
+			ctx:              context.WithValue(context.Background(), "error", daoErr.InvalidUUID),
+			id:               "invalidUUID",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"invalid UUID format","code":400}`,
+		},
+		{
+			name:             "should return a 500 if unknown error",
+			ctx:              context.WithValue(context.Background(), "error", daoErr.UnknownError),
+			id:               flagID,
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on get flag versions","code":500}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			_, errCreate := mockDao.CreateFlag(context.Background(), flagV1)
+			require.NoError(t, errCreate)
+			require.NoError(t, mockDao.UpdateFlag(context.Background(), flagV2))
+
+			hf := handler.NewFlagAPIHandler(mockDao, nil)
+			hh := handler.NewHealthHandler(mockDao)
+			s, err := api.New(&config.Configuration{
+				Mode: "development",
+			}, handler.Handlers{
+				FlagAPIHandler: &hf,
+				HealthHandler:  &hh,
+			})
+			require.NoError(t, err)
+			req := httptest.NewRequestWithContext(
+				tt.ctx, http.MethodGet, fmt.Sprintf("/v1/flags/%s/versions%s", tt.id, tt.query), nil)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestFlagsHandler_RecordFlagVersions(t *testing.T) {
+	mockDao, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	options := &handler.FlagAPIHandlerOptions{Clock: testutils2.ClockMock{}}
+	hf := handler.NewFlagAPIHandler(mockDao, options)
+	hh := handler.NewHealthHandler(mockDao)
+	s, err := api.New(&config.Configuration{
+		Mode: "development",
+	}, handler.Handlers{
+		FlagAPIHandler: &hf,
+		HealthHandler:  &hh,
+	})
+	require.NoError(t, err)
+
+	flag := testutils2.DefaultInMemoryFlags()[0]
+	_, errCreate := mockDao.CreateFlag(context.Background(), flag)
+	require.NoError(t, errCreate)
+
+	patchStatus := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/flags/%s/status", flag.ID),
+		strings.NewReader(`{"disable": true}`))
+	patchStatus.Header.Set("Content-Type", "application/json")
+	s.ServeHTTP(httptest.NewRecorder(), patchStatus)
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/v1/flags/%s", flag.ID), nil))
+
+	versions, total, errVersions := mockDao.GetFlagVersions(context.Background(), flag.ID, 10, 0)
+	require.NoError(t, errVersions)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, model.FlagVersionActionDeleted, versions[0].Action)
+	assert.Equal(t, model.FlagVersionActionUpdated, versions[1].Action)
+	assert.True(t, *versions[1].Flag.Disable)
+	assert.Equal(t, model.FlagVersionActionCreated, versions[2].Action)
+	assert.Nil(t, versions[2].Flag.Disable)
+}
+
+func TestFlagsHandler_RestoreFlagVersion(t *testing.T) {
+	flagID := "926214f3-80c1-46e6-a913-b2d40b92a932"
+	flagV1 := testutils2.DefaultInMemoryFlags()[0]
+	flagV2 := testutils2.DefaultInMemoryFlags()[0]
+	flagV2.Name = "flag1-renamed"
+	flagV2.Variations = &map[string]interface{}{
+		"variation1": testutils2.Interface("C"),
+		"variation2": testutils2.Interface("D"),
+	}
+	flagV2.LastUpdatedDate = time.Date(2024, 10, 26, 11, 50, 27, 0, time.UTC)
+	flagV2.Revision = 1
+	otherFlag := testutils2.DefaultInMemoryFlags()[1]
+	otherFlag.Name = "flag1"
+
+	type test struct {
+		name             string
+		ctx              context.Context
+		id               string
+		versionID        string
+		deleteFlag       bool
+		createOtherFlag  bool
+		expectedHTTPCode int
+		expectedBody     string
+	}
+	tests := []test{
+		{
+			name:             "should restore the flag to the first version",
+			ctx:              context.Background(),
+			id:               flagID,
+			versionID:        flagID + "-1",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":3,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}`,
+		},
+		{
+			name:             "should return a 404 if the version does not exist",
+			ctx:              context.Background(),
+			id:               flagID,
+			versionID:        flagID + "-42",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"version not found","code":404}`,
+		},
+		{
+			name:             "should return a 404 if the flag has been deleted",
+			ctx:              context.Background(),
+			id:               flagID,
+			versionID:        flagID + "-1",
+			deleteFlag:       true,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+		{
+			name:             "should return a 409 if another flag is using the name of the version",
+			ctx:              context.Background(),
+			id:               flagID,
+			versionID:        flagID + "-1",
+			createOtherFlag:  true,
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"flag with name flag1 already exists","code":409}`,
+		},
+		{
+			name:             "should return a 500 if the update fails",
+			ctx:              context.WithValue(context.Background(), "error_update", daoErr.UnknownError),
+			id:               flagID,
+			versionID:        flagID + "-1",
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on update flags","code":500}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			_, errCreate := mockDao.CreateFlag(context.Background(), flagV1)
+			require.NoError(t, errCreate)
+			require.NoError(t, mockDao.UpdateFlag(context.Background(), flagV2))
+			if tt.deleteFlag {
+				require.NoError(t, mockDao.DeleteFlagByID(context.Background(), flagID))
+			}
+			if tt.createOtherFlag {
+				_, errCreate := mockDao.CreateFlag(context.Background(), otherFlag)
+				require.NoError(t, errCreate)
+			}
+
+			options := &handler.FlagAPIHandlerOptions{Clock: testutils2.ClockMock{}}
+			hf := handler.NewFlagAPIHandler(mockDao, options)
+			hh := handler.NewHealthHandler(mockDao)
+			s, err := api.New(&config.Configuration{
+				Mode: "development",
+			}, handler.Handlers{
+				FlagAPIHandler: &hf,
+				HealthHandler:  &hh,
+			})
+			require.NoError(t, err)
+			req := httptest.NewRequestWithContext(tt.ctx, http.MethodPost,
+				fmt.Sprintf("/v1/flags/%s/versions/%s/restore", tt.id, tt.versionID), nil)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			if rec.Code == http.StatusOK {
+				restored, err := mockDao.GetFlagByID(context.Background(), flagID)
+				require.NoError(t, err)
+				assert.Equal(t, "flag1", restored.Name)
+				versions, total, err := mockDao.GetFlagVersions(context.Background(), flagID, 1, 0)
+				require.NoError(t, err)
+				assert.Equal(t, 3, total)
+				assert.Equal(t, model.FlagVersionActionUpdated, versions[0].Action)
+			}
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package handler
 
 import (
-	"errors"
 	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
 	"github.com/go-feature-flag/flag-management/server/dao"
 	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
 	"github.com/go-feature-flag/flag-management/server/util"
@@ -34,19 +34,61 @@ func NewFlagAPIHandler(dao dao.FlagStorage, options *FlagAPIHandlerOptions) Flag
 	return FlagAPIHandler{dao: dao, options: options}
 }
 
+type flagListResponse struct {
+	Flags  []model.FeatureFlag `json:"flags"`
+	Total  int                 `json:"total" example:"42"`
+	Limit  int                 `json:"limit" example:"20"`
+	Offset int                 `json:"offset" example:"0"`
+}
+
 // GetAllFeatureFlags is returning the list of all the flags
 // @Summary      Return all the flags available
 // @Tags Feature Flag management API
-// @Description  GET request to get all the flags available.
-// @Success      200  {object} []model.FeatureFlag "Success"
+// @Description  GET request to get a paginated list of the flags available.
+// @Description  The flags can be filtered by name prefix, type, status and metadata, and sorted by
+// @Description  name, createdDate or lastUpdatedDate (prefix the field with "-" for a descending order).
+// @Param        limit query int false "Maximum number of flags to return (default 20, max 100)"
+// @Param        offset query int false "Number of flags to skip before starting to collect the result set"
+// @Param        sort query string false "Field used to sort the flags (default -lastUpdatedDate)"
+// @Param        project query string false "Return only the flags of this project"
+// @Param        environment query string false "Return only the flags of this environment"
+// @Param        name query string false "Return only the flags with a name starting with this value"
+// @Param        type query string false "Return only the flags of this type"
+// @Param        disabled query bool false "Return only the disabled (true) or enabled (false) flags"
+// @Param        metadataKey query string false "Return only the flags having this key in their metadata"
+// @Param        metadataValue query string false "Return only the flags where metadataKey has this value"
+// @Param        If-None-Match header string false "ETag of a previous response, if nothing changed we return a 304"
+// @Success      200  {object} flagListResponse "Success"
+// @Success      304 "Not Modified - nothing changed since the ETag provided in If-None-Match"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
 // @Failure      500 {object} api.CustomErr "Internal server error"
+// @Header       200 {string} ETag "Entity tag of the list of flags"
 // @Router       /v1/flags [get]
 func (f FlagAPIHandler) GetAllFeatureFlags(c echo.Context) error {
-	flags, err := f.dao.GetFlags(c.Request().Context())
+	query, err := parseFlagQuery(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+		return err
+	}
+
+	summary, daoErr := f.dao.GetFlagsSummary(c.Request().Context(), query)
+	if daoErr != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, daoErr)
 	}
-	return c.JSON(http.StatusOK, flags)
+	if notModified(c, flagListETag(c, summary)) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	flags, total, daoErr := f.dao.GetFlags(c.Request().Context(), query)
+	if daoErr != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, daoErr)
+	}
+	return c.JSON(http.StatusOK, flagListResponse{
+		Flags:  flags,
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
 }
 
 // GetFeatureFlagByID is returning the flag belonging to the given ID
@@ -54,15 +96,25 @@ func (f FlagAPIHandler) GetAllFeatureFlags(c echo.Context) error {
 // @Tags Feature Flag management API
 // @Description  GET all the information about a flag with a specific .
 // @Param        id path string true "ID of the feature flag"
+// @Param        If-None-Match header string false "ETag of a previous response, if the flag didn't change we return a 304"
 // @Success      200  {object} model.FeatureFlag "Success"
+// @Success      304 "Not Modified - the flag didn't change since the ETag provided in If-None-Match"
 // @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
 // @Failure      500 {object} api.CustomErr "Internal server error"
+// @Header       200 {string} ETag "Entity tag of the flag"
 // @Router       /v1/flags/{id} [get]
 func (f FlagAPIHandler) GetFeatureFlagByID(c echo.Context) error {
 	flag, err := f.dao.GetFlagByID(c.Request().Context(), c.Param("id"))
 	if err != nil {
 		return f.handleDaoError(c, err)
 	}
+	if !inRouteScope(c, flag) {
+		return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+	}
+	if notModified(c, flagETag(flag)) {
+		return c.NoContent(http.StatusNotModified)
+	}
 	return c.JSON(http.StatusOK, flag)
 }
 
@@ -71,10 +123,12 @@ func (f FlagAPIHandler) GetFeatureFlagByID(c echo.Context) error {
 // @Tags Feature Flag management API
 // @Description  POST will insert in the database the new feature flag with all his properties,
 // @Description  and it will add all the associated rules too.
+// @Description  The flag is created in the project and environment of the payload, or in the default ones if not set.
 // @Param 		 data body model.FeatureFlag true "Payload which represents the flag to insert"
 // @Success      201  {object} model.FeatureFlag "Created"
-// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      400 {object} api.CustomErr "Bad Request - invalid flag or unknown project and environment"
 // @Failure      409 {object} api.CustomErr "Conflict - when trying to insert a flag with a name that already exists"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
 // @Failure      500 {object} api.CustomErr "Internal server error"
 // @Router       /v1/flags [post]
 func (f FlagAPIHandler) CreateNewFlag(c echo.Context) error {
@@ -83,8 +137,21 @@ func (f FlagAPIHandler) CreateNewFlag(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
+	// the flag is always created in the environment of the route
+	if project, environment := routeScope(c); project != "" {
+		flag.Project, flag.Environment = project, environment
+	}
+	flag.Project, flag.Environment = flag.GetProject(), flag.GetEnvironment()
+	if _, err := f.dao.GetEnvironment(c.Request().Context(), flag.Project, flag.Environment); err != nil {
+		if err.Code() == daoErr.NotFound {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Errorf("environment %s does not exist in project %s", flag.Environment, flag.Project))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
 	// Check if flag with this name exists
-	_, err := f.dao.GetFlagByName(c.Request().Context(), flag.Name)
+	_, err := f.dao.GetFlagByName(c.Request().Context(), flag.Project, flag.Environment, flag.Name)
 	if err == nil {
 		return echo.NewHTTPError(http.StatusConflict, fmt.Errorf("flag with name %s already exists", flag.Name))
 	}
@@ -98,78 +165,36 @@ func (f FlagAPIHandler) CreateNewFlag(c echo.Context) error {
 	}
 	flag.CreatedDate = f.options.Clock.Now()
 	flag.LastUpdatedDate = f.options.Clock.Now()
-	// TODO: remove this line and extract the information from the token
-	flag.LastModifiedBy = "toto"
+	flag.Revision = 1
+	flag.LastModifiedBy = auth.PrincipalFromContext(c).String()
 
 	if code, err := validateFlag(flag); err != nil {
 		return echo.NewHTTPError(code, err)
 	}
-	/**
-	TODO: Add a validation layer here, it should check:
-	- the flag name is not empty
-	- that reference from variation in rule exists in variations
-	- the type of the variations is correct
-	- ...
-	*/
 
 	id, err := f.dao.CreateFlag(c.Request().Context(), flag)
 	if err != nil {
-		if err.Code() == daoErr.ConversionError {
+		switch err.Code() {
+		case daoErr.ConversionError:
 			return echo.NewHTTPError(http.StatusBadRequest, err)
+		case daoErr.AlreadyExists:
+			return echo.NewHTTPError(http.StatusConflict, err)
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 	flag.ID = id
 
 	// TODO: Check what to return here because it has not all the new id created in the DAO (example rule ID)
+	c.Response().Header().Set(headerETag, flagETag(flag))
 	return c.JSON(http.StatusCreated, flag)
 }
 
+// validateFlag returns a 400 if the flag is invalid
 func validateFlag(flag model.FeatureFlag) (int, error) {
-	// Check if the flag name is valid
-	if flag.Name == "" {
-		return http.StatusBadRequest, errors.New("flag name is required")
-	}
-
-	if status, err := validateRule(flag.DefaultRule, true); err != nil {
-		return status, err
-	}
-
-	if _, err := model.FlagTypeFromValue(string(flag.VariationType)); err != nil {
+	if err := flag.Validate(); err != nil {
 		return http.StatusBadRequest, err
 	}
-
-	for _, rule := range flag.GetRules() {
-		if status, err := validateRule(&rule, false); err != nil {
-			return status, err
-		}
-	}
-
-	return http.StatusOK, nil
-}
-
-func validateRule(rule *model.Rule, isDefault bool) (int, error) {
-	if rule == nil || *rule == (model.Rule{}) {
-		if isDefault {
-			return http.StatusBadRequest, errors.New("flag default rule is required")
-		}
-		return http.StatusBadRequest, errors.New("targeting rule is nil")
-	}
-	if rule.ProgressiveRollout == nil &&
-		rule.Percentages == nil &&
-		(rule.VariationResult == nil || *rule.VariationResult == "") {
-		err := fmt.Errorf("invalid rule %s", rule.Name)
-		if isDefault {
-			err = errors.New("flag default rule is invalid")
-		}
-		return http.StatusBadRequest, err
-	}
-
-	if !isDefault {
-		if rule.Query == "" {
-			return http.StatusBadRequest, errors.New("query is required for targeting rules")
-		}
-	}
 	return http.StatusOK, nil
 }
 
@@ -177,11 +202,15 @@ func validateRule(rule *model.Rule, isDefault bool) (int, error) {
 // @Summary      Updates the flag with the given ID
 // @Tags Feature Flag management API
 // @Description  PUT - Updates the flag with the given ID with what is in the payload. It will replace completely the feature flag.
+// @Description  The project and environment of a flag cannot be changed.
 // @Param        id path string true "ID of the feature flag"
+// @Param        If-Match header string false "ETag of the flag you have retrieved, we return a 412 if the flag has changed since"
 // @Param 		 data body model.FeatureFlag true "Payload which represents the flag to update"
 // @Success      200  {object} model.FeatureFlag "Success"
 // @Failure      400 {object} api.CustomErr "Bad Request"
 // @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      412 {object} api.CustomErr "Precondition Failed - the flag has been modified since you retrieved it"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
 // @Failure      500 {object} api.CustomErr "Internal server error"
 // @Router       /v1/flags/{id} [put]
 func (f FlagAPIHandler) UpdateFlagByID(c echo.Context) error {
@@ -189,6 +218,12 @@ func (f FlagAPIHandler) UpdateFlagByID(c echo.Context) error {
 	if err != nil {
 		return f.handleDaoError(c, err)
 	}
+	if !inRouteScope(c, retrievedFlag) {
+		return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+	}
+	if err := checkIfMatch(c, retrievedFlag); err != nil {
+		return err
+	}
 
 	// update the flag
 	var flag model.FeatureFlag
@@ -200,16 +235,30 @@ func (f FlagAPIHandler) UpdateFlagByID(c echo.Context) error {
 		return echo.NewHTTPError(code, err)
 	}
 
-	if flag.ID == "" {
-		flag.ID = c.Param("id")
+	// the flag checked above is the only one this request can modify
+	if flag.ID != "" && flag.ID != retrievedFlag.ID {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Errorf("id of the payload %s does not match the id of the path %s", flag.ID, retrievedFlag.ID))
 	}
+	flag.ID = retrievedFlag.ID
 	flag.LastUpdatedDate = f.options.Clock.Now()
+	flag.LastModifiedBy = auth.PrincipalFromContext(c).String()
 	flag.CreatedDate = retrievedFlag.CreatedDate
+	flag.Project = retrievedFlag.Project
+	flag.Environment = retrievedFlag.Environment
+	// the revision written is the one of the flag checked above, without revision in the payload we expect
+	// the flag to be the one we have just retrieved
+	if flag.Revision != 0 && flag.Revision != retrievedFlag.Revision {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, errFlagModified)
+	}
+	flag.Revision = retrievedFlag.Revision
 
 	err = f.dao.UpdateFlag(c.Request().Context(), flag)
 	if err != nil {
 		return f.handleDaoError(c, err)
 	}
+	flag.Revision++
+	c.Response().Header().Set(headerETag, flagETag(flag))
 	return c.JSON(http.StatusOK, flag)
 }
 
@@ -221,10 +270,20 @@ func (f FlagAPIHandler) UpdateFlagByID(c echo.Context) error {
 // @Success      204  {object} model.FeatureFlag "No Content"
 // @Failure      400 {object} api.CustomErr "Bad Request"
 // @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
 // @Failure      500 {object} api.CustomErr "Internal server error"
 // @Router       /v1/flags/{id} [delete]
 func (f FlagAPIHandler) DeleteFlagByID(c echo.Context) error {
 	idParam := c.Param("id")
+	if project, _ := routeScope(c); project != "" {
+		flag, err := f.dao.GetFlagByID(c.Request().Context(), idParam)
+		if err != nil {
+			return f.handleDaoError(c, err)
+		}
+		if !inRouteScope(c, flag) {
+			return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+		}
+	}
 	err := f.dao.DeleteFlagByID(c.Request().Context(), idParam)
 	if err != nil {
 		return f.handleDaoError(c, err)
@@ -237,10 +296,13 @@ func (f FlagAPIHandler) DeleteFlagByID(c echo.Context) error {
 // @Tags Feature Flag management API
 // @Description  PATCH - Update the status of the flag with the given ID
 // @Param        id path string true "ID of the feature flag"
+// @Param        If-Match header string false "ETag of the flag you have retrieved, we return a 412 if the flag has changed since"
 // @Param 		 data body model.FeatureFlagStatusUpdate true "The patch query to update the flag status"
 // @Success      200  {object} model.FeatureFlag "Success"
 // @Failure      400 {object} api.CustomErr "Bad Request"
 // @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      412 {object} api.CustomErr "Precondition Failed - the flag has been modified since you retrieved it"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
 // @Failure      500 {object} api.CustomErr "Internal server error"
 // @Router       /v1/flags/{id}/status [patch]
 func (f FlagAPIHandler) UpdateFeatureFlagStatus(c echo.Context) error {
@@ -249,6 +311,12 @@ func (f FlagAPIHandler) UpdateFeatureFlagStatus(c echo.Context) error {
 	if err != nil {
 		return f.handleDaoError(c, err)
 	}
+	if !inRouteScope(c, flag) {
+		return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+	}
+	if err := checkIfMatch(c, flag); err != nil {
+		return err
+	}
 
 	var statusUpdate model.FeatureFlagStatusUpdate
 	if err := c.Bind(&statusUpdate); err != nil {
@@ -257,10 +325,13 @@ func (f FlagAPIHandler) UpdateFeatureFlagStatus(c echo.Context) error {
 
 	flag.Disable = &statusUpdate.Disable
 	flag.LastUpdatedDate = f.options.Clock.Now()
+	flag.LastModifiedBy = auth.PrincipalFromContext(c).String()
 	err = f.dao.UpdateFlag(c.Request().Context(), flag)
 	if err != nil {
 		return f.handleDaoError(c, err)
 	}
+	flag.Revision++
+	c.Response().Header().Set(headerETag, flagETag(flag))
 	return c.JSON(http.StatusOK, flag)
 }
 
@@ -268,9 +339,13 @@ func (f FlagAPIHandler) UpdateFeatureFlagStatus(c echo.Context) error {
 func (f FlagAPIHandler) handleDaoError(c echo.Context, err daoErr.DaoError) error {
 	switch err.Code() {
 	case daoErr.NotFound:
-		return echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("flag not found"))
+		return echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
 	case daoErr.InvalidUUID:
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid UUID format"))
+	case daoErr.StaleRevision:
+		return echo.NewHTTPError(http.StatusPreconditionFailed, errFlagModified)
+	case daoErr.AlreadyExists:
+		return echo.NewHTTPError(http.StatusConflict, err)
 	default:
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
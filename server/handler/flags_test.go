@@ -14,6 +14,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,14 +38,14 @@ func TestFlagsHandler_GetAllFeatureFlags(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusOK,
 			flags:            make([]model.FeatureFlag, 0),
-			expectedBody:     "[]\n",
+			expectedBody:     `{"flags":[],"total":0,"limit":20,"offset":0}`,
 		},
 		{
 			name:             "should return a flag with a default rule",
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusOK,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     `[{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a111","name":"flagr6w8","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a222","name":"flagr576987209","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}]`,
+			expectedBody:     `{"flags":[{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a222","name":"flagr576987209","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}},{"id":"926214f3-80c1-46e6-a913-b2d40b92a111","name":"flagr6w8","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}],"total":3,"limit":20,"offset":0}`,
 		},
 		{
 			name:             "should return a 500 if an error occured ",
@@ -80,6 +81,314 @@ func TestFlagsHandler_GetAllFeatureFlags(t *testing.T) {
 	}
 }
 
+func TestFlagsHandler_GetAllFeatureFlagsWithQuery(t *testing.T) {
+	flags := []model.FeatureFlag{
+		{
+			ID:              "926214f3-80c1-46e6-a913-b2d40b92a001",
+			Name:            "checkout-button",
+			VariationType:   model.FlagTypeBoolean,
+			CreatedDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Metadata:        &map[string]interface{}{"team": "payment"},
+		},
+		{
+			ID:              "926214f3-80c1-46e6-a913-b2d40b92a002",
+			Name:            "checkout-color",
+			VariationType:   model.FlagTypeString,
+			CreatedDate:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			Disable:         testutils2.Bool(true),
+			Metadata:        &map[string]interface{}{"team": "growth"},
+		},
+		{
+			ID:              "926214f3-80c1-46e6-a913-b2d40b92a003",
+			Name:            "search-v2",
+			VariationType:   model.FlagTypeBoolean,
+			CreatedDate:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			LastUpdatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Disable:         testutils2.Bool(false),
+		},
+	}
+
+	tests := []struct {
+		name             string
+		query            string
+		expectedHTTPCode int
+		expectedNames    []string
+		expectedTotal    int
+		expectedBody     string
+	}{
+		{
+			name:             "should sort by last updated date by default",
+			query:            "",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-button", "checkout-color", "search-v2"},
+			expectedTotal:    3,
+		},
+		{
+			name:             "should paginate the flags",
+			query:            "?limit=1&offset=1",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-color"},
+			expectedTotal:    3,
+		},
+		{
+			name:             "should return an empty page if offset is after the last flag",
+			query:            "?offset=10",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{},
+			expectedTotal:    3,
+		},
+		{
+			name:             "should sort by name in ascending order",
+			query:            "?sort=name",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-button", "checkout-color", "search-v2"},
+			expectedTotal:    3,
+		},
+		{
+			name:             "should sort by created date in descending order",
+			query:            "?sort=-createdDate",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"search-v2", "checkout-color", "checkout-button"},
+			expectedTotal:    3,
+		},
+		{
+			name:             "should filter by name prefix",
+			query:            "?name=checkout&limit=1",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-button"},
+			expectedTotal:    2,
+		},
+		{
+			name:             "should filter by type",
+			query:            "?type=boolean",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-button", "search-v2"},
+			expectedTotal:    2,
+		},
+		{
+			name:             "should filter enabled flags",
+			query:            "?disabled=false",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-button", "search-v2"},
+			expectedTotal:    2,
+		},
+		{
+			name:             "should filter by metadata key",
+			query:            "?metadataKey=team",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-button", "checkout-color"},
+			expectedTotal:    2,
+		},
+		{
+			name:             "should filter by metadata key and value",
+			query:            "?metadataKey=team&metadataValue=growth",
+			expectedHTTPCode: http.StatusOK,
+			expectedNames:    []string{"checkout-color"},
+			expectedTotal:    1,
+		},
+		{
+			name:             "should return a 400 if limit is too big",
+			query:            "?limit=101",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"limit should be an integer between 1 and 100","code":400}`,
+		},
+		{
+			name:             "should return a 400 if sort field is not supported",
+			query:            "?sort=description",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"sort field description not supported","code":400}`,
+		},
+		{
+			name:             "should return a 400 if type is not supported",
+			query:            "?type=float",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"flag type float not supported","code":400}`,
+		},
+		{
+			name:             "should return a 400 if disabled is not a boolean",
+			query:            "?disabled=maybe",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"disabled should be a boolean","code":400}`,
+		},
+		{
+			name:             "should return a 400 if metadataValue is used without metadataKey",
+			query:            "?metadataValue=growth",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"metadataValue can only be used with metadataKey","code":400}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			mockDao.SetFlags(flags)
+
+			hf := handler.NewFlagAPIHandler(mockDao, nil)
+			hh := handler.NewHealthHandler(mockDao)
+			s, err := api.New(&config.Configuration{
+				Mode: "development",
+			}, handler.Handlers{
+				FlagAPIHandler: &hf,
+				HealthHandler:  &hh,
+			})
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodGet, "/v1/flags"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			if tt.expectedHTTPCode != http.StatusOK {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+				return
+			}
+
+			var body struct {
+				Flags []model.FeatureFlag `json:"flags"`
+				Total int                 `json:"total"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			names := make([]string, 0, len(body.Flags))
+			for _, flag := range body.Flags {
+				names = append(names, flag.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+			assert.Equal(t, tt.expectedTotal, body.Total)
+		})
+	}
+}
+
+func TestFlagsHandler_GetAllFeatureFlagsETag(t *testing.T) {
+	mockDao, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	mockDao.SetFlags(testutils2.DefaultInMemoryFlags())
+
+	hf := handler.NewFlagAPIHandler(mockDao, &handler.FlagAPIHandlerOptions{Clock: testutils2.ClockMock{}})
+	hh := handler.NewHealthHandler(mockDao)
+	s, err := api.New(&config.Configuration{
+		Mode: "development",
+	}, handler.Handlers{
+		FlagAPIHandler: &hf,
+		HealthHandler:  &hh,
+	})
+	require.NoError(t, err)
+
+	get := func(path string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/v1/flags", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	t.Run("should return a 304 if nothing changed", func(t *testing.T) {
+		rec := get("/v1/flags", etag)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("should return a 304 if the ETag is part of a list", func(t *testing.T) {
+		rec := get("/v1/flags", `"foo", W/`+etag)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("should return a 200 if the ETag does not match", func(t *testing.T) {
+		rec := get("/v1/flags", `"foo"`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("should return a 200 if the query is different", func(t *testing.T) {
+		rec := get("/v1/flags?limit=1", etag)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("should return a 200 after a flag is updated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch,
+			"/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/status", strings.NewReader(`{"disable":true}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = get("/v1/flags", etag)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("should return a 200 after a flag is deleted", func(t *testing.T) {
+		etag := get("/v1/flags", "").Header().Get("ETag")
+		req := httptest.NewRequest(http.MethodDelete, "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a111", nil)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = get("/v1/flags", etag)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	})
+}
+
+func TestFlagsHandler_GetFeatureFlagByIDETag(t *testing.T) {
+	mockDao, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	mockDao.SetFlags(testutils2.DefaultInMemoryFlags())
+
+	hf := handler.NewFlagAPIHandler(mockDao, &handler.FlagAPIHandlerOptions{Clock: testutils2.ClockMock{}})
+	hh := handler.NewHealthHandler(mockDao)
+	s, err := api.New(&config.Configuration{
+		Mode: "development",
+	}, handler.Handlers{
+		FlagAPIHandler: &hf,
+		HealthHandler:  &hh,
+	})
+	require.NoError(t, err)
+
+	get := func(id string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/flags/"+id, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	flagID := "926214f3-80c1-46e6-a913-b2d40b92a932"
+	first := get(flagID, "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	assert.Equal(t, http.StatusNotModified, get(flagID, etag).Code)
+	assert.Equal(t, http.StatusNotModified, get(flagID, "*").Code)
+
+	other := get("926214f3-80c1-46e6-a913-b2d40b92a111", etag)
+	assert.Equal(t, http.StatusOK, other.Code, "another flag should have a different ETag")
+
+	req := httptest.NewRequest(http.MethodPatch, "/v1/flags/"+flagID+"/status", strings.NewReader(`{"disable":true}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	updated := get(flagID, etag)
+	assert.Equal(t, http.StatusOK, updated.Code)
+	assert.NotEqual(t, etag, updated.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusNotFound, get("926214f3-80c1-46e6-a913-b2d40b92a999", etag).Code)
+}
+
 func TestFlagsHandler_GetFeatureFlagByID(t *testing.T) {
 	type test struct {
 		name             string
@@ -167,7 +476,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"errorDetails\":\"flag name is required\",\"code\":400}\n",
+			expectedBody:     "{\"errorDetails\":\"name: flag name is required\",\"code\":400,\"errors\":[{\"field\":\"name\",\"message\":\"flag name is required\"}]}\n",
 			newFlag: model.FeatureFlag{
 				Name:        "",
 				Description: testutils2.String("description1"),
@@ -216,11 +525,11 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			newFlagAsString:  `"id":"926214f3-80c1-46e6-a913-b2d40b92a93","name":"flag2","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2024-10-25T11:50:27Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"}}`,
 		},
 		{
-			name:             "should return an error if error when finding flag by name",
+			name:             "should return an error if error when checking the environment of the flag",
 			ctx:              context.WithValue(context.Background(), "error", daoErr.UnknownError),
 			expectedHTTPCode: http.StatusInternalServerError,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"errorDetails\":\"error on get flag by name\",\"code\":500}\n",
+			expectedBody:     "{\"errorDetails\":\"error on get environment\",\"code\":500}\n",
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -289,7 +598,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"errorDetails\":\"flag default rule is required\",\"code\":400}\n",
+			expectedBody:     "{\"errorDetails\":\"defaultRule: flag default rule is required\",\"code\":400,\"errors\":[{\"field\":\"defaultRule\",\"message\":\"flag default rule is required\"}]}\n",
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -309,7 +618,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"errorDetails\":\"flag default rule is invalid\",\"code\":400}\n",
+			expectedBody:     "{\"errorDetails\":\"defaultRule: flag default rule is invalid\",\"code\":400,\"errors\":[{\"field\":\"defaultRule\",\"message\":\"flag default rule is invalid\"}]}\n",
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -332,7 +641,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"errorDetails\":\"invalid rule rule1\",\"code\":400}\n",
+			expectedBody:     "{\"errorDetails\":\"targeting[0]: invalid rule rule1\\ntargeting[0].query: query is required for targeting rules\",\"code\":400,\"errors\":[{\"field\":\"targeting[0]\",\"message\":\"invalid rule rule1\"},{\"field\":\"targeting[0].query\",\"message\":\"query is required for targeting rules\"}]}\n",
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -357,7 +666,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"errorDetails\":\"targeting rule is nil\",\"code\":400}\n",
+			expectedBody:     "{\"errorDetails\":\"targeting[0]: targeting rule is nil\",\"code\":400,\"errors\":[{\"field\":\"targeting[0]\",\"message\":\"targeting rule is nil\"}]}\n",
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -382,7 +691,37 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"errorDetails\":\"query is required for targeting rules\",\"code\":400}\n",
+			expectedBody:     "{\"errorDetails\":\"targeting[0].query: query is required for targeting rules\",\"code\":400,\"errors\":[{\"field\":\"targeting[0].query\",\"message\":\"query is required for targeting rules\"}]}\n",
+			newFlag: model.FeatureFlag{
+				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
+				Name:        "flag2",
+				Description: testutils2.String("description1"),
+				Variations: &map[string]interface{}{
+					"variation1": testutils2.Interface("A"),
+					"variation2": testutils2.Interface("B"),
+				},
+				VariationType:   "string",
+				LastModifiedBy:  "foo",
+				LastUpdatedDate: time.Unix(1729849827, 0),
+				CreatedDate:     time.Unix(1729849827, 0),
+				DefaultRule: &model.Rule{
+					Name:            "defaultRule",
+					VariationResult: testutils2.String("variation1"),
+				},
+				Rules: &[]model.Rule{
+					{
+						Name:            "rule1",
+						VariationResult: testutils2.String("variation1"),
+					},
+				},
+			},
+		},
+		{
+			name:             "should return a 400 pointing at the rule if a targeting query is invalid",
+			ctx:              context.Background(),
+			expectedHTTPCode: http.StatusBadRequest,
+			flags:            testutils2.DefaultInMemoryFlags(),
+			expectedBody:     `{"errorDetails":"targeting[0].query: syntax error at position 13: no viable alternative at input 'targetingKey value'","code":400,"errors":[{"field":"targeting[0].query","ruleId":"5e4a4d4e-0a4c-4b9b-9f0e-8c4b8a3e6f1d","message":"syntax error at position 13: no viable alternative at input 'targetingKey value'"}]}`,
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -401,7 +740,9 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 				},
 				Rules: &[]model.Rule{
 					{
+						ID:              "5e4a4d4e-0a4c-4b9b-9f0e-8c4b8a3e6f1d",
 						Name:            "rule1",
+						Query:           "targetingKey value",
 						VariationResult: testutils2.String("variation1"),
 					},
 				},
@@ -412,7 +753,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusCreated,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     "{\"id\":\"926214f3-80c1-46e6-a913-b2d40b92a93\",\"name\":\"flag2\",\"createdDate\":\"2020-01-01T00:00:00Z\",\"lastUpdatedDate\":\"2020-01-01T00:00:00Z\",\"LastModifiedBy\":\"toto\",\"description\":\"description1\",\"type\":\"string\",\"variations\":{\"variation1\":\"A\",\"variation2\":\"B\"},\"targeting\":[{\"id\":\"\",\"name\":\"rule1\",\"query\":\"targetingKey eq \\\"value\\\"\",\"variation\":\"variation1\"}],\"defaultRule\":{\"id\":\"\",\"name\":\"defaultRule\",\"variation\":\"variation1\"}}\n",
+			expectedBody:     "{\"id\":\"926214f3-80c1-46e6-a913-b2d40b92a93\",\"name\":\"flag2\",\"project\":\"default\",\"environment\":\"default\",\"createdDate\":\"2020-01-01T00:00:00Z\",\"lastUpdatedDate\":\"2020-01-01T00:00:00Z\",\"revision\":1,\"LastModifiedBy\":\"anonymous\",\"description\":\"description1\",\"type\":\"string\",\"variations\":{\"variation1\":\"A\",\"variation2\":\"B\"},\"targeting\":[{\"id\":\"\",\"name\":\"rule1\",\"query\":\"targetingKey eq \\\"value\\\"\",\"variation\":\"variation1\"}],\"defaultRule\":{\"id\":\"\",\"name\":\"defaultRule\",\"variation\":\"variation1\"}}\n",
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -443,7 +784,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     `{"errorDetails":"flag type is required","code":400}`,
+			expectedBody:     `{"errorDetails":"type: flag type is required","code":400,"errors":[{"field":"type","message":"flag type is required"}]}`,
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -473,7 +814,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     `{"errorDetails":"flag type is required","code":400}`,
+			expectedBody:     `{"errorDetails":"type: flag type is required","code":400,"errors":[{"field":"type","message":"flag type is required"}]}`,
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -504,7 +845,7 @@ func TestFlagsHandler_CreateNewFlag(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     `{"errorDetails":"flag type notsupported not supported","code":400}`,
+			expectedBody:     `{"errorDetails":"type: flag type notsupported not supported","code":400,"errors":[{"field":"type","message":"flag type notsupported not supported"}]}`,
 			newFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a93",
 				Name:        "flag2",
@@ -628,7 +969,7 @@ func TestFlagsHandler_DeleteFlagByID(t *testing.T) {
 			s.ServeHTTP(rec, req)
 			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
 			if rec.Code == http.StatusNoContent {
-				flagAfterDelete, err := mockDao.GetFlags(tt.ctx)
+				flagAfterDelete, _, err := mockDao.GetFlags(tt.ctx, dao.FlagQuery{})
 				require.NoError(t, err)
 				assert.Equal(t, tt.expectedNumberOfFlags, len(flagAfterDelete))
 			} else {
@@ -710,7 +1051,7 @@ func TestFlagsHandler_UpdateFlagByID(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusOK,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"C","variation2":"D"},"defaultRule":{"id":"","variation":"variation1"}}`,
+			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":1,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"C","variation2":"D"},"defaultRule":{"id":"","variation":"variation1"}}`,
 			id:               "926214f3-80c1-46e6-a913-b2d40b92a932",
 			updatedFlag: model.FeatureFlag{
 				ID:          "926214f3-80c1-46e6-a913-b2d40b92a932",
@@ -732,7 +1073,7 @@ func TestFlagsHandler_UpdateFlagByID(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusOK,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"C","variation2":"D"},"defaultRule":{"id":"","variation":"variation1"}}`,
+			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":1,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"C","variation2":"D"},"defaultRule":{"id":"","variation":"variation1"}}`,
 			id:               "926214f3-80c1-46e6-a913-b2d40b92a932",
 			updatedFlag: model.FeatureFlag{
 				Name:        "flag1",
@@ -753,7 +1094,7 @@ func TestFlagsHandler_UpdateFlagByID(t *testing.T) {
 			ctx:              context.Background(),
 			expectedHTTPCode: http.StatusBadRequest,
 			flags:            testutils2.DefaultInMemoryFlags(),
-			expectedBody:     `{"errorDetails":"flag name is required","code":400}`,
+			expectedBody:     `{"errorDetails":"name: flag name is required","code":400,"errors":[{"field":"name","message":"flag name is required"}]}`,
 			id:               "926214f3-80c1-46e6-a913-b2d40b92a932",
 			updatedFlag: model.FeatureFlag{
 				Description: testutils2.String("description1"),
@@ -808,6 +1149,152 @@ func TestFlagsHandler_UpdateFlagByID(t *testing.T) {
 	}
 }
 
+func TestFlagsHandler_OptimisticConcurrency(t *testing.T) {
+	flagID := "926214f3-80c1-46e6-a913-b2d40b92a932"
+	updateBody := `{"name":"flag1","type":"string","variations":{"variation1":"C","variation2":"D"},` +
+		`"defaultRule":{"id":"","variation":"variation1"}}`
+
+	type test struct {
+		name             string
+		ctx              context.Context
+		method           string
+		path             string
+		body             string
+		ifMatch          func(currentETag string) string
+		expectedHTTPCode int
+		expectedBody     string
+	}
+	tests := []test{
+		{
+			name:             "PUT should update the flag if If-Match is the current ETag",
+			ctx:              context.Background(),
+			method:           http.MethodPut,
+			path:             "/v1/flags/" + flagID,
+			body:             updateBody,
+			ifMatch:          func(currentETag string) string { return currentETag },
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			name:             "PUT should return a 412 if If-Match is not the current ETag",
+			ctx:              context.Background(),
+			method:           http.MethodPut,
+			path:             "/v1/flags/" + flagID,
+			body:             updateBody,
+			ifMatch:          func(_ string) string { return `"outdated"` },
+			expectedHTTPCode: http.StatusPreconditionFailed,
+			expectedBody:     `{"errorDetails":"the flag has been modified since you retrieved it","code":412}`,
+		},
+		{
+			name:   "PUT should return a 412 if the revision in the body is stale",
+			ctx:    context.Background(),
+			method: http.MethodPut,
+			path:   "/v1/flags/" + flagID,
+			body: `{"name":"flag1","type":"string","variations":{"variation1":"C","variation2":"D"},` +
+				`"defaultRule":{"id":"","variation":"variation1"},"revision":42}`,
+			expectedHTTPCode: http.StatusPreconditionFailed,
+			expectedBody:     `{"errorDetails":"the flag has been modified since you retrieved it","code":412}`,
+		},
+		{
+			name:   "PUT should return a 412 if the revision in the body is stale even if If-Match is current",
+			ctx:    context.Background(),
+			method: http.MethodPut,
+			path:   "/v1/flags/" + flagID,
+			body: `{"name":"flag1","type":"string","variations":{"variation1":"C","variation2":"D"},` +
+				`"defaultRule":{"id":"","variation":"variation1"},"revision":42}`,
+			ifMatch:          func(currentETag string) string { return currentETag },
+			expectedHTTPCode: http.StatusPreconditionFailed,
+			expectedBody:     `{"errorDetails":"the flag has been modified since you retrieved it","code":412}`,
+		},
+		{
+			name:             "PUT should return a 412 if the flag is modified during the update",
+			ctx:              context.WithValue(context.Background(), "error_update", daoErr.StaleRevision),
+			method:           http.MethodPut,
+			path:             "/v1/flags/" + flagID,
+			body:             updateBody,
+			expectedHTTPCode: http.StatusPreconditionFailed,
+			expectedBody:     `{"errorDetails":"the flag has been modified since you retrieved it","code":412}`,
+		},
+		{
+			name:             "PATCH status should update the flag if If-Match is the current ETag",
+			ctx:              context.Background(),
+			method:           http.MethodPatch,
+			path:             "/v1/flags/" + flagID + "/status",
+			body:             `{"disable":true}`,
+			ifMatch:          func(currentETag string) string { return currentETag },
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			name:             "PATCH status should return a 412 if If-Match is not the current ETag",
+			ctx:              context.Background(),
+			method:           http.MethodPatch,
+			path:             "/v1/flags/" + flagID + "/status",
+			body:             `{"disable":true}`,
+			ifMatch:          func(_ string) string { return `"outdated"` },
+			expectedHTTPCode: http.StatusPreconditionFailed,
+			expectedBody:     `{"errorDetails":"the flag has been modified since you retrieved it","code":412}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			mockDao.SetFlags(testutils2.DefaultInMemoryFlags())
+
+			hf := handler.NewFlagAPIHandler(mockDao, &handler.FlagAPIHandlerOptions{Clock: testutils2.ClockMock{}})
+			hh := handler.NewHealthHandler(mockDao)
+			s, err := api.New(&config.Configuration{
+				Mode: "development",
+			}, handler.Handlers{
+				FlagAPIHandler: &hf,
+				HealthHandler:  &hh,
+			})
+			require.NoError(t, err)
+
+			getRec := httptest.NewRecorder()
+			s.ServeHTTP(getRec, httptest.NewRequest(http.MethodGet, "/v1/flags/"+flagID, nil))
+			require.Equal(t, http.StatusOK, getRec.Code)
+			currentETag := getRec.Header().Get("ETag")
+
+			req := httptest.NewRequestWithContext(tt.ctx, tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.ifMatch != nil {
+				req.Header.Set("If-Match", tt.ifMatch(currentETag))
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+
+			if tt.expectedHTTPCode != http.StatusOK {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+				flag, err := mockDao.GetFlagByID(context.Background(), flagID)
+				require.NoError(t, err)
+				assert.Equal(t, int64(0), flag.Revision, "the flag should not have been updated")
+				return
+			}
+
+			var updated model.FeatureFlag
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+			assert.Equal(t, int64(1), updated.Revision)
+			newETag := rec.Header().Get("ETag")
+			assert.NotEqual(t, currentETag, newETag)
+
+			// the ETag returned by the update should be the one of the stored flag
+			getRec = httptest.NewRecorder()
+			s.ServeHTTP(getRec, httptest.NewRequest(http.MethodGet, "/v1/flags/"+flagID, nil))
+			assert.Equal(t, newETag, getRec.Header().Get("ETag"))
+
+			// a second update based on the previous ETag should fail
+			req = httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("If-Match", currentETag)
+			rec = httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		})
+	}
+}
+
 func TestFlagsHandler_UpdateFeatureFlagStatus(t *testing.T) {
 	type test struct {
 		name             string
@@ -825,7 +1312,7 @@ func TestFlagsHandler_UpdateFeatureFlagStatus(t *testing.T) {
 			expectedHTTPCode: http.StatusOK,
 			flags:            testutils2.DefaultInMemoryFlags(),
 			body:             `{"disable": true}`,
-			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
+			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":1,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":true}`,
 			id:               "926214f3-80c1-46e6-a913-b2d40b92a932",
 		},
 		{
@@ -834,7 +1321,7 @@ func TestFlagsHandler_UpdateFeatureFlagStatus(t *testing.T) {
 			expectedHTTPCode: http.StatusOK,
 			flags:            testutils2.DefaultInMemoryFlags(),
 			body:             `{"disable": false}`,
-			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","LastModifiedBy":"foo","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":false}`,
+			expectedBody:     `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","name":"flag1","createdDate":"2024-10-25T11:50:27Z","lastUpdatedDate":"2020-01-01T00:00:00Z","revision":1,"LastModifiedBy":"anonymous","description":"description1","type":"string","variations":{"variation1":"A","variation2":"B"},"defaultRule":{"id":"","variation":"variation1"},"disable":false}`,
 			id:               "926214f3-80c1-46e6-a913-b2d40b92a932",
 		},
 		{
@@ -2,24 +2,46 @@ package handler
 
 import (
 	"errors"
+	"github.com/go-feature-flag/flag-management/server/auth"
 	"github.com/go-feature-flag/flag-management/server/dao"
 )
 
 type Handlers struct {
 	FlagAPIHandler *FlagAPIHandler
 	HealthHandler  *HealthHandler
+	ProjectHandler *ProjectHandler
+
+	// APIKeyHandler and APIKeyValidator are only available if the storage is able to store API keys
+	APIKeyHandler   *APIKeyHandler
+	APIKeyValidator *auth.APIKeyValidator
+
+	// ScheduledChangeHandler is only available if the storage is able to store scheduled changes
+	ScheduledChangeHandler *ScheduledChangeHandler
 }
 
-func InitHandlers(dao dao.FlagStorage) (Handlers, error) {
-	if dao == nil {
+func InitHandlers(storage dao.FlagStorage) (Handlers, error) {
+	if storage == nil {
 		return Handlers{}, ErrMissingDao
 	}
-	flagAPIHandler := NewFlagAPIHandler(dao, &FlagAPIHandlerOptions{})
-	healthHandler := NewHealthHandler(dao)
-	return Handlers{
+	flagAPIHandler := NewFlagAPIHandler(storage, &FlagAPIHandlerOptions{})
+	healthHandler := NewHealthHandler(storage)
+	projectHandler := NewProjectHandler(storage, &ProjectHandlerOptions{})
+	handlers := Handlers{
 		FlagAPIHandler: &flagAPIHandler,
 		HealthHandler:  &healthHandler,
-	}, nil
+		ProjectHandler: &projectHandler,
+	}
+
+	if apiKeyDao, ok := storage.(dao.APIKeyStorage); ok {
+		apiKeyHandler := NewAPIKeyHandler(apiKeyDao, &APIKeyHandlerOptions{})
+		handlers.APIKeyHandler = &apiKeyHandler
+		handlers.APIKeyValidator = auth.NewAPIKeyValidator(apiKeyDao, nil)
+	}
+	if changeDao, ok := storage.(dao.ScheduledChangeStorage); ok {
+		scheduledChangeHandler := NewScheduledChangeHandler(storage, changeDao, &ScheduledChangeHandlerOptions{})
+		handlers.ScheduledChangeHandler = &scheduledChangeHandler
+	}
+	return handlers, nil
 }
 
 var ErrMissingFlagAPIHandler = errors.New("flagAPIHandler cannot be nil")
@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
 	dao2 "github.com/go-feature-flag/flag-management/server/dao"
 	handler2 "github.com/go-feature-flag/flag-management/server/handler"
 	"testing"
@@ -15,6 +16,10 @@ func TestInitHandlers(t *testing.T) {
 	require.NoError(t, err)
 	expectedFlagAPIHandler := handler2.NewFlagAPIHandler(mockDao, &handler2.FlagAPIHandlerOptions{})
 	expectedHealthHandler := handler2.NewHealthHandler(mockDao)
+	expectedAPIKeyHandler := handler2.NewAPIKeyHandler(mockDao, &handler2.APIKeyHandlerOptions{})
+	expectedProjectHandler := handler2.NewProjectHandler(mockDao, &handler2.ProjectHandlerOptions{})
+	expectedScheduledChangeHandler := handler2.NewScheduledChangeHandler(mockDao, mockDao,
+		&handler2.ScheduledChangeHandlerOptions{})
 
 	tests := []struct {
 		name        string
@@ -34,8 +39,12 @@ func TestInitHandlers(t *testing.T) {
 			name: "should return a handler with a dao",
 			dao:  mockDao,
 			want: handler2.Handlers{
-				FlagAPIHandler: &expectedFlagAPIHandler,
-				HealthHandler:  &expectedHealthHandler,
+				FlagAPIHandler:         &expectedFlagAPIHandler,
+				HealthHandler:          &expectedHealthHandler,
+				ProjectHandler:         &expectedProjectHandler,
+				APIKeyHandler:          &expectedAPIKeyHandler,
+				APIKeyValidator:        auth.NewAPIKeyValidator(mockDao, nil),
+				ScheduledChangeHandler: &expectedScheduledChangeHandler,
 			},
 			wantErr: assert.NoError,
 		},
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/flagconfig"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ImportFlags is creating or updating the flags of an environment from a GO Feature Flag configuration file
+// @Summary      Import a GO Feature Flag configuration file
+// @Tags Feature Flag management API
+// @Description  POST a configuration file read by the GO Feature Flag relay proxy (JSON, YAML or TOML).
+// @Description  The flags of the file are created, or updated if a flag with the same name already exists in the
+// @Description  environment. All the flags are stored in a single transaction, if one is invalid nothing is stored.
+// @Description  The format is selected with the format query parameter or with the Content-Type header.
+// @Accept       json
+// @Accept       application/yaml
+// @Accept       application/toml
+// @Param        project query string false "Project of the flags to import (default: default)"
+// @Param        environment query string false "Environment of the flags to import (default: default)"
+// @Param        format query string false "Format of the file (json, yaml or toml), it takes precedence over the Content-Type header"
+// @Param        dryRun query bool false "If true, nothing is stored and the response describes what would change"
+// @Param 		 data body map[string]flagconfig.Flag true "GO Feature Flag configuration file"
+// @Success      200  {object} flagconfig.ImportResult "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request - the file or one of its flags is invalid"
+// @Failure      404 {object} api.CustomErr "Not Found - the environment does not exist"
+// @Failure      412 {object} api.CustomErr "Precondition Failed - one of the flags has been modified during the import"
+// @Failure      415 {object} api.CustomErr "Unsupported Media Type - the format of the file is not supported"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/import [post]
+func (f FlagAPIHandler) ImportFlags(c echo.Context) error {
+	format, err := importFormat(c)
+	if err != nil {
+		return err
+	}
+	dryRun := false
+	if value := c.QueryParam("dryRun"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("dryRun should be a boolean"))
+		}
+	}
+
+	content, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	file, err := flagconfig.Unmarshal(content, format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid configuration file: %w", err))
+	}
+
+	project, environment := queryScope(c)
+	if err := f.checkEnvironmentExists(c, project, environment); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	plan, err := flagconfig.PlanImport(ctx, f.dao, file, flagconfig.ImportOptions{
+		Project:     project,
+		Environment: environment,
+		Author:      auth.PrincipalFromContext(c).String(),
+		Now:         f.options.Clock.Now(),
+	})
+	if err != nil {
+		return f.handleImportError(c, err)
+	}
+	if !dryRun {
+		if err := plan.Apply(ctx, f.dao); err != nil {
+			return f.handleDaoError(c, err)
+		}
+	}
+	plan.Result.DryRun = dryRun
+	return c.JSON(http.StatusOK, plan.Result)
+}
+
+// handleImportError returns a 400 if some flags of the file are invalid.
+func (f FlagAPIHandler) handleImportError(c echo.Context, err error) error {
+	var invalidFlags flagconfig.InvalidFlagsError
+	if errors.As(err, &invalidFlags) {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	var storageErr daoErr.DaoError
+	if errors.As(err, &storageErr) {
+		return f.handleDaoError(c, storageErr)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err)
+}
+
+// importFormat selects the format of the imported file, the format query parameter takes precedence over the
+// Content-Type header.
+func importFormat(c echo.Context) (flagconfig.Format, error) {
+	if value := c.QueryParam("format"); value != "" {
+		format, err := flagconfig.FormatFromValue(value)
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+		return format, nil
+	}
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	format, ok := flagconfig.FormatFromMediaType(contentType)
+	if !ok {
+		return "", echo.NewHTTPError(http.StatusUnsupportedMediaType,
+			fmt.Errorf("content type %s not supported, use application/json, application/yaml or application/toml",
+				contentType))
+	}
+	return format, nil
+}
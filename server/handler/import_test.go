@@ -0,0 +1,241 @@
+package handler_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagsHandler_ImportFlags(t *testing.T) {
+	existingFlags := []model.FeatureFlag{
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+			Name:          "flag1",
+			Description:   testutils2.String("description1"),
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils2.String("A")},
+			Revision:      1,
+		},
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a933",
+			Name:          "flag2",
+			VariationType: model.FlagTypeBoolean,
+			Variations:    &map[string]interface{}{"on": true, "off": false},
+			DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a001", VariationResult: testutils2.String("off")},
+			Revision:      1,
+		},
+	}
+	doubleFlag := model.FeatureFlag{
+		ID:            "926214f3-80c1-46e6-a913-b2d40b92a934",
+		Name:          "flag4",
+		VariationType: model.FlagTypeDouble,
+		Variations:    &map[string]interface{}{"low": 1.0, "high": 2.0},
+		DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a002", VariationResult: testutils2.String("low")},
+		Revision:      1,
+	}
+	yamlFile := `flag1:
+  variations:
+    A: a
+    B: b
+  targeting:
+    - name: beta
+      query: beta eq true
+      variation: B
+  defaultRule:
+    variation: A
+flag2:
+  variations:
+    "on": true
+    "off": false
+  defaultRule:
+    variation: "off"
+flag3:
+  variations:
+    small: 1
+    big: 100
+  defaultRule:
+    percentage:
+      small: 50
+      big: 50
+`
+
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		path             string
+		contentType      string
+		body             string
+		flags            []model.FeatureFlag
+		expectedHTTPCode int
+		expectedBody     string
+		expectedFlags    int
+	}{
+		{
+			name:             "should create all the flags of the file",
+			path:             "/v1/import",
+			contentType:      "application/yaml",
+			body:             yamlFile,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"dryRun":false,"created":["flag1","flag2","flag3"],"updated":[],"unchanged":[]}`,
+			expectedFlags:    3,
+		},
+		{
+			name:             "should update the existing flags that are different",
+			path:             "/v1/import",
+			contentType:      "application/yaml",
+			body:             yamlFile,
+			flags:            existingFlags,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"dryRun":false,"created":["flag3"],"updated":["flag1"],"unchanged":["flag2"]}`,
+			expectedFlags:    3,
+		},
+		{
+			name:             "should not store anything in dry run mode",
+			path:             "/v1/import?dryRun=true",
+			contentType:      "application/yaml",
+			body:             yamlFile,
+			flags:            existingFlags,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"dryRun":true,"created":["flag3"],"updated":["flag1"],"unchanged":["flag2"]}`,
+			expectedFlags:    2,
+		},
+		{
+			name:             "should use the format query parameter before the Content-Type header",
+			path:             "/v1/import?format=json",
+			contentType:      "text/plain",
+			body:             `{"flag1":{"variations":{"A":"a","B":"b"},"defaultRule":{"variation":"A"}}}`,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"dryRun":false,"created":["flag1"],"updated":[],"unchanged":[]}`,
+			expectedFlags:    1,
+		},
+		{
+			name:             "should return a 400 with all the invalid flags and store nothing",
+			path:             "/v1/import",
+			contentType:      "application/json",
+			body:             `{"flag1":{"variations":{"A":"a","B":true}},"flag2":{"variations":{"A":"a"}},"flag3":{"variations":{"A":"a"},"defaultRule":{"variation":"A"}}}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"flag flag1: variation B is a boolean but other variations are string\nflag flag2: defaultRule: flag default rule is required","code":400,"errors":[{"field":"flag2.defaultRule","message":"flag default rule is required"}]}`,
+		},
+		{
+			name:             "should return a 400 if the file changes the type of a flag",
+			path:             "/v1/import",
+			contentType:      "application/json",
+			body:             `{"flag2":{"variations":{"on":"yes","off":"no"},"defaultRule":{"variation":"off"}}}`,
+			flags:            existingFlags,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"flag flag2: variations: the variations are string but the flag is boolean, the type of a flag cannot be changed","code":400,"errors":[{"field":"flag2.variations","message":"the variations are string but the flag is boolean, the type of a flag cannot be changed"}]}`,
+		},
+		{
+			name:             "should not update a double flag whose variations have no decimal part",
+			path:             "/v1/import",
+			contentType:      "application/json",
+			body:             `{"flag4":{"variations":{"low":1,"high":2},"defaultRule":{"variation":"low"}}}`,
+			flags:            []model.FeatureFlag{doubleFlag},
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"dryRun":false,"created":[],"updated":[],"unchanged":["flag4"]}`,
+			expectedFlags:    1,
+		},
+		{
+			name:             "should return a 400 if the file is invalid",
+			path:             "/v1/import",
+			contentType:      "application/json",
+			body:             `{"flag1":`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"invalid configuration file: unexpected end of JSON input","code":400}`,
+		},
+		{
+			name:             "should return a 400 if dryRun is not a boolean",
+			path:             "/v1/import?dryRun=maybe",
+			contentType:      "application/json",
+			body:             `{}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"dryRun should be a boolean","code":400}`,
+		},
+		{
+			name:             "should return a 415 if the content type is not supported",
+			path:             "/v1/import",
+			contentType:      "text/plain",
+			body:             yamlFile,
+			expectedHTTPCode: http.StatusUnsupportedMediaType,
+			expectedBody:     `{"errorDetails":"content type text/plain not supported, use application/json, application/yaml or application/toml","code":415}`,
+		},
+		{
+			name:             "should return a 404 if the environment does not exist",
+			path:             "/v1/import?project=checkout&environment=qa",
+			contentType:      "application/yaml",
+			body:             yamlFile,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"environment qa not found in project checkout","code":404}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails to import the flags",
+			ctx:              context.WithValue(context.Background(), "error_import", daoErr.UnknownError),
+			path:             "/v1/import",
+			contentType:      "application/yaml",
+			body:             yamlFile,
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on import flags","code":500}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			flags := make([]model.FeatureFlag, len(tt.flags))
+			copy(flags, tt.flags)
+			mockDao.SetFlags(flags)
+			s := newProjectTestServer(t, mockDao)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req := httptest.NewRequestWithContext(ctx, http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code, rec.Body.String())
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			stored, total, err := mockDao.GetFlags(context.Background(), dao.FlagQuery{})
+			require.Nil(t, err)
+			if tt.expectedHTTPCode != http.StatusOK {
+				assert.Equal(t, len(tt.flags), total)
+				return
+			}
+			assert.Equal(t, tt.expectedFlags, total)
+			for _, flag := range stored {
+				assert.NotEmpty(t, flag.ID)
+				assert.Equal(t, model.DefaultProject, flag.GetProject())
+				switch flag.Name {
+				case "flag1":
+					assert.Equal(t, model.FlagTypeString, flag.VariationType)
+					if tt.flags != nil && tt.expectedFlags == 3 {
+						// the fields that are not part of the file are kept
+						assert.Equal(t, "926214f3-80c1-46e6-a913-b2d40b92a932", flag.ID)
+						assert.Equal(t, "description1", *flag.Description)
+						assert.Equal(t, "926214f3-80c1-46e6-a913-b2d40b92a000", flag.GetDefaultRule().ID)
+						assert.Equal(t, int64(2), flag.Revision)
+						assert.Equal(t, "anonymous", flag.LastModifiedBy)
+					}
+				case "flag4":
+					assert.Equal(t, model.FlagTypeDouble, flag.VariationType)
+					assert.Equal(t, int64(1), flag.Revision)
+				case "flag3":
+					assert.Equal(t, model.FlagTypeInteger, flag.VariationType)
+					assert.NotEmpty(t, flag.GetDefaultRule().ID)
+					assert.Equal(t, "anonymous", flag.LastModifiedBy)
+				}
+			}
+		})
+	}
+}
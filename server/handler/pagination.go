@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPaginationLimit = 20
+	maxPaginationLimit     = 100
+)
+
+// parsePagination reads the limit and offset query parameters of the request.
+// If they are not provided we use a limit of 20 and an offset of 0.
+func parsePagination(c echo.Context) (limit int, offset int, err error) {
+	limit = defaultPaginationLimit
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPaginationLimit {
+			return 0, 0, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Errorf("limit should be an integer between 1 and %d", maxPaginationLimit))
+		}
+	}
+
+	if value := c.QueryParam("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return 0, 0, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Errorf("offset should be a positive integer"))
+		}
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/util"
+	"net/http"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/labstack/echo/v4"
+)
+
+type ProjectHandlerOptions struct {
+	Clock util.Clock
+}
+
+type ProjectHandler struct {
+	dao     dao.ProjectStorage
+	options *ProjectHandlerOptions
+}
+
+// NewProjectHandler creates a new instance of the ProjectHandler handler
+// It is a controller class to manage the projects and environments used to scope the flags
+func NewProjectHandler(dao dao.ProjectStorage, options *ProjectHandlerOptions) ProjectHandler {
+	if options == nil {
+		options = &ProjectHandlerOptions{}
+	}
+	if options.Clock == nil {
+		options.Clock = util.DefaultClock{}
+	}
+	return ProjectHandler{dao: dao, options: options}
+}
+
+type projectListResponse struct {
+	Projects []model.Project `json:"projects"`
+}
+
+type environmentListResponse struct {
+	Environments []model.Environment `json:"environments"`
+}
+
+type scopeCreationRequest struct {
+	// Name is the identifier used in the URLs, it can only contain letters, digits, '.', '_' or '-'
+	Name string `json:"name" example:"production"`
+	// Description (optional) explains what it is used for
+	Description *string `json:"description,omitempty"`
+}
+
+// GetProjects is returning the list of the projects
+// @Summary      Return all the projects
+// @Tags Project management API
+// @Description  GET all the projects ordered by name.
+// @Success      200  {object} projectListResponse "Success"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects [get]
+func (h ProjectHandler) GetProjects(c echo.Context) error {
+	projects, err := h.dao.GetProjects(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, projectListResponse{Projects: projects})
+}
+
+// GetProjectByName is returning the project with the given name
+// @Summary      Return a project
+// @Tags Project management API
+// @Description  GET the project with the given name.
+// @Param        project path string true "Name of the project"
+// @Success      200  {object} model.Project "Success"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project} [get]
+func (h ProjectHandler) GetProjectByName(c echo.Context) error {
+	project, err := h.dao.GetProject(c.Request().Context(), c.Param("project"))
+	if err != nil {
+		return h.handleDaoError(err, "project")
+	}
+	return c.JSON(http.StatusOK, project)
+}
+
+// CreateProject is creating a new project
+// @Summary      Create a new project
+// @Tags Project management API
+// @Description  POST - Create a new project, add environments to the project to be able to create flags in it.
+// @Param 		 data body scopeCreationRequest true "Payload which represents the project to create"
+// @Success      201  {object} model.Project "Created"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      409 {object} api.CustomErr "Conflict - a project with the same name already exists"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects [post]
+func (h ProjectHandler) CreateProject(c echo.Context) error {
+	var request scopeCreationRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := model.ValidateScopeName(request.Name); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	ctx := c.Request().Context()
+	_, err := h.dao.GetProject(ctx, request.Name)
+	if err == nil {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Errorf("project %s already exists", request.Name))
+	}
+	if err.Code() != daoErr.NotFound {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	project := model.Project{
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedDate: h.options.Clock.Now(),
+	}
+	if err := h.dao.CreateProject(ctx, project); err != nil {
+		return h.handleDaoError(err, "project")
+	}
+	return c.JSON(http.StatusCreated, project)
+}
+
+// DeleteProjectByName is deleting the project with the given name
+// @Summary      Delete a project
+// @Tags Project management API
+// @Description  DELETE - Delete the project with the given name, all its environments should be deleted first.
+// @Param        project path string true "Name of the project"
+// @Success      204 "No Content"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      409 {object} api.CustomErr "Conflict - the project still has environments"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project} [delete]
+func (h ProjectHandler) DeleteProjectByName(c echo.Context) error {
+	if err := h.dao.DeleteProject(c.Request().Context(), c.Param("project")); err != nil {
+		return h.handleDaoError(err, "project")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetEnvironments is returning the list of the environments of a project
+// @Summary      Return the environments of a project
+// @Tags Project management API
+// @Description  GET all the environments of the project ordered by name.
+// @Param        project path string true "Name of the project"
+// @Success      200  {object} environmentListResponse "Success"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments [get]
+func (h ProjectHandler) GetEnvironments(c echo.Context) error {
+	ctx := c.Request().Context()
+	if _, err := h.dao.GetProject(ctx, c.Param("project")); err != nil {
+		return h.handleDaoError(err, "project")
+	}
+	environments, err := h.dao.GetEnvironments(ctx, c.Param("project"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, environmentListResponse{Environments: environments})
+}
+
+// GetEnvironmentByName is returning the environment with the given name
+// @Summary      Return an environment
+// @Tags Project management API
+// @Description  GET the environment of the project with the given name.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Success      200  {object} model.Environment "Success"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment} [get]
+func (h ProjectHandler) GetEnvironmentByName(c echo.Context) error {
+	environment, err := h.dao.GetEnvironment(c.Request().Context(), c.Param("project"), c.Param("environment"))
+	if err != nil {
+		return h.handleDaoError(err, "environment")
+	}
+	return c.JSON(http.StatusOK, environment)
+}
+
+// CreateEnvironment is creating a new environment in a project
+// @Summary      Create a new environment
+// @Tags Project management API
+// @Description  POST - Create a new environment in the project.
+// @Param        project path string true "Name of the project"
+// @Param 		 data body scopeCreationRequest true "Payload which represents the environment to create"
+// @Success      201  {object} model.Environment "Created"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found - the project does not exist"
+// @Failure      409 {object} api.CustomErr "Conflict - an environment with the same name already exists in the project"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments [post]
+func (h ProjectHandler) CreateEnvironment(c echo.Context) error {
+	var request scopeCreationRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := model.ValidateScopeName(request.Name); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	ctx := c.Request().Context()
+	projectName := c.Param("project")
+	if _, err := h.dao.GetProject(ctx, projectName); err != nil {
+		return h.handleDaoError(err, "project")
+	}
+	_, err := h.dao.GetEnvironment(ctx, projectName, request.Name)
+	if err == nil {
+		return echo.NewHTTPError(http.StatusConflict,
+			fmt.Errorf("environment %s already exists in project %s", request.Name, projectName))
+	}
+	if err.Code() != daoErr.NotFound {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	environment := model.Environment{
+		Project:     projectName,
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedDate: h.options.Clock.Now(),
+	}
+	if err := h.dao.CreateEnvironment(ctx, environment); err != nil {
+		if err.Code() == daoErr.AlreadyExists {
+			return h.handleDaoError(err, "environment")
+		}
+		// the environment references the project, a NotFound error means the project does not exist anymore
+		return h.handleDaoError(err, "project")
+	}
+	return c.JSON(http.StatusCreated, environment)
+}
+
+// DeleteEnvironmentByName is deleting the environment with the given name
+// @Summary      Delete an environment
+// @Tags Project management API
+// @Description  DELETE - Delete the environment of the project, all its flags should be deleted first.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Success      204 "No Content"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      409 {object} api.CustomErr "Conflict - the environment still has flags"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment} [delete]
+func (h ProjectHandler) DeleteEnvironmentByName(c echo.Context) error {
+	err := h.dao.DeleteEnvironment(c.Request().Context(), c.Param("project"), c.Param("environment"))
+	if err != nil {
+		return h.handleDaoError(err, "environment")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// handleDaoError is a helper function to handle the dao errors and return the correct HTTP status code.
+func (h ProjectHandler) handleDaoError(err daoErr.DaoError, entity string) error {
+	switch err.Code() {
+	case daoErr.NotFound:
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("%s not found", entity))
+	case daoErr.InUse:
+		return echo.NewHTTPError(http.StatusConflict, fmt.Errorf("%s is still in use: %w", entity, err))
+	case daoErr.AlreadyExists:
+		return echo.NewHTTPError(http.StatusConflict, fmt.Errorf("%s already exists: %w", entity, err))
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,224 @@
+package handler_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/api"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/handler"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProjectTestServer(t *testing.T, mockDao *dao.InMemoryMockDao) *api.Server {
+	hf := handler.NewFlagAPIHandler(mockDao, &handler.FlagAPIHandlerOptions{Clock: testutils2.ClockMock{}})
+	hh := handler.NewHealthHandler(mockDao)
+	hp := handler.NewProjectHandler(mockDao, &handler.ProjectHandlerOptions{Clock: testutils2.ClockMock{}})
+	s, err := api.New(&config.Configuration{
+		Mode: "development",
+	}, handler.Handlers{
+		FlagAPIHandler: &hf,
+		HealthHandler:  &hh,
+		ProjectHandler: &hp,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+// newProjectTestDao returns a storage with a "checkout" project having a "staging" and a "production" environment
+func newProjectTestDao(t *testing.T) *dao.InMemoryMockDao {
+	mockDao, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.Nil(t, mockDao.CreateProject(ctx, model.Project{Name: "checkout"}))
+	require.Nil(t, mockDao.CreateEnvironment(ctx, model.Environment{Project: "checkout", Name: "staging"}))
+	require.Nil(t, mockDao.CreateEnvironment(ctx, model.Environment{Project: "checkout", Name: "production"}))
+	return mockDao
+}
+
+func TestProjectHandler(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		method           string
+		path             string
+		body             string
+		flags            []model.FeatureFlag
+		expectedHTTPCode int
+		expectedBody     string
+	}{
+		{
+			name:             "should list the projects ordered by name",
+			method:           http.MethodGet,
+			path:             "/v1/projects",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"projects":[{"name":"checkout","createdDate":"0001-01-01T00:00:00Z"},{"name":"default","createdDate":"0001-01-01T00:00:00Z"}]}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails to list the projects",
+			ctx:              context.WithValue(context.Background(), "error", daoErr.UnknownError),
+			method:           http.MethodGet,
+			path:             "/v1/projects",
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on get projects","code":500}`,
+		},
+		{
+			name:             "should get a project",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"name":"checkout","createdDate":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:             "should return a 404 for an unknown project",
+			method:           http.MethodGet,
+			path:             "/v1/projects/unknown",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"project not found","code":404}`,
+		},
+		{
+			name:             "should create a project",
+			method:           http.MethodPost,
+			path:             "/v1/projects",
+			body:             `{"name":"search","description":"search engine"}`,
+			expectedHTTPCode: http.StatusCreated,
+			expectedBody:     `{"name":"search","description":"search engine","createdDate":"2020-01-01T00:00:00Z"}`,
+		},
+		{
+			name:             "should return a 400 if the project name is invalid",
+			method:           http.MethodPost,
+			path:             "/v1/projects",
+			body:             `{"name":"my project"}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"name my project is invalid, it should only contain letters, digits, '.', '_' or '-'","code":400}`,
+		},
+		{
+			name:             "should return a 409 if the project already exists",
+			method:           http.MethodPost,
+			path:             "/v1/projects",
+			body:             `{"name":"checkout"}`,
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"project checkout already exists","code":409}`,
+		},
+		{
+			name:             "should return a 409 when deleting a project with environments",
+			method:           http.MethodDelete,
+			path:             "/v1/projects/checkout",
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"project is still in use: project checkout still has environments","code":409}`,
+		},
+		{
+			name:             "should return a 404 when deleting an unknown project",
+			method:           http.MethodDelete,
+			path:             "/v1/projects/unknown",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"project not found","code":404}`,
+		},
+		{
+			name:             "should list the environments of a project",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"environments":[{"project":"checkout","name":"production","createdDate":"0001-01-01T00:00:00Z"},{"project":"checkout","name":"staging","createdDate":"0001-01-01T00:00:00Z"}]}`,
+		},
+		{
+			name:             "should return a 404 when listing the environments of an unknown project",
+			method:           http.MethodGet,
+			path:             "/v1/projects/unknown/environments",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"project not found","code":404}`,
+		},
+		{
+			name:             "should get an environment",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments/staging",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"project":"checkout","name":"staging","createdDate":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:             "should return a 404 for an environment of another project",
+			method:           http.MethodGet,
+			path:             "/v1/projects/default/environments/staging",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"environment not found","code":404}`,
+		},
+		{
+			name:             "should create an environment",
+			method:           http.MethodPost,
+			path:             "/v1/projects/checkout/environments",
+			body:             `{"name":"qa"}`,
+			expectedHTTPCode: http.StatusCreated,
+			expectedBody:     `{"project":"checkout","name":"qa","createdDate":"2020-01-01T00:00:00Z"}`,
+		},
+		{
+			name:             "should return a 404 when creating an environment in an unknown project",
+			method:           http.MethodPost,
+			path:             "/v1/projects/unknown/environments",
+			body:             `{"name":"qa"}`,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"project not found","code":404}`,
+		},
+		{
+			name:             "should return a 409 if the environment already exists",
+			method:           http.MethodPost,
+			path:             "/v1/projects/checkout/environments",
+			body:             `{"name":"staging"}`,
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"environment staging already exists in project checkout","code":409}`,
+		},
+		{
+			name:             "should return a 400 if the environment name is missing",
+			method:           http.MethodPost,
+			path:             "/v1/projects/checkout/environments",
+			body:             `{}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"name is required","code":400}`,
+		},
+		{
+			name:             "should delete an environment without flags",
+			method:           http.MethodDelete,
+			path:             "/v1/projects/checkout/environments/staging",
+			expectedHTTPCode: http.StatusNoContent,
+		},
+		{
+			name:   "should return a 409 when deleting an environment with flags",
+			method: http.MethodDelete,
+			path:   "/v1/projects/checkout/environments/staging",
+			flags: []model.FeatureFlag{
+				{ID: "926214f3-80c1-46e6-a913-b2d40b92a932", Name: "flag1", Project: "checkout", Environment: "staging"},
+			},
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"environment is still in use: environment staging still has flags","code":409}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			if tt.flags != nil {
+				mockDao.SetFlags(tt.flags)
+			}
+			s := newProjectTestServer(t, mockDao)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req := httptest.NewRequestWithContext(ctx, tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
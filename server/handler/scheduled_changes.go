@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/util"
+	"net/http"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var errScheduledChangeNotFound = errors.New("scheduled change not found")
+
+type ScheduledChangeHandlerOptions struct {
+	Clock util.Clock
+}
+
+type ScheduledChangeHandler struct {
+	flagDao   dao.FlagStorage
+	changeDao dao.ScheduledChangeStorage
+	options   *ScheduledChangeHandlerOptions
+}
+
+// NewScheduledChangeHandler creates a new instance of the ScheduledChangeHandler handler
+// It is a controller class to schedule changes of the flags, they are applied by the scheduler worker
+func NewScheduledChangeHandler(flagDao dao.FlagStorage, changeDao dao.ScheduledChangeStorage,
+	options *ScheduledChangeHandlerOptions) ScheduledChangeHandler {
+	if options == nil {
+		options = &ScheduledChangeHandlerOptions{}
+	}
+	if options.Clock == nil {
+		options.Clock = util.DefaultClock{}
+	}
+	return ScheduledChangeHandler{flagDao: flagDao, changeDao: changeDao, options: options}
+}
+
+type scheduledChangeListResponse struct {
+	ScheduledChanges []model.ScheduledChange `json:"scheduledChanges"`
+}
+
+type scheduledChangeRequest struct {
+	// ScheduledDate is the date from which the change is applied, it should be in the future
+	ScheduledDate time.Time `json:"scheduledDate" example:"2024-06-01T08:00:00Z"`
+	// Changes are the fields of the flag replaced when the change is applied
+	Changes model.FlagChanges `json:"changes"`
+}
+
+// GetScheduledChanges is returning the list of the scheduled changes
+// @Summary      Return all the scheduled changes
+// @Tags Scheduled change management API
+// @Description  GET all the scheduled changes of all the flags, ordered by scheduled date.
+// @Param        status query string false "Only return the changes with this status (pending, applied, failed or cancelled)"
+// @Success      200  {object} scheduledChangeListResponse "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/schedules [get]
+func (h ScheduledChangeHandler) GetScheduledChanges(c echo.Context) error {
+	return h.listScheduledChanges(c, "")
+}
+
+// GetFlagScheduledChanges is returning the scheduled changes of the flag with the given ID
+// @Summary      Return the scheduled changes of a flag
+// @Tags Scheduled change management API
+// @Description  GET the scheduled changes of the flag with the given ID, ordered by scheduled date.
+// @Param        id path string true "ID of the feature flag"
+// @Param        status query string false "Only return the changes with this status (pending, applied, failed or cancelled)"
+// @Success      200  {object} scheduledChangeListResponse "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/flags/{id}/schedules [get]
+func (h ScheduledChangeHandler) GetFlagScheduledChanges(c echo.Context) error {
+	flag, err := h.getFlag(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return h.listScheduledChanges(c, flag.ID)
+}
+
+func (h ScheduledChangeHandler) listScheduledChanges(c echo.Context, flagID string) error {
+	query := dao.ScheduledChangeQuery{FlagID: flagID}
+	if statusParam := c.QueryParam("status"); statusParam != "" {
+		status, err := model.ScheduledChangeStatusFromValue(statusParam)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+		query.Status = status
+	}
+	changes, err := h.changeDao.GetScheduledChanges(c.Request().Context(), query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, scheduledChangeListResponse{ScheduledChanges: changes})
+}
+
+// CreateFlagScheduledChange is scheduling a change of the flag with the given ID
+// @Summary      Schedule a change of a flag
+// @Tags Scheduled change management API
+// @Description  POST - Schedule a change of the targeting rules, the default rule or the status of the flag.
+// @Description  The change is applied by the server once the scheduled date is reached, in the name of the
+// @Description  user who scheduled it. The flag should still be valid with the change applied.
+// @Param        id path string true "ID of the feature flag"
+// @Param 		 data body scheduledChangeRequest true "Payload which represents the change to schedule"
+// @Success      201  {object} model.ScheduledChange "Created"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/flags/{id}/schedules [post]
+func (h ScheduledChangeHandler) CreateFlagScheduledChange(c echo.Context) error {
+	flag, err := h.getFlag(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	var request scheduledChangeRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if code, err := h.validateScheduledChange(flag, request); err != nil {
+		return echo.NewHTTPError(code, err)
+	}
+
+	now := h.options.Clock.Now()
+	change := model.ScheduledChange{
+		ID:              uuid.NewString(),
+		FlagID:          flag.ID,
+		ScheduledDate:   request.ScheduledDate,
+		Changes:         request.Changes,
+		Status:          model.ScheduledChangeStatusPending,
+		CreatedDate:     now,
+		CreatedBy:       auth.PrincipalFromContext(c).String(),
+		LastUpdatedDate: now,
+	}
+	if _, err := h.changeDao.CreateScheduledChange(c.Request().Context(), change); err != nil {
+		return h.handleDaoError(err)
+	}
+	return c.JSON(http.StatusCreated, change)
+}
+
+// GetScheduledChangeByID is returning the scheduled change with the given ID
+// @Summary      Return the scheduled change with the given ID
+// @Tags Scheduled change management API
+// @Description  GET the scheduled change with the given ID, with its status and the error if it has failed.
+// @Param        scheduleId path string true "ID of the scheduled change"
+// @Success      200  {object} model.ScheduledChange "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/schedules/{scheduleId} [get]
+func (h ScheduledChangeHandler) GetScheduledChangeByID(c echo.Context) error {
+	change, err := h.changeDao.GetScheduledChangeByID(c.Request().Context(), c.Param("scheduleId"))
+	if err != nil {
+		return h.handleDaoError(err)
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+// UpdateScheduledChangeByID is editing the pending scheduled change with the given ID
+// @Summary      Edit a pending scheduled change
+// @Tags Scheduled change management API
+// @Description  PUT - Replace the date and the changes of a pending scheduled change.
+// @Description  The changes already applied, failed or cancelled cannot be edited anymore.
+// @Param        scheduleId path string true "ID of the scheduled change"
+// @Param 		 data body scheduledChangeRequest true "Payload which represents the new change"
+// @Success      200  {object} model.ScheduledChange "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      409 {object} api.CustomErr "Conflict - the change is not pending anymore"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/schedules/{scheduleId} [put]
+func (h ScheduledChangeHandler) UpdateScheduledChangeByID(c echo.Context) error {
+	change, err := h.getPendingChange(c)
+	if err != nil {
+		return err
+	}
+	var request scheduledChangeRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	flag, err := h.getFlag(c, change.FlagID)
+	if err != nil {
+		return err
+	}
+	if code, err := h.validateScheduledChange(flag, request); err != nil {
+		return echo.NewHTTPError(code, err)
+	}
+
+	change.ScheduledDate = request.ScheduledDate
+	change.Changes = request.Changes
+	change.LastUpdatedDate = h.options.Clock.Now()
+	if err := h.changeDao.UpdateScheduledChange(c.Request().Context(), change); err != nil {
+		return h.handleDaoError(err)
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+// CancelScheduledChangeByID is cancelling the pending scheduled change with the given ID
+// @Summary      Cancel a pending scheduled change
+// @Tags Scheduled change management API
+// @Description  DELETE - Cancel a pending scheduled change, it will not be applied.
+// @Description  The change is kept with the status cancelled.
+// @Param        scheduleId path string true "ID of the scheduled change"
+// @Success      200  {object} model.ScheduledChange "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      409 {object} api.CustomErr "Conflict - the change is not pending anymore"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/schedules/{scheduleId} [delete]
+func (h ScheduledChangeHandler) CancelScheduledChangeByID(c echo.Context) error {
+	change, err := h.getPendingChange(c)
+	if err != nil {
+		return err
+	}
+	change.Status = model.ScheduledChangeStatusCancelled
+	change.LastUpdatedDate = h.options.Clock.Now()
+	if err := h.changeDao.UpdateScheduledChange(c.Request().Context(), change); err != nil {
+		return h.handleDaoError(err)
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+// GetEnvironmentFlagScheduledChanges is returning the scheduled changes of the flag with the given ID of an environment
+// @Summary      Return the scheduled changes of a flag of an environment
+// @Tags Scheduled change management API
+// @Description  GET the scheduled changes of the flag with the given ID, ordered by scheduled date.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param        status query string false "Only return the changes with this status (pending, applied, failed or cancelled)"
+// @Success      200  {object} scheduledChangeListResponse "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id}/schedules [get]
+func (h ScheduledChangeHandler) GetEnvironmentFlagScheduledChanges(c echo.Context) error {
+	return h.GetFlagScheduledChanges(c)
+}
+
+// CreateEnvironmentFlagScheduledChange is scheduling a change of the flag with the given ID of an environment
+// @Summary      Schedule a change of a flag of an environment
+// @Tags Scheduled change management API
+// @Description  POST - Schedule a change of the targeting rules, the default rule or the status of the flag.
+// @Description  The change is applied by the server once the scheduled date is reached.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param 		 data body scheduledChangeRequest true "Payload which represents the change to schedule"
+// @Success      201  {object} model.ScheduledChange "Created"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id}/schedules [post]
+func (h ScheduledChangeHandler) CreateEnvironmentFlagScheduledChange(c echo.Context) error {
+	return h.CreateFlagScheduledChange(c)
+}
+
+// validateScheduledChange checks that the change can be applied to the flag in its current state
+func (h ScheduledChangeHandler) validateScheduledChange(
+	flag model.FeatureFlag, request scheduledChangeRequest) (int, error) {
+	if request.ScheduledDate.IsZero() {
+		return http.StatusBadRequest, errors.New("scheduled date is required")
+	}
+	if !request.ScheduledDate.After(h.options.Clock.Now()) {
+		return http.StatusBadRequest, errors.New("scheduled date should be in the future")
+	}
+	if request.Changes.IsEmpty() {
+		return http.StatusBadRequest,
+			errors.New("scheduled change should modify the targeting, the default rule or the status of the flag")
+	}
+	return validateFlag(request.Changes.ApplyTo(flag))
+}
+
+// getFlag returns the flag with the given ID if it is in the scope of the route
+func (h ScheduledChangeHandler) getFlag(c echo.Context, id string) (model.FeatureFlag, error) {
+	flag, err := h.flagDao.GetFlagByID(c.Request().Context(), id)
+	if err != nil {
+		switch err.Code() {
+		case daoErr.NotFound:
+			return model.FeatureFlag{}, echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+		case daoErr.InvalidUUID:
+			return model.FeatureFlag{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid UUID format"))
+		default:
+			return model.FeatureFlag{}, echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+	}
+	if !inRouteScope(c, flag) {
+		return model.FeatureFlag{}, echo.NewHTTPError(http.StatusNotFound, errFlagNotFound)
+	}
+	return flag, nil
+}
+
+// getPendingChange returns the scheduled change of the route, with a 409 if it is not pending anymore
+func (h ScheduledChangeHandler) getPendingChange(c echo.Context) (model.ScheduledChange, error) {
+	change, err := h.changeDao.GetScheduledChangeByID(c.Request().Context(), c.Param("scheduleId"))
+	if err != nil {
+		return model.ScheduledChange{}, h.handleDaoError(err)
+	}
+	if !change.IsPending() {
+		return model.ScheduledChange{}, echo.NewHTTPError(http.StatusConflict,
+			fmt.Errorf("scheduled change is %s, only the pending changes can be modified", change.Status))
+	}
+	return change, nil
+}
+
+func (h ScheduledChangeHandler) handleDaoError(err daoErr.DaoError) error {
+	switch err.Code() {
+	case daoErr.NotFound:
+		return echo.NewHTTPError(http.StatusNotFound, errScheduledChangeNotFound)
+	case daoErr.InvalidUUID:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid UUID format"))
+	case daoErr.StaleRevision:
+		return echo.NewHTTPError(http.StatusConflict,
+			errors.New("scheduled change is not pending anymore, only the pending changes can be modified"))
+	case daoErr.AlreadyExists:
+		return echo.NewHTTPError(http.StatusConflict, err)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+}
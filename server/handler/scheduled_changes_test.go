@@ -0,0 +1,300 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-feature-flag/flag-management/server/api"
+	"github.com/go-feature-flag/flag-management/server/config"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/handler"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScheduledChangeTestServer(t *testing.T, mockDao *dao.InMemoryMockDao) *api.Server {
+	hf := handler.NewFlagAPIHandler(mockDao, &handler.FlagAPIHandlerOptions{Clock: testutils2.ClockMock{}})
+	hh := handler.NewHealthHandler(mockDao)
+	hs := handler.NewScheduledChangeHandler(mockDao, mockDao,
+		&handler.ScheduledChangeHandlerOptions{Clock: testutils2.ClockMock{}})
+	s, err := api.New(&config.Configuration{
+		Mode: "development",
+	}, handler.Handlers{
+		FlagAPIHandler:         &hf,
+		HealthHandler:          &hh,
+		ScheduledChangeHandler: &hs,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func scheduledChangeTestFlags() []model.FeatureFlag {
+	return []model.FeatureFlag{
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+			Name:          "flag1",
+			Project:       "checkout",
+			Environment:   "staging",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils2.String("A")},
+			Disable:       testutils2.Bool(true),
+		},
+	}
+}
+
+func scheduledChangeTestChanges() []model.ScheduledChange {
+	appliedDate := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	return []model.ScheduledChange{
+		{
+			ID:              "0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+			FlagID:          "926214f3-80c1-46e6-a913-b2d40b92a932",
+			ScheduledDate:   time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+			Changes:         model.FlagChanges{Disable: testutils2.Bool(false)},
+			Status:          model.ScheduledChangeStatusPending,
+			CreatedDate:     time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC),
+			CreatedBy:       "foo",
+			LastUpdatedDate: time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:              "0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c02",
+			FlagID:          "926214f3-80c1-46e6-a913-b2d40b92a932",
+			ScheduledDate:   appliedDate,
+			Changes:         model.FlagChanges{Disable: testutils2.Bool(true)},
+			Status:          model.ScheduledChangeStatusApplied,
+			CreatedDate:     time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC),
+			CreatedBy:       "foo",
+			LastUpdatedDate: appliedDate,
+			AppliedDate:     &appliedDate,
+		},
+	}
+}
+
+func TestScheduledChangeHandler(t *testing.T) {
+	pendingChange := `{"id":"0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01","flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","scheduledDate":"2020-02-01T00:00:00Z","changes":{"disable":false},"status":"pending","createdDate":"2019-11-01T00:00:00Z","createdBy":"foo","lastUpdatedDate":"2019-11-01T00:00:00Z"}`
+	appliedChange := `{"id":"0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c02","flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","scheduledDate":"2019-12-01T00:00:00Z","changes":{"disable":true},"status":"applied","createdDate":"2019-11-01T00:00:00Z","createdBy":"foo","lastUpdatedDate":"2019-12-01T00:00:00Z","appliedDate":"2019-12-01T00:00:00Z"}`
+
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		method           string
+		path             string
+		body             string
+		expectedHTTPCode int
+		expectedBody     string
+	}{
+		{
+			name:             "should list all the scheduled changes ordered by date",
+			method:           http.MethodGet,
+			path:             "/v1/schedules",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"scheduledChanges":[` + appliedChange + `,` + pendingChange + `]}`,
+		},
+		{
+			name:             "should filter the scheduled changes by status",
+			method:           http.MethodGet,
+			path:             "/v1/schedules?status=pending",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"scheduledChanges":[` + pendingChange + `]}`,
+		},
+		{
+			name:             "should return a 400 for an unknown status",
+			method:           http.MethodGet,
+			path:             "/v1/schedules?status=done",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"scheduled change status done not supported","code":400}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails to list the scheduled changes",
+			ctx:              context.WithValue(context.Background(), "error", daoErr.UnknownError),
+			method:           http.MethodGet,
+			path:             "/v1/schedules",
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on get scheduled changes","code":500}`,
+		},
+		{
+			name:             "should list the scheduled changes of a flag",
+			method:           http.MethodGet,
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules?status=applied",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"scheduledChanges":[` + appliedChange + `]}`,
+		},
+		{
+			name:             "should list the scheduled changes of a flag of an environment",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments/staging/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules?status=pending",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"scheduledChanges":[` + pendingChange + `]}`,
+		},
+		{
+			name:             "should return a 404 for a flag of another environment",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments/production/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+		{
+			name:             "should return a 404 for the scheduled changes of an unknown flag",
+			method:           http.MethodGet,
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a999/schedules",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+		{
+			name:             "should get a scheduled change",
+			method:           http.MethodGet,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     pendingChange,
+		},
+		{
+			name:             "should return a 404 for an unknown scheduled change",
+			method:           http.MethodGet,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c99",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"scheduled change not found","code":404}`,
+		},
+		{
+			name:             "should edit a pending scheduled change",
+			method:           http.MethodPut,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+			body:             `{"scheduledDate":"2020-03-01T00:00:00Z","changes":{"defaultRule":{"id":"926214f3-80c1-46e6-a913-b2d40b92a000","variation":"B"}}}`,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"id":"0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01","flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","scheduledDate":"2020-03-01T00:00:00Z","changes":{"defaultRule":{"id":"926214f3-80c1-46e6-a913-b2d40b92a000","variation":"B"}},"status":"pending","createdDate":"2019-11-01T00:00:00Z","createdBy":"foo","lastUpdatedDate":"2020-01-01T00:00:00Z"}`,
+		},
+		{
+			name:             "should not edit a scheduled change to a date in the past",
+			method:           http.MethodPut,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+			body:             `{"scheduledDate":"2019-03-01T00:00:00Z","changes":{"disable":false}}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"scheduled date should be in the future","code":400}`,
+		},
+		{
+			name:             "should not edit a scheduled change already applied",
+			method:           http.MethodPut,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c02",
+			body:             `{"scheduledDate":"2020-03-01T00:00:00Z","changes":{"disable":false}}`,
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"scheduled change is applied, only the pending changes can be modified","code":409}`,
+		},
+		{
+			name:             "should cancel a pending scheduled change",
+			method:           http.MethodDelete,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"id":"0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01","flagId":"926214f3-80c1-46e6-a913-b2d40b92a932","scheduledDate":"2020-02-01T00:00:00Z","changes":{"disable":false},"status":"cancelled","createdDate":"2019-11-01T00:00:00Z","createdBy":"foo","lastUpdatedDate":"2020-01-01T00:00:00Z"}`,
+		},
+		{
+			name:             "should not cancel a scheduled change already applied",
+			method:           http.MethodDelete,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c02",
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"scheduled change is applied, only the pending changes can be modified","code":409}`,
+		},
+		{
+			name:             "should return a 500 if the storage fails to cancel the scheduled change",
+			ctx:              context.WithValue(context.Background(), "error_update", daoErr.UnknownError),
+			method:           http.MethodDelete,
+			path:             "/v1/schedules/0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedBody:     `{"errorDetails":"error on update scheduled change","code":500}`,
+		},
+		{
+			name:             "should not schedule a change without date",
+			method:           http.MethodPost,
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules",
+			body:             `{"changes":{"disable":false}}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"scheduled date is required","code":400}`,
+		},
+		{
+			name:             "should not schedule a change without changes",
+			method:           http.MethodPost,
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules",
+			body:             `{"scheduledDate":"2020-03-01T00:00:00Z","changes":{}}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"scheduled change should modify the targeting, the default rule or the status of the flag","code":400}`,
+		},
+		{
+			name:             "should not schedule a change making the flag invalid",
+			method:           http.MethodPost,
+			path:             "/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules",
+			body:             `{"scheduledDate":"2020-03-01T00:00:00Z","changes":{"targeting":[{"name":"beta","query":"beta eq true","variation":"C"}]}}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"targeting[0].variation: variation C does not exist","code":400,"errors":[{"field":"targeting[0].variation","message":"variation C does not exist"}]}`,
+		},
+		{
+			name:             "should not schedule a change of a flag of another environment",
+			method:           http.MethodPost,
+			path:             "/v1/projects/checkout/environments/production/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules",
+			body:             `{"scheduledDate":"2020-03-01T00:00:00Z","changes":{"disable":false}}`,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			mockDao.SetFlags(scheduledChangeTestFlags())
+			mockDao.SetScheduledChanges(scheduledChangeTestChanges())
+			s := newScheduledChangeTestServer(t, mockDao)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req := httptest.NewRequestWithContext(ctx, tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestScheduledChangeHandler_CreateFlagScheduledChange(t *testing.T) {
+	paths := []string{
+		"/v1/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules",
+		"/v1/projects/checkout/environments/staging/flags/926214f3-80c1-46e6-a913-b2d40b92a932/schedules",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			mockDao.SetFlags(scheduledChangeTestFlags())
+			s := newScheduledChangeTestServer(t, mockDao)
+
+			body := `{"scheduledDate":"2020-03-01T08:00:00Z","changes":{"disable":false}}`
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+			var got model.ScheduledChange
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.NotEmpty(t, got.ID)
+			assert.Equal(t, "926214f3-80c1-46e6-a913-b2d40b92a932", got.FlagID)
+			assert.Equal(t, time.Date(2020, 3, 1, 8, 0, 0, 0, time.UTC), got.ScheduledDate)
+			assert.Equal(t, model.FlagChanges{Disable: testutils2.Bool(false)}, got.Changes)
+			assert.Equal(t, model.ScheduledChangeStatusPending, got.Status)
+			assert.Equal(t, "anonymous", got.CreatedBy)
+			assert.Equal(t, testutils2.ClockMock{}.Now(), got.CreatedDate)
+
+			stored, err := mockDao.GetScheduledChangeByID(context.Background(), got.ID)
+			require.Nil(t, err)
+			assert.Equal(t, got, stored)
+		})
+	}
+}
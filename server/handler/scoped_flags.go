@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"net/http"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/labstack/echo/v4"
+)
+
+var errFlagNotFound = errors.New("flag not found")
+
+// routeScope returns the project and environment of the scoped routes
+// (/v1/projects/:project/environments/:environment/flags), they are empty for the /v1/flags routes.
+func routeScope(c echo.Context) (string, string) {
+	return c.Param("project"), c.Param("environment")
+}
+
+// queryScope returns the project and environment of the query parameters, the default ones if not set.
+func queryScope(c echo.Context) (string, string) {
+	project, environment := c.QueryParam("project"), c.QueryParam("environment")
+	if project == "" {
+		project = model.DefaultProject
+	}
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+	return project, environment
+}
+
+// inRouteScope returns false if the route is scoped to another environment than the one of the flag.
+// On the /v1/flags routes every flag is in scope.
+func inRouteScope(c echo.Context, flag model.FeatureFlag) bool {
+	project, environment := routeScope(c)
+	if project == "" {
+		return true
+	}
+	return flag.GetProject() == project && flag.GetEnvironment() == environment
+}
+
+// RequireEnvironment is a middleware for the scoped routes, it returns a 404 if the environment of the path
+// does not exist.
+func (f FlagAPIHandler) RequireEnvironment(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project, environment := routeScope(c)
+		if err := f.checkEnvironmentExists(c, project, environment); err != nil {
+			return err
+		}
+		return next(c)
+	}
+}
+
+// checkEnvironmentExists returns a 404 if the environment does not exist in the project.
+func (f FlagAPIHandler) checkEnvironmentExists(c echo.Context, project string, environment string) error {
+	if _, err := f.dao.GetEnvironment(c.Request().Context(), project, environment); err != nil {
+		if err.Code() == daoErr.NotFound {
+			return echo.NewHTTPError(http.StatusNotFound,
+				fmt.Errorf("environment %s not found in project %s", environment, project))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+// GetEnvironmentFeatureFlags is returning the list of the flags of an environment
+// @Summary      Return the flags of an environment
+// @Tags Feature Flag management API
+// @Description  GET request to get a paginated list of the flags of an environment.
+// @Description  It accepts the same filters and sort options as /v1/flags.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        limit query int false "Maximum number of flags to return (default 20, max 100)"
+// @Param        offset query int false "Number of flags to skip before starting to collect the result set"
+// @Param        sort query string false "Field used to sort the flags (default -lastUpdatedDate)"
+// @Param        name query string false "Return only the flags with a name starting with this value"
+// @Param        type query string false "Return only the flags of this type"
+// @Param        disabled query bool false "Return only the disabled (true) or enabled (false) flags"
+// @Param        metadataKey query string false "Return only the flags having this key in their metadata"
+// @Param        metadataValue query string false "Return only the flags where metadataKey has this value"
+// @Param        If-None-Match header string false "ETag of a previous response, if nothing changed we return a 304"
+// @Success      200  {object} flagListResponse "Success"
+// @Success      304 "Not Modified - nothing changed since the ETag provided in If-None-Match"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found - the environment does not exist"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Header       200 {string} ETag "Entity tag of the list of flags"
+// @Router       /v1/projects/{project}/environments/{environment}/flags [get]
+func (f FlagAPIHandler) GetEnvironmentFeatureFlags(c echo.Context) error {
+	return f.GetAllFeatureFlags(c)
+}
+
+// GetEnvironmentFeatureFlagByID is returning the flag belonging to the given ID in an environment
+// @Summary      Return all the information about a flag of an environment
+// @Tags Feature Flag management API
+// @Description  GET all the information about a flag, we return a 404 if the flag is not in this environment.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param        If-None-Match header string false "ETag of a previous response, if the flag didn't change we return a 304"
+// @Success      200  {object} model.FeatureFlag "Success"
+// @Success      304 "Not Modified - the flag didn't change since the ETag provided in If-None-Match"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Header       200 {string} ETag "Entity tag of the flag"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id} [get]
+func (f FlagAPIHandler) GetEnvironmentFeatureFlagByID(c echo.Context) error {
+	return f.GetFeatureFlagByID(c)
+}
+
+// CreateEnvironmentFlag is creating a new flag in an environment
+// @Summary      Create a new feature flag in an environment
+// @Tags Feature Flag management API
+// @Description  POST will insert the new feature flag in the environment of the path,
+// @Description  the project and environment of the payload are ignored.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param 		 data body model.FeatureFlag true "Payload which represents the flag to insert"
+// @Success      201  {object} model.FeatureFlag "Created"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found - the environment does not exist"
+// @Failure      409 {object} api.CustomErr "Conflict - when a flag with the same name already exists in the environment"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags [post]
+func (f FlagAPIHandler) CreateEnvironmentFlag(c echo.Context) error {
+	return f.CreateNewFlag(c)
+}
+
+// UpdateEnvironmentFlagByID is updating the flag with the given ID in an environment
+// @Summary      Updates the flag with the given ID in an environment
+// @Tags Feature Flag management API
+// @Description  PUT - Updates the flag with the given ID with what is in the payload. It will replace completely the feature flag.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param        If-Match header string false "ETag of the flag you have retrieved, we return a 412 if the flag has changed since"
+// @Param 		 data body model.FeatureFlag true "Payload which represents the flag to update"
+// @Success      200  {object} model.FeatureFlag "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      412 {object} api.CustomErr "Precondition Failed - the flag has been modified since you retrieved it"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id} [put]
+func (f FlagAPIHandler) UpdateEnvironmentFlagByID(c echo.Context) error {
+	return f.UpdateFlagByID(c)
+}
+
+// DeleteEnvironmentFlagByID is deleting the flag with the given ID in an environment
+// @Summary      Delete the flag with the given ID in an environment
+// @Tags Feature Flag management API
+// @Description  DELETE - Delete the flag with the given ID, we return a 404 if the flag is not in this environment.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Success      204  {object} model.FeatureFlag "No Content"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id} [delete]
+func (f FlagAPIHandler) DeleteEnvironmentFlagByID(c echo.Context) error {
+	return f.DeleteFlagByID(c)
+}
+
+// UpdateEnvironmentFeatureFlagStatus is updating the status of the flag with the given ID in an environment
+// @Summary      Update the status of the flag with the given ID in an environment
+// @Tags Feature Flag management API
+// @Description  PATCH - Update the status of the flag with the given ID
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param        If-Match header string false "ETag of the flag you have retrieved, we return a 412 if the flag has changed since"
+// @Param 		 data body model.FeatureFlagStatusUpdate true "The patch query to update the flag status"
+// @Success      200  {object} model.FeatureFlag "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      412 {object} api.CustomErr "Precondition Failed - the flag has been modified since you retrieved it"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id}/status [patch]
+func (f FlagAPIHandler) UpdateEnvironmentFeatureFlagStatus(c echo.Context) error {
+	return f.UpdateFeatureFlagStatus(c)
+}
+
+// GetEnvironmentFlagVersions is returning the history of the flag with the given ID in an environment
+// @Summary      Get a paginated list of available versions for a feature flag of an environment
+// @Tags Feature Flag management API
+// @Description  GET all the recorded versions of a flag, the most recent first.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param        limit query int false "Maximum number of versions to return (default 20, max 100)"
+// @Param        offset query int false "Number of versions to skip before starting to collect the result set"
+// @Success      200  {object} flagVersionListResponse "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id}/versions [get]
+func (f FlagAPIHandler) GetEnvironmentFlagVersions(c echo.Context) error {
+	return f.GetFlagVersions(c)
+}
+
+// RestoreEnvironmentFlagVersion is restoring the flag with the given ID of an environment to a previous version
+// @Summary      Restore a feature flag of an environment to a previous version
+// @Tags Feature Flag management API
+// @Description  POST - Rebuild the flag from the given version and save it as the current state of the flag.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param        versionId path string true "ID of the version to restore"
+// @Param        If-Match header string false "ETag of the flag you have retrieved, we return a 412 if the flag has changed since"
+// @Success      200  {object} model.FeatureFlag "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      409 {object} api.CustomErr "Conflict - when another flag is already using the name of the version"
+// @Failure      412 {object} api.CustomErr "Precondition Failed - the flag has been modified since you retrieved it"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id}/versions/{versionId}/restore [post]
+func (f FlagAPIHandler) RestoreEnvironmentFlagVersion(c echo.Context) error {
+	return f.RestoreFlagVersion(c)
+}
+
+// EvaluateEnvironmentFlag is returning the variation a user would get for the flag with the given ID of an environment
+// @Summary      Preview the evaluation of a flag of an environment for a user
+// @Tags Feature Flag management API
+// @Description  POST an evaluation context (targeting key and attributes) to know which rule matches, which variation
+// @Description  and value the user gets and why. Nothing is modified.
+// @Param        project path string true "Name of the project"
+// @Param        environment path string true "Name of the environment"
+// @Param        id path string true "ID of the feature flag"
+// @Param 		 data body evaluation.Context true "Evaluation context of the user"
+// @Success      200  {object} evaluation.Result "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/projects/{project}/environments/{environment}/flags/{id}/evaluate [post]
+func (f FlagAPIHandler) EvaluateEnvironmentFlag(c echo.Context) error {
+	return f.EvaluateFlag(c)
+}
@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopedFlagRoutes(t *testing.T) {
+	productionFlagID := "926214f3-80c1-46e6-a913-b2d40b92a932"
+	defaultFlagID := "926214f3-80c1-46e6-a913-b2d40b92a933"
+	flagPayload := `{"name":"flag1","project":"other","environment":"other","type":"string","variations":{"A":"A"},"defaultRule":{"variation":"A"}}`
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		body             string
+		expectedHTTPCode int
+		expectedBody     string
+		expectedTotal    int
+	}{
+		{
+			name:             "should list only the flags of the environment",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments/production/flags",
+			expectedHTTPCode: http.StatusOK,
+			expectedTotal:    1,
+		},
+		{
+			name:             "should filter the flags by project and environment on /v1/flags",
+			method:           http.MethodGet,
+			path:             "/v1/flags?project=default&environment=default",
+			expectedHTTPCode: http.StatusOK,
+			expectedTotal:    1,
+		},
+		{
+			name:             "should return a 404 if the environment does not exist",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments/qa/flags",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"environment qa not found in project checkout","code":404}`,
+		},
+		{
+			name:             "should get a flag of the environment",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments/production/flags/" + productionFlagID,
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			name:             "should return a 404 for a flag of another environment",
+			method:           http.MethodGet,
+			path:             "/v1/projects/checkout/environments/staging/flags/" + productionFlagID,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag not found","code":404}`,
+		},
+		{
+			name:             "should create the flag in the environment of the path",
+			method:           http.MethodPost,
+			path:             "/v1/projects/checkout/environments/staging/flags",
+			body:             flagPayload,
+			expectedHTTPCode: http.StatusCreated,
+		},
+		{
+			name:             "should return a 409 if the name is already used in the environment",
+			method:           http.MethodPost,
+			path:             "/v1/projects/checkout/environments/production/flags",
+			body:             flagPayload,
+			expectedHTTPCode: http.StatusConflict,
+			expectedBody:     `{"errorDetails":"flag with name flag1 already exists","code":409}`,
+		},
+		{
+			name:             "should return a 400 when creating a flag in an unknown environment on /v1/flags",
+			method:           http.MethodPost,
+			path:             "/v1/flags",
+			body:             flagPayload,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"environment other does not exist in project other","code":400}`,
+		},
+		{
+			name:             "should keep the environment of the flag when updating it",
+			method:           http.MethodPut,
+			path:             "/v1/projects/checkout/environments/production/flags/" + productionFlagID,
+			body:             flagPayload,
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			name:             "should return a 404 when updating a flag of another environment",
+			method:           http.MethodPut,
+			path:             "/v1/projects/checkout/environments/production/flags/" + defaultFlagID,
+			body:             flagPayload,
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		{
+			name:   "should return a 400 when the id of the payload is a flag of another environment",
+			method: http.MethodPut,
+			path:   "/v1/projects/checkout/environments/production/flags/" + productionFlagID,
+			body: `{"id":"` + defaultFlagID + `","name":"flag1","type":"string","variations":{"A":"A"},` +
+				`"defaultRule":{"variation":"A"}}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody: `{"errorDetails":"id of the payload ` + defaultFlagID +
+				` does not match the id of the path ` + productionFlagID + `","code":400}`,
+		},
+		{
+			name:             "should return a 404 when toggling a flag of another environment",
+			method:           http.MethodPatch,
+			path:             "/v1/projects/checkout/environments/staging/flags/" + productionFlagID + "/status",
+			body:             `{"disable":true}`,
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		{
+			name:             "should return a 404 when deleting a flag of another environment",
+			method:           http.MethodDelete,
+			path:             "/v1/projects/checkout/environments/staging/flags/" + productionFlagID,
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		{
+			name:             "should delete a flag of the environment",
+			method:           http.MethodDelete,
+			path:             "/v1/projects/checkout/environments/production/flags/" + productionFlagID,
+			expectedHTTPCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			mockDao.SetFlags([]model.FeatureFlag{
+				{
+					ID:            productionFlagID,
+					Name:          "flag1",
+					Project:       "checkout",
+					Environment:   "production",
+					VariationType: model.FlagTypeString,
+					Revision:      1,
+				},
+				{ID: defaultFlagID, Name: "flag1", VariationType: model.FlagTypeString, Revision: 1},
+			})
+			s := newProjectTestServer(t, mockDao)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code, rec.Body.String())
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+			if tt.expectedTotal > 0 {
+				assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"total":%d`, tt.expectedTotal))
+			}
+
+			switch tt.method {
+			case http.MethodPost, http.MethodPut:
+				if rec.Code >= http.StatusBadRequest {
+					return
+				}
+				// the scope of the payload is always ignored on the scoped routes
+				project, environment := "checkout", "staging"
+				if tt.method == http.MethodPut {
+					environment = "production"
+				}
+				flag, err := mockDao.GetFlagByName(context.Background(), project, environment, "flag1")
+				require.Nil(t, err)
+				assert.Equal(t, "anonymous", flag.LastModifiedBy)
+			}
+		})
+	}
+}
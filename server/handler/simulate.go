@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	"github.com/go-feature-flag/flag-management/server/evaluation"
+	"github.com/go-feature-flag/flag-management/server/util"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/labstack/echo/v4"
+)
+
+// maxSimulationContexts is the maximum number of evaluation contexts of a simulation
+const maxSimulationContexts = 100000
+
+// SimulateFlags is returning the distribution of the variations of the flags for a sample of users
+// @Summary      Simulate the evaluation of the flags for a sample of users
+// @Tags Feature Flag management API
+// @Description  POST a list of evaluation contexts to know how many users get each variation of the flags of an
+// @Description  environment, rule by rule. Each rule also returns the split it is configured to serve, with the
+// @Description  percentage of its progressive rollout at the date of the simulation (now by default).
+// @Description  The contexts can be a JSON array, JSON lines or a CSV file with a header (targetingKey column and
+// @Description  one column per attribute). The file can be uploaded in the field "file" of a multipart form.
+// @Description  The format is selected with the format query parameter, the extension of the uploaded file or the
+// @Description  Content-Type header. Nothing is modified.
+// @Accept       json
+// @Accept       application/x-ndjson
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Param        project query string false "Project of the flags to simulate (default: default)"
+// @Param        environment query string false "Environment of the flags to simulate (default: default)"
+// @Param        flag query []string false "Names of the flags to simulate (default: all the flags of the environment)" collectionFormat(multi)
+// @Param        date query string false "Date of the simulation in RFC3339 format (default: now)"
+// @Param        format query string false "Format of the contexts (json, jsonl or csv), it takes precedence over the Content-Type header"
+// @Param 		 data body []evaluation.Context true "Evaluation contexts of the users"
+// @Success      200  {object} evaluation.Simulation "Success"
+// @Failure      400 {object} api.CustomErr "Bad Request"
+// @Failure      404 {object} api.CustomErr "Not Found - the environment or one of the flags does not exist"
+// @Failure      415 {object} api.CustomErr "Unsupported Media Type - the format of the contexts is not supported"
+// @Failure      403 {object} api.CustomErr "Forbidden - your role does not allow this operation"
+// @Failure      500 {object} api.CustomErr "Internal server error"
+// @Router       /v1/simulate [post]
+func (f FlagAPIHandler) SimulateFlags(c echo.Context) error {
+	var clock util.Clock = f.options.Clock
+	if value := c.QueryParam("date"); value != "" {
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Errorf("date should be a RFC3339 date (ex: 2024-06-01T00:00:00Z)"))
+		}
+		clock = util.FixedClock{Date: date}
+	}
+
+	contexts, err := simulationContexts(c)
+	if err != nil {
+		return err
+	}
+
+	project, environment := queryScope(c)
+	if err := f.checkEnvironmentExists(c, project, environment); err != nil {
+		return err
+	}
+	flags, _, daoErr := f.dao.GetFlags(c.Request().Context(), dao.FlagQuery{
+		Project:     project,
+		Environment: environment,
+		SortBy:      dao.FlagSortByName,
+	})
+	if daoErr != nil {
+		return f.handleDaoError(c, daoErr)
+	}
+	if names := c.QueryParams()["flag"]; len(names) > 0 {
+		if flags, err = selectFlags(flags, names); err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		}
+	}
+	return c.JSON(http.StatusOK, evaluation.Simulate(flags, contexts, clock))
+}
+
+// simulationContexts reads the evaluation contexts from the body of the request or from the uploaded file.
+func simulationContexts(c echo.Context) ([]evaluation.Context, error) {
+	format, formatSet, err := simulationFormatParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var content io.Reader = c.Request().Body
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if strings.HasPrefix(strings.ToLower(contentType), echo.MIMEMultipartForm) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("the field file is required: %w", err))
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+		defer func() { _ = file.Close() }()
+		content = file
+		if !formatSet {
+			format, formatSet = evaluation.ContextFormatFromFileName(fileHeader.Filename)
+		}
+		contentType = fileHeader.Header.Get(echo.HeaderContentType)
+	}
+
+	if !formatSet {
+		if format, formatSet = evaluation.ContextFormatFromMediaType(contentType); !formatSet {
+			return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType,
+				fmt.Errorf("content type %s not supported, use application/json, application/x-ndjson or text/csv",
+					contentType))
+		}
+	}
+
+	contexts, err := evaluation.ReadContexts(content, format, maxSimulationContexts)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid evaluation contexts: %w", err))
+	}
+	if len(contexts) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, errors.New("at least one evaluation context is required"))
+	}
+	return contexts, nil
+}
+
+// simulationFormatParam returns the format of the format query parameter, false if it is not set.
+func simulationFormatParam(c echo.Context) (evaluation.ContextFormat, bool, error) {
+	value := c.QueryParam("format")
+	if value == "" {
+		return "", false, nil
+	}
+	format, err := evaluation.ContextFormatFromValue(value)
+	if err != nil {
+		return "", false, echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	return format, true, nil
+}
+
+// selectFlags keeps the flags with the given names, in the order of the names.
+func selectFlags(flags []model.FeatureFlag, names []string) ([]model.FeatureFlag, error) {
+	byName := make(map[string]model.FeatureFlag, len(flags))
+	for _, flag := range flags {
+		byName[flag.Name] = flag
+	}
+	selected := make([]model.FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flag, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("flag %s not found", name)
+		}
+		selected = append(selected, flag)
+	}
+	return selected, nil
+}
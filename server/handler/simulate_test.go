@@ -0,0 +1,257 @@
+package handler_test
+
+import (
+	"bytes"
+	"github.com/go-feature-flag/flag-management/server/model"
+	testutils2 "github.com/go-feature-flag/flag-management/server/testutils"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagsHandler_SimulateFlags(t *testing.T) {
+	// the clock of the test server is 2020-01-01, the rollout is at 25% at this date
+	flags := []model.FeatureFlag{
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+			Name:          "flag1",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			Rules: &[]model.Rule{
+				{
+					ID:              "926214f3-80c1-46e6-a913-b2d40b92a100",
+					Name:            "beta",
+					Query:           `beta eq true`,
+					VariationResult: testutils2.String("B"),
+				},
+			},
+			DefaultRule: &model.Rule{
+				ID: "926214f3-80c1-46e6-a913-b2d40b92a000",
+				ProgressiveRollout: &model.ProgressiveRollout{
+					Initial: &model.ProgressiveRolloutStep{
+						Variation: testutils2.String("A"),
+						Date:      testutils2.Time(time.Date(2019, 12, 31, 18, 0, 0, 0, time.UTC)),
+					},
+					End: &model.ProgressiveRolloutStep{
+						Variation: testutils2.String("B"),
+						Date:      testutils2.Time(time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+		},
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a933",
+			Name:          "flag2",
+			VariationType: model.FlagTypeBoolean,
+			Variations:    &map[string]interface{}{"enabled": true, "disabled": false},
+			DefaultRule: &model.Rule{
+				ID:          "926214f3-80c1-46e6-a913-b2d40b92a001",
+				Percentages: &map[string]float64{"enabled": 100, "disabled": 0},
+			},
+		},
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a934",
+			Name:          "flag3",
+			Project:       "checkout",
+			Environment:   "staging",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a"},
+			DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a002", VariationResult: testutils2.String("A")},
+		},
+	}
+	flag1Simulation := `{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","flag":"flag1",
+		"variations":{"B":{"count":1,"percentage":100}},
+		"reasons":{"TARGETING_MATCH":1},
+		"rules":[
+			{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a100","ruleName":"beta","matched":1,
+				"variations":{"B":{"count":1,"percentage":100}},"expectedPercentages":{"B":100}},
+			{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a000","default":true,"matched":0,"variations":{},
+				"expectedPercentages":{"A":75,"B":25},"rolloutPercentage":25}
+		]}`
+
+	tests := []struct {
+		name             string
+		path             string
+		contentType      string
+		body             string
+		expectedHTTPCode int
+		expectedBody     string
+	}{
+		{
+			name:             "should simulate all the flags of the default environment",
+			path:             "/v1/simulate",
+			contentType:      "application/json",
+			body:             `[{"targetingKey":"user-1","attributes":{"beta":true}}]`,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody: `{"date":"2020-01-01T00:00:00Z","contexts":1,"flags":[` + flag1Simulation + `,
+				{"id":"926214f3-80c1-46e6-a913-b2d40b92a933","flag":"flag2",
+					"variations":{"enabled":{"count":1,"percentage":100}},
+					"reasons":{"SPLIT":1},
+					"rules":[{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a001","default":true,"matched":1,
+						"variations":{"enabled":{"count":1,"percentage":100}},
+						"expectedPercentages":{"enabled":100,"disabled":0}}]}
+			]}`,
+		},
+		{
+			name:             "should simulate the selected flags at the given date from a CSV file",
+			path:             "/v1/simulate?flag=flag1&date=2020-01-02T00:00:00Z",
+			contentType:      "text/csv",
+			body:             "targetingKey,beta\nuser-1,false\nuser-2,false\n",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody: `{"date":"2020-01-02T00:00:00Z","contexts":2,"flags":[
+				{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","flag":"flag1",
+					"variations":{"B":{"count":2,"percentage":100}},
+					"reasons":{"SPLIT":2},
+					"rules":[
+						{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a100","ruleName":"beta","matched":0,"variations":{},
+							"expectedPercentages":{"B":100}},
+						{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a000","default":true,"matched":2,
+							"variations":{"B":{"count":2,"percentage":100}},
+							"expectedPercentages":{"A":0,"B":100},"rolloutPercentage":100}
+					]}
+			]}`,
+		},
+		{
+			name:             "should read JSON lines with the format query parameter",
+			path:             "/v1/simulate?flag=flag1&format=jsonl",
+			contentType:      "text/plain",
+			body:             "{\"targetingKey\":\"user-1\",\"attributes\":{\"beta\":true}}\n",
+			expectedHTTPCode: http.StatusOK,
+			expectedBody:     `{"date":"2020-01-01T00:00:00Z","contexts":1,"flags":[` + flag1Simulation + `]}`,
+		},
+		{
+			name:             "should simulate the flags of another environment",
+			path:             "/v1/simulate?project=checkout&environment=staging",
+			contentType:      "application/json",
+			body:             `[{"targetingKey":"user-1"}]`,
+			expectedHTTPCode: http.StatusOK,
+			expectedBody: `{"date":"2020-01-01T00:00:00Z","contexts":1,"flags":[
+				{"id":"926214f3-80c1-46e6-a913-b2d40b92a934","flag":"flag3",
+					"variations":{"A":{"count":1,"percentage":100}},
+					"reasons":{"STATIC":1},
+					"rules":[{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a002","default":true,"matched":1,
+						"variations":{"A":{"count":1,"percentage":100}},"expectedPercentages":{"A":100}}]}
+			]}`,
+		},
+		{
+			name:             "should return a 404 if a selected flag does not exist",
+			path:             "/v1/simulate?flag=unknown",
+			contentType:      "application/json",
+			body:             `[{"targetingKey":"user-1"}]`,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"flag unknown not found","code":404}`,
+		},
+		{
+			name:             "should return a 404 if the environment does not exist",
+			path:             "/v1/simulate?project=checkout&environment=qa",
+			contentType:      "application/json",
+			body:             `[{"targetingKey":"user-1"}]`,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedBody:     `{"errorDetails":"environment qa not found in project checkout","code":404}`,
+		},
+		{
+			name:             "should return a 400 if the date is invalid",
+			path:             "/v1/simulate?date=tomorrow",
+			contentType:      "application/json",
+			body:             `[{"targetingKey":"user-1"}]`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"date should be a RFC3339 date (ex: 2024-06-01T00:00:00Z)","code":400}`,
+		},
+		{
+			name:             "should return a 400 if there is no context",
+			path:             "/v1/simulate",
+			contentType:      "application/json",
+			body:             `[]`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"at least one evaluation context is required","code":400}`,
+		},
+		{
+			name:             "should return a 400 if the contexts are invalid",
+			path:             "/v1/simulate",
+			contentType:      "text/csv",
+			body:             "targetingKey,beta\nuser-1\n",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody: `{"errorDetails":"invalid evaluation contexts: record on line 2: wrong number of fields",
+				"code":400}`,
+		},
+		{
+			name:             "should return a 400 if the format is not supported",
+			path:             "/v1/simulate?format=xml",
+			contentType:      "application/json",
+			body:             `[{"targetingKey":"user-1"}]`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedBody:     `{"errorDetails":"format xml not supported, use json, jsonl or csv","code":400}`,
+		},
+		{
+			name:             "should return a 415 if the content type is not supported",
+			path:             "/v1/simulate",
+			contentType:      "application/yaml",
+			body:             `- targetingKey: user-1`,
+			expectedHTTPCode: http.StatusUnsupportedMediaType,
+			expectedBody: `{"errorDetails":"content type application/yaml not supported, use application/json, ` +
+				`application/x-ndjson or text/csv","code":415}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := newProjectTestDao(t)
+			mockDao.SetFlags(append([]model.FeatureFlag{}, flags...))
+			s := newProjectTestServer(t, mockDao)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedHTTPCode, rec.Code, rec.Body.String())
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestFlagsHandler_SimulateFlags_upload(t *testing.T) {
+	mockDao := newProjectTestDao(t)
+	mockDao.SetFlags([]model.FeatureFlag{
+		{
+			ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+			Name:          "flag1",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			Rules: &[]model.Rule{
+				{ID: "926214f3-80c1-46e6-a913-b2d40b92a100", Query: `plan eq "premium"`, VariationResult: testutils2.String("B")},
+			},
+			DefaultRule: &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils2.String("A")},
+		},
+	})
+	s := newProjectTestServer(t, mockDao)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "users.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("key,plan\nuser-1,premium\nuser-2,free\nuser-3,free\nuser-4,free\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/simulate", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"date":"2020-01-01T00:00:00Z","contexts":4,"flags":[
+		{"id":"926214f3-80c1-46e6-a913-b2d40b92a932","flag":"flag1",
+			"variations":{"A":{"count":3,"percentage":75},"B":{"count":1,"percentage":25}},
+			"reasons":{"TARGETING_MATCH":1,"DEFAULT":3},
+			"rules":[
+				{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a100","matched":1,
+					"variations":{"B":{"count":1,"percentage":100}},"expectedPercentages":{"B":100}},
+				{"ruleId":"926214f3-80c1-46e6-a913-b2d40b92a000","default":true,"matched":3,
+					"variations":{"A":{"count":3,"percentage":100}},"expectedPercentages":{"A":100}}
+			]}
+	]}`, rec.Body.String())
+}
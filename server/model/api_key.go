@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+type APIKeyScope string
+
+const (
+	// APIKeyScopeRead allows to read the flags
+	APIKeyScopeRead APIKeyScope = "read"
+	// APIKeyScopeWrite allows to read, create, update, restore and toggle the flags
+	APIKeyScopeWrite APIKeyScope = "write"
+	// APIKeyScopeDelete allows to delete the flags, it is usually given with the write scope
+	APIKeyScopeDelete APIKeyScope = "delete"
+)
+
+// APIKeyScopeFromValue converts a string to an APIKeyScope
+func APIKeyScopeFromValue(value string) (APIKeyScope, error) {
+	switch APIKeyScope(value) {
+	case APIKeyScopeRead, APIKeyScopeWrite, APIKeyScopeDelete:
+		return APIKeyScope(value), nil
+	default:
+		return "", fmt.Errorf("api key scope %s not supported", value)
+	}
+}
+
+// APIKey is a long-lived credential used to call the API (from a CI pipeline for example).
+// We never store the key itself, only its hash.
+type APIKey struct {
+	// ID is the unique identifier of the API key
+	ID string `json:"id"`
+
+	// Name is a human-readable name to know where the key is used
+	Name string `json:"name"`
+
+	// Prefix is the beginning of the key, it helps to identify the key without exposing it
+	Prefix string `json:"prefix"`
+
+	// HashedKey is the SHA-256 hash of the key
+	HashedKey string `json:"-"`
+
+	// Scopes are the permissions granted to the key
+	Scopes []APIKeyScope `json:"scopes"`
+
+	// CreatedDate is the date when the key has been created
+	CreatedDate time.Time `json:"createdDate"`
+
+	// CreatedBy is the principal who created the key
+	CreatedBy string `json:"createdBy"`
+
+	// ExpiresAt is the date after which the key is not valid anymore, nil means the key never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// LastUsedDate is the last time the key has been used to call the API
+	LastUsedDate *time.Time `json:"lastUsedDate,omitempty"`
+}
+
+// IsExpired returns true if the key is not valid anymore at the given date
+func (k APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// HasScope returns true if the key is allowed to act with this scope, the write scope includes the read scope.
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	if slices.Contains(k.Scopes, APIKeyScopeWrite) {
+		return true
+	}
+	return slices.Contains(k.Scopes, scope)
+}
@@ -0,0 +1,58 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyScopeFromValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      model.APIKeyScope
+		expectErr bool
+	}{
+		{"valid read scope", "read", model.APIKeyScopeRead, false},
+		{"valid write scope", "write", model.APIKeyScopeWrite, false},
+		{"valid delete scope", "delete", model.APIKeyScopeDelete, false},
+		{"empty scope", "", "", true},
+		{"unsupported scope", "admin", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := model.APIKeyScopeFromValue(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAPIKey_IsExpired(t *testing.T) {
+	now := time.Date(2024, 10, 25, 11, 50, 27, 0, time.UTC)
+	before := now.Add(-time.Minute)
+	after := now.Add(time.Minute)
+
+	assert.False(t, model.APIKey{}.IsExpired(now), "key without expiration date")
+	assert.False(t, model.APIKey{ExpiresAt: &after}.IsExpired(now), "key expiring in the future")
+	assert.True(t, model.APIKey{ExpiresAt: &before}.IsExpired(now), "key expired")
+	assert.True(t, model.APIKey{ExpiresAt: &now}.IsExpired(now), "key expiring now")
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	readOnly := model.APIKey{Scopes: []model.APIKeyScope{model.APIKeyScopeRead}}
+	write := model.APIKey{Scopes: []model.APIKeyScope{model.APIKeyScopeWrite}}
+
+	assert.True(t, readOnly.HasScope(model.APIKeyScopeRead))
+	assert.False(t, readOnly.HasScope(model.APIKeyScopeWrite))
+	assert.True(t, write.HasScope(model.APIKeyScopeRead))
+	assert.True(t, write.HasScope(model.APIKeyScopeWrite))
+	assert.False(t, model.APIKey{}.HasScope(model.APIKeyScopeRead))
+}
@@ -0,0 +1,322 @@
+package model
+
+import (
+	"fmt"
+	"github.com/go-feature-flag/flag-management/server/rulequery"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldError describes why a field of a flag is invalid.
+type FieldError struct {
+	// Field is the path of the invalid field in the JSON representation of the flag (ex: targeting[0].variation)
+	Field string `json:"field" example:"targeting[0].variation"`
+	// RuleID is the ID of the rule containing the invalid field, if any
+	RuleID string `json:"ruleId,omitempty" example:"a4c7d1e2-5b1a-4c1e-9f5e-0d2b8e6f7a10"`
+	// Message explains why the field is invalid
+	Message string `json:"message" example:"variation C does not exist"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors contains all the invalid fields of a flag.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldError := range e {
+		messages = append(messages, fieldError.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Validate checks that the flag can be stored and evaluated.
+// It returns ValidationErrors with all the invalid fields of the flag.
+func (ff *FeatureFlag) Validate() error {
+	v := &flagValidator{flag: ff}
+	if ff.Name == "" {
+		v.add("name", "flag name is required")
+	}
+	if _, err := FlagTypeFromValue(string(ff.VariationType)); err != nil {
+		v.add("type", err.Error())
+	} else {
+		v.validateVariations()
+	}
+
+	firstRuleError := len(v.errors)
+	switch {
+	case ff.DefaultRule == nil || *ff.DefaultRule == (Rule{}):
+		v.add("defaultRule", "flag default rule is required")
+	case !ff.DefaultRule.hasVariation():
+		v.add("defaultRule", "flag default rule is invalid")
+	default:
+		v.validateRuleVariations("defaultRule", *ff.DefaultRule)
+	}
+	v.setRuleID(firstRuleError, ff.GetDefaultRule().ID)
+
+	for i, rule := range ff.GetRules() {
+		field := fmt.Sprintf("targeting[%d]", i)
+		firstRuleError = len(v.errors)
+		switch {
+		case rule == (Rule{}):
+			v.add(field, "targeting rule is nil")
+		case !rule.hasVariation():
+			v.add(field, fmt.Sprintf("invalid rule %s", rule.Name))
+		default:
+			v.validateRuleVariations(field, rule)
+		}
+		switch {
+		case rule.Query != "":
+			if err := rulequery.Validate(rule.Query); err != nil {
+				v.add(field+".query", err.Error())
+			}
+		case rule != (Rule{}):
+			v.add(field+".query", "query is required for targeting rules")
+		}
+		if rule.ProgressiveRollout != nil && len(rule.ProgressiveRollout.Steps) > 0 {
+			v.validateScheduledRuleName(field, i, rule)
+		}
+		v.setRuleID(firstRuleError, rule.ID)
+	}
+
+	if ff.Experimentation != nil {
+		v.validateExperimentation(*ff.Experimentation)
+	}
+
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
+
+// hasVariation returns true if the rule returns a variation.
+func (r Rule) hasVariation() bool {
+	return r.ProgressiveRollout != nil ||
+		r.Percentages != nil ||
+		(r.VariationResult != nil && *r.VariationResult != "")
+}
+
+// flagValidator collects the invalid fields of a flag.
+type flagValidator struct {
+	flag   *FeatureFlag
+	errors ValidationErrors
+}
+
+func (v *flagValidator) add(field string, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: message})
+}
+
+// setRuleID sets the ID of the rule on the errors added since the index first.
+func (v *flagValidator) setRuleID(first int, ruleID string) {
+	for i := first; i < len(v.errors); i++ {
+		v.errors[i].RuleID = ruleID
+	}
+}
+
+// validateVariations checks that the values of the variations match the type of the flag.
+func (v *flagValidator) validateVariations() {
+	if v.flag.Variations == nil || len(*v.flag.Variations) == 0 {
+		v.add("variations", "variations are required")
+		return
+	}
+	variations := *v.flag.Variations
+	if v.flag.VariationType == FlagTypeBoolean && len(variations) != 2 {
+		v.add("variations", "boolean flags should have exactly 2 variations")
+	}
+
+	names := make([]string, 0, len(variations))
+	for name := range variations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !isValueOfType(variations[name], v.flag.VariationType) {
+			v.add("variations."+name, fmt.Sprintf("value should be of type %s", v.flag.VariationType))
+		}
+	}
+}
+
+// validateRuleVariations checks that the variations returned by the rule exist and that its percentages are valid.
+func (v *flagValidator) validateRuleVariations(field string, rule Rule) {
+	if rule.VariationResult != nil && *rule.VariationResult != "" {
+		v.checkVariationExists(field+".variation", *rule.VariationResult)
+	}
+
+	if rule.Percentages != nil {
+		names := make([]string, 0, len(*rule.Percentages))
+		for name := range *rule.Percentages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		total := 0.0
+		for _, name := range names {
+			percentage := (*rule.Percentages)[name]
+			v.checkVariationExists(fmt.Sprintf("%s.percentage.%s", field, name), name)
+			v.checkPercentage(fmt.Sprintf("%s.percentage.%s", field, name), percentage)
+			total += percentage
+		}
+		// we accept a small difference because of the float precision
+		if math.Abs(total-100) > 1e-6 {
+			v.add(field+".percentage", fmt.Sprintf("percentages should sum to 100, got %v", total))
+		}
+	}
+
+	if rule.ProgressiveRollout != nil {
+		v.validateProgressiveRollout(field+".progressiveRollout", *rule.ProgressiveRollout)
+	}
+}
+
+func (v *flagValidator) validateProgressiveRollout(field string, rollout ProgressiveRollout) {
+	if len(rollout.Steps) > 0 {
+		v.validateProgressiveRolloutSteps(field, rollout)
+		return
+	}
+
+	steps := []struct {
+		name string
+		step *ProgressiveRolloutStep
+	}{{name: "initial", step: rollout.Initial}, {name: "end", step: rollout.End}}
+	for _, s := range steps {
+		stepField := field + "." + s.name
+		if s.step == nil {
+			v.add(stepField, fmt.Sprintf("%s step is required", s.name))
+			continue
+		}
+		v.validateProgressiveRolloutStep(stepField, *s.step)
+	}
+
+	if rollout.Initial != nil && rollout.Initial.Date != nil &&
+		rollout.End != nil && rollout.End.Date != nil &&
+		!rollout.End.Date.After(*rollout.Initial.Date) {
+		v.add(field+".end.date", "end date should be after the initial date")
+	}
+}
+
+// validateProgressiveRolloutSteps checks a rollout described with its steps, the dates of the steps should
+// be increasing. Each step can roll out a different variation (see ProgressiveRollout.Segments).
+func (v *flagValidator) validateProgressiveRolloutSteps(field string, rollout ProgressiveRollout) {
+	if rollout.Initial != nil || rollout.End != nil {
+		v.add(field, "steps cannot be combined with the initial and end steps")
+	}
+	if len(rollout.Steps) < 2 {
+		v.add(field+".steps", "at least 2 steps are required")
+	}
+
+	var previousDate *time.Time
+	for i, step := range rollout.Steps {
+		stepField := fmt.Sprintf("%s.steps[%d]", field, i)
+		v.validateProgressiveRolloutStep(stepField, step)
+		if i > 0 && i < len(rollout.Steps)-1 && step.Percentage == nil {
+			v.add(stepField+".percentage", "percentage is required")
+		}
+		if step.Date != nil {
+			if previousDate != nil && !step.Date.After(*previousDate) {
+				v.add(stepField+".date", "date should be after the date of the previous step")
+			}
+			previousDate = step.Date
+		}
+	}
+}
+
+// validateScheduledRuleName checks the name of a targeting rule with a progressive rollout described with its
+// steps. The relay proxy applies the steps after the first segment with a scheduled rollout that finds the rule
+// by its name, so the name should identify the rule.
+func (v *flagValidator) validateScheduledRuleName(field string, index int, rule Rule) {
+	if rule.Name == "" {
+		v.add(field+".name", "name is required for a progressive rollout with more than 2 steps")
+		return
+	}
+	for i, other := range v.flag.GetRules() {
+		if i != index && other.Name == rule.Name {
+			v.add(field+".name", "name should be unique for a progressive rollout with more than 2 steps")
+			return
+		}
+	}
+}
+
+// validateProgressiveRolloutStep checks the fields of a single step of a progressive rollout.
+func (v *flagValidator) validateProgressiveRolloutStep(field string, step ProgressiveRolloutStep) {
+	if step.Variation == nil || *step.Variation == "" {
+		v.add(field+".variation", "variation is required")
+	} else {
+		v.checkVariationExists(field+".variation", *step.Variation)
+	}
+	if step.Percentage != nil {
+		v.checkPercentage(field+".percentage", *step.Percentage)
+	}
+	if step.Date == nil {
+		v.add(field+".date", "date is required")
+	}
+}
+
+// validateExperimentation checks that the window of the experimentation is complete and not empty.
+func (v *flagValidator) validateExperimentation(experimentation ExperimentationRollout) {
+	if experimentation.Start == nil {
+		v.add("experimentation.start", "start date is required")
+	}
+	if experimentation.End == nil {
+		v.add("experimentation.end", "end date is required")
+	}
+	if experimentation.Start != nil && experimentation.End != nil &&
+		!experimentation.End.After(*experimentation.Start) {
+		v.add("experimentation.end", "end date should be after the start date")
+	}
+}
+
+func (v *flagValidator) checkVariationExists(field string, name string) {
+	if v.flag.Variations == nil {
+		return
+	}
+	if _, ok := (*v.flag.Variations)[name]; !ok {
+		v.add(field, fmt.Sprintf("variation %s does not exist", name))
+	}
+}
+
+func (v *flagValidator) checkPercentage(field string, percentage float64) {
+	if percentage < 0 || percentage > 100 {
+		v.add(field, fmt.Sprintf("percentage should be between 0 and 100, got %v", percentage))
+	}
+}
+
+// isValueOfType returns true if the value of a variation can be used by a flag of this type.
+func isValueOfType(value interface{}, flagType FlagType) bool {
+	if pointer, ok := value.(*interface{}); ok && pointer != nil {
+		value = *pointer
+	}
+	switch flagType {
+	case FlagTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case FlagTypeString:
+		_, ok := value.(string)
+		return ok
+	case FlagTypeInteger:
+		switch number := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float32:
+			return math.Trunc(float64(number)) == float64(number)
+		case float64:
+			return math.Trunc(number) == number
+		}
+		return false
+	case FlagTypeDouble:
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return true
+		}
+		return false
+	case FlagTypeJSON:
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+}
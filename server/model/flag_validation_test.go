@@ -0,0 +1,238 @@
+package model_test
+
+import (
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlag_Validate(t *testing.T) {
+	validFlag := func() model.FeatureFlag {
+		return model.FeatureFlag{
+			Name:          "flag1",
+			VariationType: model.FlagTypeString,
+			Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+			DefaultRule:   &model.Rule{VariationResult: testutils.String("A")},
+		}
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		update func(flag *model.FeatureFlag)
+		want   model.ValidationErrors
+	}{
+		{
+			name:   "should accept a valid flag",
+			update: func(_ *model.FeatureFlag) {},
+		},
+		{
+			name: "should return all the invalid fields",
+			update: func(flag *model.FeatureFlag) {
+				flag.Name = ""
+				flag.DefaultRule = nil
+				flag.Rules = &[]model.Rule{{Name: "rule1", VariationResult: testutils.String("C")}}
+			},
+			want: model.ValidationErrors{
+				{Field: "name", Message: "flag name is required"},
+				{Field: "defaultRule", Message: "flag default rule is required"},
+				{Field: "targeting[0].variation", Message: "variation C does not exist"},
+				{Field: "targeting[0].query", Message: "query is required for targeting rules"},
+			},
+		},
+		{
+			name: "should reject a query that is not a valid nikunjy/rules expression",
+			update: func(flag *model.FeatureFlag) {
+				flag.Rules = &[]model.Rule{
+					{ID: "rule-1", Query: `beta eq true`, VariationResult: testutils.String("B")},
+					{ID: "rule-2", Query: `beta equals true`, VariationResult: testutils.String("B")},
+				}
+			},
+			want: model.ValidationErrors{{
+				Field:   "targeting[1].query",
+				RuleID:  "rule-2",
+				Message: `syntax error at position 5: no viable alternative at input 'beta equals'`,
+			}},
+		},
+		{
+			name: "should reject variations that do not match the type of the flag",
+			update: func(flag *model.FeatureFlag) {
+				flag.VariationType = model.FlagTypeInteger
+				flag.Variations = &map[string]interface{}{"A": float64(1), "B": 1.5, "C": "c", "D": int64(3)}
+			},
+			want: model.ValidationErrors{
+				{Field: "variations.B", Message: "value should be of type integer"},
+				{Field: "variations.C", Message: "value should be of type integer"},
+			},
+		},
+		{
+			name: "should accept only objects and arrays for json flags",
+			update: func(flag *model.FeatureFlag) {
+				flag.VariationType = model.FlagTypeJSON
+				flag.Variations = &map[string]interface{}{
+					"A": map[string]interface{}{"key": "value"}, "B": []interface{}{1, 2}, "C": "c"}
+			},
+			want: model.ValidationErrors{{Field: "variations.C", Message: "value should be of type json"}},
+		},
+		{
+			name: "should reject a boolean flag without exactly 2 variations",
+			update: func(flag *model.FeatureFlag) {
+				flag.VariationType = model.FlagTypeBoolean
+				flag.Variations = &map[string]interface{}{"A": true}
+			},
+			want: model.ValidationErrors{{Field: "variations", Message: "boolean flags should have exactly 2 variations"}},
+		},
+		{
+			name: "should reject percentages with unknown variations or not summing to 100",
+			update: func(flag *model.FeatureFlag) {
+				flag.DefaultRule = &model.Rule{Percentages: &map[string]float64{"A": 20, "C": 70}}
+			},
+			want: model.ValidationErrors{
+				{Field: "defaultRule.percentage.C", Message: "variation C does not exist"},
+				{Field: "defaultRule.percentage", Message: "percentages should sum to 100, got 90"},
+			},
+		},
+		{
+			name: "should reject a progressive rollout ending before it starts",
+			update: func(flag *model.FeatureFlag) {
+				flag.DefaultRule = &model.Rule{ProgressiveRollout: &model.ProgressiveRollout{
+					Initial: &model.ProgressiveRolloutStep{
+						Variation: testutils.String("A"), Percentage: testutils.Float64(0), Date: testutils.Time(start)},
+					End: &model.ProgressiveRolloutStep{
+						Variation: testutils.String("C"), Percentage: testutils.Float64(120), Date: testutils.Time(start)},
+				}}
+			},
+			want: model.ValidationErrors{
+				{Field: "defaultRule.progressiveRollout.end.variation", Message: "variation C does not exist"},
+				{Field: "defaultRule.progressiveRollout.end.percentage", Message: "percentage should be between 0 and 100, got 120"},
+				{Field: "defaultRule.progressiveRollout.end.date", Message: "end date should be after the initial date"},
+			},
+		},
+		{
+			name: "should accept a progressive rollout with several steps",
+			update: func(flag *model.FeatureFlag) {
+				flag.DefaultRule = &model.Rule{ProgressiveRollout: &model.ProgressiveRollout{
+					Steps: []model.ProgressiveRolloutStep{
+						{Variation: testutils.String("A"), Date: testutils.Time(start)},
+						{Variation: testutils.String("B"), Percentage: testutils.Float64(10),
+							Date: testutils.Time(start.Add(24 * time.Hour))},
+						{Variation: testutils.String("B"), Date: testutils.Time(start.Add(48 * time.Hour))},
+					},
+				}}
+			},
+		},
+		{
+			name: "should accept progressive rollout steps rolling out different variations",
+			update: func(flag *model.FeatureFlag) {
+				flag.DefaultRule = &model.Rule{ProgressiveRollout: &model.ProgressiveRollout{
+					Steps: []model.ProgressiveRolloutStep{
+						{Variation: testutils.String("A"), Date: testutils.Time(start)},
+						{Variation: testutils.String("B"), Percentage: testutils.Float64(100),
+							Date: testutils.Time(start.Add(24 * time.Hour))},
+						{Variation: testutils.String("A"), Date: testutils.Time(start.Add(48 * time.Hour))},
+					},
+				}}
+			},
+		},
+		{
+			name: "should reject a targeting rule with progressive rollout steps and without a unique name",
+			update: func(flag *model.FeatureFlag) {
+				rollout := &model.ProgressiveRollout{
+					Steps: []model.ProgressiveRolloutStep{
+						{Variation: testutils.String("A"), Date: testutils.Time(start)},
+						{Variation: testutils.String("B"), Percentage: testutils.Float64(10),
+							Date: testutils.Time(start.Add(24 * time.Hour))},
+						{Variation: testutils.String("B"), Date: testutils.Time(start.Add(48 * time.Hour))},
+					},
+				}
+				flag.Rules = &[]model.Rule{
+					{ID: "rule-1", Query: `beta eq true`, ProgressiveRollout: rollout},
+					{ID: "rule-2", Name: "beta", Query: `beta eq true`, ProgressiveRollout: rollout},
+					{ID: "rule-3", Name: "beta", Query: `beta eq true`, VariationResult: testutils.String("A")},
+				}
+			},
+			want: model.ValidationErrors{
+				{Field: "targeting[0].name", RuleID: "rule-1",
+					Message: "name is required for a progressive rollout with more than 2 steps"},
+				{Field: "targeting[1].name", RuleID: "rule-2",
+					Message: "name should be unique for a progressive rollout with more than 2 steps"},
+			},
+		},
+		{
+			name: "should reject progressive rollout steps that are not in the order of their dates",
+			update: func(flag *model.FeatureFlag) {
+				flag.DefaultRule = &model.Rule{ProgressiveRollout: &model.ProgressiveRollout{
+					Steps: []model.ProgressiveRolloutStep{
+						{Variation: testutils.String("B"), Date: testutils.Time(start)},
+						{Variation: testutils.String("A"), Date: testutils.Time(start.Add(48 * time.Hour))},
+						{Variation: testutils.String("B"), Percentage: testutils.Float64(10),
+							Date: testutils.Time(start.Add(24 * time.Hour))},
+					},
+				}}
+			},
+			want: model.ValidationErrors{
+				{Field: "defaultRule.progressiveRollout.steps[1].percentage", Message: "percentage is required"},
+				{Field: "defaultRule.progressiveRollout.steps[2].date", Message: "date should be after the date of the previous step"},
+			},
+		},
+		{
+			name: "should reject progressive rollout steps combined with the initial step",
+			update: func(flag *model.FeatureFlag) {
+				flag.DefaultRule = &model.Rule{ProgressiveRollout: &model.ProgressiveRollout{
+					Initial: &model.ProgressiveRolloutStep{Variation: testutils.String("A"), Date: testutils.Time(start)},
+					Steps:   []model.ProgressiveRolloutStep{{Date: testutils.Time(start)}},
+				}}
+			},
+			want: model.ValidationErrors{
+				{Field: "defaultRule.progressiveRollout", Message: "steps cannot be combined with the initial and end steps"},
+				{Field: "defaultRule.progressiveRollout.steps", Message: "at least 2 steps are required"},
+				{Field: "defaultRule.progressiveRollout.steps[0].variation", Message: "variation is required"},
+			},
+		},
+		{
+			name: "should accept an experimentation window",
+			update: func(flag *model.FeatureFlag) {
+				flag.Experimentation = &model.ExperimentationRollout{
+					Start: testutils.Time(start),
+					End:   testutils.Time(start.Add(24 * time.Hour)),
+				}
+			},
+		},
+		{
+			name: "should reject an experimentation without end date",
+			update: func(flag *model.FeatureFlag) {
+				flag.Experimentation = &model.ExperimentationRollout{Start: testutils.Time(start)}
+			},
+			want: model.ValidationErrors{{Field: "experimentation.end", Message: "end date is required"}},
+		},
+		{
+			name: "should reject an experimentation ending before it starts",
+			update: func(flag *model.FeatureFlag) {
+				flag.Experimentation = &model.ExperimentationRollout{
+					Start: testutils.Time(start),
+					End:   testutils.Time(start),
+				}
+			},
+			want: model.ValidationErrors{
+				{Field: "experimentation.end", Message: "end date should be after the start date"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := validFlag()
+			tt.update(&flag)
+			err := flag.Validate()
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
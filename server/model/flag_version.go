@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type FlagVersionAction string
+
+const (
+	FlagVersionActionCreated FlagVersionAction = "created"
+	FlagVersionActionUpdated FlagVersionAction = "updated"
+	FlagVersionActionDeleted FlagVersionAction = "deleted"
+)
+
+// FlagVersion is a snapshot of a feature flag (including its rules) taken every time the flag is written.
+type FlagVersion struct {
+	// ID is the unique identifier of this version
+	ID string `json:"id"`
+
+	// FlagID is the ID of the feature flag this version belongs to
+	FlagID string `json:"flagId"`
+
+	// VersionNumber is incremented by one every time the flag is written, the first version is 1
+	VersionNumber int `json:"versionNumber"`
+
+	// Action is the write operation that produced this version
+	Action FlagVersionAction `json:"action"`
+
+	// CreatedDate is the date when this version became active
+	CreatedDate time.Time `json:"createdDate"`
+
+	// Flag is the full state of the flag at this version
+	Flag FeatureFlag `json:"flag"`
+}
@@ -7,6 +7,8 @@ import (
 type FeatureFlag struct {
 	ID              string    `json:"id" db:"id"`
 	Name            string    `json:"name" db:"name"`
+	Project         string    `json:"project,omitempty" db:"project"`         // default project if not set
+	Environment     string    `json:"environment,omitempty" db:"environment"` // default environment if not set
 	CreatedDate     time.Time `json:"createdDate" db:"created_date"`
 	LastUpdatedDate time.Time `json:"lastUpdatedDate" db:"last_updated_date"`
 	LastModifiedBy  string    `json:"LastModifiedBy" db:"last_modified_by"`
@@ -30,7 +32,7 @@ type FeatureFlag struct {
 	// Experimentation is your struct to configure an experimentation.
 	// It will allow you to configure a start date and an end date for your flag.
 	// When the experimentation is not running, the flag will serve the default value.
-	// Experimentation *ExperimentationDto `json:"experimentation,omitempty""`
+	Experimentation *ExperimentationRollout `json:"experimentation,omitempty" yaml:"experimentation,omitempty" toml:"experimentation,omitempty"` // nolint: lll
 
 	// Metadata is a field containing information about your flag such as an issue tracker link, a description, etc ...
 	Metadata *map[string]interface{} `json:"metadata,omitempty"` // nolint: lll
@@ -46,6 +48,10 @@ type FeatureFlag struct {
 	// TrackEvents is false if you don't want to export the data in your data exporter.
 	// Default value is true
 	TrackEvents *bool `json:"trackEvents,omitempty" yaml:"trackEvents,omitempty" toml:"trackEvents,omitempty"`
+
+	// Revision is incremented every time the flag is updated, it is used to detect concurrent modifications.
+	// When updating a flag, the revision should be the one of the flag you have retrieved.
+	Revision int64 `json:"revision,omitempty"`
 }
 
 type Rule struct {
@@ -81,20 +87,105 @@ type ProgressiveRollout struct {
 
 	// End contains what describes the end status of the rollout.
 	End *ProgressiveRolloutStep `json:"end,omitempty" yaml:"end,omitempty" toml:"end,omitempty" jsonschema:"title=initial,description=A description of the end state of the rollout."` // nolint: lll
+
+	// Steps contains the ordered steps of a rollout with more than 2 steps, it replaces Initial and End.
+	// The first step describes the variation served before the rollout and the following steps the percentage
+	// of the users getting the variation of the step at their date (see Segments).
+	Steps []ProgressiveRolloutStep `json:"steps,omitempty" yaml:"steps,omitempty" toml:"steps,omitempty" jsonschema:"title=steps,description=The ordered steps of the rollout."` // nolint: lll
+}
+
+// NewProgressiveRollout creates a progressive rollout from its ordered steps.
+// A rollout of 2 steps is described with Initial and End to stay compatible with the clients
+// that do not know the steps.
+func NewProgressiveRollout(steps []ProgressiveRolloutStep) *ProgressiveRollout {
+	if len(steps) == 0 {
+		return nil
+	}
+	if len(steps) == 2 {
+		return &ProgressiveRollout{Initial: &steps[0], End: &steps[1]}
+	}
+	return &ProgressiveRollout{Steps: steps}
+}
+
+// GetSteps returns the ordered steps of the rollout whatever the way it is described.
+func (p ProgressiveRollout) GetSteps() []ProgressiveRolloutStep {
+	if len(p.Steps) > 0 {
+		return p.Steps
+	}
+	steps := make([]ProgressiveRolloutStep, 0, 2)
+	if p.Initial != nil {
+		steps = append(steps, *p.Initial)
+	}
+	if p.End != nil {
+		steps = append(steps, *p.End)
+	}
+	return steps
+}
+
+// Segments returns the rollout as successive rollouts of 2 steps (Initial and End), the only progressive
+// rollout the relay proxy understands. The segment between two steps rolls out the variation of the second step:
+//   - if the previous step has the same variation, the segment continues its rollout from its percentage;
+//   - otherwise the segment starts a new rollout from 0% over the variation of the previous step, which is then
+//     served to all the users not getting the new variation yet.
+//
+// The first segment starts from the first step like a rollout described with Initial and End.
+func (p ProgressiveRollout) Segments() []ProgressiveRollout {
+	steps := p.GetSteps()
+	if len(steps) < 2 {
+		return nil
+	}
+	segments := make([]ProgressiveRollout, 0, len(steps)-1)
+	for i := 1; i < len(steps); i++ {
+		initial := steps[i-1]
+		if i > 1 {
+			previous := segments[i-2]
+			if sameVariation(steps[i-1].Variation, steps[i].Variation) {
+				initial.Variation = previous.Initial.Variation
+			} else {
+				zero := 0.0
+				initial.Percentage = &zero
+			}
+		}
+		end := steps[i]
+		segments = append(segments, ProgressiveRollout{Initial: &initial, End: &end})
+	}
+	return segments
+}
+
+func sameVariation(a *string, b *string) bool {
+	return a != nil && b != nil && *a == *b
 }
 
-// ProgressiveRolloutStep define a progressive rollout step (initial and end)
+// ProgressiveRolloutStep define a progressive rollout step
 type ProgressiveRolloutStep struct {
 	// Variation - name of the variation for this step
 	Variation *string `json:"variation,omitempty" yaml:"variation,omitempty" toml:"variation,omitempty" jsonschema:"required,title=variation,description=Name of the variation to apply."` // nolint: lll
 
-	// Percentage is the percentage (initial or end) for the progressive rollout
+	// Percentage is the percentage of the users getting the rolled out variation at the date of the step
 	Percentage *float64 `json:"percentage,omitempty" yaml:"percentage,omitempty" toml:"percentage,omitempty" jsonschema:"required,title=percentage,description=The percentage (initial or end) for the progressive rollout."` // nolint: lll
 
 	// Date is the time it starts or ends.
 	Date *time.Time `json:"date,omitempty" yaml:"date,omitempty" toml:"date,omitempty" jsonschema:"required,title=date,description=Date is the time it starts or ends."` // nolint: lll
 }
 
+// ExperimentationRollout is the time window of an experimentation, outside of this window the flag is considered
+// disabled and the SDK returns its default value.
+type ExperimentationRollout struct {
+	// Start is the date from which the experimentation is running.
+	Start *time.Time `json:"start,omitempty" yaml:"start,omitempty" toml:"start,omitempty" jsonschema:"required,title=start,description=Start is the date from which the experimentation is running."` // nolint: lll
+
+	// End is the date after which the experimentation is over.
+	End *time.Time `json:"end,omitempty" yaml:"end,omitempty" toml:"end,omitempty" jsonschema:"required,title=end,description=End is the date after which the experimentation is over."` // nolint: lll
+}
+
+// IsRunning returns true if the date is inside the window of the experimentation.
+func (e ExperimentationRollout) IsRunning(now time.Time) bool {
+	if e.Start != nil && now.Before(*e.Start) {
+		return false
+	}
+	return e.End == nil || !now.After(*e.End)
+}
+
 func (ff *FeatureFlag) GetRules() []Rule {
 	if ff.Rules == nil {
 		return []Rule{}
@@ -108,3 +199,28 @@ func (ff *FeatureFlag) GetDefaultRule() Rule {
 	}
 	return *ff.DefaultRule
 }
+
+func (ff *FeatureFlag) IsDisable() bool {
+	return ff.Disable != nil && *ff.Disable
+}
+
+// IsExperimentationOver returns true if the flag has an experimentation which is not running at the given date.
+func (ff *FeatureFlag) IsExperimentationOver(now time.Time) bool {
+	return ff.Experimentation != nil && !ff.Experimentation.IsRunning(now)
+}
+
+// GetProject returns the project of the flag, or the default project if not set
+func (ff *FeatureFlag) GetProject() string {
+	if ff.Project == "" {
+		return DefaultProject
+	}
+	return ff.Project
+}
+
+// GetEnvironment returns the environment of the flag, or the default environment if not set
+func (ff *FeatureFlag) GetEnvironment() string {
+	if ff.Environment == "" {
+		return DefaultEnvironment
+	}
+	return ff.Environment
+}
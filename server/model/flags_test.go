@@ -3,6 +3,7 @@ package model_test
 import (
 	"github.com/go-feature-flag/flag-management/server/testutils"
 	"testing"
+	"time"
 
 	"github.com/go-feature-flag/flag-management/server/model"
 	"github.com/google/uuid"
@@ -160,3 +161,73 @@ func TestFeatureFlag_GetDefaultRule(t *testing.T) {
 		})
 	}
 }
+
+func TestFeatureFlag_IsDisable(t *testing.T) {
+	tests := []struct {
+		name string
+		flag *model.FeatureFlag
+		want bool
+	}{
+		{
+			name: "should return false if not set",
+			flag: &model.FeatureFlag{},
+			want: false,
+		},
+		{
+			name: "should return false if set to false",
+			flag: &model.FeatureFlag{Disable: testutils.Bool(false)},
+			want: false,
+		},
+		{
+			name: "should return true if set to true",
+			flag: &model.FeatureFlag{Disable: testutils.Bool(true)},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.flag.IsDisable())
+		})
+	}
+}
+
+func TestFeatureFlag_IsExperimentationOver(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		flag *model.FeatureFlag
+		now  time.Time
+		want bool
+	}{
+		{
+			name: "should return false without experimentation",
+			flag: &model.FeatureFlag{},
+			now:  start,
+			want: false,
+		},
+		{
+			name: "should return true before the start date",
+			flag: &model.FeatureFlag{Experimentation: &model.ExperimentationRollout{Start: &start, End: &end}},
+			now:  start.Add(-time.Second),
+			want: true,
+		},
+		{
+			name: "should return false during the experimentation",
+			flag: &model.FeatureFlag{Experimentation: &model.ExperimentationRollout{Start: &start, End: &end}},
+			now:  start,
+			want: false,
+		},
+		{
+			name: "should return true after the end date",
+			flag: &model.FeatureFlag{Experimentation: &model.ExperimentationRollout{Start: &start, End: &end}},
+			now:  end.Add(time.Second),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.flag.IsExperimentationOver(tt.now))
+		})
+	}
+}
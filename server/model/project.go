@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// DefaultProject is the project of the flags created without any project
+	DefaultProject = "default"
+	// DefaultEnvironment is the environment of the flags created without any environment
+	DefaultEnvironment = "default"
+)
+
+// scopeNameRegexp restricts the names of the projects and environments since they are used in the URLs
+var scopeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Project groups the environments of an application or a team.
+type Project struct {
+	// Name is the unique identifier of the project, it is used in the URLs
+	Name string `json:"name" example:"checkout"`
+
+	// Description (optional) explains what the project is about
+	Description *string `json:"description,omitempty"`
+
+	// CreatedDate is the date when the project has been created
+	CreatedDate time.Time `json:"createdDate"`
+}
+
+// Environment is a set of flags inside a project (ex: staging, production), the same flag name can
+// exist in several environments with a different configuration.
+type Environment struct {
+	// Project is the name of the project of the environment
+	Project string `json:"project" example:"checkout"`
+
+	// Name is the identifier of the environment inside its project, it is used in the URLs
+	Name string `json:"name" example:"production"`
+
+	// Description (optional) explains what the environment is used for
+	Description *string `json:"description,omitempty"`
+
+	// CreatedDate is the date when the environment has been created
+	CreatedDate time.Time `json:"createdDate"`
+}
+
+// ValidateScopeName checks that the name can be used as a project or environment name
+func ValidateScopeName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !scopeNameRegexp.MatchString(name) {
+		return fmt.Errorf("name %s is invalid, it should only contain letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
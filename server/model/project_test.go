@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScopeName(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+	}{
+		{"simple name", "production", false},
+		{"name with separators", "eu-west_1.prod", false},
+		{"name with digits", "2024", false},
+		{"empty name", "", true},
+		{"name with a slash", "prod/eu", true},
+		{"name with a space", "my project", true},
+		{"name starting with a separator", "-prod", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := model.ValidateScopeName(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFeatureFlag_GetScope(t *testing.T) {
+	flag := model.FeatureFlag{}
+	assert.Equal(t, model.DefaultProject, flag.GetProject())
+	assert.Equal(t, model.DefaultEnvironment, flag.GetEnvironment())
+
+	flag = model.FeatureFlag{Project: "checkout", Environment: "production"}
+	assert.Equal(t, "checkout", flag.GetProject())
+	assert.Equal(t, "production", flag.GetEnvironment())
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type ScheduledChangeStatus string
+
+const (
+	// ScheduledChangeStatusPending is a change waiting for its date, it can still be edited or cancelled
+	ScheduledChangeStatusPending ScheduledChangeStatus = "pending"
+	// ScheduledChangeStatusApplied is a change applied to the flag
+	ScheduledChangeStatusApplied ScheduledChangeStatus = "applied"
+	// ScheduledChangeStatusFailed is a change that could not be applied, the reason is in the error of the change
+	ScheduledChangeStatusFailed ScheduledChangeStatus = "failed"
+	// ScheduledChangeStatusCancelled is a change cancelled before its date
+	ScheduledChangeStatusCancelled ScheduledChangeStatus = "cancelled"
+)
+
+// ScheduledChangeStatusFromValue converts a string to a ScheduledChangeStatus
+func ScheduledChangeStatusFromValue(value string) (ScheduledChangeStatus, error) {
+	switch ScheduledChangeStatus(value) {
+	case ScheduledChangeStatusPending, ScheduledChangeStatusApplied, ScheduledChangeStatusFailed,
+		ScheduledChangeStatusCancelled:
+		return ScheduledChangeStatus(value), nil
+	default:
+		return "", fmt.Errorf("scheduled change status %s not supported", value)
+	}
+}
+
+// ScheduledChange is a change of a flag applied automatically at a given date.
+type ScheduledChange struct {
+	// ID is the unique identifier of the scheduled change
+	ID string `json:"id"`
+
+	// FlagID is the ID of the flag to change
+	FlagID string `json:"flagId"`
+
+	// ScheduledDate is the date from which the change is applied
+	ScheduledDate time.Time `json:"scheduledDate"`
+
+	// Changes are the fields of the flag replaced when the change is applied
+	Changes FlagChanges `json:"changes"`
+
+	// Status is the state of the change (pending, applied, failed or cancelled)
+	Status ScheduledChangeStatus `json:"status"`
+
+	// Error explains why the change has not been applied when the status is failed
+	Error string `json:"error,omitempty"`
+
+	// CreatedDate is the date when the change has been scheduled
+	CreatedDate time.Time `json:"createdDate"`
+
+	// CreatedBy is the principal who scheduled the change, the flag is modified in their name
+	CreatedBy string `json:"createdBy"`
+
+	// LastUpdatedDate is the last time the change has been edited, applied or cancelled
+	LastUpdatedDate time.Time `json:"lastUpdatedDate"`
+
+	// AppliedDate is the date when the change has been applied to the flag
+	AppliedDate *time.Time `json:"appliedDate,omitempty"`
+}
+
+// IsPending returns true if the change is waiting for its date
+func (s ScheduledChange) IsPending() bool {
+	return s.Status == ScheduledChangeStatusPending
+}
+
+// FlagChanges are the fields of a flag replaced by a scheduled change, the fields not set are kept as they are.
+type FlagChanges struct {
+	// Rules replaces all the targeting rules of the flag
+	Rules *[]Rule `json:"targeting,omitempty"`
+
+	// DefaultRule replaces the default rule of the flag
+	DefaultRule *Rule `json:"defaultRule,omitempty"`
+
+	// Disable enables (false) or disables (true) the flag
+	Disable *bool `json:"disable,omitempty"`
+}
+
+// IsEmpty returns true if the changes do not modify anything
+func (c FlagChanges) IsEmpty() bool {
+	return c.Rules == nil && c.DefaultRule == nil && c.Disable == nil
+}
+
+// ApplyTo returns a copy of the flag with the changes applied.
+func (c FlagChanges) ApplyTo(flag FeatureFlag) FeatureFlag {
+	if c.Rules != nil {
+		rules := append([]Rule{}, *c.Rules...)
+		flag.Rules = &rules
+	}
+	if c.DefaultRule != nil {
+		defaultRule := *c.DefaultRule
+		flag.DefaultRule = &defaultRule
+	}
+	if c.Disable != nil {
+		disable := *c.Disable
+		flag.Disable = &disable
+	}
+	return flag
+}
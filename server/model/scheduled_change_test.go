@@ -0,0 +1,78 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledChangeStatusFromValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      model.ScheduledChangeStatus
+		expectErr bool
+	}{
+		{"valid pending status", "pending", model.ScheduledChangeStatusPending, false},
+		{"valid cancelled status", "cancelled", model.ScheduledChangeStatusCancelled, false},
+		{"empty status", "", "", true},
+		{"unsupported status", "done", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := model.ScheduledChangeStatusFromValue(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFlagChanges_ApplyTo(t *testing.T) {
+	flag := model.FeatureFlag{
+		Name:        "flag1",
+		Rules:       &[]model.Rule{{ID: "rule-1", Query: `beta eq true`, VariationResult: testutils.String("B")}},
+		DefaultRule: &model.Rule{ID: "default", VariationResult: testutils.String("A")},
+		Disable:     testutils.Bool(true),
+	}
+
+	tests := []struct {
+		name    string
+		changes model.FlagChanges
+		empty   bool
+		want    model.FeatureFlag
+	}{
+		{
+			name:    "should keep the flag as it is without changes",
+			changes: model.FlagChanges{},
+			empty:   true,
+			want:    flag,
+		},
+		{
+			name: "should replace the rules, the default rule and the status",
+			changes: model.FlagChanges{
+				Rules:       &[]model.Rule{},
+				DefaultRule: &model.Rule{ID: "default", VariationResult: testutils.String("B")},
+				Disable:     testutils.Bool(false),
+			},
+			want: model.FeatureFlag{
+				Name:        "flag1",
+				Rules:       &[]model.Rule{},
+				DefaultRule: &model.Rule{ID: "default", VariationResult: testutils.String("B")},
+				Disable:     testutils.Bool(false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.empty, tt.changes.IsEmpty())
+			assert.Equal(t, tt.want, tt.changes.ApplyTo(flag))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package rulequery validates and evaluates the targeting queries of the rules.
+// The queries are evaluated by the GO Feature Flag relay proxy with the nikunjy/rules library, this package uses
+// the same library and prepares the queries the same way, so a query is accepted only if the relay proxy can parse
+// it, and a flag can be previewed with the same result as the relay proxy.
+package rulequery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/nikunjy/rules/parser"
+)
+
+// SyntaxError describes why a query cannot be parsed.
+type SyntaxError struct {
+	// Position is the index of the invalid character in the query (starting at 0)
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Position, e.Message)
+}
+
+// Validate returns a *SyntaxError if the query is not a valid nikunjy/rules expression.
+// The relay proxy silently ignores what follows a complete expression, it is reported as an error here.
+func Validate(query string) error {
+	trimmed, offsets := trim(query)
+	if err := validate(trimmed); err != nil {
+		// the position is reported in the query written by the user, not in the trimmed one
+		if err.Position < len(offsets) {
+			err.Position = offsets[err.Position]
+		} else {
+			err.Position = len(query)
+		}
+		return err
+	}
+	return nil
+}
+
+func validate(query string) *SyntaxError {
+	listener := &syntaxErrorListener{}
+	lexer := parser.NewJsonQueryLexer(antlr.NewInputStream(query))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(listener)
+	tokens := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	p := parser.NewJsonQueryParser(tokens)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(listener)
+	p.Query()
+	if listener.err != nil {
+		return listener.err
+	}
+	if next := tokens.LT(1); next.GetTokenType() != antlr.TokenEOF {
+		return &SyntaxError{
+			Position: next.GetStart(),
+			Message:  fmt.Sprintf("unexpected %q after the end of the expression", next.GetText()),
+		}
+	}
+	return nil
+}
+
+// Match returns true if the attributes match the query, an invalid query never matches like in the relay proxy.
+func Match(query string, attributes map[string]interface{}) bool {
+	return parser.Evaluate(Trim(query), attributes)
+}
+
+// Trim prepares a query like the relay proxy before parsing it, the new lines and the spaces at the beginning
+// of each line are removed.
+func Trim(query string) string {
+	res, _ := trim(query)
+	return res
+}
+
+// trim returns the query prepared like in Trim and the index in the query of each byte of the result.
+func trim(query string) (string, []int) {
+	var res strings.Builder
+	offsets := make([]int, 0, len(query))
+	start := 0
+	for _, line := range strings.SplitAfter(query, "\n") {
+		content := strings.TrimSuffix(line, "\n")
+		trimmed := strings.TrimLeft(content, " ")
+		for i := len(content) - len(trimmed); i < len(content); i++ {
+			offsets = append(offsets, start+i)
+		}
+		res.WriteString(trimmed)
+		start += len(line)
+	}
+	return res.String(), offsets
+}
+
+// syntaxErrorListener keeps the first error reported by the lexer or the parser.
+type syntaxErrorListener struct {
+	*antlr.DefaultErrorListener
+	err *SyntaxError
+}
+
+func (l *syntaxErrorListener) SyntaxError(
+	_ antlr.Recognizer, _ interface{}, _ int, column int, msg string, _ antlr.RecognitionException) {
+	if l.err == nil {
+		l.err = &SyntaxError{Position: column, Message: msg}
+	}
+}
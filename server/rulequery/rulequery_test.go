@@ -0,0 +1,54 @@
+package rulequery_test
+
+import (
+	"github.com/go-feature-flag/flag-management/server/rulequery"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		query       string
+		expectedErr string
+	}{
+		{query: `key eq "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`},
+		{query: `beta eq true`},
+		{query: `anonymous == false and email ew "@gofeatureflag.org"`},
+		{query: `user.company.name sw "go" or user.age >= 18`},
+		{query: `not (country in ["FR", "DE"]) and plan pr`},
+		{query: `(score gt -1.5e3) and (ids IN [1,2,3])`},
+		{query: `app_version ge 1.2.3`},
+		{query: `deleted-at eq null`},
+		{query: "key eq \"a\" \n  and beta eq true"},
+		{
+			// the relay proxy removes the new lines without adding a space
+			query:       "key eq \"a\"\n  and beta eq true",
+			expectedErr: `syntax error at position 13: unexpected "and" after the end of the expression`,
+		},
+		{query: ``, expectedErr: `syntax error at position 0: mismatched input '<EOF>' expecting {'(', NOT, ATTRNAME, SP}`},
+		{query: `key eq`, expectedErr: `syntax error at position 6: mismatched input '<EOF>' expecting SP`},
+		{query: `key equals "a"`, expectedErr: `syntax error at position 4: no viable alternative at input 'key equals'`},
+		{query: `key eq "a" AND beta eq true`, expectedErr: `syntax error at position 10: unexpected " " after the end of the expression`},
+		{query: `key eq "a" beta eq true`, expectedErr: `syntax error at position 10: unexpected " " after the end of the expression`},
+		{query: `key eq "a")`, expectedErr: `syntax error at position 10: unexpected ")" after the end of the expression`},
+		{query: `  key equals "a"`, expectedErr: `syntax error at position 6: no viable alternative at input 'key equals'`},
+		{query: "key eq \"a\" and \n  beta equals true", expectedErr: `syntax error at position 23: no viable alternative at input 'beta equals'`},
+		{query: `  key eq`, expectedErr: `syntax error at position 8: mismatched input '<EOF>' expecting SP`},
+		{query: `key eq "a`, expectedErr: `syntax error at position 7: token recognition error at: '"a'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := rulequery.Validate(tt.query)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	assert.Equal(t, `key eq "a" and beta eq true`, rulequery.Trim("key eq \"a\" \n    and beta eq true\n"))
+}
@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/go-feature-flag/flag-management/server/auth"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/util"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"go.uber.org/zap"
+)
+
+// DefaultInterval is the time between two checks of the scheduled changes if no interval is configured
+const DefaultInterval = time.Minute
+
+type WorkerOptions struct {
+	Clock util.Clock
+	// Interval is the time between two checks of the scheduled changes.
+	// Default is DefaultInterval.
+	Interval time.Duration
+}
+
+// Worker applies the scheduled changes of the flags once their date is reached.
+type Worker struct {
+	flags   dao.FlagStorage
+	changes dao.ScheduledChangeStorage
+	options *WorkerOptions
+}
+
+// NewWorker creates a new instance of the Worker.
+// The worker does nothing until Start is called.
+func NewWorker(flags dao.FlagStorage, changes dao.ScheduledChangeStorage, options *WorkerOptions) *Worker {
+	if options == nil {
+		options = &WorkerOptions{}
+	}
+	if options.Clock == nil {
+		options.Clock = util.DefaultClock{}
+	}
+	if options.Interval <= 0 {
+		options.Interval = DefaultInterval
+	}
+	return &Worker{flags: flags, changes: changes, options: options}
+}
+
+// Start applies the due changes every interval until the context is done.
+func (w *Worker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.ApplyDueChanges(ctx); err != nil {
+			zap.L().Error("impossible to apply the scheduled changes", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ApplyDueChanges applies all the pending changes with a scheduled date in the past, the oldest first.
+// A change is marked as applied in the same transaction as the update of its flag, so if another instance of
+// the server applies the same change at the same time, only one of them succeeds and the change is not applied
+// twice.
+func (w *Worker) ApplyDueChanges(ctx context.Context) error {
+	now := w.options.Clock.Now()
+	dueChanges, err := w.changes.GetScheduledChanges(ctx, dao.ScheduledChangeQuery{
+		Status:    model.ScheduledChangeStatusPending,
+		DueBefore: &now,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, change := range dueChanges {
+		if err := w.apply(ctx, change); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apply applies a single change to its flag and records the result in the change.
+// An error is returned only if the change should be retried at the next interval.
+func (w *Worker) apply(ctx context.Context, change model.ScheduledChange) error {
+	now := w.options.Clock.Now()
+	flag, err := w.flags.GetFlagByID(ctx, change.FlagID)
+	if err != nil {
+		if err.Code() == daoErr.NotFound {
+			return w.complete(ctx, change, model.ScheduledChangeStatusFailed, "flag not found")
+		}
+		return err
+	}
+
+	updatedFlag := change.Changes.ApplyTo(flag)
+	updatedFlag.LastUpdatedDate = now
+	updatedFlag.LastModifiedBy = change.CreatedBy
+	if err := updatedFlag.Validate(); err != nil {
+		return w.complete(ctx, change, model.ScheduledChangeStatusFailed, err.Error())
+	}
+
+	// the change is marked as applied in the same transaction as the update of the flag, so it cannot be
+	// applied twice even if storing its status fails
+	applied := completed(change, model.ScheduledChangeStatusApplied, "", now)
+	// the change is made on behalf of the user who scheduled it
+	ctx = auth.WithPrincipal(ctx, auth.Principal{Name: change.CreatedBy})
+	if err := w.changes.ApplyScheduledChange(ctx, updatedFlag, applied); err != nil {
+		if err.Code() == daoErr.StaleRevision {
+			// the flag has been modified in the meantime, we keep the change pending to apply it on top of
+			// the new revision at the next interval, or the change has already been applied by another instance
+			return nil
+		}
+		return err
+	}
+	logCompleted(applied)
+	return nil
+}
+
+// complete stores the final status of a change that has not been applied
+func (w *Worker) complete(
+	ctx context.Context, change model.ScheduledChange, status model.ScheduledChangeStatus, reason string) error {
+	change = completed(change, status, reason, w.options.Clock.Now())
+	if err := w.changes.UpdateScheduledChange(ctx, change); err != nil {
+		return err
+	}
+	logCompleted(change)
+	return nil
+}
+
+// completed returns the change with its final status
+func completed(
+	change model.ScheduledChange, status model.ScheduledChangeStatus, reason string, now time.Time,
+) model.ScheduledChange {
+	change.Status = status
+	change.Error = reason
+	change.LastUpdatedDate = now
+	if status == model.ScheduledChangeStatusApplied {
+		change.AppliedDate = &now
+	}
+	return change
+}
+
+func logCompleted(change model.ScheduledChange) {
+	zap.L().Info("scheduled change completed",
+		zap.String("id", change.ID), zap.String("flagId", change.FlagID), zap.String("status", string(change.Status)))
+}
@@ -0,0 +1,206 @@
+package scheduler_test
+
+import (
+	"context"
+	"github.com/go-feature-flag/flag-management/server/dao"
+	daoErr "github.com/go-feature-flag/flag-management/server/dao/err"
+	"github.com/go-feature-flag/flag-management/server/scheduler"
+	"github.com/go-feature-flag/flag-management/server/testutils"
+	"testing"
+	"time"
+
+	"github.com/go-feature-flag/flag-management/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorker_ApplyDueChanges(t *testing.T) {
+	// the clock of the worker is 2020-01-01
+	now := testutils.ClockMock{}.Now()
+	flag := model.FeatureFlag{
+		ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+		Name:          "flag1",
+		VariationType: model.FlagTypeString,
+		Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+		DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils.String("A")},
+		Disable:       testutils.Bool(true),
+		Revision:      1,
+	}
+	pendingChange := func(changes model.FlagChanges, date time.Time) model.ScheduledChange {
+		return model.ScheduledChange{
+			ID:              "0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+			FlagID:          flag.ID,
+			ScheduledDate:   date,
+			Changes:         changes,
+			Status:          model.ScheduledChangeStatusPending,
+			CreatedBy:       "foo",
+			LastUpdatedDate: date.Add(-time.Hour),
+		}
+	}
+	enable := model.FlagChanges{Disable: testutils.Bool(false)}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		flags        []model.FeatureFlag
+		change       model.ScheduledChange
+		wantErr      assert.ErrorAssertionFunc
+		wantStatus   model.ScheduledChangeStatus
+		wantError    string
+		wantApplied  bool
+		wantDisabled bool
+	}{
+		{
+			name:         "should apply a due change",
+			flags:        []model.FeatureFlag{flag},
+			change:       pendingChange(enable, now.Add(-time.Minute)),
+			wantErr:      assert.NoError,
+			wantStatus:   model.ScheduledChangeStatusApplied,
+			wantApplied:  true,
+			wantDisabled: false,
+		},
+		{
+			name:         "should not apply a change before its date",
+			flags:        []model.FeatureFlag{flag},
+			change:       pendingChange(enable, now.Add(time.Minute)),
+			wantErr:      assert.NoError,
+			wantStatus:   model.ScheduledChangeStatusPending,
+			wantDisabled: true,
+		},
+		{
+			name:       "should fail the change if the flag does not exist anymore",
+			change:     pendingChange(enable, now),
+			wantErr:    assert.NoError,
+			wantStatus: model.ScheduledChangeStatusFailed,
+			wantError:  "flag not found",
+		},
+		{
+			name:  "should fail the change if the flag would be invalid",
+			flags: []model.FeatureFlag{flag},
+			change: pendingChange(model.FlagChanges{
+				DefaultRule: &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils.String("C")},
+			}, now),
+			wantErr:      assert.NoError,
+			wantStatus:   model.ScheduledChangeStatusFailed,
+			wantError:    "defaultRule.variation: variation C does not exist",
+			wantDisabled: true,
+		},
+		{
+			name:         "should keep the change pending if the flag has been modified in the meantime",
+			ctx:          context.WithValue(context.Background(), "error_update", daoErr.StaleRevision),
+			flags:        []model.FeatureFlag{flag},
+			change:       pendingChange(enable, now),
+			wantErr:      assert.NoError,
+			wantStatus:   model.ScheduledChangeStatusPending,
+			wantDisabled: true,
+		},
+		{
+			name:         "should return an error if the storage fails",
+			ctx:          context.WithValue(context.Background(), "error_update", daoErr.UnknownError),
+			flags:        []model.FeatureFlag{flag},
+			change:       pendingChange(enable, now),
+			wantErr:      assert.Error,
+			wantStatus:   model.ScheduledChangeStatusPending,
+			wantDisabled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao, err := dao.NewInMemoryMockDao()
+			require.NoError(t, err)
+			mockDao.SetFlags(tt.flags)
+			mockDao.SetScheduledChanges([]model.ScheduledChange{tt.change})
+			worker := scheduler.NewWorker(mockDao, mockDao, &scheduler.WorkerOptions{Clock: testutils.ClockMock{}})
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			tt.wantErr(t, worker.ApplyDueChanges(ctx))
+
+			change, daoErr := mockDao.GetScheduledChangeByID(context.Background(), tt.change.ID)
+			require.Nil(t, daoErr)
+			assert.Equal(t, tt.wantStatus, change.Status)
+			assert.Equal(t, tt.wantError, change.Error)
+			if tt.wantApplied {
+				require.NotNil(t, change.AppliedDate)
+				assert.Equal(t, now, *change.AppliedDate)
+			} else {
+				assert.Nil(t, change.AppliedDate)
+			}
+
+			if len(tt.flags) > 0 {
+				updatedFlag, daoErr := mockDao.GetFlagByID(context.Background(), flag.ID)
+				require.Nil(t, daoErr)
+				assert.Equal(t, tt.wantDisabled, updatedFlag.IsDisable())
+				if tt.wantApplied {
+					assert.Equal(t, "foo", updatedFlag.LastModifiedBy)
+					assert.Equal(t, now, updatedFlag.LastUpdatedDate)
+				}
+			}
+		})
+	}
+}
+
+// staleChanges returns the changes as they were before another instance of the server applied them
+type staleChanges struct {
+	*dao.InMemoryMockDao
+	changes []model.ScheduledChange
+}
+
+func (s staleChanges) GetScheduledChanges(
+	_ context.Context, _ dao.ScheduledChangeQuery) ([]model.ScheduledChange, daoErr.DaoError) {
+	return s.changes, nil
+}
+
+func TestWorker_ApplyDueChanges_alreadyApplied(t *testing.T) {
+	now := testutils.ClockMock{}.Now()
+	flag := model.FeatureFlag{
+		ID:            "926214f3-80c1-46e6-a913-b2d40b92a932",
+		Name:          "flag1",
+		VariationType: model.FlagTypeString,
+		Variations:    &map[string]interface{}{"A": "a", "B": "b"},
+		DefaultRule:   &model.Rule{ID: "926214f3-80c1-46e6-a913-b2d40b92a000", VariationResult: testutils.String("A")},
+		Revision:      1,
+	}
+	change := model.ScheduledChange{
+		ID:            "0d6a1b3e-2f4c-4a8e-9c1d-7b5e3f9a2c01",
+		FlagID:        flag.ID,
+		ScheduledDate: now.Add(-time.Minute),
+		Changes:       model.FlagChanges{Disable: testutils.Bool(true)},
+		Status:        model.ScheduledChangeStatusPending,
+	}
+	mockDao, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	mockDao.SetFlags([]model.FeatureFlag{flag})
+	applied := change
+	applied.Status = model.ScheduledChangeStatusApplied
+	mockDao.SetScheduledChanges([]model.ScheduledChange{applied})
+
+	storage := staleChanges{InMemoryMockDao: mockDao, changes: []model.ScheduledChange{change}}
+	worker := scheduler.NewWorker(storage, storage, &scheduler.WorkerOptions{Clock: testutils.ClockMock{}})
+	require.NoError(t, worker.ApplyDueChanges(context.Background()))
+
+	got, daoErr := mockDao.GetFlagByID(context.Background(), flag.ID)
+	require.Nil(t, daoErr)
+	assert.Equal(t, flag, got, "the flag should not be updated by a change applied by another instance")
+}
+
+func TestWorker_Start(t *testing.T) {
+	mockDao, err := dao.NewInMemoryMockDao()
+	require.NoError(t, err)
+	worker := scheduler.NewWorker(mockDao, mockDao, &scheduler.WorkerOptions{Interval: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.Start(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the worker should stop when the context is done")
+	}
+}
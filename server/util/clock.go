@@ -9,3 +9,10 @@ type Clock interface {
 type DefaultClock struct{}
 
 func (DefaultClock) Now() time.Time { return time.Now() }
+
+// FixedClock always returns the same date, it is used to evaluate the flags at another date than now.
+type FixedClock struct {
+	Date time.Time
+}
+
+func (c FixedClock) Now() time.Time { return c.Date }